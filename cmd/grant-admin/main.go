@@ -0,0 +1,48 @@
+// Command grant-admin grants the admin role to a user, giving access to the usage reports of all users.
+//
+// The user must have signed in once. Run it with -revoke to remove the role.
+package main
+
+import (
+	"context"
+	"flag"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/secret"
+	"paperdebugger/internal/services"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the user")
+	revoke := flag.Bool("revoke", false, "revoke the admin role instead of granting it")
+	flag.Parse()
+
+	log := logger.GetLogger()
+	if *email == "" {
+		log.Fatalf("[PAPERDEBUGGER] -email is required")
+	}
+
+	config := cfg.GetCfg()
+	keyring, err := secret.NewKeyring(config)
+	if err != nil {
+		log.Fatalf("[PAPERDEBUGGER] invalid master keys: %v", err)
+	}
+
+	database, err := db.NewDB(config, log)
+	if err != nil {
+		log.Fatalf("[PAPERDEBUGGER] failed to connect to the database: %v", err)
+	}
+
+	userService := services.NewUserService(database, keyring, config, log)
+	if err := userService.SetUserAdmin(context.Background(), *email, !*revoke); err != nil {
+		log.Fatalf("[PAPERDEBUGGER] failed to update the admin role of %s: %v", *email, err)
+	}
+
+	if *revoke {
+		log.Infof("[PAPERDEBUGGER] revoked the admin role of %s", *email)
+		return
+	}
+	log.Infof("[PAPERDEBUGGER] granted the admin role to %s", *email)
+}
@@ -0,0 +1,52 @@
+// Command migrate-api-keys encrypts the API keys stored in the users collection with the current master key.
+//
+// It encrypts the API keys stored in plaintext, and re-encrypts the ones encrypted with a previous master key.
+// To rotate the master key:
+//  1. Set API_KEY_MASTER_KEY to the new key, and add the old key to API_KEY_PREVIOUS_MASTER_KEYS.
+//  2. Deploy, and run this command.
+//  3. Remove the old key from API_KEY_PREVIOUS_MASTER_KEYS.
+package main
+
+import (
+	"context"
+	"flag"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/secret"
+	"paperdebugger/internal/services"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only count the users whose API keys need to be encrypted")
+	flag.Parse()
+
+	log := logger.GetLogger()
+	config := cfg.GetCfg()
+
+	keyring, err := secret.NewKeyring(config)
+	if err != nil {
+		log.Fatalf("[PAPERDEBUGGER] invalid master keys: %v", err)
+	}
+	if !keyring.Enabled() {
+		log.Fatalf("[PAPERDEBUGGER] API_KEY_MASTER_KEY is not set")
+	}
+
+	database, err := db.NewDB(config, log)
+	if err != nil {
+		log.Fatalf("[PAPERDEBUGGER] failed to connect to the database: %v", err)
+	}
+
+	userService := services.NewUserService(database, keyring, config, log)
+	updated, err := userService.ReencryptAPIKeys(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("[PAPERDEBUGGER] failed to encrypt API keys after %d users: %v", updated, err)
+	}
+
+	if *dryRun {
+		log.Infof("[PAPERDEBUGGER] %d users have API keys to encrypt", updated)
+		return
+	}
+	log.Infof("[PAPERDEBUGGER] encrypted the API keys of %d users", updated)
+}
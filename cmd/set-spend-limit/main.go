@@ -0,0 +1,56 @@
+// Command set-spend-limit sets the weekly spend limit of a user on the shared API key, in USD, overriding
+// USER_WEEKLY_SPEND_LIMIT. A limit of 0 means unlimited.
+//
+// The user must have signed in once. Run it with -reset to give the user the default limit again.
+package main
+
+import (
+	"context"
+	"flag"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/secret"
+	"paperdebugger/internal/services"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the user")
+	limit := flag.Float64("limit", -1, "weekly spend limit in USD, 0 means unlimited")
+	reset := flag.Bool("reset", false, "remove the limit of the user instead of setting it")
+	flag.Parse()
+
+	log := logger.GetLogger()
+	if *email == "" {
+		log.Fatalf("[PAPERDEBUGGER] -email is required")
+	}
+	if !*reset && *limit < 0 {
+		log.Fatalf("[PAPERDEBUGGER] -limit or -reset is required")
+	}
+
+	config := cfg.GetCfg()
+	keyring, err := secret.NewKeyring(config)
+	if err != nil {
+		log.Fatalf("[PAPERDEBUGGER] invalid master keys: %v", err)
+	}
+
+	database, err := db.NewDB(config, log)
+	if err != nil {
+		log.Fatalf("[PAPERDEBUGGER] failed to connect to the database: %v", err)
+	}
+
+	userService := services.NewUserService(database, keyring, config, log)
+	if *reset {
+		if err := userService.SetUserWeeklySpendLimit(context.Background(), *email, nil); err != nil {
+			log.Fatalf("[PAPERDEBUGGER] failed to reset the weekly spend limit of %s: %v", *email, err)
+		}
+		log.Infof("[PAPERDEBUGGER] reset the weekly spend limit of %s to the default", *email)
+		return
+	}
+
+	if err := userService.SetUserWeeklySpendLimit(context.Background(), *email, limit); err != nil {
+		log.Fatalf("[PAPERDEBUGGER] failed to set the weekly spend limit of %s: %v", *email, err)
+	}
+	log.Infof("[PAPERDEBUGGER] set the weekly spend limit of %s to $%.2f", *email, *limit)
+}
@@ -24,7 +24,7 @@ func TestAuthServer(t *testing.T) {
 		t.Fatalf("Failed to create db: %v", err)
 	}
 	tokenService := services.NewTokenService(db, cfg, logger)
-	userService := services.NewUserService(db, cfg, logger)
+	userService := services.NewUserService(db, nil, cfg, logger)
 	authServer := NewAuthServer(tokenService, userService, cfg, logger)
 	assert.NotNil(t, authServer)
 
@@ -0,0 +1,54 @@
+package chat
+
+import (
+	"context"
+	"time"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// cancelTimeout is how long CancelConversationMessage waits for the generation to write what it produced.
+const cancelTimeout = 10 * time.Second
+
+func (s *ChatServerV2) CancelConversationMessage(
+	ctx context.Context,
+	req *chatv2.CancelConversationMessageRequest,
+) (*chatv2.CancelConversationMessageResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conversationID, err := bson.ObjectIDFromHex(req.GetConversationId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("invalid conversation_id")
+	}
+
+	buffer := s.streams.Get(req.GetConversationId())
+	if buffer == nil || buffer.UserID != actor.ID {
+		return nil, shared.ErrRecordNotFound("no response is being generated for this conversation")
+	}
+
+	buffer.Cancel()
+	select {
+	case <-buffer.Done():
+	case <-time.After(cancelTimeout):
+		s.logger.Error("Timed out waiting for the cancelled generation", "conversationID", req.GetConversationId())
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	conversation, err := s.chatServiceV2.GetConversationV2(ctx, actor.ID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv2.CancelConversationMessageResponse{
+		Conversation: mapper.MapModelConversationToProtoV2(conversation),
+	}, nil
+}
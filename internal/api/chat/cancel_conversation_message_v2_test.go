@@ -0,0 +1,187 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"paperdebugger/internal/accesscontrol"
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	aiclient "paperdebugger/internal/services/toolkit/client"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/grpc"
+)
+
+const partialAnswer = "The introduction"
+
+// newStalledModelStub serves chat completions. The first streamed completion sends partialAnswer, then stalls
+// until the request is cancelled. The other completions answer at once.
+func newStalledModelStub(t *testing.T) *httptest.Server {
+	var streams atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Stream bool `json:"stream"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			json.NewEncoder(w).Encode(map[string]any{
+				"id":      "stub",
+				"object":  "chat.completion",
+				"model":   "gpt-5-nano",
+				"choices": []map[string]any{{"index": 0, "finish_reason": "stop", "message": map[string]any{"role": "assistant", "content": "ok"}}},
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		chunk := func(delta map[string]any, finishReason any) {
+			data, _ := json.Marshal(map[string]any{
+				"id":      "stub",
+				"object":  "chat.completion.chunk",
+				"model":   "gpt-5-nano",
+				"choices": []map[string]any{{"index": 0, "delta": delta, "finish_reason": finishReason}},
+			})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			w.(http.Flusher).Flush()
+		}
+		if streams.Add(1) > 1 {
+			chunk(map[string]any{"role": "assistant", "content": "Title"}, "stop")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		chunk(map[string]any{"role": "assistant", "content": partialAnswer}, nil)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// chunkStream is the client stream of CreateConversationMessageStream, it reports the conversation once the
+// first chunk of the answer is received.
+type chunkStream struct {
+	grpc.ServerStream
+	ctx            context.Context
+	once           sync.Once
+	conversationID chan string
+	initialization string
+}
+
+func (s *chunkStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *chunkStream) Send(event *chatv2.CreateConversationMessageStreamResponse) error {
+	if init := event.GetStreamInitialization(); init != nil {
+		s.initialization = init.GetConversationId()
+	}
+	if event.GetMessageChunk() != nil {
+		s.once.Do(func() { s.conversationID <- s.initialization })
+	}
+	return nil
+}
+
+func TestCancelConversationMessage_KeepsPartialAnswer(t *testing.T) {
+	stub := newStalledModelStub(t)
+	os.Setenv("PD_MONGO_URI", "mongodb://localhost:27017")
+
+	config := cfg.GetCfg()
+	config.InferenceBaseURL = stub.URL
+	logger := logger.GetLogger()
+	db, err := db.NewDB(config, logger)
+	if err != nil {
+		t.Fatalf("Failed to create db: %v", err)
+	}
+
+	projectService := services.NewProjectService(db, config, logger)
+	chatServiceV2 := services.NewChatServiceV2(db, config, logger)
+	userService := services.NewUserService(db, nil, config, logger)
+	proposedEditService := services.NewProposedEditService(db, config, logger)
+	aiClient := aiclient.NewAIClientV2(
+		db,
+		&services.ReverseCommentService{},
+		projectService,
+		proposedEditService,
+		services.NewUsageService(db, config, logger),
+		nil,
+		config,
+		logger,
+	)
+	server := NewChatServerV2(
+		aiClient,
+		chatServiceV2,
+		projectService,
+		userService,
+		services.NewRetrievalService(db, config, logger),
+		proposedEditService,
+		logger,
+		config,
+	)
+
+	user, err := userService.UpsertUserByEmail(context.Background(), &models.User{
+		Email: fmt.Sprintf("cancel-%d@example.com", time.Now().UnixNano()),
+	})
+	assert.NoError(t, err)
+	ctx := contextutil.SetActor(context.Background(), &accesscontrol.Actor{ID: user.ID})
+
+	stream := &chunkStream{ctx: ctx, conversationID: make(chan string, 1)}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.CreateConversationMessageStream(&chatv2.CreateConversationMessageStreamRequest{
+			ProjectId:        "test-project",
+			UserMessage:      "Summarize the introduction",
+			ModelSlug:        "gpt-5-nano",
+			ConversationType: chatv2.ConversationType_CONVERSATION_TYPE_DEBUG.Enum(),
+		}, stream)
+	}()
+
+	var conversationID string
+	select {
+	case conversationID = <-stream.conversationID:
+	case <-time.After(30 * time.Second):
+		t.Fatal("Timed out waiting for the answer to be streamed")
+	}
+
+	resp, err := server.CancelConversationMessage(ctx, &chatv2.CancelConversationMessageRequest{
+		ConversationId: conversationID,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, <-done)
+
+	hasPartialAnswer := func(messages []*chatv2.Message) bool {
+		for _, message := range messages {
+			if message.GetPayload().GetAssistant().GetContent() == partialAnswer {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, hasPartialAnswer(resp.GetConversation().GetMessages()))
+
+	stored, err := server.GetConversation(ctx, &chatv2.GetConversationRequest{ConversationId: conversationID})
+	assert.NoError(t, err)
+	assert.True(t, hasPartialAnswer(stored.GetConversation().GetMessages()))
+}
+
+func TestCancelConversationMessage_InvalidConversationID(t *testing.T) {
+	server := &ChatServerV2{}
+	ctx := contextutil.SetActor(context.Background(), &accesscontrol.Actor{ID: bson.NewObjectID()})
+
+	_, err := server.CancelConversationMessage(ctx, &chatv2.CancelConversationMessageRequest{ConversationId: "invalid"})
+	assert.Equal(t, http.StatusBadRequest, shared.GetHTTPCode(err))
+}
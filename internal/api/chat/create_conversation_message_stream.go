@@ -168,6 +168,10 @@ func (s *ChatServerV1) appendConversationMessage(
 	if err != nil {
 		return nil, err
 	}
+	// The messages of chat v2 are not in the histories of chat v1, which would diverge from them
+	if len(conversation.Messages) > 0 {
+		return nil, shared.ErrBadRequest("this conversation can only be continued with chat v2")
+	}
 
 	userMsg, userOaiMsg, err := s.buildUserMessage(ctx, userMessage, userSelectedText, conversationType)
 	if err != nil {
@@ -288,7 +292,8 @@ func (s *ChatServerV1) CreateConversationMessageStream(
 
 	// Usage is the same as ChatCompletion, just passing the stream parameter
 	llmProvider := &models.LLMProviderConfig{
-		APIKey: settings.OpenAIAPIKey,
+		APIKey:    settings.OpenAIAPIKey,
+		APIKeyAAD: models.APIKeyAAD(conversation.UserID, nil),
 	}
 
 	openaiChatHistory, inappChatHistory, err := s.aiClientV1.ChatCompletionStreamV1(ctx, stream, conversation.ID.Hex(), modelSlug, conversation.OpenaiChatHistory, llmProvider)
@@ -8,8 +8,10 @@ import (
 	"paperdebugger/internal/libs/shared"
 	"paperdebugger/internal/models"
 	"paperdebugger/internal/services"
+	"paperdebugger/internal/services/toolkit/handler"
 	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/openai/openai-go/v3"
@@ -18,7 +20,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
-func (s *ChatServerV2) sendStreamError(stream chatv2.ChatService_CreateConversationMessageStreamServer, err error) error {
+func (s *ChatServerV2) sendStreamError(stream handler.StreamSenderV2, err error) error {
 	return stream.Send(&chatv2.CreateConversationMessageStreamResponse{
 		ResponsePayload: &chatv2.CreateConversationMessageStreamResponse_StreamError{
 			StreamError: &chatv2.StreamError{
@@ -50,8 +52,8 @@ func (s *ChatServerV2) buildSystemMessage(systemPrompt string) (*chatv2.Message,
 	return inappMessage, openaiMessage
 }
 
-func (s *ChatServerV2) buildUserMessage(ctx context.Context, userMessage, userSelectedText, surrounding string, conversationType chatv2.ConversationType) (*chatv2.Message, openai.ChatCompletionMessageParamUnion, error) {
-	userPrompt, err := s.chatServiceV2.GetPrompt(ctx, userMessage, userSelectedText, surrounding, conversationType)
+func (s *ChatServerV2) buildUserMessage(ctx context.Context, userMessage, userSelectedText, surrounding string, projectVersion string, conversationType chatv2.ConversationType) (*chatv2.Message, openai.ChatCompletionMessageParamUnion, error) {
+	userPrompt, err := s.chatServiceV2.GetPrompt(ctx, userMessage, userSelectedText, surrounding, nil, conversationType)
 	if err != nil {
 		return nil, openai.ChatCompletionMessageParamUnion{}, err
 	}
@@ -75,9 +77,10 @@ func (s *ChatServerV2) buildUserMessage(ctx context.Context, userMessage, userSe
 			Payload: &chatv2.MessagePayload{
 				MessageType: &chatv2.MessagePayload_User{
 					User: &chatv2.MessageTypeUser{
-						Content:      userMessage,
-						SelectedText: &userSelectedText,
-						Surrounding:  &surrounding,
+						Content:        userMessage,
+						SelectedText:   &userSelectedText,
+						Surrounding:    &surrounding,
+						ProjectVersion: projectVersion,
 					},
 				},
 			},
@@ -88,6 +91,22 @@ func (s *ChatServerV2) buildUserMessage(ctx context.Context, userMessage, userSe
 	return inappMessage, openaiMessage, nil
 }
 
+// retrieveUserMessage retrieves the excerpts of the project relevant to the user message, and returns the message
+// sent to the model with them. The excerpts are only sent with the latest user message, they are not written to
+// the conversation.
+func (s *ChatServerV2) retrieveUserMessage(ctx context.Context, userId bson.ObjectID, project *models.Project, userMessage, userSelectedText, surrounding string) (*openai.ChatCompletionMessageParamUnion, error) {
+	retrievedChunks, err := s.retrievalService.Retrieve(ctx, userId, project, strings.TrimSpace(userMessage+"\n"+userSelectedText), services.DefaultRetrievalTopK)
+	if err != nil {
+		return nil, err
+	}
+	userPrompt, err := s.chatServiceV2.GetPrompt(ctx, userMessage, userSelectedText, surrounding, retrievedChunks, chatv2.ConversationType_CONVERSATION_TYPE_UNSPECIFIED)
+	if err != nil {
+		return nil, err
+	}
+	openaiMessage := openai.UserMessage(userPrompt)
+	return &openaiMessage, nil
+}
+
 // convertToBSON converts a protobuf message to BSON
 func convertToBSONV2(msg *chatv2.Message) (bson.M, error) {
 	jsonBytes, err := protojson.Marshal(msg)
@@ -101,13 +120,32 @@ func convertToBSONV2(msg *chatv2.Message) (bson.M, error) {
 	return bsonMsg, nil
 }
 
+// newConversationMessage returns the message of the conversation tree for an in-app message and the messages sent
+// to the model for it.
+func newConversationMessage(msg *chatv2.Message, openaiMessages ...openai.ChatCompletionMessageParamUnion) (models.ConversationMessage, error) {
+	bsonMsg, err := convertToBSONV2(msg)
+	if err != nil {
+		return models.ConversationMessage{}, err
+	}
+	return models.ConversationMessage{
+		ID:             msg.GetMessageId(),
+		Inapp:          bsonMsg,
+		Openai:         openaiMessages,
+		ProjectVersion: msg.GetPayload().GetUser().GetProjectVersion(),
+	}, nil
+}
+
 // createConversation creates a conversation and writes it to the database
 // Returns the Conversation object
 func (s *ChatServerV2) createConversation(
 	ctx context.Context,
+	conversationId bson.ObjectID,
 	userId bson.ObjectID,
 	projectId string,
+	projectVersion string,
 	latexFullSource string,
+	macroGlossary string,
+	retrievalEnabled bool,
 	projectInstructions string,
 	userInstructions string,
 	userMessage string,
@@ -116,34 +154,40 @@ func (s *ChatServerV2) createConversation(
 	modelSlug string,
 	conversationType chatv2.ConversationType,
 ) (*models.Conversation, error) {
-	systemPrompt, err := s.chatServiceV2.GetSystemPromptV2(ctx, latexFullSource, projectInstructions, userInstructions, conversationType)
+	systemPrompt, err := s.chatServiceV2.GetSystemPromptV2(ctx, latexFullSource, macroGlossary, retrievalEnabled, projectInstructions, userInstructions, conversationType)
 	if err != nil {
 		return nil, err
 	}
 
-	_, openaiSystemMsg := s.buildSystemMessage(systemPrompt)
-	inappUserMsg, openaiUserMsg, err := s.buildUserMessage(ctx, userMessage, userSelectedText, surrounding, conversationType)
+	inappUserMsg, openaiUserMsg, err := s.buildUserMessage(ctx, userMessage, userSelectedText, surrounding, projectVersion, conversationType)
 	if err != nil {
 		return nil, err
 	}
-
-	messages := []*chatv2.Message{inappUserMsg}
-	oaiHistory := []openai.ChatCompletionMessageParamUnion{
-		openaiSystemMsg,
-		openaiUserMsg,
+	userMsg, err := newConversationMessage(inappUserMsg, openaiUserMsg)
+	if err != nil {
+		return nil, err
 	}
 
 	return s.chatServiceV2.InsertConversationToDBV2(
-		ctx, userId, projectId, modelSlug, messages, oaiHistory,
+		ctx, conversationId, userId, projectId, modelSlug, systemPrompt, retrievalEnabled, []models.ConversationMessage{userMsg}, projectVersion,
 	)
 }
 
 // appendConversationMessage appends a message to the conversation and writes it to the database
+// If editMessageId is not empty, the message is appended to a new branch replacing that user message
+// If the project changed since the previous turn, the system prompt is rebuilt and the changes are appended before the message
 // Returns the Conversation object
 func (s *ChatServerV2) appendConversationMessage(
 	ctx context.Context,
 	userId bson.ObjectID,
 	conversationId string,
+	editMessageId string,
+	projectVersion string,
+	latexFullSource string,
+	macroGlossary string,
+	retrievalEnabled bool,
+	projectInstructions string,
+	userInstructions string,
 	userMessage string,
 	userSelectedText string,
 	surrounding string,
@@ -159,69 +203,198 @@ func (s *ChatServerV2) appendConversationMessage(
 		return nil, err
 	}
 
-	userMsg, userOaiMsg, err := s.buildUserMessage(ctx, userMessage, userSelectedText, surrounding, conversationType)
+	if editMessageId != "" {
+		if err := s.chatServiceV2.BranchForEditV2(conversation, editMessageId); err != nil {
+			return nil, err
+		}
+	}
+
+	var messages []models.ConversationMessage
+	if projectVersion != "" {
+		systemPrompt, err := s.chatServiceV2.GetSystemPromptV2(ctx, latexFullSource, macroGlossary, retrievalEnabled, projectInstructions, userInstructions, conversationType)
+		if err != nil {
+			return nil, err
+		}
+		var diff string
+		if previousVersion := conversation.BranchProjectVersion(); previousVersion != "" && previousVersion != projectVersion {
+			diff, err = s.projectService.DiffProjectVersions(ctx, userId, conversation.ProjectID, previousVersion, projectVersion)
+			if err != nil {
+				// The model is still told that the project changed
+				s.logger.Error("Failed to diff project versions", "error", err, "conversationID", conversationId)
+				diff = ""
+			}
+		}
+		refreshMsg, refreshOaiMsg := s.chatServiceV2.RefreshProjectV2(conversation, projectVersion, diff, systemPrompt)
+		if refreshMsg != nil {
+			msg, err := newConversationMessage(refreshMsg, *refreshOaiMsg)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, msg)
+		}
+		// The system prompt includes the paper unless the excerpts are retrieved, which may change between turns
+		if conversation.RetrievalEnabled != retrievalEnabled {
+			conversation.SystemPrompt = systemPrompt
+			conversation.RetrievalEnabled = retrievalEnabled
+		}
+	}
+
+	inappUserMsg, openaiUserMsg, err := s.buildUserMessage(ctx, userMessage, userSelectedText, surrounding, projectVersion, conversationType)
 	if err != nil {
 		return nil, err
 	}
-
-	bsonMsg, err := convertToBSONV2(userMsg)
+	userMsg, err := newConversationMessage(inappUserMsg, openaiUserMsg)
 	if err != nil {
 		return nil, err
 	}
-	conversation.InappChatHistory = append(conversation.InappChatHistory, bsonMsg)
-	conversation.OpenaiChatHistoryCompletion = append(conversation.OpenaiChatHistoryCompletion, userOaiMsg)
+	messages = append(messages, userMsg)
 
-	if err := s.chatServiceV2.UpdateConversationV2(conversation); err != nil {
+	if err := s.chatServiceV2.AppendMessagesV2(ctx, conversation, messages...); err != nil {
 		return nil, err
 	}
 
 	return conversation, nil
 }
 
-// prepare creates a new conversation if conversationId is "", otherwise appends a message to the conversation
+// compactConversation condenses the history sent to the model if it does not fit in the model's context window.
+// The compaction is appended after the user message of the turn, with the condensed history, so that it is visible
+// in GetConversation and the response follows it.
+func (s *ChatServerV2) compactConversation(
+	ctx context.Context,
+	conversation *models.Conversation,
+	modelSlug string,
+	llmProvider *models.LLMProviderConfig,
+	customModel *models.CustomModel,
+) error {
+	compacted, compaction, err := s.aiClientV2.CompactChatHistoryV2(
+		ctx,
+		conversation.UserID,
+		conversation.ProjectID,
+		modelSlug,
+		conversation.OpenaiHistory(),
+		contextWindowForModel(modelSlug, customModel),
+		llmProvider,
+		customModel,
+	)
+	if err != nil {
+		return err
+	}
+	if compaction == nil {
+		return nil
+	}
+	if conversation.SystemPrompt != "" {
+		compacted = compacted[1:] // The system prompt is sent before the messages of the branch
+	}
+
+	msg, err := newConversationMessage(&chatv2.Message{
+		MessageId: "pd_msg_compaction_" + uuid.New().String(),
+		Payload: &chatv2.MessagePayload{
+			MessageType: &chatv2.MessagePayload_Compaction{
+				Compaction: &chatv2.MessageTypeCompaction{
+					Summary:            compaction.Summary,
+					SummarizedMessages: int32(compaction.SummarizedMessages),
+					DroppedToolOutputs: int32(compaction.DroppedToolOutputs),
+					TokensBefore:       compaction.TokensBefore,
+					TokensAfter:        compaction.TokensAfter,
+				},
+			},
+		},
+		Timestamp: time.Now().Unix(),
+	}, compacted...)
+	if err != nil {
+		return err
+	}
+	msg.Compacted = true
+
+	s.logger.Info("Compacted conversation history", "conversationID", conversation.ID.Hex(),
+		"tokensBefore", compaction.TokensBefore, "tokensAfter", compaction.TokensAfter,
+		"summarizedMessages", compaction.SummarizedMessages, "droppedToolOutputs", compaction.DroppedToolOutputs)
+
+	// The compaction is written even if the generation is cancelled afterwards
+	return s.chatServiceV2.AppendMessagesV2(context.WithoutCancel(ctx), conversation, msg)
+}
+
+// prepare creates the conversation conversationId if newConversation is true, otherwise appends a message to it
 // conversationType can be switched multiple times within a single conversation
-func (s *ChatServerV2) prepare(ctx context.Context, projectId string, conversationId string, userMessage string, userSelectedText string, surrounding string, modelSlug string, conversationType chatv2.ConversationType) (context.Context, *models.Conversation, *models.Settings, error) {
+// editMessageId is the user message replaced by this one in a new branch, it is only used for existing conversations
+// With full document RAG, it also returns the user message to send to the model with the relevant excerpts
+func (s *ChatServerV2) prepare(ctx context.Context, projectId string, conversationId string, newConversation bool, editMessageId string, userMessage string, userSelectedText string, surrounding string, modelSlug string, conversationType chatv2.ConversationType) (context.Context, *models.Conversation, *models.Settings, *openai.ChatCompletionMessageParamUnion, error) {
 	actor, err := contextutil.GetActor(ctx)
 	if err != nil {
-		return ctx, nil, nil, err
+		return ctx, nil, nil, nil, err
 	}
 
 	project, err := s.projectService.GetProject(ctx, actor.ID, projectId)
 	if err != nil && err != mongo.ErrNoDocuments {
-		return ctx, nil, nil, err
+		return ctx, nil, nil, nil, err
 	}
 
 	userInstructions, err := s.userService.GetUserInstructions(ctx, actor.ID)
 	if err != nil {
-		return ctx, nil, nil, err
+		return ctx, nil, nil, nil, err
+	}
+
+	settings, err := s.userService.GetUserSettings(ctx, actor.ID)
+	if err != nil {
+		return ctx, nil, nil, nil, err
 	}
 
 	var latexFullSource string
+	var macroGlossary string
+	var projectVersion string // Empty in debug mode, the project is not sent to the model
 	var projectInstructions string = ""
+	var retrievalEnabled bool
+	var requestUserMessage *openai.ChatCompletionMessageParamUnion
 	switch conversationType {
 	case chatv2.ConversationType_CONVERSATION_TYPE_DEBUG:
 		latexFullSource = "latex_full_source is not available in debug mode"
 	default:
 		if project == nil || project.IsOutOfDate() {
-			return ctx, nil, nil, shared.ErrProjectOutOfDate("project is out of date")
+			return ctx, nil, nil, nil, shared.ErrProjectOutOfDate("project is out of date")
 		}
 
-		latexFullSource, err = project.GetFullContent()
+		// The model proposes edits with propose_edit, whose anchors and lines must match the source of the docs, so
+		// the paper is never shown with its macros expanded: the macros can only be listed in a glossary.
+		macroExpansion := models.MacroExpansionNone
+		if settings.ChatMacroGlossary {
+			macroExpansion = models.MacroExpansionGlossary
+		}
+		latexFullSource, macroGlossary, err = project.GetFullContentWithMacros(macroExpansion)
 		if err != nil {
-			return ctx, nil, nil, err
+			return ctx, nil, nil, nil, err
 		}
 
+		projectVersion = project.ContentVersion()
 		projectInstructions = project.Instructions
+
+		// With full document RAG, only the chunks relevant to this turn are sent to the model.
+		// If they cannot be retrieved, the full paper is sent instead.
+		if settings.FullDocumentRag {
+			requestUserMessage, err = s.retrieveUserMessage(ctx, actor.ID, project, userMessage, userSelectedText, surrounding)
+			if err != nil {
+				s.logger.Error("Failed to retrieve project chunks, sending the full paper", "error", err, "projectID", projectId)
+			}
+			retrievalEnabled = err == nil
+		}
 	}
 
 	var conversation *models.Conversation
 
-	if conversationId == "" {
+	if newConversation {
+		var objectID bson.ObjectID
+		objectID, err = bson.ObjectIDFromHex(conversationId)
+		if err != nil {
+			return ctx, nil, nil, nil, err
+		}
 		conversation, err = s.createConversation(
 			ctx,
+			objectID,
 			actor.ID,
 			projectId,
+			projectVersion,
 			latexFullSource,
+			macroGlossary,
+			retrievalEnabled,
 			projectInstructions,
 			userInstructions,
 			userMessage,
@@ -235,6 +408,13 @@ func (s *ChatServerV2) prepare(ctx context.Context, projectId string, conversati
 			ctx,
 			actor.ID,
 			conversationId,
+			editMessageId,
+			projectVersion,
+			latexFullSource,
+			macroGlossary,
+			retrievalEnabled,
+			projectInstructions,
+			userInstructions,
 			userMessage,
 			userSelectedText,
 			surrounding,
@@ -243,18 +423,13 @@ func (s *ChatServerV2) prepare(ctx context.Context, projectId string, conversati
 	}
 
 	if err != nil {
-		return ctx, nil, nil, err
+		return ctx, nil, nil, nil, err
 	}
 
 	ctx = contextutil.SetProjectID(ctx, conversation.ProjectID)
 	ctx = contextutil.SetConversationID(ctx, conversation.ID.Hex())
 
-	settings, err := s.userService.GetUserSettings(ctx, actor.ID)
-	if err != nil {
-		return ctx, conversation, nil, err
-	}
-
-	return ctx, conversation, settings, nil
+	return ctx, conversation, settings, requestUserMessage, nil
 }
 
 func (s *ChatServerV2) CreateConversationMessageStream(
@@ -263,11 +438,24 @@ func (s *ChatServerV2) CreateConversationMessageStream(
 ) error {
 	ctx := stream.Context()
 
+	conversationId := req.GetConversationId()
+	newConversation := conversationId == ""
+	if newConversation {
+		conversationId = bson.NewObjectID().Hex()
+	}
+
+	gen, err := s.startGeneration(ctx, conversationId)
+	if err != nil {
+		return s.sendStreamError(stream, err)
+	}
+
 	modelSlug := req.GetModelSlug()
-	ctx, conversation, settings, err := s.prepare(
+	ctx, conversation, settings, requestUserMessage, err := s.prepare(
 		ctx,
 		req.GetProjectId(),
-		req.GetConversationId(),
+		conversationId,
+		newConversation,
+		"",
 		req.GetUserMessage(),
 		req.GetUserSelectedText(),
 		req.GetSurrounding(),
@@ -275,22 +463,88 @@ func (s *ChatServerV2) CreateConversationMessageStream(
 		req.GetConversationType(),
 	)
 	if err != nil {
+		s.releaseGeneration(gen)
 		return s.sendStreamError(stream, err)
 	}
 
+	return s.generate(ctx, stream, gen, conversation, requestUserMessage, settings, modelSlug, req.GetCustomModelId())
+}
+
+// generation is a response being generated for a conversation. It is started before the conversation is read or
+// written, so that concurrent requests for the same conversation are rejected instead of writing over each other.
+type generation struct {
+	conversationId string
+	ctx            context.Context // Not cancelled when the client disconnects
+	cancel         context.CancelFunc
+	buffer         *handler.StreamBufferV2
+}
+
+// startGeneration reserves the conversation for a new response, it fails if one is already being generated.
+// The generation must be run with generate, or released with releaseGeneration.
+func (s *ChatServerV2) startGeneration(ctx context.Context, conversationId string) (*generation, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	generationCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	buffer, err := s.streams.Start(conversationId, actor.ID, cancel)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &generation{conversationId: conversationId, ctx: generationCtx, cancel: cancel, buffer: buffer}, nil
+}
+
+// releaseGeneration gives the conversation back when the response is not generated.
+func (s *ChatServerV2) releaseGeneration(gen *generation) {
+	gen.cancel()
+	s.streams.Release(gen.conversationId, gen.buffer)
+}
+
+// generate runs streamConversation in the background, so that the generation is not interrupted when the
+// client disconnects, and forwards its events to the client. Clients can reconnect with ResumeConversationStream,
+// and stop the generation with CancelConversationMessage.
+// If requestUserMessage is not nil, it replaces the last user message in the request, see retrieveUserMessage.
+func (s *ChatServerV2) generate(
+	ctx context.Context,
+	stream handler.StreamSenderV2,
+	gen *generation,
+	conversation *models.Conversation,
+	requestUserMessage *openai.ChatCompletionMessageParamUnion,
+	settings *models.Settings,
+	modelSlug string,
+	customModelID string,
+) error {
+	generationCtx := contextutil.SetProjectID(gen.ctx, conversation.ProjectID)
+	generationCtx = contextutil.SetConversationID(generationCtx, conversation.ID.Hex())
+
+	go func() {
+		defer gen.cancel()
+		defer s.streams.Finish(gen.conversationId, gen.buffer)
+		s.streamConversation(generationCtx, gen.buffer, conversation, requestUserMessage, settings, modelSlug, customModelID)
+	}()
+
+	return gen.buffer.Follow(ctx, stream, 0)
+}
+
+// streamConversation streams the response to the last user message of the conversation, then writes it to the database
+func (s *ChatServerV2) streamConversation(
+	ctx context.Context,
+	stream handler.StreamSenderV2,
+	conversation *models.Conversation,
+	requestUserMessage *openai.ChatCompletionMessageParamUnion,
+	settings *models.Settings,
+	modelSlug string,
+	customModelID string,
+) error {
 	// Check if user has an API key for requested model
 	var llmProvider *models.LLMProviderConfig
 	var customModel *models.CustomModel
 	customModel = nil
 
-	customModelID := req.GetCustomModelId()
 	if customModelID != "" {
-		for i := range settings.CustomModels {
-			if settings.CustomModels[i].Id.Hex() == customModelID {
-				customModel = &settings.CustomModels[i]
-				break
-			}
-		}
+		customModel = settings.FindCustomModel(customModelID)
 		if customModel == nil {
 			return s.sendStreamError(stream, fmt.Errorf("custom model not found: %q", customModelID))
 		}
@@ -304,47 +558,46 @@ func (s *ChatServerV2) CreateConversationMessageStream(
 			IsCustomModel: false,
 		}
 	} else {
-		customModel.BaseUrl = strings.ToLower(customModel.BaseUrl)
-
-		if strings.Contains(customModel.BaseUrl, "paperdebugger.com") {
-			customModel.BaseUrl = ""
-		}
-		if !strings.HasPrefix(customModel.BaseUrl, "https://") {
-			customModel.BaseUrl = strings.Replace(customModel.BaseUrl, "http://", "", 1)
-			customModel.BaseUrl = "https://" + customModel.BaseUrl
-		}
+		llmProvider = models.NewCustomModelLLMProvider(conversation.UserID, customModel)
+	}
 
-		llmProvider = &models.LLMProviderConfig{
-			APIKey:        customModel.APIKey,
-			Endpoint:      customModel.BaseUrl,
-			IsCustomModel: true,
-		}
+	if err := s.compactConversation(ctx, conversation, modelSlug, llmProvider, customModel); err != nil {
+		return s.sendStreamError(stream, err)
 	}
 
-	openaiChatHistory, inappChatHistory, _, err := s.aiClientV2.ChatCompletionStreamV2(ctx, stream, conversation.UserID, conversation.ProjectID, conversation.ID.Hex(), modelSlug, conversation.OpenaiChatHistoryCompletion, llmProvider, customModel)
+	requestHistory := conversation.OpenaiHistory()
+	if last := len(requestHistory) - 1; requestUserMessage != nil && last >= 0 && requestHistory[last].OfUser != nil {
+		requestHistory[last] = *requestUserMessage
+	}
+	openaiChatHistory, inappChatHistory, _, err := s.aiClientV2.ChatCompletionStreamV2(ctx, stream, conversation.UserID, conversation.ProjectID, conversation.ID.Hex(), modelSlug, requestHistory, llmProvider, customModel)
 	if err != nil {
 		return s.sendStreamError(stream, err)
 	}
 
-	// Append messages to the conversation
-	bsonMessages := make([]bson.M, len(inappChatHistory))
+	// Append the response to the conversation, the messages sent to the model are attached to its last message
+	messages := make([]models.ConversationMessage, len(inappChatHistory))
 	for i := range inappChatHistory {
-		bsonMsg, err := convertToBSONV2(&inappChatHistory[i])
+		msg, err := newConversationMessage(&inappChatHistory[i])
 		if err != nil {
 			return s.sendStreamError(stream, err)
 		}
-		bsonMessages[i] = bsonMsg
+		messages[i] = msg
+	}
+	if len(messages) > 0 {
+		messages[len(messages)-1].Openai = openaiChatHistory[len(requestHistory):]
 	}
-	conversation.InappChatHistory = append(conversation.InappChatHistory, bsonMessages...)
-	conversation.OpenaiChatHistoryCompletion = openaiChatHistory
-	if err := s.chatServiceV2.UpdateConversationV2(conversation); err != nil {
+	// ctx is cancelled by CancelConversationMessage, the partial response must still be written
+	if err := s.chatServiceV2.AppendMessagesV2(context.WithoutCancel(ctx), conversation, messages...); err != nil {
 		return s.sendStreamError(stream, err)
 	}
 
 	if conversation.Title == services.DefaultConversationTitle {
+		// The title is generated after the response, which may be after ctx is cancelled
+		ctx := context.WithoutCancel(ctx)
 		go func() {
-			protoMessages := make([]*chatv2.Message, len(conversation.InappChatHistory))
-			for i, bsonMsg := range conversation.InappChatHistory {
+			history := conversation.InappHistory()
+			protoMessages := make([]*chatv2.Message, len(history))
+			for i, bsonMsg := range history {
 				protoMessages[i] = mapper.BSONToChatMessageV2(bsonMsg)
 			}
 			title, err := s.aiClientV2.GetConversationTitleV2(ctx, conversation.UserID, conversation.ProjectID, protoMessages, llmProvider, modelSlug, customModel)
@@ -352,8 +605,7 @@ func (s *ChatServerV2) CreateConversationMessageStream(
 				s.logger.Error("Failed to get conversation title", "error", err, "conversationID", conversation.ID.Hex())
 				return
 			}
-			conversation.Title = title
-			if err := s.chatServiceV2.UpdateConversationV2(conversation); err != nil {
+			if err := s.chatServiceV2.UpdateConversationTitleV2(ctx, conversation.ID, title); err != nil {
 				s.logger.Error("Failed to update conversation with new title", "error", err, "conversationID", conversation.ID.Hex())
 				return
 			}
@@ -0,0 +1,58 @@
+package chat
+
+import (
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// EditConversationMessageStream replaces a user message in a new branch of the conversation,
+// and streams the response to the edited message. The previous branch is kept as an alternative.
+func (s *ChatServerV2) EditConversationMessageStream(
+	req *chatv2.EditConversationMessageStreamRequest,
+	stream chatv2.ChatService_EditConversationMessageStreamServer,
+) error {
+	ctx := stream.Context()
+
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return s.sendStreamError(stream, err)
+	}
+
+	conversationID, err := bson.ObjectIDFromHex(req.GetConversationId())
+	if err != nil {
+		return s.sendStreamError(stream, shared.ErrBadRequest("invalid conversation_id"))
+	}
+
+	gen, err := s.startGeneration(ctx, req.GetConversationId())
+	if err != nil {
+		return s.sendStreamError(stream, err)
+	}
+
+	conversation, err := s.chatServiceV2.GetConversationV2(ctx, actor.ID, conversationID)
+	if err != nil {
+		s.releaseGeneration(gen)
+		return s.sendStreamError(stream, err)
+	}
+
+	ctx, conversation, settings, requestUserMessage, err := s.prepare(
+		ctx,
+		conversation.ProjectID,
+		req.GetConversationId(),
+		false,
+		req.GetMessageId(),
+		req.GetUserMessage(),
+		req.GetUserSelectedText(),
+		req.GetSurrounding(),
+		req.GetModelSlug(),
+		req.GetConversationType(),
+	)
+	if err != nil {
+		s.releaseGeneration(gen)
+		return s.sendStreamError(stream, err)
+	}
+
+	return s.generate(ctx, stream, gen, conversation, requestUserMessage, settings, req.GetModelSlug(), req.GetCustomModelId())
+}
@@ -31,7 +31,8 @@ func (s *ChatServerV2) GetCitationKeys(
 	}
 
 	llmProvider := &models.LLMProviderConfig{
-		APIKey: settings.OpenAIAPIKey,
+		APIKey:    settings.OpenAIAPIKey,
+		APIKeyAAD: models.APIKeyAAD(actor.ID, nil),
 	}
 
 	citationKeys, err := s.aiClientV2.GetCitationKeys(
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/models"
 	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
 
 	"github.com/openai/openai-go/v3"
@@ -205,6 +206,25 @@ var allModels = []modelConfig{
 	},
 }
 
+// defaultContextWindow is assumed for models that are not in allModels and do not declare one.
+const defaultContextWindow = 128000
+
+// contextWindowForModel returns the total context of the model used for the request.
+func contextWindowForModel(modelSlug string, customModel *models.CustomModel) int64 {
+	if customModel != nil {
+		if customModel.ContextWindow > 0 {
+			return int64(customModel.ContextWindow)
+		}
+		return defaultContextWindow
+	}
+	for _, config := range allModels {
+		if config.slugOpenRouter == modelSlug || (config.slugOpenAI != "" && config.slugOpenAI == modelSlug) {
+			return config.totalContext
+		}
+	}
+	return defaultContextWindow
+}
+
 func (s *ChatServerV2) ListSupportedModels(
 	ctx context.Context,
 	req *chatv2.ListSupportedModelsRequest,
@@ -0,0 +1,127 @@
+package chat
+
+import (
+	"context"
+
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type proposedEditRequest interface {
+	GetConversationId() string
+	GetEditId() string
+	GetProjectId() string
+	GetMessageId() string
+}
+
+func validateProposedEditRequest(req proposedEditRequest) error {
+	if req.GetProjectId() == "" {
+		return shared.ErrBadRequest("project_id is required")
+	}
+	if req.GetConversationId() == "" {
+		return shared.ErrBadRequest("conversation_id is required")
+	}
+	if req.GetMessageId() == "" {
+		return shared.ErrBadRequest("message_id is required")
+	}
+	if req.GetEditId() == "" {
+		return shared.ErrBadRequest("edit_id is required")
+	}
+	return nil
+}
+
+func (s *ChatServerV2) AcceptProposedEdit(
+	ctx context.Context,
+	req *chatv2.AcceptProposedEditRequest,
+) (*chatv2.AcceptProposedEditResponse, error) {
+	edit, err := s.resolveProposedEdit(ctx, req, models.ProposedEditAccepted)
+	if err != nil {
+		return nil, err
+	}
+	return &chatv2.AcceptProposedEditResponse{ProposedEdit: edit}, nil
+}
+
+func (s *ChatServerV2) RejectProposedEdit(
+	ctx context.Context,
+	req *chatv2.RejectProposedEditRequest,
+) (*chatv2.RejectProposedEditResponse, error) {
+	edit, err := s.resolveProposedEdit(ctx, req, models.ProposedEditRejected)
+	if err != nil {
+		return nil, err
+	}
+	return &chatv2.RejectProposedEditResponse{ProposedEdit: edit}, nil
+}
+
+// resolveProposedEdit records that the user accepted or rejected the edit, both in the edit and in its message.
+// An edit can only be accepted if the replaced lines did not change since it was proposed.
+func (s *ChatServerV2) resolveProposedEdit(ctx context.Context, req proposedEditRequest, status models.ProposedEditStatus) (*chatv2.MessageTypeProposedEdit, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateProposedEditRequest(req); err != nil {
+		return nil, err
+	}
+
+	conversationID, err := bson.ObjectIDFromHex(req.GetConversationId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("invalid conversation_id")
+	}
+	editID, err := bson.ObjectIDFromHex(req.GetEditId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("invalid edit_id")
+	}
+
+	project, err := s.projectService.GetProject(ctx, actor.ID, req.GetProjectId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("failed to get project")
+	}
+
+	conversation, err := s.chatServiceV2.GetConversationV2(ctx, actor.ID, conversationID)
+	if err != nil {
+		return nil, shared.ErrBadRequest("failed to get conversation")
+	}
+
+	edit, err := s.proposedEditService.GetProposedEdit(ctx, actor.ID, req.GetProjectId(), editID)
+	if err != nil {
+		return nil, err
+	}
+	if edit.ConversationID != req.GetConversationId() {
+		return nil, shared.ErrBadRequest("the edit was not proposed in this conversation")
+	}
+
+	if status == models.ProposedEditAccepted {
+		var doc *models.ProjectDoc
+		for i := range project.Docs {
+			if project.Docs[i].ID == edit.DocID {
+				doc = &project.Docs[i]
+				break
+			}
+		}
+		if doc == nil || !edit.AppliesTo(doc) {
+			return nil, shared.ErrDocVersionConflict("the doc changed since the edit was proposed")
+		}
+	}
+
+	// The message is checked before the edit is resolved, so that an invalid request changes nothing
+	if err := s.chatServiceV2.CheckProposedEditV2(conversation, req.GetMessageId(), req.GetEditId()); err != nil {
+		return nil, err
+	}
+
+	edit, err = s.proposedEditService.ResolveProposedEdit(ctx, actor.ID, req.GetProjectId(), editID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	message := services.ToProposedEditMessage(edit)
+	if err := s.chatServiceV2.SetProposedEditV2(ctx, conversation, req.GetMessageId(), message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
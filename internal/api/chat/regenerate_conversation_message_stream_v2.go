@@ -0,0 +1,71 @@
+package chat
+
+import (
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"github.com/openai/openai-go/v3"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// RegenerateConversationMessageStream regenerates the response to a user turn in a new branch of the
+// conversation. The previous response is kept as an alternative.
+func (s *ChatServerV2) RegenerateConversationMessageStream(
+	req *chatv2.RegenerateConversationMessageStreamRequest,
+	stream chatv2.ChatService_RegenerateConversationMessageStreamServer,
+) error {
+	ctx := stream.Context()
+
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return s.sendStreamError(stream, err)
+	}
+
+	conversationID, err := bson.ObjectIDFromHex(req.GetConversationId())
+	if err != nil {
+		return s.sendStreamError(stream, shared.ErrBadRequest("invalid conversation_id"))
+	}
+
+	gen, err := s.startGeneration(ctx, req.GetConversationId())
+	if err != nil {
+		return s.sendStreamError(stream, err)
+	}
+
+	conversation, err := s.chatServiceV2.GetConversationV2(ctx, actor.ID, conversationID)
+	if err != nil {
+		s.releaseGeneration(gen)
+		return s.sendStreamError(stream, err)
+	}
+
+	// The branch is written with the response
+	if err := s.chatServiceV2.BranchForRegenerateV2(conversation, req.GetMessageId()); err != nil {
+		s.releaseGeneration(gen)
+		return s.sendStreamError(stream, err)
+	}
+
+	settings, err := s.userService.GetUserSettings(ctx, actor.ID)
+	if err != nil {
+		s.releaseGeneration(gen)
+		return s.sendStreamError(stream, err)
+	}
+
+	// The excerpts of the paper are retrieved again, they are not written to the conversation
+	var requestUserMessage *openai.ChatCompletionMessageParamUnion
+	if conversation.RetrievalEnabled {
+		project, err := s.projectService.GetProject(ctx, actor.ID, conversation.ProjectID)
+		if err != nil {
+			s.releaseGeneration(gen)
+			return s.sendStreamError(stream, err)
+		}
+		user := models.InappMessageV2(conversation.Message(conversation.LeafID).Inapp).GetPayload().GetUser()
+		requestUserMessage, err = s.retrieveUserMessage(ctx, actor.ID, project, user.GetContent(), user.GetSelectedText(), user.GetSurrounding())
+		if err != nil {
+			s.releaseGeneration(gen)
+			return s.sendStreamError(stream, err)
+		}
+	}
+
+	return s.generate(ctx, stream, gen, conversation, requestUserMessage, settings, req.GetModelSlug(), req.GetCustomModelId())
+}
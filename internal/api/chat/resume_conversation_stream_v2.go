@@ -0,0 +1,29 @@
+package chat
+
+import (
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+)
+
+// ResumeConversationStream reconnects to the response being generated for a conversation. The events from
+// from_sequence are replayed, then the stream follows the generation until it is finished.
+// The events of a finished generation can still be replayed for a short while.
+func (s *ChatServerV2) ResumeConversationStream(
+	req *chatv2.ResumeConversationStreamRequest,
+	stream chatv2.ChatService_ResumeConversationStreamServer,
+) error {
+	ctx := stream.Context()
+
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return s.sendStreamError(stream, err)
+	}
+
+	buffer := s.streams.Get(req.GetConversationId())
+	if buffer == nil || buffer.UserID != actor.ID {
+		return s.sendStreamError(stream, shared.ErrRecordNotFound("no response is being generated for this conversation"))
+	}
+
+	return buffer.Follow(ctx, stream, req.GetFromSequence())
+}
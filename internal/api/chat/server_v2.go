@@ -5,17 +5,21 @@ import (
 	"paperdebugger/internal/libs/logger"
 	"paperdebugger/internal/services"
 	aiclient "paperdebugger/internal/services/toolkit/client"
+	"paperdebugger/internal/services/toolkit/handler"
 	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
 )
 
 type ChatServerV2 struct {
 	chatv2.UnimplementedChatServiceServer
-	aiClientV2     *aiclient.AIClientV2
-	chatServiceV2  *services.ChatServiceV2
-	projectService *services.ProjectService
-	userService    *services.UserService
-	logger         *logger.Logger
-	cfg            *cfg.Cfg
+	aiClientV2          *aiclient.AIClientV2
+	chatServiceV2       *services.ChatServiceV2
+	projectService      *services.ProjectService
+	userService         *services.UserService
+	retrievalService    *services.RetrievalService
+	proposedEditService *services.ProposedEditService
+	streams             *handler.StreamRegistryV2
+	logger              *logger.Logger
+	cfg                 *cfg.Cfg
 }
 
 func NewChatServerV2(
@@ -23,15 +27,20 @@ func NewChatServerV2(
 	chatServiceV2 *services.ChatServiceV2,
 	projectService *services.ProjectService,
 	userService *services.UserService,
+	retrievalService *services.RetrievalService,
+	proposedEditService *services.ProposedEditService,
 	logger *logger.Logger,
 	cfg *cfg.Cfg,
 ) chatv2.ChatServiceServer {
 	return &ChatServerV2{
-		aiClientV2:     aiClientV2,
-		projectService: projectService,
-		userService:    userService,
-		logger:         logger,
-		chatServiceV2:  chatServiceV2,
-		cfg:            cfg,
+		aiClientV2:          aiClientV2,
+		projectService:      projectService,
+		userService:         userService,
+		retrievalService:    retrievalService,
+		proposedEditService: proposedEditService,
+		streams:             handler.NewStreamRegistryV2(),
+		logger:              logger,
+		chatServiceV2:       chatServiceV2,
+		cfg:                 cfg,
 	}
 }
@@ -0,0 +1,51 @@
+package chat
+
+import (
+	"context"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/services/toolkit/handler"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (s *ChatServerV2) SwitchConversationBranch(
+	ctx context.Context,
+	req *chatv2.SwitchConversationBranchRequest,
+) (*chatv2.SwitchConversationBranchResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conversationID, err := bson.ObjectIDFromHex(req.GetConversationId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("invalid conversation_id")
+	}
+
+	// The response being generated is appended to the active branch
+	if s.streams.IsGenerating(req.GetConversationId()) {
+		return nil, shared.ErrBadRequest(handler.ErrGenerationInProgress)
+	}
+
+	conversation, err := s.chatServiceV2.GetConversationV2(ctx, actor.ID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !conversation.SwitchBranch(req.GetMessageId()) {
+		return nil, shared.ErrRecordNotFound("message not found in any branch")
+	}
+
+	err = s.chatServiceV2.SetActiveBranchV2(ctx, conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chatv2.SwitchConversationBranchResponse{
+		Conversation: mapper.MapModelConversationToProtoV2(conversation),
+	}, nil
+}
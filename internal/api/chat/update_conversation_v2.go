@@ -34,11 +34,12 @@ func (s *ChatServerV2) UpdateConversation(
 		return nil, shared.ErrBadRequest("title is required")
 	}
 
-	conversation.Title = req.GetTitle()
-	err = s.chatServiceV2.UpdateConversationV2(conversation)
+	// Only the title is written, a response may be being appended to the conversation
+	err = s.chatServiceV2.UpdateConversationTitleV2(ctx, conversation.ID, req.GetTitle())
 	if err != nil {
 		return nil, err
 	}
+	conversation.Title = req.GetTitle()
 
 	return &chatv2.UpdateConversationResponse{
 		Conversation: mapper.MapModelConversationToProtoV2(conversation),
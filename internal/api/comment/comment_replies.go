@@ -0,0 +1,131 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	aiclient "paperdebugger/internal/services/toolkit/client"
+	commentv1 "paperdebugger/pkg/gen/api/comment/v1"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const quoteContextRadius = 10 // Lines around the quote given to the model
+
+func (s *CommentServer) AddCommentReply(
+	ctx context.Context,
+	req *commentv1.AddCommentReplyRequest,
+) (*commentv1.AddCommentReplyResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	commentID, err := bson.ObjectIDFromHex(req.GetCommentId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("invalid comment_id")
+	}
+	if strings.TrimSpace(req.GetContent()) == "" {
+		return nil, shared.ErrBadRequest("content is required")
+	}
+
+	comment, err := s.reverseCommentService.AddCommentReplies(ctx, actor.ID, req.GetProjectId(), commentID, models.CommentReply{
+		Role:    models.CommentReplyRoleUser,
+		Content: req.GetContent(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.AddCommentReplyResponse{Comment: mapper.MapModelCommentToProto(comment)}, nil
+}
+
+// AskAboutComment answers a question about a comment with the quoted passage as context, and adds the question and
+// the answer to the discussion of the comment.
+func (s *CommentServer) AskAboutComment(
+	ctx context.Context,
+	req *commentv1.AskAboutCommentRequest,
+) (*commentv1.AskAboutCommentResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	commentID, err := bson.ObjectIDFromHex(req.GetCommentId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("invalid comment_id")
+	}
+	if strings.TrimSpace(req.GetQuestion()) == "" {
+		return nil, shared.ErrBadRequest("question is required")
+	}
+
+	comment, err := s.reverseCommentService.GetComment(ctx, actor.ID, req.GetProjectId(), commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectService.GetProject(ctx, actor.ID, req.GetProjectId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("failed to get project")
+	}
+	quoteContext := comment.QuoteText
+	for i := range project.Docs {
+		if project.Docs[i].ID == comment.DocID && !comment.Orphaned {
+			quoteContext = comment.QuoteContext(&project.Docs[i], quoteContextRadius)
+			break
+		}
+	}
+
+	settings, err := s.userService.GetUserSettings(ctx, actor.ID)
+	if err != nil {
+		return nil, err
+	}
+	llmProvider := &models.LLMProviderConfig{
+		APIKey:    settings.OpenAIAPIKey,
+		APIKeyAAD: models.APIKeyAAD(actor.ID, nil),
+	}
+
+	modelSlug := req.GetModelSlug()
+	if modelSlug == "" {
+		modelSlug = aiclient.DefaultModelSlugV2
+	}
+	var customModel *models.CustomModel
+	if req.GetCustomModelId() != "" {
+		customModel = settings.FindCustomModel(req.GetCustomModelId())
+		if customModel == nil {
+			return nil, shared.ErrBadRequest(fmt.Sprintf("custom model not found: %q", req.GetCustomModelId()))
+		}
+		modelSlug = customModel.Slug
+		llmProvider = models.NewCustomModelLLMProvider(actor.ID, customModel)
+	}
+
+	// The tools of the model can read the rest of the project, but not edit it
+	ctx = contextutil.SetProjectID(ctx, req.GetProjectId())
+	answer, _, err := s.aiClientV2.AskAboutCommentV2(ctx, actor.ID, req.GetProjectId(), comment, quoteContext, req.GetQuestion(), modelSlug, llmProvider, customModel)
+	if err != nil {
+		s.logger.Error("Failed to answer comment question", "error", err, "commentID", req.GetCommentId())
+		return nil, err
+	}
+
+	comment, err = s.reverseCommentService.AddCommentReplies(ctx, actor.ID, req.GetProjectId(), commentID,
+		models.CommentReply{Role: models.CommentReplyRoleUser, Content: req.GetQuestion()},
+		models.CommentReply{Role: models.CommentReplyRoleAssistant, Content: answer},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.AskAboutCommentResponse{
+		Comment: mapper.MapModelCommentToProto(comment),
+		Answer:  mapper.MapModelCommentReplyToProto(&comment.Replies[len(comment.Replies)-1]),
+	}, nil
+}
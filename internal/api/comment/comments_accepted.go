@@ -55,16 +55,7 @@ func (s *CommentServer) CommentsAccepted(
 		return nil, shared.ErrBadRequest("failed to get conversation")
 	}
 
-	messageID := req.GetMessageId()
-	messageExists := false
-	for _, message := range conversation.InappChatHistory {
-		msgId, ok := message["messageId"].(string)
-		if ok && msgId == messageID {
-			messageExists = true
-			break
-		}
-	}
-	if !messageExists {
+	if !conversation.HasMessage(req.GetMessageId()) {
 		return nil, shared.ErrBadRequest("message_id not found in conversation")
 	}
 
@@ -0,0 +1,74 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	commentv1 "paperdebugger/pkg/gen/api/comment/v1"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (s *CommentServer) RejectComments(
+	ctx context.Context,
+	req *commentv1.RejectCommentsRequest,
+) (*commentv1.RejectCommentsResponse, error) {
+	comments, err := s.setCommentsStatus(ctx, req.GetProjectId(), req.GetCommentIds(), models.CommentStatusRejected)
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.RejectCommentsResponse{Comments: comments}, nil
+}
+
+func (s *CommentServer) ResolveComments(
+	ctx context.Context,
+	req *commentv1.ResolveCommentsRequest,
+) (*commentv1.ResolveCommentsResponse, error) {
+	comments, err := s.setCommentsStatus(ctx, req.GetProjectId(), req.GetCommentIds(), models.CommentStatusResolved)
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.ResolveCommentsResponse{Comments: comments}, nil
+}
+
+func (s *CommentServer) setCommentsStatus(ctx context.Context, projectID string, commentIDs []string, status models.CommentStatus) ([]*commentv1.Comment, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if projectID == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	if len(commentIDs) == 0 {
+		return nil, shared.ErrBadRequest("comment_ids is required")
+	}
+
+	seen := map[bson.ObjectID]bool{}
+	var objectIDs []bson.ObjectID
+	for _, commentID := range commentIDs {
+		objectID, err := bson.ObjectIDFromHex(commentID)
+		if err != nil {
+			return nil, shared.ErrBadRequest(fmt.Sprintf("invalid comment_id %s", commentID))
+		}
+		if !seen[objectID] {
+			seen[objectID] = true
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	comments, err := s.reverseCommentService.SetCommentsStatus(ctx, actor.ID, projectID, objectIDs, status)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*commentv1.Comment, 0, len(comments))
+	for i := range comments {
+		result = append(result, mapper.MapModelCommentToProto(&comments[i]))
+	}
+	return result, nil
+}
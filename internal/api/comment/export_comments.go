@@ -0,0 +1,77 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	commentv1 "paperdebugger/pkg/gen/api/comment/v1"
+)
+
+func (s *CommentServer) ExportComments(
+	ctx context.Context,
+	req *commentv1.ExportCommentsRequest,
+) (*commentv1.ExportCommentsResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	if req.GetFormat() == commentv1.CommentExportFormat_COMMENT_EXPORT_FORMAT_UNSPECIFIED {
+		return nil, shared.ErrBadRequest("format is required")
+	}
+
+	filter := services.CommentFilter{Statuses: []models.CommentStatus{models.CommentStatusNoAction, models.CommentStatusAccepted}}
+	if len(req.GetStatuses()) > 0 {
+		filter.Statuses = nil
+		for _, status := range req.GetStatuses() {
+			modelStatus, ok := mapper.MapProtoCommentStatusToModel(status)
+			if !ok {
+				return nil, shared.ErrBadRequest(fmt.Sprintf("invalid status %s", status))
+			}
+			filter.Statuses = append(filter.Statuses, modelStatus)
+		}
+	}
+
+	project, err := s.projectService.GetProject(ctx, actor.ID, req.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+	comments, _, err := s.reverseCommentService.ListComments(ctx, actor.ID, req.GetProjectId(), filter, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	report := models.NewCommentReport(project, comments)
+
+	response := &commentv1.ExportCommentsResponse{}
+	switch req.GetFormat() {
+	case commentv1.CommentExportFormat_COMMENT_EXPORT_FORMAT_MARKDOWN:
+		response.Files = []*commentv1.ExportedFile{{Path: "review.md", Content: report.Markdown()}}
+	case commentv1.CommentExportFormat_COMMENT_EXPORT_FORMAT_JSON:
+		content, err := report.JSON()
+		if err != nil {
+			return nil, shared.ErrInternal(err)
+		}
+		response.Files = []*commentv1.ExportedFile{{Path: "review.json", Content: string(content)}}
+	case commentv1.CommentExportFormat_COMMENT_EXPORT_FORMAT_LATEX:
+		bundle, skipped := report.LaTeXBundle()
+		for path, content := range bundle {
+			response.Files = append(response.Files, &commentv1.ExportedFile{Path: path, Content: content})
+		}
+		sort.Slice(response.Files, func(i, j int) bool { return response.Files[i].Path < response.Files[j].Path })
+		for _, id := range skipped {
+			response.SkippedCommentIds = append(response.SkippedCommentIds, id.Hex())
+		}
+	default:
+		return nil, shared.ErrBadRequest(fmt.Sprintf("invalid format %s", req.GetFormat()))
+	}
+	return response, nil
+}
@@ -0,0 +1,36 @@
+package comment
+
+import (
+	"context"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	commentv1 "paperdebugger/pkg/gen/api/comment/v1"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func (s *CommentServer) GetComment(
+	ctx context.Context,
+	req *commentv1.GetCommentRequest,
+) (*commentv1.GetCommentResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	commentID, err := bson.ObjectIDFromHex(req.GetCommentId())
+	if err != nil {
+		return nil, shared.ErrBadRequest("invalid comment_id")
+	}
+
+	comment, err := s.reverseCommentService.GetComment(ctx, actor.ID, req.GetProjectId(), commentID)
+	if err != nil {
+		return nil, err
+	}
+	return &commentv1.GetCommentResponse{Comment: mapper.MapModelCommentToProto(comment)}, nil
+}
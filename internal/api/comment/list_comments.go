@@ -0,0 +1,68 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	commentv1 "paperdebugger/pkg/gen/api/comment/v1"
+)
+
+const (
+	defaultCommentsLimit = 50
+	maxCommentsLimit     = 200
+)
+
+func (s *CommentServer) ListComments(
+	ctx context.Context,
+	req *commentv1.ListCommentsRequest,
+) (*commentv1.ListCommentsResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	if req.GetOffset() < 0 {
+		return nil, shared.ErrBadRequest("offset must not be negative")
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultCommentsLimit
+	}
+	limit = min(limit, maxCommentsLimit)
+
+	filter := services.CommentFilter{
+		DocID:    req.GetDocId(),
+		Section:  req.GetSection(),
+		Orphaned: req.Orphaned,
+	}
+	for _, importance := range req.GetImportance() {
+		filter.Importance = append(filter.Importance, models.ImportanceLevel(importance))
+	}
+	for _, status := range req.GetStatuses() {
+		modelStatus, ok := mapper.MapProtoCommentStatusToModel(status)
+		if !ok {
+			return nil, shared.ErrBadRequest(fmt.Sprintf("invalid status %s", status))
+		}
+		filter.Statuses = append(filter.Statuses, modelStatus)
+	}
+
+	comments, total, err := s.reverseCommentService.ListComments(ctx, actor.ID, req.GetProjectId(), filter, limit, int(req.GetOffset()))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &commentv1.ListCommentsResponse{TotalCount: int32(total)}
+	for i := range comments {
+		response.Comments = append(response.Comments, mapper.MapModelCommentToProto(&comments[i]))
+	}
+	return response, nil
+}
@@ -4,6 +4,7 @@ import (
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/logger"
 	"paperdebugger/internal/services"
+	aiclient "paperdebugger/internal/services/toolkit/client"
 	commentv1 "paperdebugger/pkg/gen/api/comment/v1"
 )
 
@@ -13,6 +14,8 @@ type CommentServer struct {
 	projectService        *services.ProjectService
 	conversationService   *services.ChatService
 	reverseCommentService *services.ReverseCommentService
+	userService           *services.UserService
+	aiClientV2            *aiclient.AIClientV2
 	logger                *logger.Logger
 	cfg                   *cfg.Cfg
 }
@@ -21,6 +24,8 @@ func NewCommentServer(
 	projectService *services.ProjectService,
 	conversationService *services.ChatService,
 	reverseCommentService *services.ReverseCommentService,
+	userService *services.UserService,
+	aiClientV2 *aiclient.AIClientV2,
 	logger *logger.Logger,
 	cfg *cfg.Cfg,
 ) commentv1.CommentServiceServer {
@@ -28,6 +33,8 @@ func NewCommentServer(
 		projectService:        projectService,
 		conversationService:   conversationService,
 		reverseCommentService: reverseCommentService,
+		userService:           userService,
+		aiClientV2:            aiClientV2,
 		logger:                logger,
 		cfg:                   cfg,
 	}
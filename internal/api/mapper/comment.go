@@ -0,0 +1,64 @@
+package mapper
+
+import (
+	"paperdebugger/internal/models"
+	commentv1 "paperdebugger/pkg/gen/api/comment/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var commentStatuses = map[models.CommentStatus]commentv1.CommentStatus{
+	models.CommentStatusNoAction: commentv1.CommentStatus_COMMENT_STATUS_OPEN,
+	models.CommentStatusAccepted: commentv1.CommentStatus_COMMENT_STATUS_ACCEPTED,
+	models.CommentStatusRejected: commentv1.CommentStatus_COMMENT_STATUS_REJECTED,
+	models.CommentStatusResolved: commentv1.CommentStatus_COMMENT_STATUS_RESOLVED,
+}
+
+func MapModelCommentToProto(comment *models.Comment) *commentv1.Comment {
+	replies := make([]*commentv1.CommentReply, 0, len(comment.Replies))
+	for i := range comment.Replies {
+		replies = append(replies, MapModelCommentReplyToProto(&comment.Replies[i]))
+	}
+	return &commentv1.Comment{
+		Id:            comment.ID.Hex(),
+		ProjectId:     comment.ProjectID,
+		DocId:         comment.DocID,
+		DocPath:       comment.DocPath,
+		DocVersion:    int32(comment.DocVersion),
+		DocSha1:       comment.DocSHA1,
+		QuotePosition: int32(comment.QuotePosition),
+		QuoteText:     comment.QuoteText,
+		Comment:       comment.Comment,
+		Importance:    string(comment.ImportanceLevel),
+		Section:       comment.Section,
+		Status:        commentStatuses[comment.IsAddedToOverleaf],
+		CreatedAt:     timestamppb.New(comment.CreatedAt.Time()),
+		UpdatedAt:     timestamppb.New(comment.UpdatedAt.Time()),
+		Orphaned:      comment.Orphaned,
+		Replies:       replies,
+	}
+}
+
+var commentReplyRoles = map[models.CommentReplyRole]commentv1.CommentReplyRole{
+	models.CommentReplyRoleUser:      commentv1.CommentReplyRole_COMMENT_REPLY_ROLE_USER,
+	models.CommentReplyRoleAssistant: commentv1.CommentReplyRole_COMMENT_REPLY_ROLE_ASSISTANT,
+}
+
+func MapModelCommentReplyToProto(reply *models.CommentReply) *commentv1.CommentReply {
+	return &commentv1.CommentReply{
+		Id:        reply.ID.Hex(),
+		Role:      commentReplyRoles[reply.Role],
+		Content:   reply.Content,
+		CreatedAt: timestamppb.New(reply.CreatedAt.Time()),
+	}
+}
+
+// MapProtoCommentStatusToModel returns the status of a comment, and false for an unspecified or unknown status.
+func MapProtoCommentStatusToModel(status commentv1.CommentStatus) (models.CommentStatus, bool) {
+	for modelStatus, protoStatus := range commentStatuses {
+		if protoStatus == status {
+			return modelStatus, true
+		}
+	}
+	return 0, false
+}
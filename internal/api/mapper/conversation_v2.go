@@ -1,6 +1,8 @@
 package mapper
 
 import (
+	"slices"
+
 	"paperdebugger/internal/models"
 	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
 
@@ -24,7 +26,7 @@ func BSONToChatMessageV2(msg bson.M) *chatv2.Message {
 
 func MapModelConversationToProtoV2(conversation *models.Conversation) *chatv2.Conversation {
 	// Convert BSON messages back to protobuf messages
-	filteredMessages := lo.Map(conversation.InappChatHistory, func(msg bson.M, _ int) *chatv2.Message {
+	filteredMessages := lo.Map(conversation.InappHistory(), func(msg bson.M, _ int) *chatv2.Message {
 		return BSONToChatMessageV2(msg)
 	})
 
@@ -38,9 +40,27 @@ func MapModelConversationToProtoV2(conversation *models.Conversation) *chatv2.Co
 	}
 
 	return &chatv2.Conversation{
-		Id:        conversation.ID.Hex(),
-		Title:     conversation.Title,
-		ModelSlug: modelSlug,
-		Messages:  filteredMessages,
+		Id:           conversation.ID.Hex(),
+		Title:        conversation.Title,
+		ModelSlug:    modelSlug,
+		Messages:     filteredMessages,
+		BranchId:     conversation.LeafID,
+		Alternatives: mapMessageAlternativesV2(conversation),
 	}
 }
+
+func mapMessageAlternativesV2(conversation *models.Conversation) []*chatv2.MessageAlternatives {
+	history := conversation.InappHistory()
+	alternatives := conversation.MessageAlternatives()
+	indexes := lo.Keys(alternatives)
+	slices.Sort(indexes)
+
+	return lo.Map(indexes, func(index int, _ int) *chatv2.MessageAlternatives {
+		return &chatv2.MessageAlternatives{
+			MessageId: models.InappMessageID(history[index]),
+			Alternatives: lo.Map(alternatives[index], func(msg bson.M, _ int) *chatv2.Message {
+				return BSONToChatMessageV2(msg)
+			}),
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"paperdebugger/internal/libs/tex"
 	"paperdebugger/internal/models"
 	projectv1 "paperdebugger/pkg/gen/api/project/v1"
 
@@ -35,3 +36,52 @@ func MapModelProjectToProto(project *models.Project) *projectv1.Project {
 		// Do not map docs here, user should get docs from the "websocket sync"
 	}
 }
+
+func MapModelProjectRevisionToProto(revision *models.ProjectRevision) *projectv1.ProjectRevision {
+	docs := make([]*projectv1.ProjectRevisionDoc, len(revision.Docs))
+	for i, doc := range revision.Docs {
+		docs[i] = &projectv1.ProjectRevisionDoc{
+			Id:       doc.ID,
+			Version:  int32(doc.Version),
+			Filepath: doc.Filepath,
+		}
+	}
+	return &projectv1.ProjectRevision{
+		Revision:  int32(revision.Revision),
+		CreatedAt: timestamppb.New(revision.CreatedAt.Time()),
+		RootDocId: revision.RootDocID,
+		Docs:      docs,
+	}
+}
+
+func MapProtoDocPatchToModel(patch *projectv1.DocPatch) models.DocPatch {
+	edits := make([]models.DocLineEdit, len(patch.GetEdits()))
+	for i, edit := range patch.GetEdits() {
+		edits[i] = models.DocLineEdit{
+			StartLine:   int(edit.GetStartLine()),
+			DeleteCount: int(edit.GetDeleteCount()),
+			Lines:       edit.GetLines(),
+		}
+	}
+	return models.DocPatch{
+		Type:        models.DocPatchType(patch.GetType()),
+		DocID:       patch.GetDocId(),
+		BaseVersion: int(patch.GetBaseVersion()),
+		Version:     int(patch.GetVersion()),
+		Filepath:    patch.GetFilepath(),
+		Lines:       patch.GetLines(),
+		Edits:       edits,
+	}
+}
+
+func MapLintFindingToProto(finding tex.LintFinding) *projectv1.LintFinding {
+	return &projectv1.LintFinding{
+		Rule:     string(finding.Rule),
+		Key:      finding.Key,
+		Command:  finding.Command,
+		Message:  finding.Message,
+		Section:  finding.Section,
+		FilePath: finding.Location.Filepath,
+		Line:     int32(finding.Location.Line),
+	}
+}
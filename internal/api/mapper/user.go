@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"paperdebugger/internal/libs/secret"
 	"paperdebugger/internal/models"
 	userv1 "paperdebugger/pkg/gen/api/user/v1"
 
@@ -42,6 +43,8 @@ func MapProtoSettingsToModel(settings *userv1.Settings) *models.Settings {
 		ShowedOnboarding:             settings.ShowedOnboarding,
 		OpenAIAPIKey:                 settings.OpenaiApiKey,
 		CustomModels:                 customModels,
+		MacroExpansion:               mapProtoMacroExpansionToModel(settings.MacroExpansion),
+		ChatMacroGlossary:            settings.ChatMacroGlossary,
 	}
 }
 
@@ -54,7 +57,7 @@ func MapModelSettingsToProto(settings *models.Settings) *userv1.Settings {
 			Slug:              m.Slug,
 			Name:              m.Name,
 			BaseUrl:           m.BaseUrl,
-			ApiKey:            m.APIKey,
+			ApiKey:            secret.Mask(m.APIKey),
 			ContextWindow:     m.ContextWindow,
 			MaxOutput:         m.MaxOutput,
 			InputPrice:        m.InputPrice,
@@ -71,7 +74,31 @@ func MapModelSettingsToProto(settings *models.Settings) *userv1.Settings {
 		EnableCitationSuggestion:     settings.EnableCitationSuggestion,
 		FullDocumentRag:              settings.FullDocumentRag,
 		ShowedOnboarding:             settings.ShowedOnboarding,
-		OpenaiApiKey:                 settings.OpenAIAPIKey,
+		OpenaiApiKey:                 secret.Mask(settings.OpenAIAPIKey),
 		CustomModels:                 customModels,
+		MacroExpansion:               mapModelMacroExpansionToProto(settings.MacroExpansion),
+		ChatMacroGlossary:            settings.ChatMacroGlossary,
+	}
+}
+
+func mapProtoMacroExpansionToModel(mode userv1.MacroExpansion) models.MacroExpansion {
+	switch mode {
+	case userv1.MacroExpansion_MACRO_EXPANSION_EXPAND:
+		return models.MacroExpansionExpand
+	case userv1.MacroExpansion_MACRO_EXPANSION_GLOSSARY:
+		return models.MacroExpansionGlossary
+	default:
+		return models.MacroExpansionNone
+	}
+}
+
+func mapModelMacroExpansionToProto(mode models.MacroExpansion) userv1.MacroExpansion {
+	switch mode {
+	case models.MacroExpansionExpand:
+		return userv1.MacroExpansion_MACRO_EXPANSION_EXPAND
+	case models.MacroExpansionGlossary:
+		return userv1.MacroExpansion_MACRO_EXPANSION_GLOSSARY
+	default:
+		return userv1.MacroExpansion_MACRO_EXPANSION_UNSPECIFIED
 	}
 }
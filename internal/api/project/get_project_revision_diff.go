@@ -0,0 +1,46 @@
+package project
+
+import (
+	"context"
+
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+)
+
+func (s *ProjectServer) GetProjectRevisionDiff(
+	ctx context.Context,
+	req *projectv1.GetProjectRevisionDiffRequest,
+) (*projectv1.GetProjectRevisionDiffResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	if req.GetFromRevision() <= 0 {
+		return nil, shared.ErrBadRequest("from_revision is required")
+	}
+
+	from, err := s.projectService.GetProjectRevision(ctx, actor.ID, req.GetProjectId(), int(req.GetFromRevision()))
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.projectService.GetProjectRevision(ctx, actor.ID, req.GetProjectId(), int(req.GetToRevision()))
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := s.projectService.DiffProjectRevisions(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectv1.GetProjectRevisionDiffResponse{
+		FromRevision: int32(from.Revision),
+		ToRevision:   int32(to.Revision),
+		Diff:         diff,
+	}, nil
+}
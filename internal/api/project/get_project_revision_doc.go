@@ -0,0 +1,42 @@
+package project
+
+import (
+	"context"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+)
+
+func (s *ProjectServer) GetProjectRevisionDoc(
+	ctx context.Context,
+	req *projectv1.GetProjectRevisionDocRequest,
+) (*projectv1.GetProjectRevisionDocResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	if req.GetDocId() == "" {
+		return nil, shared.ErrBadRequest("doc_id is required")
+	}
+
+	revision, err := s.projectService.GetProjectRevision(ctx, actor.ID, req.GetProjectId(), int(req.GetRevision()))
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := s.projectService.GetProjectRevisionDoc(ctx, revision, req.GetDocId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectv1.GetProjectRevisionDocResponse{
+		Revision: int32(revision.Revision),
+		Doc:      mapper.MapModelProjectDocToProto(*doc),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package project
+
+import (
+	"context"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+)
+
+func (s *ProjectServer) LintProject(
+	ctx context.Context,
+	req *projectv1.LintProjectRequest,
+) (*projectv1.LintProjectResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+
+	project, err := s.projectService.GetProject(ctx, actor.ID, req.GetProjectId())
+	if err != nil {
+		return nil, err
+	}
+
+	findings, err := project.Lint()
+	if err != nil {
+		return nil, shared.ErrBadRequest(err.Error())
+	}
+
+	response := &projectv1.LintProjectResponse{ProjectId: req.GetProjectId()}
+	for _, finding := range findings {
+		response.Findings = append(response.Findings, mapper.MapLintFindingToProto(finding))
+	}
+
+	if req.GetAddComments() {
+		response.Comments, err = s.reverseCommentService.LintComments(ctx, project, findings)
+		if err != nil {
+			s.logger.Error("Failed to add lint comments", "error", err, "projectID", req.GetProjectId())
+			return nil, shared.ErrInternal("failed to create overleaf comments")
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,49 @@
+package project
+
+import (
+	"context"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+)
+
+const (
+	defaultRevisionsLimit = 20
+	maxRevisionsLimit     = 100
+)
+
+func (s *ProjectServer) ListProjectRevisions(
+	ctx context.Context,
+	req *projectv1.ListProjectRevisionsRequest,
+) (*projectv1.ListProjectRevisionsResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+
+	if req.GetBeforeRevision() < 0 {
+		return nil, shared.ErrBadRequest("before_revision must not be negative")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultRevisionsLimit
+	}
+	limit = min(limit, maxRevisionsLimit)
+
+	revisions, err := s.projectService.ListProjectRevisions(ctx, actor.ID, req.GetProjectId(), int(req.GetBeforeRevision()), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &projectv1.ListProjectRevisionsResponse{}
+	for i := range revisions {
+		response.Revisions = append(response.Revisions, mapper.MapModelProjectRevisionToProto(&revisions[i]))
+	}
+	return response, nil
+}
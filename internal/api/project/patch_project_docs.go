@@ -0,0 +1,45 @@
+package project
+
+import (
+	"context"
+
+	"paperdebugger/internal/api/mapper"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+
+	"github.com/samber/lo"
+)
+
+func (s *ProjectServer) PatchProjectDocs(
+	ctx context.Context,
+	req *projectv1.PatchProjectDocsRequest,
+) (*projectv1.PatchProjectDocsResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+
+	project, err := s.projectService.PatchProjectDocs(
+		ctx,
+		actor.ID,
+		req.GetProjectId(),
+		lo.Map(req.GetPatches(), func(patch *projectv1.DocPatch, _ int) models.DocPatch {
+			return mapper.MapProtoDocPatchToModel(patch)
+		}),
+		req.GetRootDocId(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.reverseCommentService.ReanchorCommentsOrLog(ctx, project)
+
+	return &projectv1.PatchProjectDocsResponse{
+		Project: mapper.MapModelProjectToProto(project),
+	}, nil
+}
@@ -0,0 +1,167 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"paperdebugger/internal/accesscontrol"
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	"paperdebugger/internal/services/toolkit/tools"
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// newMCPServerStub serves the endpoints of paperdebugger-mcp-server used by the paper score tools.
+func newMCPServerStub(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/classify-paper", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"category":    "Machine Learning",
+			"confidence":  90,
+			"explanation": "stub",
+		})
+	})
+	mux.HandleFunc("/paper-score", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"score":      7.5,
+			"percentile": 0.8,
+			"details":    map[string]int{"clarity": 8},
+		})
+	})
+	mux.HandleFunc("/paper-score-comments", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"results": []map[string]any{
+				{
+					"section":    "Introduction",
+					"anchorText": "We study the problem of testing.",
+					"weakness":   "The motivation is unclear.",
+					"importance": "High",
+				},
+			},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProjectServer_RunTools(t *testing.T) {
+	stub := newMCPServerStub(t)
+	os.Setenv("PD_MONGO_URI", "mongodb://localhost:27017")
+	os.Setenv("MCP_SERVER_URL", stub.URL)
+	t.Cleanup(func() { os.Unsetenv("MCP_SERVER_URL") })
+
+	cfg := cfg.GetCfg()
+	logger := logger.GetLogger()
+	db, err := db.NewDB(cfg, logger)
+	if err != nil {
+		t.Fatalf("Failed to create db: %v", err)
+	}
+
+	projectService := services.NewProjectService(db, cfg, logger)
+	chatServiceV2 := services.NewChatServiceV2(db, cfg, logger)
+	userService := services.NewUserService(db, nil, cfg, logger)
+	reverseCommentService := services.NewReverseCommentService(db, cfg, logger, projectService)
+	server := NewProjectServer(
+		projectService,
+		chatServiceV2,
+		reverseCommentService,
+		tools.NewPaperScoreTool(db, projectService, userService, cfg),
+		tools.NewPaperScoreCommentTool(db, projectService, userService, reverseCommentService, cfg),
+		logger,
+		cfg,
+	)
+
+	userID := bson.NewObjectID()
+	projectID := "test-project-" + bson.NewObjectID().Hex()
+	ctx := contextutil.SetActor(context.Background(), &accesscontrol.Actor{ID: userID})
+
+	_, err = projectService.UpsertProject(ctx, userID, projectID, &models.Project{
+		Name:      "Test Project",
+		RootDocID: "doc-main",
+		Docs: []models.ProjectDoc{
+			{
+				ID:       "doc-main",
+				Version:  1,
+				Filepath: "main.tex",
+				Lines: []string{
+					"\\documentclass{article}",
+					"\\begin{document}",
+					"\\section{Introduction}",
+					"We study the problem of testing.",
+					"\\end{document}",
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	conversation, err := chatServiceV2.InsertConversationToDBV2(ctx, bson.NewObjectID(), userID, projectID, "gpt-5-nano", "", false, nil, "")
+	assert.NoError(t, err)
+
+	t.Run("conversation is required", func(t *testing.T) {
+		resp, err := server.RunProjectPaperScore(ctx, &projectv1.RunProjectPaperScoreRequest{
+			ProjectId: projectID,
+		})
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("comments require a paper score", func(t *testing.T) {
+		_, err := server.RunProjectPaperScoreComment(ctx, &projectv1.RunProjectPaperScoreCommentRequest{
+			ProjectId:      projectID,
+			ConversationId: conversation.ID.Hex(),
+		})
+		assert.Equal(t, http.StatusNotFound, shared.GetHTTPCode(err))
+	})
+
+	t.Run("paper score then comments", func(t *testing.T) {
+		scoreResp, err := server.RunProjectPaperScore(ctx, &projectv1.RunProjectPaperScoreRequest{
+			ProjectId:      projectID,
+			ConversationId: conversation.ID.Hex(),
+		})
+		assert.NoError(t, err)
+		assert.InDelta(t, 7.5, scoreResp.GetPaperScore().GetScore(), 1e-6)
+
+		// The second run within the cool down period is throttled.
+		_, err = server.RunProjectPaperScore(ctx, &projectv1.RunProjectPaperScoreRequest{
+			ProjectId:      projectID,
+			ConversationId: conversation.ID.Hex(),
+		})
+		assert.Error(t, err)
+
+		commentResp, err := server.RunProjectPaperScoreComment(ctx, &projectv1.RunProjectPaperScoreCommentRequest{
+			ProjectId:      projectID,
+			ConversationId: conversation.ID.Hex(),
+		})
+		assert.NoError(t, err)
+		assert.Len(t, commentResp.GetComments(), 1)
+		assert.Len(t, commentResp.GetComments()[0].GetResults(), 1)
+	})
+
+	t.Run("overleaf comment", func(t *testing.T) {
+		resp, err := server.RunProjectOverleafComment(ctx, &projectv1.RunProjectOverleafCommentRequest{
+			ProjectId:  projectID,
+			Section:    "Introduction",
+			AnchorText: "We study the problem of testing.",
+			Comment:    "Cite prior work here.",
+			Importance: "Low",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, resp.GetComments(), 1)
+		assert.Equal(t, "doc-main", resp.GetComments()[0].GetDocId())
+		assert.Equal(t, "We study the problem of testing.", resp.GetComments()[0].GetQuoteText())
+	})
+}
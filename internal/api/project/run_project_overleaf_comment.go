@@ -0,0 +1,52 @@
+package project
+
+import (
+	"context"
+
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+)
+
+func (s *ProjectServer) RunProjectOverleafComment(
+	ctx context.Context,
+	req *projectv1.RunProjectOverleafCommentRequest,
+) (*projectv1.RunProjectOverleafCommentResponse, error) {
+	if _, err := contextutil.GetActor(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.GetProjectId() == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	if req.GetSection() == "" {
+		return nil, shared.ErrBadRequest("section is required")
+	}
+	if req.GetAnchorText() == "" {
+		return nil, shared.ErrBadRequest("anchor_text is required")
+	}
+	if req.GetComment() == "" {
+		return nil, shared.ErrBadRequest("comment is required")
+	}
+
+	ctx = contextutil.SetProjectID(ctx, req.GetProjectId())
+	comments, err := s.reverseCommentService.ReverseComments(ctx, &projectv1.PaperScoreCommentResult{
+		Results: []*projectv1.PaperScoreCommentEntry{
+			{
+				Section:    req.GetSection(),
+				AnchorText: req.GetAnchorText(),
+				Weakness:   req.GetComment(),
+				Importance: req.GetImportance(),
+			},
+		},
+	})
+	if err != nil {
+		s.logger.Error("Failed to reverse overleaf comment", "error", err, "projectID", req.GetProjectId())
+		return nil, shared.ErrInternal("failed to create overleaf comment")
+	}
+
+	return &projectv1.RunProjectOverleafCommentResponse{
+		ProjectId: req.GetProjectId(),
+		Comments:  comments,
+	}, nil
+}
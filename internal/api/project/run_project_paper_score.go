@@ -0,0 +1,94 @@
+package project
+
+import (
+	"context"
+	"errors"
+
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/shared"
+	toolCallRecordDB "paperdebugger/internal/services/toolkit/db"
+	"paperdebugger/internal/services/toolkit/tools"
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"google.golang.org/grpc/status"
+)
+
+// prepareToolContext validates that the conversation belongs to the actor and the project,
+// and returns a context carrying the project and conversation ids expected by the tools.
+func (s *ProjectServer) prepareToolContext(ctx context.Context, projectID string, conversationID string) (context.Context, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if projectID == "" {
+		return nil, shared.ErrBadRequest("project_id is required")
+	}
+	if conversationID == "" {
+		return nil, shared.ErrBadRequest("conversation_id is required")
+	}
+
+	conversationObjectID, err := bson.ObjectIDFromHex(conversationID)
+	if err != nil {
+		return nil, shared.ErrBadRequest("invalid conversation_id")
+	}
+
+	conversation, err := s.chatServiceV2.GetConversationV2(ctx, actor.ID, conversationObjectID)
+	if err != nil {
+		return nil, shared.ErrRecordNotFound("conversation not found")
+	}
+	if conversation.ProjectID != projectID {
+		return nil, shared.ErrBadRequest("conversation does not belong to the project")
+	}
+
+	ctx = contextutil.SetProjectID(ctx, projectID)
+	ctx = contextutil.SetConversationID(ctx, conversationID)
+	return ctx, nil
+}
+
+// newToolCallID returns the id recorded in the function call record for tools run through the API,
+// since there is no tool call id issued by the language model.
+func newToolCallID() string {
+	return "pd_rpc_" + uuid.New().String()
+}
+
+// mapToolError reports throttled calls as exceeded quotas, missing projects or paper scores as not found, and
+// everything else as internal errors. Errors that already carry a status are returned as-is.
+func mapToolError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, toolCallRecordDB.ErrCoolDown):
+		return shared.ErrQuotaExceeded(err)
+	case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, tools.ErrPaperScoreNotFound):
+		return shared.ErrRecordNotFound(err)
+	case errors.Is(err, tools.ErrPaperScoreNotReady):
+		return shared.ErrBadRequest(err)
+	}
+	return shared.ErrInternal(err)
+}
+
+func (s *ProjectServer) RunProjectPaperScore(
+	ctx context.Context,
+	req *projectv1.RunProjectPaperScoreRequest,
+) (*projectv1.RunProjectPaperScoreResponse, error) {
+	ctx, err := s.prepareToolContext(ctx, req.GetProjectId(), req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.paperScoreTool.Run(ctx, newToolCallID())
+	if err != nil {
+		s.logger.Error("Failed to run paper score", "error", err, "projectID", req.GetProjectId())
+		return nil, mapToolError(err)
+	}
+
+	return &projectv1.RunProjectPaperScoreResponse{
+		ProjectId:  req.GetProjectId(),
+		PaperScore: result,
+	}, nil
+}
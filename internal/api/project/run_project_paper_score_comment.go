@@ -0,0 +1,28 @@
+package project
+
+import (
+	"context"
+
+	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+)
+
+func (s *ProjectServer) RunProjectPaperScoreComment(
+	ctx context.Context,
+	req *projectv1.RunProjectPaperScoreCommentRequest,
+) (*projectv1.RunProjectPaperScoreCommentResponse, error) {
+	ctx, err := s.prepareToolContext(ctx, req.GetProjectId(), req.GetConversationId())
+	if err != nil {
+		return nil, err
+	}
+
+	result, _, err := s.paperScoreCommentTool.Run(ctx, newToolCallID())
+	if err != nil {
+		s.logger.Error("Failed to run paper score comment", "error", err, "projectID", req.GetProjectId())
+		return nil, mapToolError(err)
+	}
+
+	return &projectv1.RunProjectPaperScoreCommentResponse{
+		ProjectId: req.GetProjectId(),
+		Comments:  []*projectv1.PaperScoreCommentResult{result},
+	}, nil
+}
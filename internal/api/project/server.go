@@ -4,24 +4,37 @@ import (
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/logger"
 	"paperdebugger/internal/services"
+	"paperdebugger/internal/services/toolkit/tools"
 	projectv1 "paperdebugger/pkg/gen/api/project/v1"
 )
 
 type ProjectServer struct {
 	projectv1.UnimplementedProjectServiceServer
-	projectService *services.ProjectService
-	logger         *logger.Logger
-	cfg            *cfg.Cfg
+	projectService        *services.ProjectService
+	chatServiceV2         *services.ChatServiceV2
+	reverseCommentService *services.ReverseCommentService
+	paperScoreTool        *tools.PaperScoreTool
+	paperScoreCommentTool *tools.PaperScoreCommentTool
+	logger                *logger.Logger
+	cfg                   *cfg.Cfg
 }
 
 func NewProjectServer(
 	projectService *services.ProjectService,
+	chatServiceV2 *services.ChatServiceV2,
+	reverseCommentService *services.ReverseCommentService,
+	paperScoreTool *tools.PaperScoreTool,
+	paperScoreCommentTool *tools.PaperScoreCommentTool,
 	logger *logger.Logger,
 	cfg *cfg.Cfg,
 ) projectv1.ProjectServiceServer {
 	return &ProjectServer{
-		projectService: projectService,
-		logger:         logger,
-		cfg:            cfg,
+		projectService:        projectService,
+		chatServiceV2:         chatServiceV2,
+		reverseCommentService: reverseCommentService,
+		paperScoreTool:        paperScoreTool,
+		paperScoreCommentTool: paperScoreCommentTool,
+		logger:                logger,
+		cfg:                   cfg,
 	}
 }
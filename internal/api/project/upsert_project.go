@@ -34,12 +34,14 @@ func (s *ProjectServer) UpsertProject(
 		Docs: lo.Map(req.GetDocs(), func(doc *projectv1.ProjectDoc, _ int) models.ProjectDoc {
 			return mapper.MapProtoProjectDocToModel(doc)
 		}),
+		FilePaths: req.GetFilePaths(),
 	}
 
 	project, err = s.projectService.UpsertProject(ctx, actor.ID, req.GetProjectId(), project)
 	if err != nil {
 		return nil, err
 	}
+	s.reverseCommentService.ReanchorCommentsOrLog(ctx, project)
 
 	return &projectv1.UpsertProjectResponse{
 		Project: mapper.MapModelProjectToProto(project),
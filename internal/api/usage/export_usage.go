@@ -0,0 +1,72 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"time"
+
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	usagev1 "paperdebugger/pkg/gen/api/usage/v1"
+
+	"google.golang.org/genproto/googleapis/api/httpbody"
+)
+
+var usageCSVHeader = []string{"hour", "user_id", "project_id", "model_slug", "success_cost", "failed_cost", "prompt_tokens", "completion_tokens"}
+
+func (s *UsageServer) ExportUsage(
+	ctx context.Context,
+	req *usagev1.ExportUsageRequest,
+) (*httpbody.HttpBody, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	start, end, err := usageRange(req.GetStartTime(), req.GetEndTime())
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := s.usageService.ListHourlyUsages(ctx, services.UsageFilter{Start: start, End: end})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := writeUsageCSV(usages)
+	if err != nil {
+		return nil, shared.ErrInternal(err)
+	}
+
+	return &httpbody.HttpBody{
+		ContentType: "text/csv",
+		Data:        data,
+	}, nil
+}
+
+func writeUsageCSV(usages []models.HourlyUsage) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(usageCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, usage := range usages {
+		record := []string{
+			usage.HourBucket.Time().UTC().Format(time.RFC3339),
+			usage.UserID.Hex(),
+			usage.ProjectID,
+			usage.ModelSlug,
+			strconv.FormatFloat(usage.SuccessCost, 'f', -1, 64),
+			strconv.FormatFloat(usage.FailedCost, 'f', -1, 64),
+			strconv.FormatInt(usage.PromptTokens, 10),
+			strconv.FormatInt(usage.CompletionTokens, 10),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
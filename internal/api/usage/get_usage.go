@@ -0,0 +1,43 @@
+package usage
+
+import (
+	"context"
+
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/services"
+	usagev1 "paperdebugger/pkg/gen/api/usage/v1"
+)
+
+func (s *UsageServer) GetUsage(
+	ctx context.Context,
+	req *usagev1.GetUsageRequest,
+) (*usagev1.GetUsageResponse, error) {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := usageRange(req.GetStartTime(), req.GetEndTime())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := services.UsageFilter{UserID: actor.ID, ProjectID: req.GetProjectId(), Start: start, End: end}
+	groups, err := s.usageService.AggregateUsage(ctx, filter, services.UsageGroupByProject, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]*usagev1.ProjectUsage, len(groups))
+	for i, group := range groups {
+		projects[i] = &usagev1.ProjectUsage{
+			ProjectId: group.Key,
+			Usage:     toProtoUsage(group.UsageTotals),
+		}
+	}
+
+	return &usagev1.GetUsageResponse{
+		Total:    toProtoUsage(sumUsage(groups)),
+		Projects: projects,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package usage
+
+import (
+	"context"
+
+	"paperdebugger/internal/services"
+	usagev1 "paperdebugger/pkg/gen/api/usage/v1"
+)
+
+func (s *UsageServer) GetUsageByModel(
+	ctx context.Context,
+	req *usagev1.GetUsageByModelRequest,
+) (*usagev1.GetUsageByModelResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	start, end, err := usageRange(req.GetStartTime(), req.GetEndTime())
+	if err != nil {
+		return nil, err
+	}
+
+	filter := services.UsageFilter{Start: start, End: end}
+	groups, err := s.usageService.AggregateUsage(ctx, filter, services.UsageGroupByModel, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	modelUsages := make([]*usagev1.ModelUsage, len(groups))
+	for i, group := range groups {
+		modelUsages[i] = &usagev1.ModelUsage{
+			ModelSlug: group.Key,
+			Usage:     toProtoUsage(group.UsageTotals),
+		}
+	}
+
+	return &usagev1.GetUsageByModelResponse{
+		Total:  toProtoUsage(sumUsage(groups)),
+		Models: modelUsages,
+	}, nil
+}
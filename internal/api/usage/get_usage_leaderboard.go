@@ -0,0 +1,74 @@
+package usage
+
+import (
+	"context"
+
+	"paperdebugger/internal/services"
+	usagev1 "paperdebugger/pkg/gen/api/usage/v1"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	defaultLeaderboardLimit = 20
+	maxLeaderboardLimit     = 100
+)
+
+func (s *UsageServer) GetUsageLeaderboard(
+	ctx context.Context,
+	req *usagev1.GetUsageLeaderboardRequest,
+) (*usagev1.GetUsageLeaderboardResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	start, end, err := usageRange(req.GetStartTime(), req.GetEndTime())
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	limit = min(limit, maxLeaderboardLimit)
+
+	groupBy := services.UsageGroupByUser
+	if req.GetType() == usagev1.UsageLeaderboardType_USAGE_LEADERBOARD_TYPE_PROJECT {
+		groupBy = services.UsageGroupByProject
+	}
+
+	filter := services.UsageFilter{Start: start, End: end}
+	groups, err := s.usageService.AggregateUsage(ctx, filter, groupBy, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users that do not exist anymore have no email
+	var emails map[bson.ObjectID]string
+	if groupBy == services.UsageGroupByUser {
+		userIDs := make([]bson.ObjectID, 0, len(groups))
+		for _, group := range groups {
+			if id, err := bson.ObjectIDFromHex(group.Key); err == nil {
+				userIDs = append(userIDs, id)
+			}
+		}
+		emails, err = s.userService.GetUserEmails(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	entries := make([]*usagev1.UsageLeaderboardEntry, len(groups))
+	for i, group := range groups {
+		entries[i] = &usagev1.UsageLeaderboardEntry{
+			Id:    group.Key,
+			Usage: toProtoUsage(group.UsageTotals),
+		}
+		if id, err := bson.ObjectIDFromHex(group.Key); err == nil {
+			entries[i].Email = emails[id]
+		}
+	}
+
+	return &usagev1.GetUsageLeaderboardResponse{Entries: entries}, nil
+}
@@ -0,0 +1,99 @@
+package usage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/services"
+	usagev1 "paperdebugger/pkg/gen/api/usage/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultUsageRange is the time range of the reports when none is given.
+const defaultUsageRange = 7 * 24 * time.Hour
+
+type UsageServer struct {
+	usagev1.UnimplementedUsageServiceServer
+
+	usageService *services.UsageService
+	userService  *services.UserService
+	cfg          *cfg.Cfg
+	logger       *logger.Logger
+}
+
+func NewUsageServer(
+	usageService *services.UsageService,
+	userService *services.UserService,
+	cfg *cfg.Cfg,
+	logger *logger.Logger,
+) usagev1.UsageServiceServer {
+	return &UsageServer{
+		usageService: usageService,
+		userService:  userService,
+		cfg:          cfg,
+		logger:       logger,
+	}
+}
+
+// requireAdmin returns a PermissionDenied error if the actor is not an admin.
+func (s *UsageServer) requireAdmin(ctx context.Context) error {
+	actor, err := contextutil.GetActor(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.userService.GetUserByID(ctx, actor.ID)
+	if err != nil {
+		return err
+	}
+	if !user.IsAdmin {
+		return shared.ErrPermissionDenied("usage reports are restricted to admins")
+	}
+	return nil
+}
+
+// usageRange returns the time range of a report, defaulting to the last defaultUsageRange.
+// Ranges starting before the hourly usages expire are rejected, rather than reporting partial totals.
+func usageRange(startTime, endTime *timestamppb.Timestamp) (time.Time, time.Time, error) {
+	end := time.Now()
+	if endTime != nil {
+		end = endTime.AsTime()
+	}
+	start := end.Add(-defaultUsageRange)
+	if startTime != nil {
+		start = startTime.AsTime()
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, shared.ErrBadRequest("start_time must be before end_time")
+	}
+	if retained := time.Now().Add(-services.HourlyUsageRetention); start.Truncate(time.Hour).Before(retained) {
+		return time.Time{}, time.Time{}, shared.ErrBadRequest(fmt.Sprintf("usage is only kept for %d days, start_time must be after %s", int(services.HourlyUsageRetention.Hours()/24), retained.Format(time.RFC3339)))
+	}
+	return start, end, nil
+}
+
+func toProtoUsage(totals services.UsageTotals) *usagev1.Usage {
+	return &usagev1.Usage{
+		SuccessCost:      totals.SuccessCost,
+		FailedCost:       totals.FailedCost,
+		PromptTokens:     totals.PromptTokens,
+		CompletionTokens: totals.CompletionTokens,
+	}
+}
+
+// sumUsage returns the totals of all groups.
+func sumUsage(groups []services.UsageGroup) services.UsageTotals {
+	var total services.UsageTotals
+	for _, group := range groups {
+		total.SuccessCost += group.SuccessCost
+		total.FailedCost += group.FailedCost
+		total.PromptTokens += group.PromptTokens
+		total.CompletionTokens += group.CompletionTokens
+	}
+	return total
+}
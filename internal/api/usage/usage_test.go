@@ -0,0 +1,58 @@
+package usage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"paperdebugger/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestUsageRange(t *testing.T) {
+	start, end, err := usageRange(nil, nil)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), end, time.Second)
+	assert.Equal(t, defaultUsageRange, end.Sub(start))
+
+	endTime := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	start, end, err = usageRange(nil, timestamppb.New(endTime))
+	assert.NoError(t, err)
+	assert.Equal(t, endTime.UTC(), end)
+	assert.Equal(t, endTime.Add(-defaultUsageRange).UTC(), start)
+
+	_, _, err = usageRange(timestamppb.New(endTime), timestamppb.New(endTime.Add(-time.Hour)))
+	assert.Error(t, err)
+
+	// Hourly usages are expired after two weeks, older ranges would be partial.
+	_, _, err = usageRange(timestamppb.New(time.Now().Add(-15*24*time.Hour)), nil)
+	assert.Error(t, err)
+	_, _, err = usageRange(nil, timestamppb.New(time.Now().Add(-10*24*time.Hour)))
+	assert.Error(t, err)
+}
+
+func TestWriteUsageCSV(t *testing.T) {
+	userID := bson.NewObjectID()
+	hour := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	data, err := writeUsageCSV([]models.HourlyUsage{
+		{
+			UserID:           userID,
+			ProjectID:        "project,1",
+			ModelSlug:        "openai/gpt-5.1",
+			HourBucket:       bson.NewDateTimeFromTime(hour),
+			SuccessCost:      0.125,
+			PromptTokens:     1200,
+			CompletionTokens: 300,
+		},
+	})
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 2)
+	assert.Equal(t, strings.Join(usageCSVHeader, ","), lines[0])
+	assert.Equal(t, "2025-03-10T12:00:00Z,"+userID.Hex()+`,"project,1",openai/gpt-5.1,0.125,0,1200,300`, lines[1])
+}
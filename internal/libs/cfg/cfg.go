@@ -2,6 +2,9 @@ package cfg
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +19,27 @@ type Cfg struct {
 	MongoURI     string
 	XtraMCPURI   string
 	MCPServerURL string
+
+	// Embeddings are used by the full document RAG when EmbeddingModel is set, otherwise BM25 is used.
+	EmbeddingBaseURL string
+	EmbeddingAPIKey  string
+	EmbeddingModel   string
+
+	// Limits of a single chat turn. The system prompt advertises 20 tool calls per turn.
+	MaxToolCallsPerTurn int
+	ToolCallTimeout     time.Duration
+	TurnTimeout         time.Duration
+	TurnMaxCost         float64 // in USD, only for the shared API key
+
+	// Weekly spend quotas on the shared API key, in USD. 0 means unlimited.
+	// Users can have their own quota, see models.User.WeeklySpendLimit and the set-spend-limit command.
+	UserWeeklySpendLimit    float64
+	ProjectWeeklySpendLimit float64
+
+	// Master keys encrypting the API keys of the users, base64 encoded 32 bytes. See package secret.
+	// API keys are stored in plaintext when APIKeyMasterKey is not set.
+	APIKeyMasterKey          string
+	APIKeyPreviousMasterKeys []string // Only used to decrypt, during key rotation
 }
 
 var cfg *Cfg
@@ -31,6 +55,20 @@ func GetCfg() *Cfg {
 		MongoURI:         mongoURI(),
 		XtraMCPURI:       xtraMCPURI(),
 		MCPServerURL:     mcpServerURL(),
+		EmbeddingBaseURL: embeddingBaseURL(),
+		EmbeddingAPIKey:  embeddingAPIKey(),
+		EmbeddingModel:   os.Getenv("EMBEDDING_MODEL"),
+
+		MaxToolCallsPerTurn: intEnv("MAX_TOOL_CALLS_PER_TURN", 20),
+		ToolCallTimeout:     durationEnv("TOOL_CALL_TIMEOUT", 2*time.Minute),
+		TurnTimeout:         durationEnv("TURN_TIMEOUT", 10*time.Minute),
+		TurnMaxCost:         floatEnv("TURN_MAX_COST", 1.0),
+
+		UserWeeklySpendLimit:    limitEnv("USER_WEEKLY_SPEND_LIMIT", 0),
+		ProjectWeeklySpendLimit: limitEnv("PROJECT_WEEKLY_SPEND_LIMIT", 0),
+
+		APIKeyMasterKey:          os.Getenv("API_KEY_MASTER_KEY"),
+		APIKeyPreviousMasterKeys: listEnv("API_KEY_PREVIOUS_MASTER_KEYS"),
 	}
 
 	return cfg
@@ -76,3 +114,63 @@ func mcpServerURL() string {
 	}
 	return "http://paperdebugger-mcp-server:8000"
 }
+
+func embeddingBaseURL() string {
+	val := os.Getenv("EMBEDDING_BASE_URL")
+	if val != "" {
+		return val
+	}
+	return openAIBaseURL()
+}
+
+func embeddingAPIKey() string {
+	val := os.Getenv("EMBEDDING_API_KEY")
+	if val != "" {
+		return val
+	}
+	return os.Getenv("OPENAI_API_KEY")
+}
+
+func intEnv(key string, fallback int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || val <= 0 {
+		return fallback
+	}
+	return val
+}
+
+func floatEnv(key string, fallback float64) float64 {
+	val, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || val <= 0 {
+		return fallback
+	}
+	return val
+}
+
+// limitEnv is like floatEnv, but accepts 0 to disable the limit.
+func limitEnv(key string, fallback float64) float64 {
+	val, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || val < 0 {
+		return fallback
+	}
+	return val
+}
+
+// listEnv returns the non-empty comma separated values of the variable.
+func listEnv(key string) []string {
+	var values []string
+	for _, val := range strings.Split(os.Getenv(key), ",") {
+		if val = strings.TrimSpace(val); val != "" {
+			values = append(values, val)
+		}
+	}
+	return values
+}
+
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	val, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || val <= 0 {
+		return fallback
+	}
+	return val
+}
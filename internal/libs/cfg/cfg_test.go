@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -31,3 +32,37 @@ func TestCfg(t *testing.T) {
 	assert.NotEmpty(t, cfg.OpenAIAPIKey)
 	assert.NotEmpty(t, cfg.MongoURI)
 }
+
+func TestCfg_TurnLimits(t *testing.T) {
+	cfg := GetCfg()
+	assert.Equal(t, 20, cfg.MaxToolCallsPerTurn)
+	assert.Equal(t, 2*time.Minute, cfg.ToolCallTimeout)
+
+	os.Setenv("MAX_TOOL_CALLS_PER_TURN", "5")
+	os.Setenv("TOOL_CALL_TIMEOUT", "30s")
+	os.Setenv("TURN_MAX_COST", "invalid")
+	defer os.Unsetenv("MAX_TOOL_CALLS_PER_TURN")
+	defer os.Unsetenv("TOOL_CALL_TIMEOUT")
+	defer os.Unsetenv("TURN_MAX_COST")
+
+	cfg = GetCfg()
+	assert.Equal(t, 5, cfg.MaxToolCallsPerTurn)
+	assert.Equal(t, 30*time.Second, cfg.ToolCallTimeout)
+	assert.Equal(t, 1.0, cfg.TurnMaxCost)
+}
+
+func TestCfg_SpendLimits(t *testing.T) {
+	// Unlimited by default, so that upgrading deployments keep serving their users.
+	cfg := GetCfg()
+	assert.Equal(t, 0.0, cfg.UserWeeklySpendLimit)
+	assert.Equal(t, 0.0, cfg.ProjectWeeklySpendLimit)
+
+	os.Setenv("USER_WEEKLY_SPEND_LIMIT", "5")
+	os.Setenv("PROJECT_WEEKLY_SPEND_LIMIT", "-1")
+	defer os.Unsetenv("USER_WEEKLY_SPEND_LIMIT")
+	defer os.Unsetenv("PROJECT_WEEKLY_SPEND_LIMIT")
+
+	cfg = GetCfg()
+	assert.Equal(t, 5.0, cfg.UserWeeklySpendLimit)
+	assert.Equal(t, 0.0, cfg.ProjectWeeklySpendLimit)
+}
@@ -0,0 +1,140 @@
+package rag
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+var commandRegex = regexp.MustCompile(`\\[a-zA-Z@]+\*?`)
+
+// Tokenize lowercases the text and splits it into words, dropping LaTeX command names.
+func Tokenize(text string) []string {
+	text = commandRegex.ReplaceAllString(text, " ")
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) < 2 {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// BM25Index ranks chunks against a query with the Okapi BM25 function.
+type BM25Index struct {
+	chunks    []Chunk
+	termFreqs []map[string]int
+	docLens   []int
+	avgDocLen float64
+	docFreqs  map[string]int
+	numDocs   int
+}
+
+func NewBM25Index(chunks []Chunk) *BM25Index {
+	index := &BM25Index{
+		chunks:    chunks,
+		termFreqs: make([]map[string]int, len(chunks)),
+		docLens:   make([]int, len(chunks)),
+		docFreqs:  make(map[string]int),
+		numDocs:   len(chunks),
+	}
+
+	totalLen := 0
+	for i, chunk := range chunks {
+		tokens := Tokenize(chunk.Section + " " + chunk.Text)
+		freqs := make(map[string]int)
+		for _, token := range tokens {
+			freqs[token]++
+		}
+		for token := range freqs {
+			index.docFreqs[token]++
+		}
+		index.termFreqs[i] = freqs
+		index.docLens[i] = len(tokens)
+		totalLen += len(tokens)
+	}
+	if index.numDocs > 0 {
+		index.avgDocLen = float64(totalLen) / float64(index.numDocs)
+	}
+	return index
+}
+
+// Score returns the BM25 score of every chunk for the query, in chunk order.
+func (idx *BM25Index) Score(query string) []float64 {
+	scores := make([]float64, idx.numDocs)
+	if idx.avgDocLen == 0 {
+		return scores
+	}
+	for _, term := range Tokenize(query) {
+		df := idx.docFreqs[term]
+		if df == 0 {
+			continue
+		}
+		idf := math.Log((float64(idx.numDocs)-float64(df)+0.5)/(float64(df)+0.5) + 1)
+		for i, freqs := range idx.termFreqs {
+			tf := float64(freqs[term])
+			if tf == 0 {
+				continue
+			}
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(idx.docLens[i])/idx.avgDocLen)
+			scores[i] += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+	}
+	return scores
+}
+
+// Search returns up to k chunks with a positive score, best first.
+func (idx *BM25Index) Search(query string, k int) []Chunk {
+	return TopK(idx.chunks, idx.Score(query), k)
+}
+
+// TopK returns up to k chunks with a positive score, best first. Ties keep document order.
+func TopK(chunks []Chunk, scores []float64, k int) []Chunk {
+	order := make([]int, 0, len(chunks))
+	for i := range chunks {
+		if scores[i] > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	if k > 0 && len(order) > k {
+		order = order[:k]
+	}
+
+	result := make([]Chunk, len(order))
+	for i, idx := range order {
+		result[i] = chunks[idx]
+	}
+	return result
+}
+
+// CosineSimilarity returns the cosine of the angle between two vectors, or 0 if they
+// have different dimensions or either is zero.
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,38 @@
+package rag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t,
+		[]string{"transformers", "are", "attention", "based", "über", "models"},
+		Tokenize(`\textbf{Transformers} are attention-based \emph{über} models, a.`),
+	)
+}
+
+func TestBM25Search(t *testing.T) {
+	chunks := []Chunk{
+		{Index: 0, Section: "Introduction", Text: "We study graph neural networks for molecules."},
+		{Index: 1, Section: "Method", Text: "Our attention layer aggregates neighbour features."},
+		{Index: 2, Section: "Experiments", Text: "We evaluate attention on three molecule benchmarks."},
+	}
+	index := NewBM25Index(chunks)
+
+	results := index.Search("attention benchmarks", 2)
+	assert.Len(t, results, 2)
+	assert.Equal(t, 2, results[0].Index)
+	assert.Equal(t, 1, results[1].Index)
+
+	assert.Empty(t, index.Search("unrelated query", 2))
+	assert.Empty(t, NewBM25Index(nil).Search("attention", 2))
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	assert.InDelta(t, 1.0, CosineSimilarity([]float64{1, 2}, []float64{2, 4}), 1e-9)
+	assert.InDelta(t, 0.0, CosineSimilarity([]float64{1, 0}, []float64{0, 1}), 1e-9)
+	assert.Equal(t, 0.0, CosineSimilarity([]float64{1}, []float64{1, 2}))
+	assert.Equal(t, 0.0, CosineSimilarity([]float64{0, 0}, []float64{1, 2}))
+}
@@ -0,0 +1,72 @@
+package rag
+
+import (
+	"strings"
+
+	"paperdebugger/internal/libs/tex"
+)
+
+// DefaultChunkSize is the soft limit, in bytes, of the text of a chunk.
+const DefaultChunkSize = 1500
+
+// Chunk is a contiguous piece of an expanded LaTeX document.
+type Chunk struct {
+	Index   int    // position of the chunk in the document
+	Section string // title of the enclosing sectioning command, empty for the preamble
+	Text    string
+}
+
+// sectionTitles returns the titles of the sections by the line they start on, 0-indexed. The sections are those
+// of tex.ParseOutline, commented-out sections being ignored.
+func sectionTitles(content string) map[int]string {
+	titles := map[int]string{}
+	for _, section := range tex.ParseOutline(content).Sections {
+		titles[section.Line-1] = section.Title
+	}
+	return titles
+}
+
+// isParagraphBreak reports whether a new chunk may start before the line when the
+// current chunk is full. Splitting inside an environment is avoided when possible.
+func isParagraphBreak(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, `\begin{`) || !strings.HasPrefix(trimmed, `\`)
+}
+
+// SplitChunks splits an expanded LaTeX document into chunks. A chunk never spans two
+// sections, and a section longer than chunkSize is split at line boundaries into
+// paragraph-sized chunks.
+func SplitChunks(content string, chunkSize int) []Chunk {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	chunks := []Chunk{}
+	section := ""
+	var current []string
+	currentLen := 0
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(current, "\n"))
+		if text != "" {
+			chunks = append(chunks, Chunk{Index: len(chunks), Section: section, Text: text})
+		}
+		current = nil
+		currentLen = 0
+	}
+
+	titles := sectionTitles(content)
+	for i, line := range strings.Split(content, "\n") {
+		if title, ok := titles[i]; ok {
+			flush()
+			section = title
+		} else if currentLen >= chunkSize && isParagraphBreak(line) {
+			flush()
+		}
+		current = append(current, line)
+		currentLen += len(line) + 1
+	}
+	flush()
+
+	return chunks
+}
@@ -0,0 +1,70 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitChunksBySection(t *testing.T) {
+	content := `\documentclass{article}
+\begin{document}
+\section{Introduction}
+We introduce the method.
+\subsection*{Background}
+Some background.
+\section[Short]{Related Work}
+Prior work exists.
+\end{document}`
+
+	chunks := SplitChunks(content, 0)
+	assert.Len(t, chunks, 4)
+	assert.Equal(t, "", chunks[0].Section)
+	assert.Equal(t, "Introduction", chunks[1].Section)
+	assert.Equal(t, "Background", chunks[2].Section)
+	assert.Equal(t, "Related Work", chunks[3].Section)
+	assert.True(t, strings.HasPrefix(chunks[3].Text, `\section[Short]{Related Work}`))
+	for i, chunk := range chunks {
+		assert.Equal(t, i, chunk.Index)
+	}
+}
+
+func TestSplitChunksLongSection(t *testing.T) {
+	lines := []string{`\section{Method}`}
+	for i := 0; i < 20; i++ {
+		lines = append(lines, strings.Repeat("word ", 20))
+	}
+
+	chunks := SplitChunks(strings.Join(lines, "\n"), 300)
+	assert.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assert.Equal(t, "Method", chunk.Section)
+		assert.LessOrEqual(t, len(chunk.Text), 300+101)
+	}
+}
+
+func TestSplitChunksKeepsEnvironmentTogether(t *testing.T) {
+	content := strings.Join([]string{
+		`\section{Results}`,
+		strings.Repeat("a", 50),
+		`\begin{itemize}`,
+		`\item first`,
+		`\item second`,
+		`\end{itemize}`,
+	}, "\n")
+
+	chunks := SplitChunks(content, 40)
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, "\\begin{itemize}\n\\item first\n\\item second\n\\end{itemize}", chunks[1].Text)
+}
+
+func TestSplitChunksIgnoresCommentedSections(t *testing.T) {
+	content := "\\section{Method}\nWe propose.\n% \\section{Old}\n\\paragraph{Setup}\nDetails."
+
+	chunks := SplitChunks(content, 0)
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, "Method", chunks[0].Section)
+	assert.Contains(t, chunks[0].Text, `% \section{Old}`)
+	assert.Equal(t, "Setup", chunks[1].Section)
+}
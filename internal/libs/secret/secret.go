@@ -0,0 +1,229 @@
+// Package secret encrypts the secrets stored in the database, such as the API keys of the users.
+//
+// Secrets are encrypted with envelope encryption: each secret is encrypted with its own random data key
+// using AES-256-GCM, and the data key is encrypted with the master key. The encrypted secret is stored as a
+// string, together with the ID of the master key, so that the master key can be rotated:
+//
+//	enc:v2:<master key ID>:<base64 encrypted data key>:<base64 encrypted secret>
+//
+// The secret is authenticated with additional data naming its owner and field, so that an encrypted secret copied
+// to another record fails to decrypt. Secrets encrypted with enc:v1 have no additional data, they are re-encrypted
+// like the ones of a previous master key.
+//
+// To rotate the master key, set the new key as the master key, move the old one to the previous master keys,
+// and re-encrypt the stored secrets with the migrate-api-keys command.
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"paperdebugger/internal/libs/cfg"
+)
+
+const (
+	encryptedPrefix = "enc:"
+	currentVersion  = "v2"
+	legacyVersion   = "v1" // Without additional authenticated data
+	keySize         = 32   // AES-256
+
+	// MaskedValue is returned by the API in place of a secret. Sending it back means the secret is unchanged.
+	MaskedValue = "********"
+)
+
+var ErrUnknownMasterKey = errors.New("secret is encrypted with an unknown master key")
+
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// Keyring holds the current master key, used to encrypt, and the previous ones, only used to decrypt.
+// A Keyring without master key stores secrets in plaintext. A nil Keyring behaves the same.
+type Keyring struct {
+	current *masterKey
+	keys    map[string]*masterKey
+}
+
+// NewKeyring creates the keyring of the master keys in cfg.
+func NewKeyring(cfg *cfg.Cfg) (*Keyring, error) {
+	return NewKeyringFromKeys(cfg.APIKeyMasterKey, cfg.APIKeyPreviousMasterKeys)
+}
+
+// NewKeyringFromKeys creates a keyring from base64 encoded 32 byte master keys.
+func NewKeyringFromKeys(current string, previous []string) (*Keyring, error) {
+	k := &Keyring{keys: map[string]*masterKey{}}
+	if current == "" {
+		if len(previous) > 0 {
+			return nil, errors.New("previous master keys are set without a master key")
+		}
+		return k, nil
+	}
+
+	for i, encoded := range append([]string{current}, previous...) {
+		key, err := newMasterKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			k.current = key
+		}
+		k.keys[key.id] = key
+	}
+	return k, nil
+}
+
+func newMasterKey(encoded string) (*masterKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid master key: %w", err)
+	}
+	if len(raw) != keySize {
+		return nil, fmt.Errorf("invalid master key: expected %d bytes, got %d", keySize, len(raw))
+	}
+	aead, err := newAEAD(raw)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(raw)
+	return &masterKey{id: hex.EncodeToString(sum[:4]), aead: aead}, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Enabled reports whether secrets are encrypted.
+func (k *Keyring) Enabled() bool {
+	return k != nil && k.current != nil
+}
+
+// Encrypt encrypts the secret with a new data key, bound to aad. Empty secrets, and all secrets when the keyring
+// is not enabled, are returned as-is.
+func (k *Keyring) Encrypt(plaintext string, aad string) (string, error) {
+	if plaintext == "" || !k.Enabled() {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, keySize)
+	rand.Read(dataKey) // Never fails, see crypto/rand.Read
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	encryptedDataKey := seal(k.current.aead, dataKey, nil)
+	encryptedSecret := seal(dataAEAD, []byte(plaintext), []byte(aad))
+	return encryptedPrefix + currentVersion + ":" + k.current.id + ":" +
+		base64.StdEncoding.EncodeToString(encryptedDataKey) + ":" +
+		base64.StdEncoding.EncodeToString(encryptedSecret), nil
+}
+
+// Decrypt decrypts a secret returned by Encrypt with the same aad. Plaintext secrets, stored before encryption
+// was enabled, are returned as-is.
+func (k *Keyring) Decrypt(value string, aad string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if len(parts) != 4 {
+		return "", errors.New("malformed encrypted secret")
+	}
+	switch parts[0] {
+	case currentVersion:
+	case legacyVersion:
+		aad = ""
+	default:
+		return "", fmt.Errorf("unsupported encrypted secret version %q", parts[0])
+	}
+	if k == nil {
+		return "", ErrUnknownMasterKey
+	}
+	key, ok := k.keys[parts[1]]
+	if !ok {
+		return "", ErrUnknownMasterKey
+	}
+
+	encryptedDataKey, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted secret: %w", err)
+	}
+	encryptedSecret, err := base64.StdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted secret: %w", err)
+	}
+
+	dataKey, err := open(key.aead, encryptedDataKey, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataAEAD, encryptedSecret, []byte(aad))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsReencryption reports whether the secret is not encrypted with the current version and master key.
+func (k *Keyring) NeedsReencryption(value string) bool {
+	if value == "" || !k.Enabled() {
+		return false
+	}
+	return !strings.HasPrefix(value, encryptedPrefix+currentVersion+":"+k.current.id+":")
+}
+
+// Reencrypt encrypts the secret with the current version and master key, bound to aad, if it is not already.
+func (k *Keyring) Reencrypt(value string, aad string) (string, error) {
+	if !k.NeedsReencryption(value) {
+		return value, nil
+	}
+	plaintext, err := k.Decrypt(value, aad)
+	if err != nil {
+		return "", err
+	}
+	return k.Encrypt(plaintext, aad)
+}
+
+// IsEncrypted reports whether the value was returned by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// Mask returns the value shown to the clients in place of the secret.
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	return MaskedValue
+}
+
+// seal encrypts the plaintext with a random nonce, prepended to the ciphertext, and authenticates aad.
+func seal(aead cipher.AEAD, plaintext []byte, aad []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce) // Never fails, see crypto/rand.Read
+	return aead.Seal(nonce, nonce, plaintext, aad)
+}
+
+func open(aead cipher.AEAD, ciphertext []byte, aad []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
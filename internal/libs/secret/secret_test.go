@@ -0,0 +1,135 @@
+package secret
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testAAD = "users/1/settings.openai_api_key"
+
+func newTestKey() string {
+	key := make([]byte, keySize)
+	rand.Read(key)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	keyring, err := NewKeyringFromKeys(newTestKey(), nil)
+	assert.NoError(t, err)
+	assert.True(t, keyring.Enabled())
+
+	encrypted, err := keyring.Encrypt("sk-test-1234", testAAD)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "sk-test")
+	assert.False(t, keyring.NeedsReencryption(encrypted))
+
+	// Each secret has its own data key.
+	other, err := keyring.Encrypt("sk-test-1234", testAAD)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, other)
+
+	decrypted, err := keyring.Decrypt(encrypted, testAAD)
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-test-1234", decrypted)
+
+	// Plaintext secrets stored before encryption are returned as-is.
+	decrypted, err = keyring.Decrypt("sk-legacy", testAAD)
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-legacy", decrypted)
+	assert.True(t, keyring.NeedsReencryption("sk-legacy"))
+
+	empty, err := keyring.Encrypt("", testAAD)
+	assert.NoError(t, err)
+	assert.Equal(t, "", empty)
+
+	// Secrets copied to another record fail to decrypt.
+	_, err = keyring.Decrypt(encrypted, "users/2/settings.openai_api_key")
+	assert.Error(t, err)
+
+	// Tampered secrets fail to decrypt.
+	_, err = keyring.Decrypt(encrypted[:len(encrypted)-4]+"AAA=", testAAD)
+	assert.Error(t, err)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	oldKey, newKey := newTestKey(), newTestKey()
+
+	oldKeyring, err := NewKeyringFromKeys(oldKey, nil)
+	assert.NoError(t, err)
+	encrypted, err := oldKeyring.Encrypt("sk-test-1234", testAAD)
+	assert.NoError(t, err)
+
+	// The new master key alone cannot decrypt the secret.
+	newKeyring, err := NewKeyringFromKeys(newKey, nil)
+	assert.NoError(t, err)
+	_, err = newKeyring.Decrypt(encrypted, testAAD)
+	assert.ErrorIs(t, err, ErrUnknownMasterKey)
+
+	rotating, err := NewKeyringFromKeys(newKey, []string{oldKey})
+	assert.NoError(t, err)
+	assert.True(t, rotating.NeedsReencryption(encrypted))
+
+	reencrypted, err := rotating.Reencrypt(encrypted, testAAD)
+	assert.NoError(t, err)
+	assert.False(t, rotating.NeedsReencryption(reencrypted))
+
+	decrypted, err := newKeyring.Decrypt(reencrypted, testAAD)
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-test-1234", decrypted)
+}
+
+func TestKeyring_LegacyVersion(t *testing.T) {
+	keyring, err := NewKeyringFromKeys(newTestKey(), nil)
+	assert.NoError(t, err)
+
+	// Secrets of the first version have no additional data.
+	dataKey := make([]byte, keySize)
+	rand.Read(dataKey)
+	dataAEAD, err := newAEAD(dataKey)
+	assert.NoError(t, err)
+	legacy := encryptedPrefix + legacyVersion + ":" + keyring.current.id + ":" +
+		base64.StdEncoding.EncodeToString(seal(keyring.current.aead, dataKey, nil)) + ":" +
+		base64.StdEncoding.EncodeToString(seal(dataAEAD, []byte("sk-test-1234"), nil))
+
+	decrypted, err := keyring.Decrypt(legacy, testAAD)
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-test-1234", decrypted)
+	assert.True(t, keyring.NeedsReencryption(legacy))
+
+	reencrypted, err := keyring.Reencrypt(legacy, testAAD)
+	assert.NoError(t, err)
+	assert.False(t, keyring.NeedsReencryption(reencrypted))
+	decrypted, err = keyring.Decrypt(reencrypted, testAAD)
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-test-1234", decrypted)
+}
+
+func TestKeyring_Disabled(t *testing.T) {
+	keyring, err := NewKeyringFromKeys("", nil)
+	assert.NoError(t, err)
+	assert.False(t, keyring.Enabled())
+
+	value, err := keyring.Encrypt("sk-test-1234", testAAD)
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-test-1234", value)
+	assert.False(t, keyring.NeedsReencryption(value))
+
+	var nilKeyring *Keyring
+	value, err = nilKeyring.Encrypt("sk-test-1234", testAAD)
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-test-1234", value)
+
+	_, err = NewKeyringFromKeys("", []string{newTestKey()})
+	assert.Error(t, err)
+	_, err = NewKeyringFromKeys(base64.StdEncoding.EncodeToString([]byte("short")), nil)
+	assert.Error(t, err)
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "", Mask(""))
+	assert.Equal(t, MaskedValue, Mask("enc:v1:abcd:xx:yy"))
+}
@@ -25,6 +25,8 @@ var errorCodeMessages = map[sharedv1.ErrorCode]string{
 	sharedv1.ErrorCode_ERROR_CODE_PERMISSION_DENIED:    "Permission denied",
 	sharedv1.ErrorCode_ERROR_CODE_INVALID_USER:         "User not found or invalid",
 	sharedv1.ErrorCode_ERROR_CODE_PROJECT_OUT_OF_DATE:  "Project is out of date",
+	sharedv1.ErrorCode_ERROR_CODE_QUOTA_EXCEEDED:       "Usage quota exceeded",
+	sharedv1.ErrorCode_ERROR_CODE_DOC_VERSION_CONFLICT: "Doc version conflict",
 }
 
 var (
@@ -39,6 +41,8 @@ var (
 	ErrPermissionDenied   = makeErrorFunc(sharedv1.ErrorCode_ERROR_CODE_PERMISSION_DENIED)
 	ErrInvalidUser        = makeErrorFunc(sharedv1.ErrorCode_ERROR_CODE_INVALID_USER)
 	ErrProjectOutOfDate   = makeErrorFunc(sharedv1.ErrorCode_ERROR_CODE_PROJECT_OUT_OF_DATE)
+	ErrQuotaExceeded      = makeErrorFunc(sharedv1.ErrorCode_ERROR_CODE_QUOTA_EXCEEDED)
+	ErrDocVersionConflict = makeErrorFunc(sharedv1.ErrorCode_ERROR_CODE_DOC_VERSION_CONFLICT)
 )
 
 var codesMapHttpCode = map[codes.Code]int{
@@ -67,6 +71,8 @@ var codesMapHttpCode = map[codes.Code]int{
 	codes.Code(sharedv1.ErrorCode_ERROR_CODE_PERMISSION_DENIED):    http.StatusForbidden,
 	codes.Code(sharedv1.ErrorCode_ERROR_CODE_INVALID_USER):         http.StatusUnauthorized,
 	codes.Code(sharedv1.ErrorCode_ERROR_CODE_PROJECT_OUT_OF_DATE):  http.StatusBadRequest,
+	codes.Code(sharedv1.ErrorCode_ERROR_CODE_QUOTA_EXCEEDED):       http.StatusTooManyRequests,
+	codes.Code(sharedv1.ErrorCode_ERROR_CODE_DOC_VERSION_CONFLICT): http.StatusConflict,
 }
 
 func makeErrorFunc(
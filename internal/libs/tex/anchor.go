@@ -0,0 +1,299 @@
+package tex
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AnchorMatch is the span of a content that matches an anchor text. Start and End are rune offsets in the content.
+type AnchorMatch struct {
+	Start      int
+	End        int
+	Text       string  // The text of the content in the span
+	Similarity float64 // 1 for an exact match
+}
+
+// MatchAnchor finds the span of content that best matches the anchor text, with a similarity of at least
+// minSimilarity. Both texts are compared after normalization, so that whitespace, ~, comments, braces and the names
+// of commands such as \emph do not count, nor does the case. The similarity is 1 minus the edit distance of the
+// normalized texts divided by the length of the longer one.
+//
+// Candidate regions are found with a bit-parallel (bitap) search, which finds the offsets where a span within the
+// allowed edit distance ends, and are then verified with a banded edit distance to find the best span. The cost is
+// linear in the length of the content, except where it contains many near matches.
+func MatchAnchor(content string, anchor string, minSimilarity float64) (AnchorMatch, bool) {
+	if anchor == "" {
+		return AnchorMatch{}, false
+	}
+	if i := strings.Index(content, anchor); i >= 0 {
+		start := utf8.RuneCountInString(content[:i])
+		return AnchorMatch{Start: start, End: start + utf8.RuneCountInString(anchor), Text: anchor, Similarity: 1}, true
+	}
+
+	text := normalizeAnchorText(content, false)
+	pattern := normalizeAnchorText(anchor, true)
+	m := len(pattern.runes)
+	if m == 0 || len(text.runes) == 0 {
+		return AnchorMatch{}, false
+	}
+
+	// Spans with more than maxDistance edits are below minSimilarity, whatever their length
+	maxDistance := int((1 - minSimilarity) * float64(m))
+	if maxDistance >= m {
+		maxDistance = m - 1
+	}
+
+	best := anchorCandidate{distance: -1}
+	normalized := string(text.runes)
+	if i := strings.Index(normalized, string(pattern.runes)); i >= 0 {
+		start := utf8.RuneCountInString(normalized[:i])
+		best = anchorCandidate{start: start, end: start + m, distance: 0, similarity: 1}
+	} else {
+		for _, region := range bitapRegions(text.runes, pattern.runes, maxDistance) {
+			candidate := matchRegion(text.runes[region[0]:region[1]], pattern.runes, maxDistance)
+			if candidate.distance >= 0 && (best.distance < 0 || candidate.similarity > best.similarity) {
+				candidate.start += region[0]
+				candidate.end += region[0]
+				best = candidate
+			}
+		}
+	}
+	if best.distance < 0 || best.similarity < minSimilarity {
+		return AnchorMatch{}, false
+	}
+
+	// The span is extended over the combining marks following it, so that no character is cut, and over the
+	// braces closing the groups it opens
+	runes := []rune(content)
+	start, end := text.starts[best.start], text.ends[best.end-1]
+	for end < len(runes) && unicode.Is(unicode.Mn, runes[end]) {
+		end++
+	}
+	open := 0
+	for _, r := range runes[start:end] {
+		switch r {
+		case '{':
+			open++
+		case '}':
+			open--
+		}
+	}
+	for ; open > 0 && end < len(runes) && runes[end] == '}'; open-- {
+		end++
+	}
+	return AnchorMatch{Start: start, End: end, Text: string(runes[start:end]), Similarity: best.similarity}, true
+}
+
+// normalizedText is a text normalized for anchor matching. The rune i of the normalized text comes from the runes
+// starts[i] to ends[i] of the original text.
+type normalizedText struct {
+	runes  []rune
+	starts []int
+	ends   []int
+}
+
+func (t *normalizedText) add(r rune, start int, end int) {
+	if unicode.IsSpace(r) {
+		if len(t.runes) == 0 || t.runes[len(t.runes)-1] == ' ' {
+			return
+		}
+		r = ' '
+	}
+	t.runes = append(t.runes, unicode.ToLower(r))
+	t.starts = append(t.starts, start)
+	t.ends = append(t.ends, end)
+}
+
+// addText adds the runes of text, which starts at the rune offset of the original text.
+func (t *normalizedText) addText(text string, offset int) {
+	for _, r := range text {
+		t.add(r, offset, offset+1)
+		offset++
+	}
+}
+
+// normalizeAnchorText lowers the case of the text and drops the LaTeX markup that does not change how it reads:
+// comments, braces and command names are dropped, and runs of whitespace and ~ become one space. Escaped characters,
+// such as \%, are kept. The comments are kept if keepComments is true, for anchors that are plain text in which
+// % is not a comment.
+func normalizeAnchorText(content string, keepComments bool) *normalizedText {
+	t := &normalizedText{
+		runes:  make([]rune, 0, len(content)),
+		starts: make([]int, 0, len(content)),
+		ends:   make([]int, 0, len(content)),
+	}
+	offset := 0 // The rune offset of the token
+	for _, token := range Tokenize(content) {
+		length := utf8.RuneCountInString(token.Text)
+		switch {
+		case token.Kind == TokenComment && keepComments:
+			t.addText(token.Text, offset)
+		case token.Kind == TokenComment, token.Kind == TokenBeginGroup, token.Kind == TokenEndGroup:
+		case token.Kind == TokenSpace:
+			t.add(' ', offset, offset+length)
+		case token.Kind == TokenCommand:
+			name := token.CommandName()
+			switch {
+			case name == `\` || name == " ":
+				t.add(' ', offset, offset+length)
+			case len(name) == 1 && strings.Contains(`%&$#_{}`, name):
+				t.add([]rune(name)[0], offset, offset+length)
+			}
+		case token.Kind == TokenSpecial:
+			if token.Text == "~" {
+				t.add(' ', offset, offset+length)
+			} else {
+				t.add(rune(token.Text[0]), offset, offset+1)
+			}
+		default:
+			t.addText(token.Text, offset)
+		}
+		offset += length
+	}
+	if n := len(t.runes); n > 0 && t.runes[n-1] == ' ' {
+		t.runes, t.starts, t.ends = t.runes[:n-1], t.starts[:n-1], t.ends[:n-1]
+	}
+	return t
+}
+
+// bitapRegions returns the regions of the text that contain a span within maxDistance edits of the pattern. The
+// edit distance of the best span ending at each offset of the text is computed with the bit-parallel algorithm of
+// Myers, in blocks of 64 rows for the patterns longer than a word, and each end within maxDistance gives a region
+// long enough to contain its span.
+func bitapRegions(text []rune, pattern []rune, maxDistance int) [][2]int {
+	m := len(pattern)
+	blocks := (m + 63) / 64
+	lastBit := uint64(1) << ((m - 1) % 64) // The bit of the last row in the last block
+
+	// equal[r] has the bits of the rows of the pattern equal to r
+	equal := map[rune][]uint64{}
+	for i, r := range pattern {
+		if equal[r] == nil {
+			equal[r] = make([]uint64, blocks)
+		}
+		equal[r][i/64] |= 1 << (i % 64)
+	}
+	none := make([]uint64, blocks)
+
+	// The vertical deltas of the column, +1 down the first column as the distance of row i is i
+	positive, negative := make([]uint64, blocks), make([]uint64, blocks)
+	for b := range positive {
+		positive[b] = ^uint64(0)
+	}
+
+	var regions [][2]int
+	distance := m
+	for j, r := range text {
+		eq, ok := equal[r]
+		if !ok {
+			eq = none
+		}
+		carry := 0 // The horizontal delta of the row above the block, 0 in the first row as a span can start anywhere
+		for b := range blocks {
+			highBit := uint64(1) << 63
+			if b == blocks-1 {
+				highBit = lastBit
+			}
+			carry = advanceBitapBlock(&positive[b], &negative[b], eq[b], carry, highBit)
+		}
+		distance += carry
+
+		if distance <= maxDistance {
+			start, end := max(0, j+1-m-maxDistance), j+1
+			if n := len(regions); n > 0 && regions[n-1][1] >= start {
+				regions[n-1][1] = end
+			} else {
+				regions = append(regions, [2]int{start, end})
+			}
+		}
+	}
+	return regions
+}
+
+// advanceBitapBlock computes the vertical deltas of a block of rows for the next column, given the horizontal delta
+// entering the block from above, and returns the horizontal delta of the row of highBit.
+func advanceBitapBlock(positive *uint64, negative *uint64, eq uint64, carry int, highBit uint64) int {
+	pv, mv := *positive, *negative
+	xv := eq | mv
+	if carry < 0 {
+		eq |= 1
+	}
+	xh := (((eq & pv) + pv) ^ pv) | eq
+	ph := mv | ^(xh | pv)
+	mh := pv & xh
+
+	out := 0
+	if ph&highBit != 0 {
+		out = 1
+	} else if mh&highBit != 0 {
+		out = -1
+	}
+
+	ph <<= 1
+	mh <<= 1
+	if carry < 0 {
+		mh |= 1
+	} else if carry > 0 {
+		ph |= 1
+	}
+	*positive = mh | ^(xv | ph)
+	*negative = ph & xv
+	return out
+}
+
+type anchorCandidate struct {
+	start      int
+	end        int
+	distance   int // -1 if there is no candidate
+	similarity float64
+}
+
+// matchRegion returns the span of the text with the best similarity to the pattern among those within maxDistance
+// edits, by approximate string matching: the edit distance is computed with the pattern matching anywhere in the
+// text. Only the band of rows whose distance is at most maxDistance is computed (Ukkonen's cutoff).
+func matchRegion(text []rune, pattern []rune, maxDistance int) anchorCandidate {
+	m := len(pattern)
+	// previous and current are the columns of the distances, and of the starts of the spans they are for
+	previous, current := make([]int, m+1), make([]int, m+1)
+	previousStart, currentStart := make([]int, m+1), make([]int, m+1)
+	for i := range previous {
+		previous[i] = i // The spans of the first column all start at 0
+	}
+	lastActive := min(maxDistance, m)
+
+	best := anchorCandidate{distance: -1}
+	for j := 1; j <= len(text); j++ {
+		current[0], currentStart[0] = 0, j
+		top := min(m, lastActive+1)
+		for i := 1; i <= top; i++ {
+			cost := 1
+			if pattern[i-1] == text[j-1] {
+				cost = 0
+			}
+			distance, start := previous[i-1]+cost, previousStart[i-1]
+			if current[i-1]+1 < distance {
+				distance, start = current[i-1]+1, currentStart[i-1]
+			}
+			if i <= lastActive && previous[i]+1 < distance {
+				distance, start = previous[i]+1, previousStart[i]
+			}
+			current[i], currentStart[i] = distance, start
+		}
+		lastActive = top
+		for lastActive > 0 && current[lastActive] > maxDistance {
+			lastActive--
+		}
+
+		if lastActive == m {
+			distance, start := current[m], currentStart[m]
+			similarity := 1 - float64(distance)/float64(max(m, j-start))
+			if similarity > best.similarity || best.distance < 0 {
+				best = anchorCandidate{start: start, end: j, distance: distance, similarity: similarity}
+			}
+		}
+		previous, current = current, previous
+		previousStart, currentStart = currentStart, previousStart
+	}
+	return best
+}
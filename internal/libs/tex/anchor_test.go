@@ -0,0 +1,173 @@
+package tex
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"paperdebugger/internal/libs/stringutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchAnchor(t *testing.T) {
+	const content = "\\section{Introduction}\n" +
+		"Die Größe des Modells~\\cite{smith2020} ist \\emph{entscheidend} für die Genauigkeit.\n" +
+		"% A comment about the results\n" +
+		"We show that the naïve approach fails on 数据集 with 10\\% noise.\n"
+
+	tests := []struct {
+		name   string
+		anchor string
+		text   string
+		exact  bool
+	}{
+		{"exact", "ist \\emph{entscheidend}", "ist \\emph{entscheidend}", true},
+		{"markup", "ist entscheidend für die", "ist \\emph{entscheidend} für die", false},
+		{"tilde and case", "die größe des modells \\cite{smith2020}", "Die Größe des Modells~\\cite{smith2020}", false},
+		{"typo", "the naive approach fails on 数据集", "the naïve approach fails on 数据集", false},
+		{"line break", "Genauigkeit. We show", "Genauigkeit.\n% A comment about the results\nWe show", false},
+		{"escape", "with 10% noise", "with 10\\% noise", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, ok := MatchAnchor(content, tt.anchor, 0.65)
+			assert.True(t, ok)
+			assert.Equal(t, tt.text, match.Text)
+			runes := []rune(content)
+			assert.Equal(t, tt.text, string(runes[match.Start:match.End]))
+			assert.Equal(t, tt.exact, match.Similarity == 1 && match.Text == tt.anchor)
+		})
+	}
+
+	_, ok := MatchAnchor(content, "an unrelated sentence about graphs", 0.65)
+	assert.False(t, ok)
+	_, ok = MatchAnchor(content, "", 0.65)
+	assert.False(t, ok)
+}
+
+func TestMatchAnchorBestSpan(t *testing.T) {
+	content := "the model is fast. the modal is fist. the model is fust."
+	match, ok := MatchAnchor(content, "the model is fost", 0.65)
+	assert.True(t, ok)
+	assert.Equal(t, "the model is fast", match.Text)
+	assert.Equal(t, 0, match.Start)
+	assert.InDelta(t, 1-1.0/17, match.Similarity, 1e-9)
+}
+
+// TestBitapRegions checks that the regions of the bitap search contain the best span found by a scan of the whole
+// text, including for patterns longer than a word.
+func TestBitapRegions(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomRunes := func(n int) []rune {
+		runes := make([]rune, n)
+		for i := range runes {
+			runes[i] = []rune("abcé数")[random.Intn(5)]
+		}
+		return runes
+	}
+	for range 200 {
+		text := randomRunes(50 + random.Intn(300))
+		pattern := randomRunes(1 + random.Intn(150))
+		maxDistance := random.Intn(len(pattern))
+
+		expected := matchRegion(text, pattern, maxDistance)
+		best := anchorCandidate{distance: -1}
+		for _, region := range bitapRegions(text, pattern, maxDistance) {
+			candidate := matchRegion(text[region[0]:region[1]], pattern, maxDistance)
+			if candidate.distance >= 0 && (best.distance < 0 || candidate.similarity > best.similarity) {
+				best = candidate
+			}
+		}
+		assert.Equal(t, expected.distance < 0, best.distance < 0)
+		assert.Equal(t, expected.similarity, best.similarity)
+	}
+}
+
+// benchmarkChapter returns a chapter of about 200 KB and an anchor in its middle, with a typo and different markup.
+func benchmarkChapter() (string, string) {
+	words := strings.Fields("the model learns a representation of each token from its context and we evaluate " +
+		"größe naïve 数据 results on several benchmarks with different noise levels across languages")
+	random := rand.New(rand.NewSource(1))
+	var chapter strings.Builder
+	for chapter.Len() < 200_000 {
+		for i := 0; i < 12; i++ {
+			chapter.WriteString(words[random.Intn(len(words))] + " ")
+		}
+		chapter.WriteString(fmt.Sprintf("\\cite{ref%d}.\n", random.Intn(1000)))
+	}
+	content := chapter.String()
+	content += "In contrast, the proposed \\emph{sparse attention} reduces the memory~cost by half.\n" + content
+	return content, "In contrast, the proposed sparse atention reduces the memory cost by half"
+}
+
+func BenchmarkMatchAnchor(b *testing.B) {
+	content, anchor := benchmarkChapter()
+	b.ResetTimer()
+	for range b.N {
+		if _, ok := MatchAnchor(content, anchor, 0.65); !ok {
+			b.Fatal("anchor not found")
+		}
+	}
+}
+
+// BenchmarkLegacyFuzzyMatch measures the matcher that MatchAnchor replaces in ReverseCommentService, which computes
+// the Levenshtein similarity of a byte window at every offset of the content.
+func BenchmarkLegacyFuzzyMatch(b *testing.B) {
+	content, anchor := benchmarkChapter()
+	b.ResetTimer()
+	for range b.N {
+		legacyFuzzyMatchPosition(content, anchor)
+	}
+}
+
+func legacyFuzzyMatchPosition(docContent, anchorText string) (int, string) {
+	windowSize := len(anchorText)
+	chunkSize := 1000
+	numChunks := (len(docContent) - windowSize) / chunkSize
+	if numChunks == 0 {
+		numChunks = 1
+	}
+
+	type matchResult struct {
+		position    int
+		similarity  float64
+		matchedText string
+	}
+	resultChan := make(chan matchResult, numChunks)
+	var wg sync.WaitGroup
+	for i := 0; i < numChunks; i++ {
+		wg.Add(1)
+		go func(chunkIndex int) {
+			defer wg.Done()
+			start := chunkIndex * chunkSize
+			end := min(start+chunkSize, len(docContent)-windowSize)
+			bestResult := matchResult{position: -1}
+			for j := start; j < end; j++ {
+				window := docContent[j : j+windowSize]
+				if similarity := stringutil.Similarity(window, anchorText); similarity > bestResult.similarity {
+					bestResult = matchResult{position: j, similarity: similarity, matchedText: window}
+				}
+			}
+			resultChan <- bestResult
+		}(i)
+	}
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	best := matchResult{position: -1}
+	for result := range resultChan {
+		if result.similarity > best.similarity {
+			best = result
+		}
+	}
+	if best.similarity < 0.65 {
+		return -1, ""
+	}
+	return utf8.RuneCountInString(docContent[:best.position]), best.matchedText
+}
@@ -0,0 +1,123 @@
+package tex
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	entryStartRe  = regexp.MustCompile(`(?i)^\s*@(\w+)\s*\{`)  // eg. @article{
+	stringEntryRe = regexp.MustCompile(`(?i)^\s*@String\s*\{`) // eg. @String{
+	entryKeyRe    = regexp.MustCompile(`^\s*@(\w+)\s*\{\s*([^,\s{}]+)`)
+
+	// Fields to exclude from bibliography (not useful for citation matching)
+	excludedFields = []string{
+		"address", "institution", "pages", "eprint", "primaryclass", "volume", "number",
+		"edition", "numpages", "articleno", "publisher", "editor", "doi", "url", "acmid",
+		"issn", "archivePrefix", "year", "month", "day", "eid", "lastaccessed", "organization",
+		"school", "isbn", "mrclass", "mrnumber", "mrreviewer", "type", "order_no", "location",
+		"howpublished", "distincturl", "issue_date", "archived", "series", "source",
+	}
+	excludeFieldRe = regexp.MustCompile(`(?i)^\s*(` + strings.Join(excludedFields, "|") + `)\s*=`)
+)
+
+// braceBalance returns the net brace count (opens - closes) in a string.
+func braceBalance(s string) int {
+	return strings.Count(s, "{") - strings.Count(s, "}")
+}
+
+// isQuoteUnclosed returns true if the string has an odd number of double quotes.
+func isQuoteUnclosed(s string) bool {
+	return strings.Count(s, `"`)%2 == 1
+}
+
+// ParseBibFile extracts bibliography entries from a .bib file's lines,
+// filtering out @String macros, comments, and excluded fields (url, doi, etc.).
+func ParseBibFile(lines []string) []string {
+	var entries []string
+	var currentEntry []string
+
+	// It handles multi-line field values by tracking brace/quote balance:
+	//   - skipBraces > 0: currently skipping a {bracketed} value, wait until balanced
+	//   - skipQuotes = true: currently skipping a "quoted" value, wait for closing quote
+
+	var entryDepth int  // brace depth for current entry (0 = entry complete)
+	var skipBraces int  // > 0 means we're skipping lines until braces balance
+	var skipQuotes bool // true means we're skipping lines until closing quote
+
+	for _, line := range lines {
+		// Skip empty lines and comments
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "%") {
+			continue
+		}
+
+		// If skipping a multi-line {bracketed} field value, keep skipping until balanced
+		if skipBraces > 0 {
+			skipBraces += braceBalance(line)
+			continue
+		}
+
+		// If skipping a multi-line "quoted" field value, keep skipping until closing quote
+		if skipQuotes {
+			if isQuoteUnclosed(line) { // odd quote count = found closing quote
+				skipQuotes = false
+			}
+			continue
+		}
+
+		// Skip @String{...} macro definitions
+		if stringEntryRe.MatchString(line) {
+			skipBraces = braceBalance(line)
+			continue
+		}
+
+		// Skip excluded fields (url, doi, pages, etc.) - may span multiple lines
+		if excludeFieldRe.MatchString(line) {
+			if strings.Contains(line, "={") || strings.Contains(line, "= {") {
+				skipBraces = braceBalance(line)
+			} else if strings.Contains(line, `="`) || strings.Contains(line, `= "`) {
+				skipQuotes = isQuoteUnclosed(line)
+			}
+			continue
+		}
+
+		// Start of new entry: @article{key, or @book{key, etc.
+		if entryStartRe.MatchString(line) {
+			if len(currentEntry) > 0 {
+				entries = append(entries, strings.Join(currentEntry, "\n"))
+			}
+			currentEntry = []string{line}
+			entryDepth = braceBalance(line)
+			continue
+		}
+
+		// Continue building current entry
+		if len(currentEntry) > 0 {
+			currentEntry = append(currentEntry, line)
+			entryDepth += braceBalance(line)
+			if entryDepth <= 0 { // entry complete when braces balance
+				entries = append(entries, strings.Join(currentEntry, "\n"))
+				currentEntry = nil
+			}
+		}
+	}
+
+	// Last entry if file doesn't end with balanced braces
+	if len(currentEntry) > 0 {
+		entries = append(entries, strings.Join(currentEntry, "\n"))
+	}
+	return entries
+}
+
+// BibEntryKey returns the citation key of an entry returned by ParseBibFile, or "" for @comment and @preamble.
+func BibEntryKey(entry string) string {
+	match := entryKeyRe.FindStringSubmatch(entry)
+	if match == nil {
+		return ""
+	}
+	switch strings.ToLower(match[1]) {
+	case "comment", "preamble", "string":
+		return ""
+	}
+	return match[2]
+}
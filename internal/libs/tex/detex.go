@@ -0,0 +1,323 @@
+package tex
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mathEnvironments are rendered as an equation placeholder.
+var mathEnvironments = map[string]bool{
+	"equation": true, "equation*": true,
+	"align": true, "align*": true,
+	"gather": true, "gather*": true,
+	"multline": true, "multline*": true,
+	"eqnarray": true, "eqnarray*": true,
+	"displaymath": true, "math": true,
+}
+
+// floatEnvironments are rendered as their captions.
+var floatEnvironments = map[string]string{
+	"figure": "Figure", "figure*": "Figure", "wrapfigure": "Figure",
+	"table": "Table", "table*": "Table",
+	"algorithm": "Algorithm", "algorithm*": "Algorithm",
+}
+
+// droppedCommands are the commands rendered as nothing, with the number of their arguments. Their optional
+// arguments are dropped as well.
+var droppedCommands = map[string]int{
+	"label": 1, "vspace": 1, "hspace": 1, "includegraphics": 1, "bibliographystyle": 1, "bibliography": 1,
+	"addbibresource": 1, "usepackage": 1, "documentclass": 1, "pagestyle": 1, "thispagestyle": 1, "setlength": 2,
+	"setcounter": 2, "addtolength": 2, "newtheorem": 2, "graphicspath": 1, "input": 1, "include": 1,
+	"includeonly": 1, "hypersetup": 1, "captionsetup": 1, "todo": 1, "linewidth": 0, "centering": 0,
+	"maketitle": 0, "tableofcontents": 0, "newpage": 0, "clearpage": 0, "noindent": 0, "small": 0, "footnotesize": 0,
+}
+
+// textCommands are rendered as text.
+var textCommands = map[string]string{
+	"LaTeX": "LaTeX", "TeX": "TeX", "ldots": "...", "dots": "...", "textbackslash": `\`, "S": "§", "P": "¶",
+	"textendash": "–", "textemdash": "—", "textasciitilde": "~",
+}
+
+var citeCommandRegex = regexp.MustCompile(`^(cite|citep|citet|citealp|citealt|citeauthor|citeyear|parencite|textcite|autocite|footcite|nocite)\*?$`)
+
+var refCommandRegex = regexp.MustCompile(`^(ref|eqref|autoref|cref|Cref|vref|Vref|labelcref|pageref|nameref)\*?$`)
+
+var blankLinesRegex = regexp.MustCompile(`\n{3,}`)
+
+// Detex renders LaTeX source as readable plain text. Sections become Markdown headings, math becomes [math] and
+// [equation] placeholders, citations and references become [cite: key] and [ref: label] markers, and floats are
+// reduced to their captions. Only the document body is rendered when the source has one, with its title.
+// Unknown commands are dropped, so the user macros must be expanded first, see ExpandMacros.
+func Detex(content string) string {
+	d := &detexer{content: content, tokens: Tokenize(content)}
+	start := 0
+	for i, token := range d.tokens {
+		if token.CommandName() != "begin" {
+			continue
+		}
+		if arg, next, ok := readGroup(d.tokens, skipSpaceTokens(d.tokens, i+1)); ok && content[arg[0]:arg[1]] == "document" {
+			d.renderTitle(i)
+			start = next
+			break
+		}
+	}
+	d.render(start, len(d.tokens))
+	return cleanPlainText(d.out.String())
+}
+
+type detexer struct {
+	content string
+	tokens  []Token
+	out     strings.Builder
+}
+
+// renderTitle renders the \title of the preamble, which ends at token end.
+func (d *detexer) renderTitle(end int) {
+	for i := 0; i < end; i++ {
+		if d.tokens[i].CommandName() != "title" {
+			continue
+		}
+		next := skipOptional(d.tokens, i+1)
+		if _, argEnd, ok := readGroup(d.tokens, next); ok {
+			d.out.WriteString("# ")
+			d.renderGroup(next, argEnd)
+			d.out.WriteString("\n\n")
+		}
+		return
+	}
+}
+
+// renderGroup renders the content of the group of tokens from start to end, end excluded.
+func (d *detexer) renderGroup(start int, end int) {
+	d.render(start+1, end-1)
+}
+
+// render renders the tokens from start to end, end excluded.
+func (d *detexer) render(start int, end int) {
+	for i := start; i < end; {
+		i = d.renderToken(i, end)
+	}
+}
+
+// renderToken renders the token at i, and the tokens it applies to. It returns the index of the next token.
+func (d *detexer) renderToken(i int, end int) int {
+	token := d.tokens[i]
+	switch token.Kind {
+	case TokenText, TokenVerbatim:
+		d.out.WriteString(token.Text)
+	case TokenSpace:
+		if strings.Count(token.Text, "\n") >= 2 {
+			d.out.WriteString("\n\n")
+		} else {
+			d.out.WriteString(" ")
+		}
+	case TokenSpecial:
+		switch token.Text {
+		case "~", "&":
+			d.out.WriteString(" ")
+		default:
+			d.out.WriteString(token.Text)
+		}
+	case TokenMathShift:
+		closing := d.find(i+1, end, func(t Token) bool { return t.Kind == TokenMathShift && t.Text == token.Text })
+		if token.Text == "$$" {
+			d.out.WriteString(" [equation] ")
+		} else {
+			d.out.WriteString("[math]")
+		}
+		return closing + 1
+	case TokenCommand:
+		return d.renderCommand(i, end)
+	}
+	return i + 1
+}
+
+// renderCommand renders the command at i with its arguments. It returns the index of the next token.
+func (d *detexer) renderCommand(i int, end int) int {
+	name := d.tokens[i].CommandName()
+	switch {
+	case name == "(" || name == "[":
+		closing := ")"
+		placeholder := "[math]"
+		if name == "[" {
+			closing, placeholder = "]", " [equation] "
+		}
+		d.out.WriteString(placeholder)
+		return d.find(i+1, end, func(t Token) bool { return t.CommandName() == closing }) + 1
+	case name == "begin":
+		return d.renderEnvironment(i, end)
+	case name == "end":
+		if _, next, ok := readGroup(d.tokens, skipSpaceTokens(d.tokens, i+1)); ok {
+			d.out.WriteString("\n\n")
+			return next
+		}
+	case name == "item":
+		d.out.WriteString("\n- ")
+		return skipOptional(d.tokens, i+1)
+	case citeCommandRegex.MatchString(name) || refCommandRegex.MatchString(name):
+		kind := "ref"
+		if citeCommandRegex.MatchString(name) {
+			kind = "cite"
+		}
+		next := skipOptional(d.tokens, skipOptional(d.tokens, i+1))
+		if arg, argEnd, ok := readGroup(d.tokens, next); ok {
+			keys := strings.Split(d.content[arg[0]:arg[1]], ",")
+			for k := range keys {
+				keys[k] = strings.TrimSpace(keys[k])
+			}
+			d.out.WriteString("[" + kind + ": " + strings.Join(keys, ", ") + "]")
+			return argEnd
+		}
+	case name == "footnote":
+		next := skipOptional(d.tokens, i+1)
+		if _, argEnd, ok := readGroup(d.tokens, next); ok {
+			d.out.WriteString(" (footnote: ")
+			d.renderGroup(next, argEnd)
+			d.out.WriteString(")")
+			return argEnd
+		}
+	case name == "href":
+		if _, next, ok := readGroup(d.tokens, i+1); ok {
+			return next // The link text is rendered as a group
+		}
+	case sectionLevels[name] > 0 || name == "part":
+		if section, next, ok := parseSection(d.content, d.tokens, i, sectionLevels[name]); ok {
+			d.out.WriteString("\n\n" + strings.Repeat("#", min(max(section.Level, 1), 4)) + " ")
+			d.out.WriteString(plainText(Tokenize(section.Title)))
+			d.out.WriteString("\n\n")
+			return next
+		}
+	case name == "newcommand" || name == "renewcommand" || name == "providecommand" || name == "DeclareMathOperator" ||
+		name == "def" || name == "gdef" || name == "edef" || name == "xdef":
+		if _, next, _, ok := parseMacroDefinition(d.content, d.tokens, i); ok {
+			return next
+		}
+	case textCommands[name] != "":
+		d.out.WriteString(textCommands[name])
+	case len(name) == 1 && !isLetter(name[0]):
+		if strings.Contains("&%$#_{}", name) {
+			d.out.WriteString(name)
+		} else {
+			d.out.WriteString(" ") // A line break or a space
+		}
+	default:
+		if count, ok := droppedCommands[name]; ok {
+			next := i + 1
+			if next < len(d.tokens) && d.tokens[next].Kind == TokenSpecial && d.tokens[next].Text == "*" {
+				next++
+			}
+			for range count {
+				next = skipOptional(d.tokens, next)
+				_, argEnd, ok := readGroup(d.tokens, skipSpaceTokens(d.tokens, next))
+				if !ok {
+					break
+				}
+				next = argEnd
+			}
+			return skipOptional(d.tokens, next)
+		}
+	}
+	// Other commands are dropped, and their arguments rendered as groups
+	return i + 1
+}
+
+// renderEnvironment renders the environment beginning at i. It returns the index of the next token.
+func (d *detexer) renderEnvironment(i int, end int) int {
+	arg, next, ok := readGroup(d.tokens, skipSpaceTokens(d.tokens, i+1))
+	if !ok {
+		return i + 1
+	}
+	name := strings.TrimSpace(d.content[arg[0]:arg[1]])
+	closing := d.findEnd(next, end, name)
+
+	if mathEnvironments[name] {
+		d.out.WriteString(" [equation] ")
+		return closing
+	}
+	if label, ok := floatEnvironments[name]; ok {
+		// Only the captions of floats are rendered
+		for j := next; j < closing; j++ {
+			if d.tokens[j].CommandName() != "caption" {
+				continue
+			}
+			captionStart := skipOptional(d.tokens, j+1)
+			if _, captionEnd, ok := readGroup(d.tokens, captionStart); ok {
+				d.out.WriteString("\n\n[" + label + ": ")
+				d.renderGroup(captionStart, captionEnd)
+				d.out.WriteString("]\n\n")
+				j = captionEnd - 1
+			}
+		}
+		return closing
+	}
+
+	switch name {
+	case "abstract":
+		d.out.WriteString("\n\n## Abstract\n\n")
+	case "tabular", "tabular*", "tabularx":
+		// The column specification is not text
+		for range strings.Count(name, "*") + strings.Count(name, "x") + 1 {
+			if _, argEnd, ok := readGroup(d.tokens, skipSpaceTokens(d.tokens, next)); ok {
+				next = argEnd
+			}
+		}
+	case "thebibliography":
+		if _, argEnd, ok := readGroup(d.tokens, skipSpaceTokens(d.tokens, next)); ok {
+			next = argEnd
+		}
+	default:
+		d.out.WriteString("\n\n")
+	}
+	return skipOptional(d.tokens, next)
+}
+
+// find returns the index of the first token from start to end matching match, or end - 1 if there is none.
+func (d *detexer) find(start int, end int, match func(Token) bool) int {
+	for i := start; i < end; i++ {
+		if match(d.tokens[i]) {
+			return i
+		}
+	}
+	return end - 1
+}
+
+// findEnd returns the index of the token after the \end{name} closing the environment whose content starts at
+// start, or end if it is not closed.
+func (d *detexer) findEnd(start int, end int, name string) int {
+	depth := 0
+	for i := start; i < end; i++ {
+		command := d.tokens[i].CommandName()
+		if command != "begin" && command != "end" {
+			continue
+		}
+		arg, next, ok := readGroup(d.tokens, skipSpaceTokens(d.tokens, i+1))
+		if !ok || strings.TrimSpace(d.content[arg[0]:arg[1]]) != name {
+			continue
+		}
+		if command == "begin" {
+			depth++
+		} else if depth == 0 {
+			return next
+		} else {
+			depth--
+		}
+	}
+	return end
+}
+
+// skipOptional returns the index of the token after the optional argument at i, or i if there is none.
+func skipOptional(tokens []Token, i int) int {
+	if _, end, ok := readOptionalAt(tokens, i); ok {
+		return end
+	}
+	return i
+}
+
+// cleanPlainText collapses the spaces of each line and the blank lines.
+func cleanPlainText(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankLinesRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,61 @@
+package tex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetex(t *testing.T) {
+	const source = `\documentclass{article}
+\usepackage{amsmath}
+\newcommand{\method}{FooNet}
+\title{A \emph{Great} Paper}
+\begin{document}
+\maketitle
+\begin{abstract}
+We study things.
+\end{abstract}
+\section{Introduction}\label{sec:intro}
+Prior work~\cite{smith2020, doe2021} uses $x^2$ and \(y\), see Section~\ref{sec:method}.
+% A comment
+It is \textbf{fast}\footnote{Really.}.
+
+\begin{figure}[t]
+  \centering
+  \includegraphics[width=\linewidth]{plot.pdf}
+  \caption{Results of \method.}
+  \label{fig:results}
+\end{figure}
+\subsection*{Setup}
+\begin{equation}
+  E = mc^2
+\end{equation}
+\begin{itemize}
+  \item First \& second
+  \item[b)] Third
+\end{itemize}
+See \href{https://example.com}{the site}.
+\end{document}`
+
+	assert.Equal(t, `# A Great Paper
+
+## Abstract
+
+We study things.
+
+## Introduction
+
+Prior work [cite: smith2020, doe2021] uses [math] and [math], see Section [ref: sec:method]. It is fast (footnote: Really.).
+
+[Figure: Results of FooNet.]
+
+### Setup
+
+[equation]
+
+- First & second
+- Third
+
+See the site.`, Detex(ExpandMacros(source)))
+}
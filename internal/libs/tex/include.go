@@ -0,0 +1,148 @@
+package tex
+
+import (
+	"regexp"
+	"strings"
+)
+
+// include is an include command in a line.
+type include struct {
+	start, end  int    // Offsets of the command in the line
+	file        string // The included file, as written
+	dir         string // The directory of the import package commands
+	relativeDir bool   // Whether dir is relative to the including doc (\subimport), or to the root doc (\import)
+	include     bool   // Whether the command is subject to \includeonly
+	then        []int  // Offsets of the branch of \InputIfFileExists used when the file exists
+	orElse      []int  // Offsets of the branch of \InputIfFileExists used when the file does not exist
+}
+
+// includeCommandRegex matches the name of an include command, with the character ending it unless the line ends.
+var includeCommandRegex = regexp.MustCompile(`\\(input|include|subfile|InputIfFileExists|import|subimport|inputfrom|subinputfrom|includefrom|subincludefrom)(?:[^a-zA-Z@]|$)`)
+
+// findIncludes returns the include commands of a line, in order. Commands missing their arguments are skipped.
+func findIncludes(line string) []include {
+	var includes []include
+	pos := 0
+	for pos < len(line) {
+		match := includeCommandRegex.FindStringSubmatchIndex(line[pos:])
+		if match == nil {
+			break
+		}
+		start, nameEnd := pos+match[0], pos+match[3]
+		if isEscaped(line, start) {
+			pos = nameEnd
+			continue
+		}
+		inc, ok := parseInclude(line, start, nameEnd, line[pos+match[2]:nameEnd])
+		if !ok {
+			pos = nameEnd
+			continue
+		}
+		includes = append(includes, inc)
+		pos = inc.end
+	}
+	return includes
+}
+
+// parseInclude parses the arguments of the include command name, starting at pos.
+func parseInclude(line string, start int, pos int, name string) (include, bool) {
+	inc := include{start: start}
+	switch name {
+	case "input":
+		pos = skipSpaces(line, pos)
+		if pos < len(line) && line[pos] != '{' {
+			// \input file, the file name ends at a space or at the next token
+			end := pos
+			for end < len(line) && !strings.ContainsRune(" \t{}\\", rune(line[end])) {
+				end++
+			}
+			if end == pos {
+				return include{}, false
+			}
+			inc.file, inc.end = line[pos:end], end
+			return inc, true
+		}
+		fallthrough
+	case "include", "subfile":
+		args, end, ok := readArgs(line, pos, 1)
+		if !ok {
+			return include{}, false
+		}
+		inc.file, inc.end, inc.include = argument(line, args[0]), end, name == "include"
+	case "InputIfFileExists":
+		args, end, ok := readArgs(line, pos, 3)
+		if !ok {
+			return include{}, false
+		}
+		inc.file, inc.end, inc.then, inc.orElse = argument(line, args[0]), end, args[1], args[2]
+	default: // The import package commands
+		if pos < len(line) && line[pos] == '*' {
+			pos++
+		}
+		args, end, ok := readArgs(line, pos, 2)
+		if !ok {
+			return include{}, false
+		}
+		inc.dir, inc.file, inc.end = argument(line, args[0]), argument(line, args[1]), end
+		inc.relativeDir = strings.HasPrefix(name, "sub")
+		inc.include = strings.HasSuffix(name, "includefrom")
+	}
+	if inc.file == "" {
+		return include{}, false
+	}
+	return inc, true
+}
+
+// readArgs reads count brace groups from pos, and returns the offsets of their content and the end of the last one.
+func readArgs(line string, pos int, count int) ([][]int, int, bool) {
+	var args [][]int
+	for range count {
+		pos = skipSpaces(line, pos)
+		if pos >= len(line) || line[pos] != '{' {
+			return nil, 0, false
+		}
+		depth := 0
+		end := -1
+		for i := pos; i < len(line) && end < 0; i++ {
+			switch line[i] {
+			case '{':
+				if !isEscaped(line, i) {
+					depth++
+				}
+			case '}':
+				if !isEscaped(line, i) {
+					depth--
+					if depth == 0 {
+						end = i
+					}
+				}
+			}
+		}
+		if end < 0 {
+			return nil, 0, false
+		}
+		args = append(args, []int{pos + 1, end})
+		pos = end + 1
+	}
+	return args, pos, true
+}
+
+func argument(line string, arg []int) string {
+	return strings.TrimSpace(line[arg[0]:arg[1]])
+}
+
+func skipSpaces(line string, pos int) int {
+	for pos < len(line) && (line[pos] == ' ' || line[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
+
+// isEscaped returns whether the character at pos follows an odd number of backslashes.
+func isEscaped(line string, pos int) bool {
+	backslashes := 0
+	for i := pos - 1; i >= 0 && line[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
@@ -1,8 +1,10 @@
 package tex
 
 import (
+	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"paperdebugger/internal/libs/shared"
@@ -18,14 +20,33 @@ import (
 // preserved.
 var commentRegex = regexp.MustCompile(`(^|[^\\])((?:\\\\)*)%.*$`)
 
+// maxIncludeDepth is the depth at which includes are no longer expanded.
+const maxIncludeDepth = 10
+
+var includeOnlyRegex = regexp.MustCompile(`\\includeonly\s*\{([^}]*)\}`)
+
+// inputPathRegex matches a definition of the search paths of \input, in the \graphicspath syntax:
+// \def\input@path{{dir1/}{dir2/}}.
+var inputPathRegex = regexp.MustCompile(`\\[egx]?def\s*\\input@path\s*\{((?:\{[^{}]*\}\s*)*)\}`)
+
+var groupRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// cleanLine strips the comment and the surrounding spaces of a line. It returns the cleaned line and its byte
+// offset in the line.
+func cleanLine(line string) (string, int) {
+	trimmed := strings.TrimLeft(line, " \t\r\n\v\f")
+	start := len(line) - len(trimmed)
+	// Removing the comment only shortens the end of the line, the cleaned line starts at start.
+	cleaned := commentRegex.ReplaceAllString(strings.TrimSpace(trimmed), "$1$2")
+	return strings.TrimSpace(cleaned), start
+}
+
 func removeComments(text string) string {
 	// Split into lines, trim each line and filter empty ones
 	lines := strings.Split(text, "\n")
 	var result []string
 	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		cleaned := commentRegex.ReplaceAllString(trimmed, "$1$2")
-		cleaned = strings.TrimSpace(cleaned)
+		cleaned, _ := cleanLine(line)
 		if len(cleaned) == 0 {
 			continue
 		}
@@ -34,44 +55,231 @@ func removeComments(text string) string {
 	return strings.Join(result, "\n")
 }
 
-func expandWithDepth(content string, docs map[string]string, rootDocDir string, depth int) string {
-	// max depth is 10
-	if depth >= 10 {
-		return content
+type IncludeWarningKind string
+
+const (
+	IncludeNotFound IncludeWarningKind = "not_found" // No doc matches the included file
+	IncludeCycle    IncludeWarningKind = "cycle"     // The included doc is already being expanded
+	IncludeTooDeep  IncludeWarningKind = "too_deep"  // The include is nested more than maxIncludeDepth times
+)
+
+// IncludeWarning is an include left as-is in the expanded content.
+type IncludeWarning struct {
+	Kind     IncludeWarningKind
+	Command  string         // The include command, e.g. \subimport{chapters/}{intro}
+	Target   string         // The path of the included doc, or the included file if it was not found
+	Location SourceLocation // The location of the command
+}
+
+func (w IncludeWarning) String() string {
+	return fmt.Sprintf("%s:%d: %s", w.Location.Filepath, w.Location.Line, w.Message())
+}
+
+// Message returns the warning without its location.
+func (w IncludeWarning) Message() string {
+	var reason string
+	switch w.Kind {
+	case IncludeNotFound:
+		reason = "file not found"
+	case IncludeCycle:
+		reason = fmt.Sprintf("%s includes itself", w.Target)
+	case IncludeTooDeep:
+		reason = fmt.Sprintf("more than %d nested includes", maxIncludeDepth)
+	default:
+		reason = string(w.Kind)
+	}
+	return fmt.Sprintf("%s not expanded: %s", w.Command, reason)
+}
+
+// Expansion is the result of Expand.
+type Expansion struct {
+	Content   string
+	SourceMap *SourceMap
+	Warnings  []IncludeWarning
+}
+
+// expander writes the expansion of the docs, and records where each part of it comes from if sourceMap is not nil.
+type expander struct {
+	docs        map[string]string
+	rootDocDir  string
+	searchPaths []string        // Directories of \input@path, relative to rootDocDir
+	includeOnly map[string]bool // Files of \includeonly, nil when all files are included
+	stack       []string        // Docs being expanded, to detect cycles
+	out         strings.Builder
+	sourceMap   *SourceMap
+	warnings    []IncludeWarning
+}
+
+// write appends text, taken from line and column of the doc at path.
+func (e *expander) write(text string, path string, line int, column int) {
+	if text == "" {
+		return
+	}
+	if e.sourceMap != nil {
+		e.sourceMap.add(e.out.Len(), path, line, column)
+	}
+	e.out.WriteString(text)
+}
+
+// expandDoc writes the lines of the doc without comments, with the included docs spliced in. Relative includes of
+// the doc are resolved in dir.
+func (e *expander) expandDoc(path string, dir string, content string) {
+	e.stack = append(e.stack, path)
+	defer func() { e.stack = e.stack[:len(e.stack)-1] }()
+
+	first := true
+	for i, line := range strings.Split(content, "\n") {
+		cleaned, column := cleanLine(line)
+		if cleaned == "" {
+			continue
+		}
+		if !first {
+			e.out.WriteString("\n")
+		}
+		first = false
+		e.readSettings(cleaned)
+		e.expandLine(path, dir, cleaned, i+1, column+1)
+	}
+}
+
+// readSettings reads the \includeonly and \input@path settings of a line.
+func (e *expander) readSettings(line string) {
+	if match := includeOnlyRegex.FindStringSubmatch(line); match != nil {
+		e.includeOnly = map[string]bool{}
+		for _, name := range strings.Split(match[1], ",") {
+			e.includeOnly[includeName(name)] = true
+		}
+	}
+	if match := inputPathRegex.FindStringSubmatch(line); match != nil {
+		e.searchPaths = nil
+		for _, group := range groupRegex.FindAllStringSubmatch(match[1], -1) {
+			if dir := strings.TrimSpace(group[1]); dir != "" {
+				e.searchPaths = append(e.searchPaths, dir)
+			}
+		}
 	}
+}
 
-	expanded := content
-	inputRegex := regexp.MustCompile(`\\(?:input|include|subfile)\{([^}]+)\}`)
-	matches := inputRegex.FindAllStringSubmatch(content, -1)
+func (e *expander) expandLine(path string, dir string, line string, lineNumber int, column int) {
+	next := 0
+	for _, inc := range findIncludes(line) {
+		command := line[inc.start:inc.end]
+		if inc.include && e.includeOnly != nil && !e.includeOnly[includeName(inc.file)] {
+			// Excluded by \includeonly, as LaTeX does
+			e.write(line[next:inc.start], path, lineNumber, column+next)
+			next = inc.end
+			continue
+		}
 
-	for _, match := range matches {
-		fullMatch := match[0]
-		filename := match[1]
+		var searchDirs []string
+		switch {
+		case inc.relativeDir:
+			searchDirs = []string{filepath.Join(dir, inc.dir)}
+		case inc.dir != "":
+			searchDirs = []string{filepath.Join(e.rootDocDir, inc.dir)}
+		default:
+			searchDirs = []string{dir}
+			for _, searchPath := range e.searchPaths {
+				searchDirs = append(searchDirs, filepath.Join(e.rootDocDir, searchPath))
+			}
+		}
+		includedPath, includedDir, ok := e.resolve(inc.file, searchDirs)
+		location := SourceLocation{Filepath: path, Line: lineNumber, Column: column + inc.start}
 
-		// Normalize filename
-		normalizedFilename := filename
-		if !strings.HasSuffix(filename, ".tex") {
-			normalizedFilename = filename + ".tex"
+		if !ok {
+			if inc.orElse == nil {
+				e.warnings = append(e.warnings, IncludeWarning{Kind: IncludeNotFound, Command: command, Target: inc.file, Location: location})
+				continue // Left as-is
+			}
+			// \InputIfFileExists falls back to its else branch
+			e.write(line[next:inc.start], path, lineNumber, column+next)
+			e.write(line[inc.orElse[0]:inc.orElse[1]], path, lineNumber, column+inc.orElse[0])
+			next = inc.end
+			continue
+		}
+		if slices.Contains(e.stack, includedPath) {
+			e.warnings = append(e.warnings, IncludeWarning{Kind: IncludeCycle, Command: command, Target: includedPath, Location: location})
+			continue
+		}
+		if len(e.stack) > maxIncludeDepth {
+			e.warnings = append(e.warnings, IncludeWarning{Kind: IncludeTooDeep, Command: command, Target: includedPath, Location: location})
+			continue
 		}
-		normalizedFilename = strings.TrimSpace(normalizedFilename)
-		normalizedFilename = filepath.Join(rootDocDir, normalizedFilename)
 
-		if includedContent, ok := docs[normalizedFilename]; ok {
-			expandedContent := expandWithDepth(removeComments(includedContent), docs, rootDocDir, depth+1)
-			expanded = strings.Replace(expanded, fullMatch, expandedContent, -1)
+		e.write(line[next:inc.start], path, lineNumber, column+next)
+		if inc.then != nil {
+			e.write(line[inc.then[0]:inc.then[1]], path, lineNumber, column+inc.then[0])
 		}
+		if inc.dir == "" {
+			// Only the import package changes the directory of the includes of the included doc
+			includedDir = dir
+		}
+		e.expandDoc(includedPath, includedDir, e.docs[includedPath])
+		next = inc.end
 	}
+	e.write(line[next:], path, lineNumber, column+next)
+}
 
-	return expanded
+// resolve returns the path of the doc of the included file, searched in dirs, and the dir it was found in. As LaTeX
+// does, the file is searched with the .tex extension first.
+func (e *expander) resolve(file string, dirs []string) (string, string, bool) {
+	names := []string{file}
+	if !strings.HasSuffix(file, ".tex") {
+		names = []string{file + ".tex", file}
+	}
+	for _, dir := range dirs {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if _, ok := e.docs[path]; ok {
+				return path, dir, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// includeName normalizes a file of \include and \includeonly.
+func includeName(file string) string {
+	return strings.TrimSuffix(filepath.Clean(strings.TrimSpace(file)), ".tex")
+}
+
+// Expand returns the content of the root doc without comments, with the included docs spliced in. It handles
+// \input (with or without braces), \include and \includeonly, \subfile, \InputIfFileExists, the \import,
+// \subimport and \*from commands of the import package, and the \input@path search paths. Includes that cannot be
+// expanded are left as-is and reported in the warnings.
+func Expand(docs map[string]string, rootDoc string) (*Expansion, error) {
+	return latexpand(docs, rootDoc, true)
 }
 
+// Latexpand returns the content of the root doc without comments, with the included docs spliced in.
 func Latexpand(docs map[string]string, rootDoc string) (string, error) {
-	content, ok := docs[rootDoc]
-	rootDocDir := filepath.Dir(rootDoc)
+	expansion, err := latexpand(docs, rootDoc, false)
+	if err != nil {
+		return "", err
+	}
+	return expansion.Content, nil
+}
+
+// LatexpandWithSourceMap is Latexpand, and also returns the map from the expanded content to the docs.
+func LatexpandWithSourceMap(docs map[string]string, rootDoc string) (string, *SourceMap, error) {
+	expansion, err := latexpand(docs, rootDoc, true)
+	if err != nil {
+		return "", nil, err
+	}
+	return expansion.Content, expansion.SourceMap, nil
+}
 
+func latexpand(docs map[string]string, rootDoc string, withSourceMap bool) (*Expansion, error) {
+	content, ok := docs[rootDoc]
 	if !ok {
-		return "", shared.ErrBadRequest("root doc not found")
+		return nil, shared.ErrBadRequest("root doc not found")
 	}
 
-	return expandWithDepth(removeComments(content), docs, rootDocDir, 0), nil
+	rootDocDir := filepath.Dir(rootDoc)
+	e := &expander{docs: docs, rootDocDir: rootDocDir}
+	if withSourceMap {
+		e.sourceMap = &SourceMap{}
+	}
+	e.expandDoc(rootDoc, rootDocDir, content)
+	return &Expansion{Content: e.out.String(), SourceMap: e.sourceMap, Warnings: e.warnings}, nil
 }
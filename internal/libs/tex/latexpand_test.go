@@ -1,6 +1,8 @@
 package tex
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -58,3 +60,131 @@ func TestLatexpand(t *testing.T) {
 Hello World!
 \end{document}`, expanded)
 }
+
+func TestLatexpandWithSourceMap(t *testing.T) {
+	input := map[string]string{
+		"main.tex":           "\\begin{document}\n  % comment\n  See \\input{sections/intro} here.\n\\end{document}",
+		"sections/intro.tex": "Intro line.\n\tSecond line. % comment",
+	}
+	expanded, sourceMap, err := LatexpandWithSourceMap(input, "main.tex")
+	assert.NoError(t, err)
+	assert.Equal(t, "\\begin{document}\nSee Intro line.\nSecond line. here.\n\\end{document}", expanded)
+
+	locate := func(text string) SourceLocation {
+		location, ok := sourceMap.Lookup(strings.Index(expanded, text))
+		assert.True(t, ok)
+		return location
+	}
+	assert.Equal(t, SourceLocation{Filepath: "main.tex", Line: 1, Column: 1}, locate("\\begin"))
+	assert.Equal(t, SourceLocation{Filepath: "main.tex", Line: 3, Column: 3}, locate("See"))
+	assert.Equal(t, SourceLocation{Filepath: "sections/intro.tex", Line: 1, Column: 7}, locate("line."))
+	assert.Equal(t, SourceLocation{Filepath: "sections/intro.tex", Line: 2, Column: 2}, locate("Second"))
+	assert.Equal(t, SourceLocation{Filepath: "main.tex", Line: 3, Column: 30}, locate("here"))
+	assert.Equal(t, SourceLocation{Filepath: "main.tex", Line: 4, Column: 1}, locate("\\end"))
+
+	start := strings.Index(expanded, "See")
+	assert.Equal(t, []SourceLocation{
+		{Filepath: "main.tex", Line: 3, Column: 3},
+		{Filepath: "sections/intro.tex", Line: 1, Column: 1},
+	}, sourceMap.LookupRange(start, start+len("See Intro line.")))
+
+	_, ok := sourceMap.Lookup(len(expanded) + 10)
+	assert.True(t, ok) // Past the end maps past the end of the last line
+	_, ok = sourceMap.Lookup(-1)
+	assert.False(t, ok)
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name     string
+		docs     map[string]string
+		expected string
+	}{
+		{
+			name:     "input without braces",
+			docs:     map[string]string{"main.tex": "A \\input intro B", "intro.tex": "Intro"},
+			expected: "A Intro B",
+		},
+		{
+			name: "InputIfFileExists",
+			docs: map[string]string{
+				"main.tex":  "\\InputIfFileExists{intro}{Found }{Missing}\n\\InputIfFileExists{missing}{Found }{Missing}",
+				"intro.tex": "Intro",
+			},
+			expected: "Found Intro\nMissing",
+		},
+		{
+			name: "import and subimport",
+			docs: map[string]string{
+				"paper/main.tex":                        "\\import{chapters/}{one}\n\\subimport*{chapters/two/}{two}",
+				"paper/chapters/one.tex":                "One \\input{figure}",
+				"paper/chapters/figure.tex":             "Figure",
+				"paper/chapters/two/two.tex":            "Two \\subimport{sections/}{intro}",
+				"paper/chapters/two/sections/intro.tex": "Intro",
+			},
+			expected: "One Figure\nTwo Intro",
+		},
+		{
+			name: "includeonly",
+			docs: map[string]string{
+				"main.tex":         "\\includeonly{chapters/one}\n\\include{chapters/one}\n\\include{chapters/two}\nEnd",
+				"chapters/one.tex": "One",
+				"chapters/two.tex": "Two",
+			},
+			expected: "\\includeonly{chapters/one}\nOne\n\nEnd",
+		},
+		{
+			name: "input search paths",
+			docs: map[string]string{
+				"main.tex":            "\\def\\input@path{{sections/}{appendix/}}\n\\input{intro}\n\\input{proofs}",
+				"sections/intro.tex":  "Intro",
+				"appendix/proofs.tex": "Proofs",
+			},
+			expected: "\\def\\input@path{{sections/}{appendix/}}\nIntro\nProofs",
+		},
+		{
+			name:     "escaped command",
+			docs:     map[string]string{"main.tex": "\\\\input intro", "intro.tex": "Intro"},
+			expected: "\\\\input intro",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootDoc := "main.tex"
+			if _, ok := tt.docs[rootDoc]; !ok {
+				rootDoc = "paper/main.tex"
+			}
+			expansion, err := Expand(tt.docs, rootDoc)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expansion.Content)
+			assert.Empty(t, expansion.Warnings)
+		})
+	}
+}
+
+func TestExpandWarnings(t *testing.T) {
+	docs := map[string]string{
+		"main.tex": "\\input{a}\n\\subimport{chapters/}{missing}",
+		"a.tex":    "A \\input{b}",
+		"b.tex":    "B \\input{a}",
+	}
+	expansion, err := Expand(docs, "main.tex")
+	assert.NoError(t, err)
+	assert.Equal(t, "A B \\input{a}\n\\subimport{chapters/}{missing}", expansion.Content)
+	assert.Equal(t, []IncludeWarning{
+		{Kind: IncludeCycle, Command: "\\input{a}", Target: "a.tex", Location: SourceLocation{Filepath: "b.tex", Line: 1, Column: 3}},
+		{Kind: IncludeNotFound, Command: "\\subimport{chapters/}{missing}", Target: "missing", Location: SourceLocation{Filepath: "main.tex", Line: 2, Column: 1}},
+	}, expansion.Warnings)
+	assert.Equal(t, "b.tex:1: \\input{a} not expanded: a.tex includes itself", expansion.Warnings[0].String())
+
+	docs = map[string]string{"main.tex": "\\input{doc0}"}
+	for i := range maxIncludeDepth + 1 {
+		docs[fmt.Sprintf("doc%d.tex", i)] = fmt.Sprintf("%d \\input{doc%d}", i, i+1)
+	}
+	docs[fmt.Sprintf("doc%d.tex", maxIncludeDepth+1)] = "Too deep"
+	expansion, err = Expand(docs, "main.tex")
+	assert.NoError(t, err)
+	assert.Len(t, expansion.Warnings, 1)
+	assert.Equal(t, IncludeTooDeep, expansion.Warnings[0].Kind)
+}
@@ -0,0 +1,272 @@
+package tex
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type LintRule string
+
+const (
+	LintUndefinedReference LintRule = "undefined_reference" // A reference to a label that is not defined
+	LintUnusedLabel        LintRule = "unused_label"        // A label that is never referenced
+	LintDuplicateLabel     LintRule = "duplicate_label"     // A label defined more than once
+	LintMissingCitation    LintRule = "missing_citation"    // A citation key without bibliography entry
+	LintMissingGraphics    LintRule = "missing_graphics"    // An \includegraphics file that is not in the project
+	LintUnresolvedInclude  LintRule = "unresolved_include"  // An include that could not be expanded
+)
+
+// LintFinding is a problem found in the LaTeX source of a project.
+type LintFinding struct {
+	Rule     LintRule
+	Key      string // The label, citation key or file the finding is about
+	Command  string // The source of the command, e.g. \ref{fig:setup}
+	Message  string
+	Section  string         // The title of the section of the command, "" before the first section
+	Location SourceLocation // The location of the command
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s:%d: %s", f.Location.Filepath, f.Location.Line, f.Message)
+}
+
+// graphicsExtensions are the extensions tried, in order, for graphics included without one.
+var graphicsExtensions = []string{".pdf", ".png", ".jpg", ".jpeg", ".eps", ".svg"}
+
+// lintCommand is a command of the expanded content with one of its keys.
+type lintCommand struct {
+	key    string
+	offset int // The offset of the command
+	end    int // The offset after the command
+}
+
+// Lint checks the LaTeX source of the docs. It reports references to undefined labels, labels that are never
+// referenced or defined more than once, citation keys without entry in the .bib docs or \bibitem commands,
+// \includegraphics files that are neither in files nor in the docs, and includes that could not be expanded.
+// Graphics are not checked if files is nil, as the files that are not docs are then unknown. The findings are in
+// order of the source, after the unresolved includes.
+func Lint(docs map[string]string, rootDoc string, files []string) ([]LintFinding, error) {
+	expansion, err := Expand(docs, rootDoc)
+	if err != nil {
+		return nil, err
+	}
+	l := &linter{content: expansion.Content, tokens: Tokenize(expansion.Content), expansion: expansion}
+	l.outline = ParseOutline(l.content)
+	l.collect()
+
+	var findings []LintFinding
+	for _, warning := range expansion.Warnings {
+		findings = append(findings, LintFinding{
+			Rule:     LintUnresolvedInclude,
+			Key:      warning.Target,
+			Command:  warning.Command,
+			Message:  warning.Message(),
+			Location: warning.Location,
+		})
+	}
+
+	var commands []LintFinding // Sorted by the offset of their command below
+	var offsets []int
+	add := func(rule LintRule, command lintCommand, message string) {
+		finding := LintFinding{Rule: rule, Key: command.key, Command: l.content[command.offset:command.end], Message: message}
+		finding.Location, _ = expansion.SourceMap.Lookup(command.offset)
+		if i := l.outline.SectionAt(command.offset); i >= 0 {
+			finding.Section = l.outline.Sections[i].PlainTitle()
+		}
+		commands = append(commands, finding)
+		offsets = append(offsets, command.offset)
+	}
+
+	labels := map[string]lintCommand{}
+	for _, label := range l.labels {
+		if first, ok := labels[label.key]; ok {
+			location, _ := expansion.SourceMap.Lookup(first.offset)
+			add(LintDuplicateLabel, label, fmt.Sprintf("label %q is already defined at %s:%d", label.key, location.Filepath, location.Line))
+			continue
+		}
+		labels[label.key] = label
+	}
+	referenced := map[string]bool{}
+	for _, ref := range l.refs {
+		referenced[ref.key] = true
+		if _, ok := labels[ref.key]; !ok {
+			add(LintUndefinedReference, ref, fmt.Sprintf("label %q is not defined", ref.key))
+		}
+	}
+	for _, label := range l.labels {
+		if !referenced[label.key] && labels[label.key] == label {
+			add(LintUnusedLabel, label, fmt.Sprintf("label %q is never referenced", label.key))
+		}
+	}
+
+	bibKeys := l.bibKeys(docs)
+	for _, cite := range l.cites {
+		if !bibKeys[cite.key] {
+			add(LintMissingCitation, cite, fmt.Sprintf("no bibliography entry for citation key %q", cite.key))
+		}
+	}
+
+	if files != nil {
+		known := map[string]bool{}
+		for _, file := range files {
+			known[cleanProjectPath(file)] = true
+		}
+		for path := range docs {
+			known[cleanProjectPath(path)] = true
+		}
+		rootDocDir := filepath.Dir(rootDoc)
+		for _, graphics := range l.graphics {
+			if !l.graphicsExists(graphics.key, rootDocDir, known) {
+				add(LintMissingGraphics, graphics, fmt.Sprintf("graphics file %q not found in the project", graphics.key))
+			}
+		}
+	}
+
+	order := make([]int, len(commands))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return offsets[order[i]] < offsets[order[j]] })
+	for _, i := range order {
+		findings = append(findings, commands[i])
+	}
+	return findings, nil
+}
+
+type linter struct {
+	content      string
+	tokens       []Token
+	expansion    *Expansion
+	outline      *Outline
+	labels       []lintCommand
+	refs         []lintCommand
+	cites        []lintCommand
+	bibItems     []string
+	graphics     []lintCommand
+	graphicsDirs []string // Directories of \graphicspath, relative to the root doc
+}
+
+// collect reads the labels, references, citations, bibliography items and graphics of the content.
+func (l *linter) collect() {
+	for i := 0; i < len(l.tokens); i++ {
+		name := l.tokens[i].CommandName()
+		switch {
+		case name == "label":
+			l.labels = append(l.labels, l.readKeys(i, 0, false)...)
+		case refCommandRegex.MatchString(name):
+			l.refs = append(l.refs, l.readKeys(i, 0, true)...)
+		case name == "hyperref":
+			// \hyperref[label]{text} refers to the label of its optional argument
+			if arg, end, ok := readOptionalAt(l.tokens, skipSpaceTokens(l.tokens, i+1)); ok {
+				key := strings.TrimSpace(l.content[arg[0]:arg[1]])
+				l.refs = append(l.refs, lintCommand{key: key, offset: l.tokens[i].Offset, end: l.tokens[end-1].End()})
+			}
+		case citeCommandRegex.MatchString(name):
+			for _, cite := range l.readKeys(i, 2, true) {
+				if cite.key != "*" { // \nocite{*}
+					l.cites = append(l.cites, cite)
+				}
+			}
+		case name == "bibitem":
+			for _, item := range l.readKeys(i, 1, false) {
+				l.bibItems = append(l.bibItems, item.key)
+			}
+		case name == "includegraphics":
+			l.graphics = append(l.graphics, l.readKeys(i, 1, false)...)
+		case name == "graphicspath":
+			if arg, _, ok := readGroup(l.tokens, skipSpaceTokens(l.tokens, i+1)); ok {
+				l.graphicsDirs = nil
+				for _, group := range groupRegex.FindAllStringSubmatch(l.content[arg[0]:arg[1]], -1) {
+					if dir := strings.TrimSpace(group[1]); dir != "" {
+						l.graphicsDirs = append(l.graphicsDirs, dir)
+					}
+				}
+			}
+		}
+	}
+}
+
+// readKeys reads the keys of the argument of the command at token i, after its star and at most optionals
+// optional arguments. The argument is a list of keys separated by commas if list is true. Keys with macro
+// parameters, as in the body of a macro definition, are skipped.
+func (l *linter) readKeys(i int, optionals int, list bool) []lintCommand {
+	next := skipSpaceTokens(l.tokens, i+1)
+	if next < len(l.tokens) && l.tokens[next].Kind == TokenSpecial && l.tokens[next].Text == "*" {
+		next++
+	}
+	for range optionals {
+		next = skipOptional(l.tokens, skipSpaceTokens(l.tokens, next))
+	}
+	arg, end, ok := readGroup(l.tokens, skipSpaceTokens(l.tokens, next))
+	if !ok {
+		return nil
+	}
+
+	keys := []string{l.content[arg[0]:arg[1]]}
+	if list {
+		keys = strings.Split(keys[0], ",")
+	}
+	var commands []lintCommand
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if key == "" || strings.Contains(key, "#") {
+			continue
+		}
+		commands = append(commands, lintCommand{key: key, offset: l.tokens[i].Offset, end: l.tokens[end-1].End()})
+	}
+	return commands
+}
+
+// bibKeys returns the keys of the entries of the .bib docs and of the \bibitem commands.
+func (l *linter) bibKeys(docs map[string]string) map[string]bool {
+	keys := map[string]bool{}
+	for path, content := range docs {
+		if !strings.HasSuffix(path, ".bib") {
+			continue
+		}
+		for _, entry := range ParseBibFile(strings.Split(content, "\n")) {
+			if key := BibEntryKey(entry); key != "" {
+				keys[key] = true
+			}
+		}
+	}
+	for _, key := range l.bibItems {
+		keys[key] = true
+	}
+	return keys
+}
+
+// graphicsExists returns whether the graphics file is known, searched as \includegraphics does: in the
+// directory of the root doc then in the \graphicspath directories, with the usual extensions if it has none.
+func (l *linter) graphicsExists(file string, rootDocDir string, known map[string]bool) bool {
+	if strings.Contains(file, `\`) {
+		return true // A path built by a macro cannot be checked
+	}
+	names := []string{file}
+	if filepath.Ext(file) == "" {
+		names = nil
+		for _, extension := range graphicsExtensions {
+			names = append(names, file+extension)
+		}
+		names = append(names, file)
+	}
+	dirs := []string{rootDocDir}
+	for _, dir := range l.graphicsDirs {
+		dirs = append(dirs, filepath.Join(rootDocDir, dir))
+	}
+	for _, dir := range dirs {
+		for _, name := range names {
+			if known[cleanProjectPath(filepath.Join(dir, name))] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cleanProjectPath normalizes a path of the project, relative to its root.
+func cleanProjectPath(path string) string {
+	return strings.TrimPrefix(filepath.Clean("/"+path), "/")
+}
@@ -0,0 +1,88 @@
+package tex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBibEntryKey(t *testing.T) {
+	entries := ParseBibFile([]string{
+		"@comment{generated}",
+		"@article{smith2020,",
+		"  title = {A Study},",
+		"  url = {https://example.com},",
+		"}",
+		"@Book{ doe:book , title = {B}}",
+	})
+	var keys []string
+	for _, entry := range entries {
+		keys = append(keys, BibEntryKey(entry))
+	}
+	assert.Equal(t, []string{"", "smith2020", "doe:book"}, keys)
+}
+
+func TestLint(t *testing.T) {
+	docs := map[string]string{
+		"main.tex": `\documentclass{article}
+\graphicspath{{figures/}}
+\newcommand{\figref}[1]{Figure~\ref{#1}}
+\begin{document}
+\section{Introduction}
+\label{sec:intro}
+As shown in \cref{fig:setup,fig:missing} and \cite[p.~3]{smith2020,jones}.
+% \ref{commented}
+\input{method}
+\include{missing}
+\nocite{*}
+\bibliography{refs}
+\end{document}`,
+		"method.tex": `\section{Method}
+\begin{figure}
+\includegraphics[width=\linewidth]{setup}
+\includegraphics{plot}
+\label{fig:setup}
+\end{figure}
+\begin{equation}\label{sec:intro}x\end{equation}`,
+		"refs.bib": "@article{smith2020,\n  title = {A Study}\n}",
+	}
+
+	findings, err := Lint(docs, "main.tex", []string{"figures/setup.pdf"})
+	assert.NoError(t, err)
+
+	var lines []string
+	for _, finding := range findings {
+		lines = append(lines, string(finding.Rule)+" "+finding.String())
+	}
+	assert.Equal(t, []string{
+		`unresolved_include main.tex:10: \include{missing} not expanded: file not found`,
+		`unused_label main.tex:6: label "sec:intro" is never referenced`,
+		`undefined_reference main.tex:7: label "fig:missing" is not defined`,
+		`missing_citation main.tex:7: no bibliography entry for citation key "jones"`,
+		`missing_graphics method.tex:4: graphics file "plot" not found in the project`,
+		`duplicate_label method.tex:7: label "sec:intro" is already defined at main.tex:6`,
+	}, lines)
+	assert.Equal(t, `\cite[p.~3]{smith2020,jones}`, findings[3].Command)
+	assert.Equal(t, "Introduction", findings[3].Section)
+	assert.Equal(t, "Method", findings[4].Section)
+
+	// Without the files of the project, graphics are not checked
+	findings, err = Lint(docs, "main.tex", nil)
+	assert.NoError(t, err)
+	for _, finding := range findings {
+		assert.NotEqual(t, LintMissingGraphics, finding.Rule)
+	}
+}
+
+func TestLintUnusedLabel(t *testing.T) {
+	docs := map[string]string{
+		"main.tex": "\\section{A}\\label{sec:a}\n\\section{B}\\label{sec:b}\nSee \\hyperref[sec:a]{A}.\n" +
+			"\\begin{thebibliography}{1}\\bibitem{k} K.\\end{thebibliography}\n\\cite{k}",
+	}
+	findings, err := Lint(docs, "main.tex", nil)
+	assert.NoError(t, err)
+	assert.Len(t, findings, 1)
+	assert.Equal(t, LintUnusedLabel, findings[0].Rule)
+	assert.Equal(t, "sec:b", findings[0].Key)
+	assert.Equal(t, 2, findings[0].Location.Line)
+}
@@ -0,0 +1,282 @@
+package tex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxMacroDepth is the depth at which macros used in the bodies of macros are no longer expanded, so that recursive
+// macros terminate.
+const maxMacroDepth = 10
+
+// Macro is a user macro defined by \newcommand, \renewcommand, \providecommand, \DeclareMathOperator or \def.
+type Macro struct {
+	Name     string // Without the backslash
+	Arity    int
+	Optional bool // Whether the first argument is optional, with Default as default value
+	Default  string
+	Body     string
+	Offset   int // The offset of the definition
+	End      int // The offset after the definition
+}
+
+// Signature returns the macro as used, e.g. \norm{#1} or \vec[#1]{#2}.
+func (m Macro) Signature() string {
+	var result strings.Builder
+	result.WriteString(`\` + m.Name)
+	for i := 1; i <= m.Arity; i++ {
+		if i == 1 && m.Optional {
+			result.WriteString(fmt.Sprintf("[#%d]", i))
+		} else {
+			result.WriteString(fmt.Sprintf("{#%d}", i))
+		}
+	}
+	return result.String()
+}
+
+// CollectMacros returns the macros defined in LaTeX source that can be expanded, in order of definition. Macros
+// with delimited parameters are skipped. A macro redefined by \renewcommand or \def is returned once, with its last
+// definition.
+func CollectMacros(content string) []Macro {
+	tokens := Tokenize(content)
+	var macros []Macro
+	index := map[string]int{}
+	for i := 0; i < len(tokens); i++ {
+		macro, next, override, ok := parseMacroDefinition(content, tokens, i)
+		if !ok {
+			continue
+		}
+		if j, exists := index[macro.Name]; exists {
+			if override {
+				macros[j] = macro
+			}
+		} else {
+			index[macro.Name] = len(macros)
+			macros = append(macros, macro)
+		}
+		i = next - 1
+	}
+	return macros
+}
+
+// parseMacroDefinition parses the macro definition at token i. It returns the index of the token after it, and
+// whether the definition overrides a previous one.
+func parseMacroDefinition(content string, tokens []Token, i int) (Macro, int, bool, bool) {
+	command := tokens[i].CommandName()
+	macro := Macro{Offset: tokens[i].Offset}
+	next := i + 1
+	starred := false
+	if next < len(tokens) && tokens[next].Kind == TokenSpecial && tokens[next].Text == "*" {
+		starred = true
+		next++
+	}
+
+	switch command {
+	case "newcommand", "renewcommand", "providecommand", "DeclareMathOperator":
+		name, end, ok := readMacroName(content, tokens, skipSpaceTokens(tokens, next))
+		if !ok {
+			return Macro{}, 0, false, false
+		}
+		macro.Name, next = name, skipSpaceTokens(tokens, end)
+		if command == "DeclareMathOperator" {
+			break
+		}
+		if arg, end, ok := readOptionalAt(tokens, next); ok {
+			arity, err := strconv.Atoi(strings.TrimSpace(content[arg[0]:arg[1]]))
+			if err != nil || arity < 0 || arity > 9 {
+				return Macro{}, 0, false, false
+			}
+			macro.Arity, next = arity, skipSpaceTokens(tokens, end)
+			if arg, end, ok := readOptionalAt(tokens, next); ok && arity > 0 {
+				macro.Optional, macro.Default, next = true, content[arg[0]:arg[1]], skipSpaceTokens(tokens, end)
+			}
+		}
+	case "def", "gdef", "edef", "xdef":
+		next = skipSpaceTokens(tokens, next)
+		if next >= len(tokens) || tokens[next].Kind != TokenCommand {
+			return Macro{}, 0, false, false
+		}
+		macro.Name = tokens[next].CommandName()
+		next++
+		// Only undelimited parameters #1#2... are supported
+		for next+1 < len(tokens) && tokens[next].Kind == TokenSpecial && tokens[next].Text == "#" {
+			if tokens[next+1].Kind != TokenText || tokens[next+1].Text != strconv.Itoa(macro.Arity+1) {
+				return Macro{}, 0, false, false
+			}
+			macro.Arity++
+			next += 2
+		}
+	default:
+		return Macro{}, 0, false, false
+	}
+
+	body, end, ok := readGroup(tokens, next)
+	if !ok {
+		return Macro{}, 0, false, false
+	}
+	macro.Body = content[body[0]:body[1]]
+	if command == "DeclareMathOperator" {
+		operator := `\operatorname`
+		if starred {
+			operator += "*"
+		}
+		macro.Body = operator + "{" + macro.Body + "}"
+	}
+	macro.End = tokens[end-1].End()
+	override := command != "newcommand" && command != "providecommand"
+	return macro, end, override, true
+}
+
+// readMacroName reads the name of the macro defined by \newcommand, either braced or not.
+func readMacroName(content string, tokens []Token, i int) (string, int, bool) {
+	if i < len(tokens) && tokens[i].Kind == TokenCommand {
+		return tokens[i].CommandName(), i + 1, true
+	}
+	arg, end, ok := readGroup(tokens, i)
+	if !ok {
+		return "", 0, false
+	}
+	name := strings.TrimSpace(content[arg[0]:arg[1]])
+	if !strings.HasPrefix(name, `\`) || len(name) < 2 {
+		return "", 0, false
+	}
+	return name[1:], end, true
+}
+
+func readOptionalAt(tokens []Token, i int) ([]int, int, bool) {
+	if i >= len(tokens) || tokens[i].Kind != TokenSpecial || tokens[i].Text != "[" {
+		return nil, 0, false
+	}
+	return readOptional(tokens, i)
+}
+
+// ExpandMacros returns the LaTeX source with the uses of its user macros replaced by their bodies. The definitions
+// are kept as they are.
+func ExpandMacros(content string) string {
+	macros := CollectMacros(content)
+	if len(macros) == 0 {
+		return content
+	}
+	byName := make(map[string]Macro, len(macros))
+	for _, macro := range macros {
+		byName[macro.Name] = macro
+	}
+
+	// The definitions are copied as-is, so that the names they define are not expanded
+	var result strings.Builder
+	next := 0
+	for _, definition := range macroDefinitionSpans(content) {
+		result.WriteString(expandMacros(content[next:definition[0]], byName, 0))
+		result.WriteString(content[definition[0]:definition[1]])
+		next = definition[1]
+	}
+	result.WriteString(expandMacros(content[next:], byName, 0))
+	return result.String()
+}
+
+// macroDefinitionSpans returns the offsets of all the macro definitions of the content, including redefinitions.
+func macroDefinitionSpans(content string) [][]int {
+	tokens := Tokenize(content)
+	var spans [][]int
+	for i := 0; i < len(tokens); i++ {
+		macro, next, _, ok := parseMacroDefinition(content, tokens, i)
+		if ok {
+			spans = append(spans, []int{macro.Offset, macro.End})
+			i = next - 1
+		}
+	}
+	return spans
+}
+
+func expandMacros(content string, macros map[string]Macro, depth int) string {
+	if depth > maxMacroDepth {
+		return content
+	}
+	tokens := Tokenize(content)
+	var result strings.Builder
+	for i := 0; i < len(tokens); i++ {
+		macro, ok := macros[tokens[i].CommandName()]
+		if !ok {
+			result.WriteString(tokens[i].Text)
+			continue
+		}
+		args, next, ok := readMacroArgs(content, tokens, i+1, macro)
+		if !ok {
+			result.WriteString(tokens[i].Text)
+			continue
+		}
+		result.WriteString(expandMacros(substituteArgs(macro.Body, args), macros, depth+1))
+		i = next - 1
+	}
+	return result.String()
+}
+
+// readMacroArgs reads the arguments of a use of the macro from token i. Each argument is a brace group or a single
+// token. It returns the index of the token after the use.
+func readMacroArgs(content string, tokens []Token, i int, macro Macro) ([]string, int, bool) {
+	if macro.Arity == 0 {
+		// \method{} is the usual way to keep the space after a macro
+		if i+1 < len(tokens) && tokens[i].Kind == TokenBeginGroup && tokens[i+1].Kind == TokenEndGroup {
+			return nil, i + 2, true
+		}
+		return nil, i, true
+	}
+
+	var args []string
+	for n := 0; n < macro.Arity; n++ {
+		if n == 0 && macro.Optional {
+			if arg, end, ok := readOptionalAt(tokens, i); ok {
+				args, i = append(args, content[arg[0]:arg[1]]), end
+			} else {
+				args = append(args, macro.Default)
+			}
+			continue
+		}
+		i = skipSpaceTokens(tokens, i)
+		if i >= len(tokens) || tokens[i].Kind == TokenEndGroup {
+			return nil, 0, false
+		}
+		if arg, end, ok := readGroup(tokens, i); ok {
+			args, i = append(args, content[arg[0]:arg[1]]), end
+		} else {
+			args, i = append(args, tokens[i].Text), i+1
+		}
+	}
+	return args, i, true
+}
+
+// substituteArgs replaces the parameters #1 to #9 of a macro body by the arguments, and ## by #.
+func substituteArgs(body string, args []string) string {
+	var result strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] != '#' || i+1 >= len(body) {
+			result.WriteByte(body[i])
+			continue
+		}
+		switch c := body[i+1]; {
+		case c == '#':
+			result.WriteByte('#')
+			i++
+		case c >= '1' && c <= '9' && int(c-'1') < len(args):
+			result.WriteString(args[c-'1'])
+			i++
+		default:
+			result.WriteByte('#')
+		}
+	}
+	return result.String()
+}
+
+// MacroGlossary returns a line per macro with its signature and its body, or "" if there are no macros.
+func MacroGlossary(macros []Macro) string {
+	var lines []string
+	for _, macro := range macros {
+		line := fmt.Sprintf("%s = %s", macro.Signature(), strings.Join(strings.Fields(macro.Body), " "))
+		if macro.Optional {
+			line += fmt.Sprintf(" (#1 defaults to %q)", macro.Default)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,41 @@
+package tex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const macrosSource = `\newcommand{\method}{FooNet}
+\newcommand\norm[1]{\left\|#1\right\|}
+\newcommand{\pair}[2][x]{(#1, #2)}
+\DeclareMathOperator*{\argmax}{arg\,max}
+\def\R{\mathbb{R}}
+\def\scale#1#2{#1 \times #2}
+\def\delimited#1.{#1}
+\newcommand{\method}{Ignored}
+\renewcommand{\R}{\mathbb{R}^n}
+We propose \method{}, a model on \R where $\norm{x} = \argmax_y \pair{y}$ and \pair[a]{b}.
+\scale{2}3 \method is \verb|\method| and \foo.`
+
+func TestCollectMacros(t *testing.T) {
+	macros := CollectMacros(macrosSource)
+	assert.Len(t, macros, 6)
+	assert.Equal(t, `\method = FooNet
+\norm{#1} = \left\|#1\right\|
+\pair[#1]{#2} = (#1, #2) (#1 defaults to "x")
+\argmax = \operatorname*{arg\,max}
+\R = \mathbb{R}^n
+\scale{#1}{#2} = #1 \times #2`, MacroGlossary(macros))
+}
+
+func TestExpandMacros(t *testing.T) {
+	expanded := ExpandMacros(macrosSource)
+	assert.Contains(t, expanded, `\newcommand{\method}{FooNet}`)
+	assert.Contains(t, expanded, `We propose FooNet, a model on \mathbb{R}^n where $\left\|x\right\| = \operatorname*{arg\,max}_y (x, y)$ and (a, b).`)
+	assert.Contains(t, expanded, `2 \times 3 FooNet is \verb|\method| and \foo.`)
+
+	// Recursive macros stop expanding
+	assert.NotPanics(t, func() { ExpandMacros(`\def\loop{a\loop} \loop`) })
+	assert.Equal(t, "no macros", ExpandMacros("no macros"))
+}
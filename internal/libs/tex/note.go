@@ -0,0 +1,111 @@
+package tex
+
+// boxEnvironments are the environments where a margin note cannot be typeset, besides the floats and the math
+// environments: LaTeX is not in outer paragraph mode in them.
+var boxEnvironments = map[string]bool{
+	"tabular": true, "tabular*": true, "tabularx": true, "longtable": true, "array": true,
+	"minipage": true, "tikzpicture": true, "subfigure": true, "subtable": true,
+}
+
+// noteArgumentCommands are the commands, besides the sectioning commands, whose argument cannot hold a margin note.
+var noteArgumentCommands = map[string]bool{
+	"caption": true, "captionof": true, "subcaption": true, "footnote": true, "footnotetext": true,
+	"title": true, "author": true, "thanks": true,
+}
+
+// NotePosition returns where a note about the text at offset can be inserted in content, and whether the note must
+// be inline. A margin note can be inserted at offset, unless offset is in a comment, verbatim text, math, the title of
+// a section, a caption or a footnote: the note is moved before it. In a float, display math or a box, the note is
+// moved before the outermost such environment, as an inline note.
+func NotePosition(content string, offset int) (int, bool) {
+	tokens := Tokenize(content)
+	outline := ParseOutline(content)
+	inline := false
+	for {
+		next, block := notePositionStep(tokens, outline, offset)
+		if next == offset {
+			return offset, inline
+		}
+		offset, inline = next, inline || block
+	}
+}
+
+// notePositionStep moves offset before the innermost construct that cannot hold a note, or returns offset. It
+// reports whether the note is moved before a block: an environment or a section.
+func notePositionStep(tokens []Token, outline *Outline, offset int) (int, bool) {
+	mathStart := -1
+	for i, token := range tokens {
+		if token.Offset >= offset {
+			break
+		}
+		if offset < token.End() && (token.Kind == TokenComment || token.Kind == TokenVerbatim) {
+			// The command introducing the text, e.g. \verb or \iffalse, is moved over as well
+			if i > 0 && tokens[i-1].Kind == TokenCommand && tokens[i-1].End() == token.Offset {
+				return tokens[i-1].Offset, false
+			}
+			return token.Offset, false
+		}
+		switch name := token.CommandName(); {
+		case token.Kind == TokenMathShift:
+			if mathStart < 0 {
+				mathStart = token.Offset
+			} else {
+				mathStart = -1
+			}
+		case name == "(" || name == "[":
+			mathStart = token.Offset
+		case name == ")" || name == "]":
+			mathStart = -1
+		case noteArgumentCommands[name]:
+			if end, ok := commandEnd(tokens, i); ok && offset < end {
+				return token.Offset, false
+			}
+		}
+	}
+	if mathStart >= 0 {
+		return mathStart, false
+	}
+
+	for _, section := range outline.Sections {
+		if section.Offset < offset && offset < section.End {
+			return section.Offset, true
+		}
+	}
+	// The environments are in order of their start, so the first one containing offset is the outermost one
+	for _, environment := range outline.Environments {
+		name := environment.Name
+		_, float := floatEnvironments[name]
+		if (float || mathEnvironments[name] || boxEnvironments[name] || verbatimEnvironments[name]) &&
+			environment.Offset < offset && offset < environment.End {
+			return environment.Offset, true
+		}
+	}
+	return offset, false
+}
+
+// commandEnd returns the offset after the arguments of the command at index i: an optional star, optional
+// arguments and a group.
+func commandEnd(tokens []Token, i int) (int, bool) {
+	next := skipSpaceTokens(tokens, i+1)
+	if next < len(tokens) && tokens[next].Kind == TokenSpecial && tokens[next].Text == "*" {
+		next = skipSpaceTokens(tokens, next+1)
+	}
+	for next < len(tokens) && tokens[next].Kind == TokenSpecial && tokens[next].Text == "[" {
+		_, end, ok := readOptional(tokens, next)
+		if !ok {
+			return 0, false
+		}
+		next = skipSpaceTokens(tokens, end)
+	}
+	// \captionof takes the type of float before the caption
+	if tokens[i].CommandName() == "captionof" {
+		if _, end, ok := readGroup(tokens, next); ok {
+			next = skipSpaceTokens(tokens, end)
+		}
+	}
+	_, end, ok := readGroup(tokens, next)
+	if !ok {
+		return 0, false
+	}
+	return tokens[end-1].End(), true
+}
@@ -0,0 +1,55 @@
+package tex
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotePosition(t *testing.T) {
+	content := strings.Join([]string{
+		`\section{Results on \textsc{Bench}}`,
+		`We improve the accuracy, see $x + y$ and \verb|code|.`,
+		`% An old remark`,
+		`\begin{figure}[t]`,
+		`  \begin{minipage}{0.5\linewidth}`,
+		`    \includegraphics{plot}`,
+		`  \end{minipage}`,
+		`  \caption{Accuracy per epoch.}`,
+		`  \label{fig:plot}`,
+		`\end{figure}`,
+		`\begin{equation}`,
+		`  a = b`,
+		`\end{equation}`,
+		`Text\footnote{A footnote.} and \(z\).`,
+	}, "\n")
+	at := func(text string) int {
+		offset := strings.Index(content, text)
+		assert.GreaterOrEqual(t, offset, 0, text)
+		return offset
+	}
+	tests := []struct {
+		anchor   string
+		expected int
+		inline   bool
+	}{
+		{"We improve", at("We improve"), false},
+		{"Bench", at(`\section`), true},
+		{"+ y", at("$x"), false},
+		{"code", at(`\verb`), false},
+		{"old remark", at("% An"), false},
+		{"plot}", at(`\begin{figure}`), true},
+		{"Accuracy per", at(`\begin{figure}`), true},
+		{"fig:plot", at(`\begin{figure}`), true},
+		{"a = b", at(`\begin{equation}`), true},
+		{"A footnote", at(`\footnote`), false},
+		{"z\\)", at(`\(z`), false},
+		{"Text", at("Text"), false},
+	}
+	for _, test := range tests {
+		position, inline := NotePosition(content, at(test.anchor))
+		assert.Equal(t, test.expected, position, test.anchor)
+		assert.Equal(t, test.inline, inline, test.anchor)
+	}
+}
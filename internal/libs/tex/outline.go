@@ -0,0 +1,263 @@
+package tex
+
+import (
+	"strings"
+)
+
+// sectionLevels are the levels of the sectioning commands, from the highest.
+var sectionLevels = map[string]int{
+	"part":          0,
+	"chapter":       1,
+	"section":       2,
+	"subsection":    3,
+	"subsubsection": 4,
+	"paragraph":     5,
+	"subparagraph":  6,
+}
+
+// Section is a sectioning command. Offsets are byte offsets in the parsed content.
+type Section struct {
+	Level      int    // 0 for \part, 1 for \chapter, 2 for \section, ... 6 for \subparagraph
+	Command    string // The name of the command, e.g. subsection
+	Starred    bool
+	ShortTitle string // The optional argument, "" if there is none
+	Title      string // The LaTeX source of the title
+	Offset     int    // The offset of the command
+	TitleStart int    // The offset of the title
+	End        int    // The offset after the title argument
+	Line       int    // The line of the command, starting at 1
+}
+
+// PlainTitle returns the title without LaTeX markup.
+func (s Section) PlainTitle() string {
+	return plainText(Tokenize(s.Title))
+}
+
+// Environment is a \begin{Name} ... \end{Name} block. Offsets are byte offsets in the parsed content.
+type Environment struct {
+	Name    string
+	Offset  int // The offset of \begin
+	End     int // The offset after \end{Name}, or the length of the content if the environment is not closed
+	Line    int // The line of \begin, starting at 1
+	EndLine int // The line of \end
+	Depth   int // The number of environments the environment is nested in
+}
+
+// Outline is the sections and environments of LaTeX source, in order of their start. Commented-out and verbatim
+// blocks are ignored.
+type Outline struct {
+	Sections     []Section
+	Environments []Environment
+}
+
+// SectionEnd returns the offset where the section at index ends: the start of the next section of the same or a
+// higher level, or contentLength.
+func (o *Outline) SectionEnd(index int, contentLength int) int {
+	for i := index + 1; i < len(o.Sections); i++ {
+		if o.Sections[i].Level <= o.Sections[index].Level {
+			return o.Sections[i].Offset
+		}
+	}
+	return contentLength
+}
+
+// SectionAt returns the index of the innermost section containing offset, or -1 before the first section.
+func (o *Outline) SectionAt(offset int) int {
+	index := -1
+	for i, section := range o.Sections {
+		if section.Offset > offset {
+			break
+		}
+		index = i
+	}
+	return index
+}
+
+// sectionTitleMatches are the ways a section title may match a searched title, from the best.
+var sectionTitleMatches = []func(title, target string) bool{
+	func(title, target string) bool { return title == target },
+	strings.Contains,
+	func(title, target string) bool { return strings.Contains(target, title) },
+}
+
+// FindSection returns the index of the section whose title matches title, or -1. Titles are compared without
+// markup and case: the first exact match is preferred, then the first title containing title, then the first title
+// contained in it.
+func (o *Outline) FindSection(title string) int {
+	var targets []string
+	for _, target := range []string{strings.TrimSpace(title), plainText(Tokenize(title))} {
+		if target != "" {
+			targets = append(targets, strings.ToLower(target))
+		}
+	}
+	titles := make([]string, len(o.Sections))
+	for i, section := range o.Sections {
+		titles[i] = strings.ToLower(section.PlainTitle())
+	}
+	for _, match := range sectionTitleMatches {
+		for i, sectionTitle := range titles {
+			if sectionTitle == "" {
+				continue
+			}
+			for _, target := range targets {
+				if match(sectionTitle, target) {
+					return i
+				}
+			}
+		}
+	}
+	return -1
+}
+
+// ParseOutline parses the sections and environments of LaTeX source.
+func ParseOutline(content string) *Outline {
+	tokens := Tokenize(content)
+	outline := &Outline{}
+	var open []int // Indexes of the environments not closed yet
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		name := token.CommandName()
+		if level, ok := sectionLevels[name]; ok {
+			section, next, ok := parseSection(content, tokens, i, level)
+			if ok {
+				outline.Sections = append(outline.Sections, section)
+				i = next - 1
+			}
+			continue
+		}
+
+		if name != "begin" && name != "end" {
+			continue
+		}
+		arg, next, ok := readGroup(tokens, skipSpaceTokens(tokens, i+1))
+		if !ok {
+			continue
+		}
+		envName := strings.TrimSpace(content[arg[0]:arg[1]])
+		if name == "begin" {
+			open = append(open, len(outline.Environments))
+			outline.Environments = append(outline.Environments, Environment{
+				Name:   envName,
+				Offset: token.Offset,
+				End:    len(content),
+				Line:   token.Line,
+				Depth:  len(open) - 1,
+			})
+		} else {
+			// Close the innermost environment of that name, and the unclosed ones nested in it
+			for j := len(open) - 1; j >= 0; j-- {
+				if outline.Environments[open[j]].Name == envName {
+					environment := &outline.Environments[open[j]]
+					environment.End = tokens[next-1].End()
+					environment.EndLine = tokens[next-1].Line
+					open = open[:j]
+					break
+				}
+			}
+		}
+		i = next - 1
+	}
+	for _, j := range open {
+		outline.Environments[j].EndLine = strings.Count(content, "\n") + 1
+	}
+	return outline
+}
+
+// parseSection parses the sectioning command at index i. It returns the index of the token after it.
+func parseSection(content string, tokens []Token, i int, level int) (Section, int, bool) {
+	section := Section{Level: level, Command: tokens[i].CommandName(), Offset: tokens[i].Offset, Line: tokens[i].Line}
+	next := skipSpaceTokens(tokens, i+1)
+	if next < len(tokens) && tokens[next].Kind == TokenSpecial && tokens[next].Text == "*" {
+		section.Starred = true
+		next = skipSpaceTokens(tokens, next+1)
+	}
+	if next < len(tokens) && tokens[next].Kind == TokenSpecial && tokens[next].Text == "[" {
+		arg, end, ok := readOptional(tokens, next)
+		if !ok {
+			return Section{}, 0, false
+		}
+		section.ShortTitle = strings.TrimSpace(content[arg[0]:arg[1]])
+		next = skipSpaceTokens(tokens, end)
+	}
+	arg, end, ok := readGroup(tokens, next)
+	if !ok {
+		return Section{}, 0, false
+	}
+	section.Title = strings.TrimSpace(content[arg[0]:arg[1]])
+	section.TitleStart = arg[0] + strings.Index(content[arg[0]:arg[1]], section.Title)
+	section.End = tokens[end-1].End()
+	return section, end, true
+}
+
+// readGroup reads the brace group starting at token i. It returns the offsets of its content and the index of the
+// token after it.
+func readGroup(tokens []Token, i int) ([]int, int, bool) {
+	if i >= len(tokens) || tokens[i].Kind != TokenBeginGroup {
+		return nil, 0, false
+	}
+	depth := 0
+	for j := i; j < len(tokens); j++ {
+		switch tokens[j].Kind {
+		case TokenBeginGroup:
+			depth++
+		case TokenEndGroup:
+			depth--
+			if depth == 0 {
+				return []int{tokens[i].End(), tokens[j].Offset}, j + 1, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// readOptional reads the optional argument starting at token i, whose closing bracket is not in a group.
+func readOptional(tokens []Token, i int) ([]int, int, bool) {
+	depth := 0
+	for j := i + 1; j < len(tokens); j++ {
+		switch {
+		case tokens[j].Kind == TokenBeginGroup:
+			depth++
+		case tokens[j].Kind == TokenEndGroup:
+			depth--
+		case tokens[j].Kind == TokenSpecial && tokens[j].Text == "]" && depth == 0:
+			return []int{tokens[i].End(), tokens[j].Offset}, j + 1, true
+		}
+	}
+	return nil, 0, false
+}
+
+func skipSpaceTokens(tokens []Token, i int) int {
+	for i < len(tokens) && (tokens[i].Kind == TokenSpace || tokens[i].Kind == TokenComment) {
+		i++
+	}
+	return i
+}
+
+// plainText returns the text of the tokens without commands, braces and comments, with spaces collapsed.
+func plainText(tokens []Token) string {
+	var result strings.Builder
+	for _, token := range tokens {
+		switch token.Kind {
+		case TokenText, TokenMathShift, TokenVerbatim:
+			result.WriteString(token.Text)
+		case TokenSpace:
+			result.WriteString(" ")
+		case TokenSpecial:
+			if token.Text == "~" {
+				result.WriteString(" ")
+			} else {
+				result.WriteString(token.Text)
+			}
+		case TokenCommand:
+			name := token.CommandName()
+			switch {
+			case strings.Contains(`\ ,;:`, name) && len(name) == 1:
+				result.WriteString(" ") // A line break or a space
+			case strings.Contains("&%$#_{}", name) && len(name) == 1:
+				result.WriteString(name) // An escaped character, e.g. \&
+			}
+		}
+	}
+	return strings.Join(strings.Fields(result.String()), " ")
+}
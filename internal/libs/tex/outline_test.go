@@ -0,0 +1,92 @@
+package tex
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// formatOutline dumps an outline for the golden files.
+func formatOutline(outline *Outline) string {
+	var result strings.Builder
+	for _, s := range outline.Sections {
+		result.WriteString(fmt.Sprintf("section level=%d command=%s starred=%t line=%d short=%q title=%q plain=%q\n",
+			s.Level, s.Command, s.Starred, s.Line, s.ShortTitle, s.Title, s.PlainTitle()))
+	}
+	for _, e := range outline.Environments {
+		result.WriteString(fmt.Sprintf("environment name=%s depth=%d lines=%d-%d\n", e.Name, e.Depth, e.Line, e.EndLine))
+	}
+	return result.String()
+}
+
+func TestParseOutlineGolden(t *testing.T) {
+	files, err := filepath.Glob("testdata/outline/*.tex")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			content, err := os.ReadFile(file)
+			assert.NoError(t, err)
+			actual := formatOutline(ParseOutline(string(content)))
+
+			golden := strings.TrimSuffix(file, ".tex") + ".golden"
+			if *update {
+				assert.NoError(t, os.WriteFile(golden, []byte(actual), 0o644))
+			}
+			expected, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), actual)
+		})
+	}
+}
+
+func TestTokenizeRoundTrip(t *testing.T) {
+	files, err := filepath.Glob("testdata/outline/*.tex")
+	assert.NoError(t, err)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+
+		var result strings.Builder
+		for _, token := range Tokenize(string(content)) {
+			assert.Equal(t, string(content[token.Offset:token.End()]), token.Text)
+			assert.Equal(t, strings.Count(string(content[:token.Offset]), "\n")+1, token.Line)
+			result.WriteString(token.Text)
+		}
+		assert.Equal(t, string(content), result.String())
+	}
+}
+
+func TestSectionEnd(t *testing.T) {
+	content := "\\section{A}\na\n\\subsection{B}\nb\n\\section{C}\nc"
+	outline := ParseOutline(content)
+	assert.Len(t, outline.Sections, 3)
+	assert.Equal(t, strings.Index(content, "\\section{C}"), outline.SectionEnd(0, len(content)))
+	assert.Equal(t, strings.Index(content, "\\section{C}"), outline.SectionEnd(1, len(content)))
+	assert.Equal(t, len(content), outline.SectionEnd(2, len(content)))
+	assert.Equal(t, 1, outline.SectionAt(strings.Index(content, "b")))
+	assert.Equal(t, -1, outline.SectionAt(-1))
+	assert.Equal(t, strings.Index(content, "B"), outline.Sections[1].TitleStart)
+}
+
+func TestFindSection(t *testing.T) {
+	outline := ParseOutline("\\section{Introduction}\n\\section{Related Work \\& Background}\n\\subsection{Results}\n\\section{Results and Discussion}")
+	assert.Equal(t, 0, outline.FindSection("introduction"))
+	assert.Equal(t, 1, outline.FindSection(`Related Work \& Background`))
+	assert.Equal(t, 1, outline.FindSection("related work & background"))
+	assert.Equal(t, 1, outline.FindSection("Related"))
+	// An exact match is preferred to an earlier partial one
+	assert.Equal(t, 2, outline.FindSection("Results"))
+	assert.Equal(t, 0, outline.FindSection("1 Introduction"))
+	assert.Equal(t, -1, outline.FindSection("Conclusion"))
+	assert.Equal(t, -1, outline.FindSection(" "))
+}
@@ -0,0 +1,58 @@
+package tex
+
+import "sort"
+
+// SourceLocation is a position in a doc. Line and Column start at 1, Column counts bytes.
+type SourceLocation struct {
+	Filepath string
+	Line     int
+	Column   int
+}
+
+type sourceSegment struct {
+	offset int
+	SourceLocation
+}
+
+// SourceMap maps the byte offsets of an expanded content to the docs they come from.
+type SourceMap struct {
+	segments []sourceSegment // Sorted by offset, each segment is contiguous in a line of a doc
+}
+
+func (m *SourceMap) add(offset int, path string, line int, column int) {
+	m.segments = append(m.segments, sourceSegment{
+		offset:         offset,
+		SourceLocation: SourceLocation{Filepath: path, Line: line, Column: column},
+	})
+}
+
+// Lookup returns the location in the docs of the byte at offset in the expanded content. Offsets of the line breaks
+// added between lines map to the end of the line before them.
+func (m *SourceMap) Lookup(offset int) (SourceLocation, bool) {
+	if m == nil || offset < 0 || len(m.segments) == 0 {
+		return SourceLocation{}, false
+	}
+	i := sort.Search(len(m.segments), func(i int) bool { return m.segments[i].offset > offset }) - 1
+	if i < 0 {
+		return SourceLocation{}, false
+	}
+	segment := m.segments[i]
+	location := segment.SourceLocation
+	location.Column += offset - segment.offset
+	return location, true
+}
+
+// LookupRange returns the locations in the docs of the parts of the expanded content from start to end, end
+// excluded, in order: one location for each contiguous part of a line of a doc.
+func (m *SourceMap) LookupRange(start int, end int) []SourceLocation {
+	location, ok := m.Lookup(start)
+	if !ok || end <= start {
+		return nil
+	}
+	locations := []SourceLocation{location}
+	i := sort.Search(len(m.segments), func(i int) bool { return m.segments[i].offset > start })
+	for ; i < len(m.segments) && m.segments[i].offset < end; i++ {
+		locations = append(locations, m.segments[i].SourceLocation)
+	}
+	return locations
+}
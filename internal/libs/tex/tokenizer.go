@@ -0,0 +1,242 @@
+package tex
+
+import (
+	"strings"
+)
+
+type TokenKind int
+
+const (
+	TokenText       TokenKind = iota // A run of characters without special meaning
+	TokenSpace                       // A run of spaces, tabs and line breaks
+	TokenCommand                     // A control sequence: \name, or \ followed by one character
+	TokenBeginGroup                  // {
+	TokenEndGroup                    // }
+	TokenMathShift                   // $ or $$
+	TokenSpecial                     // One of [ ] * & ~ # ^ _
+	TokenComment                     // A % comment up to the end of the line, or an \iffalse block
+	TokenVerbatim                    // The content of a verbatim environment, or the argument of \verb
+)
+
+// Token is a token of LaTeX source. Text is the source of the token, which starts at byte Offset of line Line.
+type Token struct {
+	Kind   TokenKind
+	Text   string
+	Offset int
+	Line   int // Starts at 1
+}
+
+// End returns the offset after the token.
+func (t Token) End() int {
+	return t.Offset + len(t.Text)
+}
+
+// CommandName returns the name of a command token without the backslash, or "" for other tokens.
+func (t Token) CommandName() string {
+	if t.Kind != TokenCommand {
+		return ""
+	}
+	return t.Text[1:]
+}
+
+// verbatimEnvironments are the environments whose content is not LaTeX. The comment environment is included, as its
+// content is not typeset.
+var verbatimEnvironments = map[string]bool{
+	"verbatim":   true,
+	"verbatim*":  true,
+	"Verbatim":   true,
+	"lstlisting": true,
+	"minted":     true,
+	"comment":    true,
+}
+
+const specialCharacters = "[]*&~#^_"
+
+// Tokenize splits LaTeX source into tokens. Concatenating the text of the tokens gives back the source.
+func Tokenize(content string) []Token {
+	t := &tokenizer{content: content, line: 1}
+	for t.pos < len(content) {
+		t.next()
+	}
+	return t.tokens
+}
+
+type tokenizer struct {
+	content string
+	pos     int
+	line    int
+	tokens  []Token
+}
+
+// emit adds the token of kind ending at end.
+func (t *tokenizer) emit(kind TokenKind, end int) {
+	text := t.content[t.pos:end]
+	t.tokens = append(t.tokens, Token{Kind: kind, Text: text, Offset: t.pos, Line: t.line})
+	t.line += strings.Count(text, "\n")
+	t.pos = end
+}
+
+func (t *tokenizer) next() {
+	c := t.content[t.pos]
+	switch {
+	case c == '\\':
+		t.command()
+	case c == '{':
+		t.emit(TokenBeginGroup, t.pos+1)
+	case c == '}':
+		t.emit(TokenEndGroup, t.pos+1)
+	case c == '$':
+		end := t.pos + 1
+		if end < len(t.content) && t.content[end] == '$' {
+			end++
+		}
+		t.emit(TokenMathShift, end)
+	case c == '%':
+		end := strings.IndexByte(t.content[t.pos:], '\n')
+		if end < 0 {
+			end = len(t.content)
+		} else {
+			end += t.pos
+		}
+		t.emit(TokenComment, end)
+	case strings.IndexByte(specialCharacters, c) >= 0:
+		t.emit(TokenSpecial, t.pos+1)
+	case isSpace(c):
+		end := t.pos
+		for end < len(t.content) && isSpace(t.content[end]) {
+			end++
+		}
+		t.emit(TokenSpace, end)
+	default:
+		end := t.pos
+		for end < len(t.content) && !isSpace(t.content[end]) && strings.IndexByte(`\{}$%`+specialCharacters, t.content[end]) < 0 {
+			end++
+		}
+		t.emit(TokenText, end)
+	}
+}
+
+func (t *tokenizer) command() {
+	end := t.pos + 1
+	for end < len(t.content) && isLetter(t.content[end]) {
+		end++
+	}
+	if end == t.pos+1 && end < len(t.content) {
+		end++ // A control symbol, e.g. \% or \\
+	}
+	name := t.content[t.pos+1 : end]
+	t.emit(TokenCommand, end)
+
+	switch name {
+	case "verb":
+		t.verb()
+	case "begin":
+		t.verbatimEnvironment()
+	case "iffalse":
+		t.iffalse()
+	}
+}
+
+// verb reads the argument of \verb or \verb*, delimited by any character, up to the end of the line.
+func (t *tokenizer) verb() {
+	start := t.pos
+	if start < len(t.content) && t.content[start] == '*' {
+		start++
+	}
+	if start >= len(t.content) {
+		return
+	}
+	delimiter := t.content[start]
+	end := strings.IndexAny(t.content[start+1:], string(delimiter)+"\n")
+	if end < 0 || t.content[start+1+end] == '\n' {
+		return // Not a valid \verb, read as LaTeX
+	}
+	t.emit(TokenVerbatim, start+end+2)
+}
+
+// verbatimEnvironment reads the content of a verbatim environment as one token, after \begin{name} and the options
+// on the same line.
+func (t *tokenizer) verbatimEnvironment() {
+	rest := t.content[t.pos:]
+	if !strings.HasPrefix(rest, "{") {
+		return
+	}
+	closing := strings.IndexByte(rest, '}')
+	if closing < 0 || !verbatimEnvironments[rest[1:closing]] {
+		return
+	}
+	name := rest[1:closing]
+	t.emit(TokenBeginGroup, t.pos+1)
+	t.emit(TokenText, t.pos+len(name))
+	t.emit(TokenEndGroup, t.pos+1)
+
+	// Options of lstlisting and the language of minted are LaTeX
+	for t.pos < len(t.content) && (t.content[t.pos] == '[' || t.content[t.pos] == '{') {
+		closing := byte(']')
+		if t.content[t.pos] == '{' {
+			closing = '}'
+		}
+		end := strings.IndexByte(t.content[t.pos:], closing)
+		if end < 0 {
+			break
+		}
+		for stop := t.pos + end + 1; t.pos < stop; {
+			t.next()
+		}
+	}
+
+	end := strings.Index(t.content[t.pos:], `\end{`+name+`}`)
+	if end < 0 {
+		end = len(t.content) - t.pos
+	}
+	if end > 0 {
+		t.emit(TokenVerbatim, t.pos+end)
+	}
+}
+
+// iffalse reads the block up to the \fi matching \iffalse, or an \else at the same depth, as a comment.
+func (t *tokenizer) iffalse() {
+	depth := 0
+	for pos := t.pos; pos < len(t.content); pos++ {
+		switch t.content[pos] {
+		case '\\':
+			end := pos + 1
+			for end < len(t.content) && isLetter(t.content[end]) {
+				end++
+			}
+			name := t.content[pos+1 : end]
+			switch {
+			case name == "fi" || (name == "else" && depth == 0):
+				if depth == 0 {
+					if pos > t.pos {
+						t.emit(TokenComment, pos)
+					}
+					return
+				}
+				depth--
+			case strings.HasPrefix(name, "if"):
+				depth++
+			}
+			if end == pos+1 {
+				end++ // Skip the escaped character
+			}
+			pos = end - 1
+		case '%':
+			// A comment may contain an unbalanced \fi
+			if lineEnd := strings.IndexByte(t.content[pos:], '\n'); lineEnd >= 0 {
+				pos += lineEnd
+			} else {
+				pos = len(t.content)
+			}
+		}
+	}
+	t.emit(TokenComment, len(t.content))
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '@'
+}
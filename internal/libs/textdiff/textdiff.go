@@ -0,0 +1,229 @@
+// Package textdiff computes line-based diffs between texts, and formats them as unified diffs.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Edit is a line of a diff. OldLine and NewLine are the 0-based line numbers in the old and new texts,
+// OldLine is -1 for inserted lines and NewLine is -1 for deleted lines.
+type Edit struct {
+	Op      Op
+	Text    string
+	OldLine int
+	NewLine int
+}
+
+// SplitLines splits the text into lines. A trailing newline does not start a new line.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// Lines returns the shortest sequence of edits turning a into b, using Myers' algorithm.
+func Lines(a, b []string) []Edit {
+	// The common prefix and suffix are trimmed first, they are most of the text for typical edits.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, Edit{Op: Equal, Text: a[i], OldLine: i, NewLine: i})
+	}
+	for _, e := range myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]) {
+		if e.OldLine >= 0 {
+			e.OldLine += prefix
+		}
+		if e.NewLine >= 0 {
+			e.NewLine += prefix
+		}
+		edits = append(edits, e)
+	}
+	for i := 0; i < suffix; i++ {
+		oldLine, newLine := len(a)-suffix+i, len(b)-suffix+i
+		edits = append(edits, Edit{Op: Equal, Text: a[oldLine], OldLine: oldLine, NewLine: newLine})
+	}
+	return edits
+}
+
+// maxEditDistance bounds the memory used by myers, which is quadratic in the number of changed lines.
+// Texts further apart are diffed as a deletion of the old lines followed by an insertion of the new lines.
+const maxEditDistance = 2000
+
+func myers(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	replace := func() []Edit {
+		edits := make([]Edit, 0, n+m)
+		for i, line := range a {
+			edits = append(edits, Edit{Op: Delete, Text: line, OldLine: i, NewLine: -1})
+		}
+		for j, line := range b {
+			edits = append(edits, Edit{Op: Insert, Text: line, OldLine: -1, NewLine: j})
+		}
+		return edits
+	}
+	if n == 0 || m == 0 {
+		return replace()
+	}
+
+	// v[k+offset] is the furthest x reached on diagonal k. Before each step d, the diagonals
+	// -d-1..d+1 of v are kept to backtrack.
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= min(n+m, maxEditDistance); d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+				x = v[k+1+offset]
+			} else {
+				x = v[k-1+offset] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k+offset] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return replace()
+}
+
+func backtrack(a, b []string, trace [][]int) []Edit {
+	x, y := len(a), len(b)
+	var reversed []Edit
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Edit{Op: Equal, Text: a[x], OldLine: x, NewLine: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				reversed = append(reversed, Edit{Op: Insert, Text: b[y], OldLine: -1, NewLine: y})
+			} else {
+				x--
+				reversed = append(reversed, Edit{Op: Delete, Text: a[x], OldLine: x, NewLine: -1})
+			}
+		}
+	}
+
+	edits := make([]Edit, len(reversed))
+	for i, e := range reversed {
+		edits[len(reversed)-1-i] = e
+	}
+	return edits
+}
+
+// Unified formats the diff between a and b as a unified diff with the given number of context lines.
+// The file header is omitted if both names are empty. It returns "" if the texts are equal.
+func Unified(oldName, newName string, a, b []string, context int) string {
+	edits := Lines(a, b)
+
+	var sb strings.Builder
+	oldPos, newPos := 0, 0 // Lines of the old and new texts before edits[start]
+	for start := 0; start < len(edits); {
+		// Find the next change, and the end of its hunk: changes closer than 2*context lines share a hunk.
+		first := start
+		for first < len(edits) && edits[first].Op == Equal {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		last := first
+		for i := first; i < len(edits) && i-last <= 2*context+1; i++ {
+			if edits[i].Op != Equal {
+				last = i
+			}
+		}
+
+		from := max(first-context, start)
+		to := min(last+context+1, len(edits))
+		oldPos += from - start // Only equal lines are skipped
+		newPos += from - start
+
+		if sb.Len() == 0 && (oldName != "" || newName != "") {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		oldCount, newCount := writeHunk(&sb, edits[from:to], oldPos, newPos)
+		oldPos += oldCount
+		newPos += newCount
+		start = to
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, edits []Edit, oldPos, newPos int) (int, int) {
+	oldCount, newCount := 0, 0
+	for _, e := range edits {
+		if e.Op != Insert {
+			oldCount++
+		}
+		if e.Op != Delete {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(oldPos, oldCount), hunkRange(newPos, newCount))
+	for _, e := range edits {
+		switch e.Op {
+		case Equal:
+			sb.WriteString(" ")
+		case Delete:
+			sb.WriteString("-")
+		case Insert:
+			sb.WriteString("+")
+		}
+		sb.WriteString(e.Text)
+		sb.WriteString("\n")
+	}
+	return oldCount, newCount
+}
+
+// hunkRange formats the range of a hunk starting after pos lines. An empty range is
+// numbered by the line preceding it.
+func hunkRange(pos, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", pos)
+	case 1:
+		return fmt.Sprintf("%d", pos+1)
+	default:
+		return fmt.Sprintf("%d,%d", pos+1, count)
+	}
+}
@@ -0,0 +1,105 @@
+package textdiff_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"paperdebugger/internal/libs/textdiff"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// apply rebuilds both texts from the edits.
+func apply(edits []textdiff.Edit) ([]string, []string) {
+	var a, b []string
+	for _, e := range edits {
+		if e.Op != textdiff.Insert {
+			a = append(a, e.Text)
+		}
+		if e.Op != textdiff.Delete {
+			b = append(b, e.Text)
+		}
+	}
+	return a, b
+}
+
+func TestLines(t *testing.T) {
+	a := []string{"a", "b", "c", "a", "b", "b", "a"}
+	b := []string{"c", "b", "a", "b", "a", "c"}
+	edits := textdiff.Lines(a, b)
+
+	gotA, gotB := apply(edits)
+	assert.Equal(t, a, gotA)
+	assert.Equal(t, b, gotB)
+	changes := 0
+	for _, e := range edits {
+		if e.Op != textdiff.Equal {
+			changes++
+		}
+	}
+	assert.Equal(t, 5, changes) // The example of Myers' paper
+}
+
+func TestLines_Random(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := []string{"x", "y", "z"}
+	for range 200 {
+		a := make([]string, rng.Intn(12))
+		for i := range a {
+			a[i] = words[rng.Intn(len(words))]
+		}
+		b := make([]string, rng.Intn(12))
+		for i := range b {
+			b[i] = words[rng.Intn(len(words))]
+		}
+
+		edits := textdiff.Lines(a, b)
+		gotA, gotB := apply(edits)
+		assert.Equal(t, len(a), len(gotA))
+		assert.Equal(t, len(b), len(gotB))
+		assert.Equal(t, strings.Join(a, ""), strings.Join(gotA, ""))
+		assert.Equal(t, strings.Join(b, ""), strings.Join(gotB, ""))
+		for _, e := range edits {
+			if e.OldLine >= 0 {
+				assert.Equal(t, a[e.OldLine], e.Text)
+			}
+			if e.NewLine >= 0 {
+				assert.Equal(t, b[e.NewLine], e.Text)
+			}
+		}
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := textdiff.SplitLines("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n")
+	b := textdiff.SplitLines("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n")
+
+	assert.Equal(t, `--- main.tex
++++ main.tex
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -10 +10,2 @@
+ 10
++11
+`, textdiff.Unified("main.tex", "main.tex", a, b, 1))
+
+	// Changes whose contexts touch share a hunk.
+	c := textdiff.SplitLines("1\n2\nthree\n4\n5\nsix\n7\n8\n9\n10\n")
+	assert.Equal(t, `@@ -2,6 +2,6 @@
+ 2
+-3
++three
+ 4
+ 5
+-6
++six
+ 7
+`, textdiff.Unified("", "", a, c, 1))
+
+	assert.Equal(t, "@@ -0,0 +1 @@\n+new\n", textdiff.Unified("", "", nil, []string{"new"}, 3))
+	assert.Equal(t, "", textdiff.Unified("a", "b", a, a, 3))
+}
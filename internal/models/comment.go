@@ -1,6 +1,11 @@
 package models
 
 import (
+	"strings"
+	"unicode/utf8"
+
+	"paperdebugger/internal/libs/tex"
+
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -11,6 +16,7 @@ const (
 	CommentStatusNoAction CommentStatus = iota
 	CommentStatusAccepted
 	CommentStatusRejected
+	CommentStatusResolved // Addressed in the paper
 )
 
 // The importance level of a comment
@@ -38,8 +44,90 @@ type Comment struct {
 	IsAddedToOverleaf CommentStatus   `bson:"is_added_to_overleaf"`
 	DocPath           string          `bson:"doc_path"`
 	Section           string          `bson:"section"`
+	Orphaned          bool            `bson:"orphaned"` // The quote was not found after the doc changed
+	Replies           []CommentReply  `bson:"replies"`
+}
+
+type CommentReplyRole string
+
+const (
+	CommentReplyRoleUser      CommentReplyRole = "user"
+	CommentReplyRoleAssistant CommentReplyRole = "assistant"
+)
+
+// CommentReply is a message of the discussion of a comment, by a reviewer or the assistant.
+type CommentReply struct {
+	ID        bson.ObjectID    `bson:"id"`
+	Role      CommentReplyRole `bson:"role"`
+	Content   string           `bson:"content"`
+	CreatedAt bson.DateTime    `bson:"created_at"`
 }
 
 func (c Comment) CollectionName() string {
 	return "comments"
 }
+
+// IsOpen returns whether the comment still applies to the paper, so that it follows the changes of its doc.
+func (c *Comment) IsOpen() bool {
+	return c.IsAddedToOverleaf == CommentStatusNoAction || c.IsAddedToOverleaf == CommentStatusAccepted
+}
+
+// Reanchor relocates the quote of the comment in the new version of its doc. The occurrence of the quote nearest to
+// its previous position is used, or else the span that best matches the quote with at least minSimilarity. The
+// comment is orphaned if neither is found, and keeps its previous quote. Reanchor returns whether the quote was found.
+// The doc SHA1 is left to the caller.
+func (c *Comment) Reanchor(doc *ProjectDoc, minSimilarity float64) bool {
+	content := strings.Join(doc.Lines, "\n")
+	c.DocVersion = doc.Version
+	c.DocPath = doc.Filepath
+
+	position, found := -1, false
+	for offset := 0; c.QuoteText != ""; {
+		i := strings.Index(content[offset:], c.QuoteText)
+		if i < 0 {
+			break
+		}
+		candidate := utf8.RuneCountInString(content[:offset+i])
+		if !found || abs(candidate-c.QuotePosition) < abs(position-c.QuotePosition) {
+			position, found = candidate, true
+		}
+		offset += i + 1
+	}
+	if found {
+		c.QuotePosition, c.Orphaned = position, false
+		return true
+	}
+
+	match, ok := tex.MatchAnchor(content, c.QuoteText, minSimilarity)
+	if !ok {
+		c.Orphaned = true
+		return false
+	}
+	c.QuotePosition, c.QuoteText, c.Orphaned = match.Start, match.Text, false
+	return true
+}
+
+// QuoteContext returns the lines of the doc around the quote of the comment, with up to radius lines before and
+// after the lines of the quote.
+func (c *Comment) QuoteContext(doc *ProjectDoc, radius int) string {
+	first := c.quoteLine(doc)
+	last := min(first+strings.Count(c.QuoteText, "\n"), len(doc.Lines)-1)
+	return strings.Join(doc.Lines[max(first-radius, 0):min(last+radius+1, len(doc.Lines))], "\n")
+}
+
+// quoteLine returns the index of the line of the doc where the quote of the comment starts.
+func (c *Comment) quoteLine(doc *ProjectDoc) int {
+	line, position := 0, 0
+	for line < len(doc.Lines)-1 && position+utf8.RuneCountInString(doc.Lines[line]) < c.QuotePosition {
+		position += utf8.RuneCountInString(doc.Lines[line]) + 1
+		line++
+	}
+	return line
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,341 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"paperdebugger/internal/libs/tex"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// importanceRanks orders the importance levels, most important first.
+var importanceRanks = map[ImportanceLevel]int{
+	ImportanceLevelCritical: 0,
+	ImportanceLevelHigh:     1,
+	ImportanceLevelMedium:   2,
+	ImportanceLevelLow:      3,
+	ImportanceLevelNone:     4,
+}
+
+func importanceRank(level ImportanceLevel) int {
+	if rank, ok := importanceRanks[level]; ok {
+		return rank
+	}
+	return len(importanceRanks)
+}
+
+// CommentReport is the reviewer report of the comments of a project: the comments grouped by section, in the order
+// of the sections in the paper, and sorted by importance in each section.
+type CommentReport struct {
+	Project  *Project
+	Sections []CommentReportSection
+}
+
+type CommentReportSection struct {
+	Title    string // "" for the comments without section
+	Comments []Comment
+}
+
+// NewCommentReport groups the comments by section. The sections that are not in the outline of the project come
+// after the others, in alphabetical order, and the comments without section come last.
+func NewCommentReport(project *Project, comments []Comment) *CommentReport {
+	outline := &tex.Outline{}
+	if content, err := project.GetFullContent(); err == nil {
+		outline = tex.ParseOutline(content)
+	}
+	sectionRank := func(title string) int {
+		if title == "" {
+			return len(outline.Sections) + 1
+		}
+		if index := outline.FindSection(title); index >= 0 {
+			return index
+		}
+		return len(outline.Sections)
+	}
+
+	bySection := map[string][]Comment{}
+	for _, comment := range comments {
+		title := strings.TrimSpace(comment.Section)
+		bySection[title] = append(bySection[title], comment)
+	}
+	report := &CommentReport{Project: project}
+	for title, sectionComments := range bySection {
+		sort.SliceStable(sectionComments, func(i, j int) bool {
+			a, b := sectionComments[i], sectionComments[j]
+			if importanceRank(a.ImportanceLevel) != importanceRank(b.ImportanceLevel) {
+				return importanceRank(a.ImportanceLevel) < importanceRank(b.ImportanceLevel)
+			}
+			if a.DocPath != b.DocPath {
+				return a.DocPath < b.DocPath
+			}
+			return a.QuotePosition < b.QuotePosition
+		})
+		report.Sections = append(report.Sections, CommentReportSection{Title: title, Comments: sectionComments})
+	}
+	sort.Slice(report.Sections, func(i, j int) bool {
+		a, b := sectionRank(report.Sections[i].Title), sectionRank(report.Sections[j].Title)
+		if a != b {
+			return a < b
+		}
+		return report.Sections[i].Title < report.Sections[j].Title
+	})
+	return report
+}
+
+// quoteLine returns the line (1-based) of the quote of the comment in its doc, or 0 if the doc is not in the project
+// or the comment is orphaned.
+func (r *CommentReport) quoteLine(comment *Comment) int {
+	if comment.Orphaned {
+		return 0
+	}
+	for i := range r.Project.Docs {
+		if r.Project.Docs[i].ID == comment.DocID {
+			return comment.quoteLine(&r.Project.Docs[i]) + 1
+		}
+	}
+	return 0
+}
+
+func (r *CommentReport) commentCount() int {
+	count := 0
+	for _, section := range r.Sections {
+		count += len(section.Comments)
+	}
+	return count
+}
+
+var commentStatusNames = map[CommentStatus]string{
+	CommentStatusNoAction: "open",
+	CommentStatusAccepted: "accepted",
+	CommentStatusRejected: "rejected",
+	CommentStatusResolved: "resolved",
+}
+
+// Markdown renders the report for reviewers.
+func (r *CommentReport) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Review of %s\n\n", r.Project.Name))
+
+	counts := map[ImportanceLevel]int{}
+	for _, section := range r.Sections {
+		for _, comment := range section.Comments {
+			counts[comment.ImportanceLevel]++
+		}
+	}
+	var summary []string
+	for _, level := range []ImportanceLevel{ImportanceLevelCritical, ImportanceLevelHigh, ImportanceLevelMedium, ImportanceLevelLow} {
+		if counts[level] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[level], strings.ToLower(string(level))))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("%d comments", r.commentCount()))
+	if len(summary) > 0 {
+		sb.WriteString(": " + strings.Join(summary, ", "))
+	}
+	sb.WriteString(".\n")
+
+	for _, section := range r.Sections {
+		title := section.Title
+		if title == "" {
+			title = "Other comments"
+		}
+		sb.WriteString(fmt.Sprintf("\n## %s\n", title))
+		for i := range section.Comments {
+			comment := &section.Comments[i]
+			importance := string(comment.ImportanceLevel)
+			if importance == "" {
+				importance = "Note"
+			}
+			location := comment.DocPath
+			if line := r.quoteLine(comment); line > 0 {
+				location = fmt.Sprintf("%s:%d", comment.DocPath, line)
+			}
+			sb.WriteString(fmt.Sprintf("\n### %d. %s (`%s`)\n\n", i+1, importance, location))
+			if comment.QuoteText != "" {
+				for _, line := range strings.Split(comment.QuoteText, "\n") {
+					sb.WriteString("> " + line + "\n")
+				}
+				sb.WriteString("\n")
+			}
+			sb.WriteString(comment.Comment + "\n")
+			if comment.IsAddedToOverleaf != CommentStatusNoAction || comment.Orphaned {
+				status := commentStatusNames[comment.IsAddedToOverleaf]
+				if comment.Orphaned {
+					status += ", the quoted text is no longer in the paper"
+				}
+				sb.WriteString(fmt.Sprintf("\n_Status: %s._\n", status))
+			}
+			if len(comment.Replies) > 0 {
+				sb.WriteString("\n")
+				for _, reply := range comment.Replies {
+					author := "Reviewer"
+					if reply.Role == CommentReplyRoleAssistant {
+						author = "Assistant"
+					}
+					sb.WriteString(fmt.Sprintf("- **%s:** %s\n", author, strings.ReplaceAll(reply.Content, "\n", "\n  ")))
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+type commentReportJSON struct {
+	ProjectID   string               `json:"project_id"`
+	ProjectName string               `json:"project_name"`
+	Sections    []commentSectionJSON `json:"sections"`
+}
+
+type commentSectionJSON struct {
+	Title    string        `json:"title"`
+	Comments []commentJSON `json:"comments"`
+}
+
+type commentJSON struct {
+	ID            string             `json:"id"`
+	DocID         string             `json:"doc_id"`
+	DocPath       string             `json:"doc_path"`
+	Line          int                `json:"line,omitempty"` // Omitted if the comment is orphaned
+	QuotePosition int                `json:"quote_position"`
+	QuoteText     string             `json:"quote_text"`
+	Comment       string             `json:"comment"`
+	Importance    string             `json:"importance"`
+	Status        string             `json:"status"`
+	Orphaned      bool               `json:"orphaned"`
+	Replies       []commentReplyJSON `json:"replies"`
+}
+
+type commentReplyJSON struct {
+	Role      string `json:"role"`
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
+}
+
+// JSON renders the report for tools.
+func (r *CommentReport) JSON() ([]byte, error) {
+	report := commentReportJSON{ProjectID: r.Project.ProjectID, ProjectName: r.Project.Name, Sections: []commentSectionJSON{}}
+	for _, section := range r.Sections {
+		sectionJSON := commentSectionJSON{Title: section.Title, Comments: []commentJSON{}}
+		for i := range section.Comments {
+			comment := &section.Comments[i]
+			replies := []commentReplyJSON{}
+			for _, reply := range comment.Replies {
+				replies = append(replies, commentReplyJSON{
+					Role:      string(reply.Role),
+					Content:   reply.Content,
+					CreatedAt: reply.CreatedAt.Time().UTC().Format(time.RFC3339),
+				})
+			}
+			sectionJSON.Comments = append(sectionJSON.Comments, commentJSON{
+				ID:            comment.ID.Hex(),
+				DocID:         comment.DocID,
+				DocPath:       comment.DocPath,
+				Line:          r.quoteLine(comment),
+				QuotePosition: comment.QuotePosition,
+				QuoteText:     comment.QuoteText,
+				Comment:       comment.Comment,
+				Importance:    string(comment.ImportanceLevel),
+				Status:        commentStatusNames[comment.IsAddedToOverleaf],
+				Orphaned:      comment.Orphaned,
+				Replies:       replies,
+			})
+		}
+		report.Sections = append(report.Sections, sectionJSON)
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// todoColors are the colors of the todonotes of each importance level.
+var todoColors = map[ImportanceLevel]string{
+	ImportanceLevelCritical: "red!40",
+	ImportanceLevelHigh:     "orange!40",
+	ImportanceLevelMedium:   "yellow!40",
+	ImportanceLevelLow:      "green!25",
+}
+
+var (
+	todonotesPackageRegex = regexp.MustCompile(`\\usepackage\s*(\[[^\]]*\])?\s*\{[^}]*\btodonotes\b[^}]*\}`)
+	beginDocumentRegex    = regexp.MustCompile(`\\begin\s*\{document\}`)
+	commentPrefixRegex    = regexp.MustCompile(`^[\p{So}\p{Sk}\p{Mn}\p{Cf}]+\s*`) // The emoji of the comments of the assistant
+	latexSpecialReplacer  = strings.NewReplacer(
+		`\`, `\textbackslash{}`, `{`, `\{`, `}`, `\}`, `$`, `\$`, `&`, `\&`, `#`, `\#`,
+		`^`, `\textasciicircum{}`, `_`, `\_`, `%`, `\%`, `~`, `\textasciitilde{}`, "\n", " ",
+	)
+)
+
+// LaTeXBundle returns the docs of the project with a \todo note of todonotes before the quote of each comment, and
+// todonotes loaded by the root doc. The notes of the quotes in floats, math, comments or titles are moved out of
+// them, see tex.NotePosition. The comments whose quote is not at its position in the doc, e.g. as they are orphaned,
+// are not inserted and are returned.
+func (r *CommentReport) LaTeXBundle() (map[string]string, []bson.ObjectID) {
+	type insertion struct {
+		position int // In runes
+		note     string
+	}
+	insertions := map[string][]insertion{}
+	var skipped []bson.ObjectID
+	docs := map[string]*ProjectDoc{}
+	for i := range r.Project.Docs {
+		docs[r.Project.Docs[i].ID] = &r.Project.Docs[i]
+	}
+
+	for _, section := range r.Sections {
+		for i := range section.Comments {
+			comment := &section.Comments[i]
+			doc, ok := docs[comment.DocID]
+			if !ok || comment.Orphaned || comment.QuoteText == "" {
+				skipped = append(skipped, comment.ID)
+				continue
+			}
+			runes := []rune(strings.Join(doc.Lines, "\n"))
+			if comment.QuotePosition < 0 || !strings.HasPrefix(string(runes[min(comment.QuotePosition, len(runes)):]), comment.QuoteText) {
+				skipped = append(skipped, comment.ID)
+				continue
+			}
+
+			color := todoColors[comment.ImportanceLevel]
+			if color == "" {
+				color = "gray!25"
+			}
+			content := string(runes)
+			offset, inline := tex.NotePosition(content, len(string(runes[:comment.QuotePosition])))
+			options := fmt.Sprintf(`color=%s,size=\scriptsize`, color)
+			if inline {
+				options = "inline," + options
+			}
+			text := latexSpecialReplacer.Replace(commentPrefixRegex.ReplaceAllString(comment.Comment, ""))
+			note := fmt.Sprintf(`\todo[%s]{%s}`, options, text)
+			insertions[doc.ID] = append(insertions[doc.ID], insertion{position: utf8.RuneCountInString(content[:offset]), note: note})
+		}
+	}
+
+	bundle := map[string]string{}
+	for _, doc := range r.Project.Docs {
+		content := strings.Join(doc.Lines, "\n")
+		if docInsertions := insertions[doc.ID]; len(docInsertions) > 0 {
+			// Inserted from the end, so that the positions of the other insertions do not move, and the notes at the
+			// same position stay in the order of the report
+			slices.Reverse(docInsertions)
+			sort.SliceStable(docInsertions, func(i, j int) bool { return docInsertions[i].position > docInsertions[j].position })
+			runes := []rune(content)
+			for _, insertion := range docInsertions {
+				runes = slices.Insert(runes, insertion.position, []rune(insertion.note)...)
+			}
+			content = string(runes)
+		}
+		if doc.ID == r.Project.RootDocID && !todonotesPackageRegex.MatchString(content) {
+			if loc := beginDocumentRegex.FindStringIndex(content); loc != nil {
+				content = content[:loc[0]] + "\\usepackage{todonotes}\n" + content[loc[0]:]
+			}
+		}
+		bundle[doc.Filepath] = content
+	}
+	return bundle, skipped
+}
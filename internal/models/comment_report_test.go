@@ -0,0 +1,128 @@
+package models_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"paperdebugger/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func newCommentReportTestData() (*models.Project, []models.Comment) {
+	project := &models.Project{
+		ProjectID: "p1",
+		Name:      "Sparse Attention",
+		RootDocID: "main",
+		Docs: []models.ProjectDoc{
+			{ID: "main", Version: 1, Filepath: "main.tex", Lines: []string{
+				`\documentclass{article}`,
+				`\begin{document}`,
+				`\section{Introduction}`,
+				`We propose a fast method.`,
+				`\input{method}`,
+				`\end{document}`,
+			}},
+			{ID: "method", Version: 1, Filepath: "method.tex", Lines: []string{
+				`\section{Method}`,
+				`It costs 50% less.`,
+			}},
+		},
+	}
+	comments := []models.Comment{
+		{BaseModel: models.BaseModel{ID: bson.NewObjectID()}, DocID: "method", DocPath: "method.tex", Section: "Method",
+			QuotePosition: 17, QuoteText: "It costs 50% less.", Comment: "👨🏻‍💻 Low: Compared to what_?", ImportanceLevel: models.ImportanceLevelLow},
+		{BaseModel: models.BaseModel{ID: bson.NewObjectID()}, DocID: "main", DocPath: "main.tex", Section: "Introduction",
+			QuotePosition: 77, QuoteText: "fast", Comment: "Fast is vague.", ImportanceLevel: models.ImportanceLevelMedium},
+		{BaseModel: models.BaseModel{ID: bson.NewObjectID()}, DocID: "method", DocPath: "method.tex", Section: "Method",
+			QuotePosition: 17, QuoteText: "It costs", Comment: "No baseline.", ImportanceLevel: models.ImportanceLevelCritical,
+			Replies: []models.CommentReply{{Role: models.CommentReplyRoleUser, Content: "Which one?"}, {Role: models.CommentReplyRoleAssistant, Content: "Dense attention."}}},
+		{BaseModel: models.BaseModel{ID: bson.NewObjectID()}, DocID: "main", DocPath: "main.tex",
+			QuoteText: "removed text", Comment: "Typo.", ImportanceLevel: models.ImportanceLevelHigh, Orphaned: true},
+	}
+	return project, comments
+}
+
+func TestCommentReportMarkdown(t *testing.T) {
+	project, comments := newCommentReportTestData()
+	report := models.NewCommentReport(project, comments)
+
+	var titles []string
+	for _, section := range report.Sections {
+		titles = append(titles, section.Title)
+	}
+	assert.Equal(t, []string{"Introduction", "Method", ""}, titles)
+	assert.Equal(t, models.ImportanceLevelCritical, report.Sections[1].Comments[0].ImportanceLevel)
+
+	markdown := report.Markdown()
+	assert.True(t, strings.HasPrefix(markdown, "# Review of Sparse Attention\n\n4 comments: 1 critical, 1 high, 1 medium, 1 low.\n"))
+	assert.Contains(t, markdown, "## Method\n\n### 1. Critical (`method.tex:2`)\n\n> It costs\n\nNo baseline.\n\n- **Reviewer:** Which one?\n- **Assistant:** Dense attention.\n")
+	assert.Contains(t, markdown, "## Other comments\n\n### 1. High (`main.tex`)\n\n> removed text\n\nTypo.\n\n_Status: open, the quoted text is no longer in the paper._\n")
+}
+
+func TestCommentReportJSON(t *testing.T) {
+	project, comments := newCommentReportTestData()
+	data, err := models.NewCommentReport(project, comments).JSON()
+	assert.NoError(t, err)
+
+	var report struct {
+		ProjectID string `json:"project_id"`
+		Sections  []struct {
+			Title    string `json:"title"`
+			Comments []struct {
+				Line       int    `json:"line"`
+				Importance string `json:"importance"`
+				Status     string `json:"status"`
+			} `json:"comments"`
+		} `json:"sections"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "p1", report.ProjectID)
+	assert.Len(t, report.Sections, 3)
+	assert.Equal(t, "Introduction", report.Sections[0].Title)
+	assert.Equal(t, 4, report.Sections[0].Comments[0].Line)
+	assert.Equal(t, "open", report.Sections[0].Comments[0].Status)
+}
+
+func TestCommentReportLaTeXBundle(t *testing.T) {
+	project, comments := newCommentReportTestData()
+	bundle, skipped := models.NewCommentReport(project, comments).LaTeXBundle()
+
+	assert.Equal(t, []bson.ObjectID{comments[3].ID}, skipped)
+	assert.Equal(t, "\\documentclass{article}\n\\usepackage{todonotes}\n\\begin{document}\n\\section{Introduction}\n"+
+		"We propose a \\todo[color=yellow!40,size=\\scriptsize]{Fast is vague.}fast method.\n\\input{method}\n\\end{document}", bundle["main.tex"])
+	assert.Equal(t, "\\section{Method}\n"+
+		"\\todo[color=red!40,size=\\scriptsize]{No baseline.}"+
+		"\\todo[color=green!25,size=\\scriptsize]{Low: Compared to what\\_?}It costs 50% less.", bundle["method.tex"])
+}
+
+func TestCommentReportLaTeXBundle_Float(t *testing.T) {
+	project := &models.Project{
+		RootDocID: "main",
+		Docs: []models.ProjectDoc{{ID: "main", Version: 1, Filepath: "main.tex", Lines: []string{
+			`Café results $x = 1$.`,
+			`\begin{figure}`,
+			`\includegraphics{plot}`,
+			`\label{fig:plot}`,
+			`\end{figure}`,
+		}}},
+	}
+	content := strings.Join(project.Docs[0].Lines, "\n")
+	position := func(quote string) int {
+		return utf8.RuneCountInString(content[:strings.Index(content, quote)])
+	}
+	comments := []models.Comment{
+		{BaseModel: models.BaseModel{ID: bson.NewObjectID()}, DocID: "main", QuotePosition: position("fig:plot"), QuoteText: "fig:plot",
+			Comment: "Unused label.", ImportanceLevel: models.ImportanceLevelLow},
+		{BaseModel: models.BaseModel{ID: bson.NewObjectID()}, DocID: "main", QuotePosition: position("1$"), QuoteText: "1",
+			Comment: "Which x?", ImportanceLevel: models.ImportanceLevelMedium},
+	}
+	bundle, skipped := models.NewCommentReport(project, comments).LaTeXBundle()
+
+	assert.Empty(t, skipped)
+	assert.Equal(t, "Café results \\todo[color=yellow!40,size=\\scriptsize]{Which x?}$x = 1$.\n"+
+		"\\todo[inline,color=green!25,size=\\scriptsize]{Unused label.}\\begin{figure}\n\\includegraphics{plot}\n\\label{fig:plot}\n\\end{figure}", bundle["main.tex"])
+}
@@ -0,0 +1,50 @@
+package models_test
+
+import (
+	"testing"
+
+	"paperdebugger/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentReanchor(t *testing.T) {
+	doc := &models.ProjectDoc{
+		ID:       "main",
+		Version:  3,
+		Filepath: "main.tex",
+		Lines:    []string{"Größe matters.", "the model is fast.", "Again, the model is fast."},
+	}
+
+	// The nearest occurrence of the quote is used
+	comment := &models.Comment{DocVersion: 2, QuotePosition: 30, QuoteText: "the model is fast"}
+	assert.True(t, comment.Reanchor(doc, 0.65))
+	assert.Equal(t, 41, comment.QuotePosition)
+	assert.Equal(t, 3, comment.DocVersion)
+	assert.False(t, comment.Orphaned)
+
+	// A quote that was slightly edited is matched approximately
+	comment = &models.Comment{DocVersion: 2, QuotePosition: 0, QuoteText: "Grösse matters"}
+	assert.True(t, comment.Reanchor(doc, 0.65))
+	assert.Equal(t, 0, comment.QuotePosition)
+	assert.Equal(t, "Größe matters", comment.QuoteText)
+
+	// A quote that disappeared orphans the comment
+	comment = &models.Comment{DocVersion: 2, QuotePosition: 5, QuoteText: "an unrelated sentence"}
+	assert.False(t, comment.Reanchor(doc, 0.65))
+	assert.True(t, comment.Orphaned)
+	assert.Equal(t, 5, comment.QuotePosition)
+	assert.Equal(t, "an unrelated sentence", comment.QuoteText)
+	assert.Equal(t, 3, comment.DocVersion)
+}
+
+func TestCommentQuoteContext(t *testing.T) {
+	doc := &models.ProjectDoc{Lines: []string{"one", "two", "thrée", "four", "five", "six"}}
+
+	comment := &models.Comment{QuotePosition: 11, QuoteText: "ée\nfo"} // In the third line
+	assert.Equal(t, "two\nthrée\nfour\nfive", comment.QuoteContext(doc, 1))
+	assert.Equal(t, "thrée\nfour", comment.QuoteContext(doc, 0))
+
+	comment = &models.Comment{QuotePosition: 0, QuoteText: "one"}
+	assert.Equal(t, "one\ntwo\nthrée", comment.QuoteContext(doc, 2))
+}
@@ -1,9 +1,12 @@
 package models
 
 import (
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
 	"github.com/openai/openai-go/v2/responses"
 	"github.com/openai/openai-go/v3"
 	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type Conversation struct {
@@ -19,8 +22,310 @@ type Conversation struct {
 	OpenaiChatParams            responses.ResponseNewParams              `bson:"openai_chat_params"`  // Conversation parameters, such as temperature, etc.
 	OpenaiChatHistoryCompletion []openai.ChatCompletionMessageParamUnion `bson:"openai_chat_history_completion"`
 	OpenaiChatParamsCompletion  openai.ChatCompletionNewParams           `bson:"openai_chat_params_completion"`
+
+	// The messages of chat v2 conversations form a tree: editing a message or regenerating a response adds a
+	// sibling instead of copying the history. The active branch is the path from the root to LeafID.
+	// Chat v2 conversations created before the tree have their histories above, see BuildMessageTree.
+	Messages []ConversationMessage `bson:"messages"`
+	LeafID   string                `bson:"leaf_id"`
+	// SystemPrompt is sent to the model before the messages of the active branch.
+	SystemPrompt string `bson:"system_prompt"`
+	// RetrievalEnabled is true if SystemPrompt does not include the paper, the excerpts relevant to each turn are
+	// sent with its user message instead. They are not stored, so that the history does not grow with them.
+	RetrievalEnabled bool `bson:"retrieval_enabled,omitempty"`
+
+	// ProjectVersion is the version of the project in SystemPrompt, see Project.ContentVersion. It is empty for
+	// debug conversations, and conversations created before it was recorded.
+	// The content of each version is kept in the revisions of the project, see ProjectRevision.
+	ProjectVersion string `bson:"project_version"`
+}
+
+// ConversationMessage is a message of the tree of a chat v2 conversation.
+type ConversationMessage struct {
+	ID       string `bson:"id"`        // The id of the in-app message
+	ParentID string `bson:"parent_id"` // Empty for the first message of a branch
+	Inapp    bson.M `bson:"inapp"`     // Stored as raw BSON, like InappChatHistory
+	// Openai are the messages sent to the model for this message. The tool calls of a response and their
+	// results are attached to its last message.
+	Openai []openai.ChatCompletionMessageParamUnion `bson:"openai"`
+	// Compacted is true if Openai replaces the messages of the ancestors, as they were condensed to fit the
+	// context window of the model.
+	Compacted bool `bson:"compacted,omitempty"`
+	// ProjectVersion is the version of the project the user message was sent with, empty in debug mode.
+	ProjectVersion string `bson:"project_version,omitempty"`
 }
 
 func (c Conversation) CollectionName() string {
 	return "conversations"
 }
+
+// InappMessageID returns the id of an in-app message.
+func InappMessageID(msg bson.M) string {
+	id, _ := msg["messageId"].(string)
+	return id
+}
+
+// InappMessageV2 decodes an in-app message of chat v2, it returns nil if the message is invalid.
+func InappMessageV2(msg bson.M) *chatv2.Message {
+	jsonBytes, err := bson.MarshalExtJSON(msg, true, true)
+	if err != nil {
+		return nil
+	}
+	m := &chatv2.Message{}
+	if err := protojson.Unmarshal(jsonBytes, m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// IsUser returns whether the message is a user message.
+func (m *ConversationMessage) IsUser() bool {
+	return InappMessageV2(m.Inapp).GetPayload().GetUser() != nil
+}
+
+// isContext returns whether the message tells the model about the context of the conversation rather than being
+// part of the exchange, such as a compaction or a change of the project.
+func (m *ConversationMessage) isContext() bool {
+	payload := InappMessageV2(m.Inapp).GetPayload()
+	return payload.GetCompaction() != nil || payload.GetProjectRefresh() != nil
+}
+
+// Message returns the message messageID of the tree, or nil if there is none.
+func (c *Conversation) Message(messageID string) *ConversationMessage {
+	for i := range c.Messages {
+		if c.Messages[i].ID == messageID {
+			return &c.Messages[i]
+		}
+	}
+	return nil
+}
+
+// HasMessage returns whether the conversation has the in-app message messageID, in any branch.
+func (c *Conversation) HasMessage(messageID string) bool {
+	for _, msg := range c.InappChatHistory {
+		if InappMessageID(msg) == messageID {
+			return true
+		}
+	}
+	return c.Message(messageID) != nil
+}
+
+// path returns the messages from the root to messageID.
+func (c *Conversation) path(messageID string) []*ConversationMessage {
+	byID := make(map[string]*ConversationMessage, len(c.Messages))
+	for i := range c.Messages {
+		byID[c.Messages[i].ID] = &c.Messages[i]
+	}
+
+	var path []*ConversationMessage
+	for id := messageID; id != "" && len(path) < len(c.Messages); {
+		msg, ok := byID[id]
+		if !ok {
+			break
+		}
+		path = append(path, msg)
+		id = msg.ParentID
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// ActiveBranch returns the messages of the active branch, from the root to the leaf.
+func (c *Conversation) ActiveBranch() []*ConversationMessage {
+	return c.path(c.LeafID)
+}
+
+// InappHistory returns the in-app messages of the active branch.
+func (c *Conversation) InappHistory() []bson.M {
+	branch := c.ActiveBranch()
+	history := make([]bson.M, len(branch))
+	for i, msg := range branch {
+		history[i] = msg.Inapp
+	}
+	return history
+}
+
+// OpenaiHistory returns the messages of the active branch sent to the model: the system prompt, then the messages
+// from the latest compaction.
+func (c *Conversation) OpenaiHistory() []openai.ChatCompletionMessageParamUnion {
+	var history []openai.ChatCompletionMessageParamUnion
+	if c.SystemPrompt != "" {
+		history = append(history, openai.SystemMessage(c.SystemPrompt))
+	}
+	branch := c.ActiveBranch()
+	start := 0
+	for i, msg := range branch {
+		if msg.Compacted {
+			start = i
+		}
+	}
+	for _, msg := range branch[start:] {
+		history = append(history, msg.Openai...)
+	}
+	return history
+}
+
+// BranchProjectVersion returns the version of the project of the latest user message of the active branch, or ""
+// if it is unknown.
+func (c *Conversation) BranchProjectVersion() string {
+	branch := c.ActiveBranch()
+	for i := len(branch) - 1; i >= 0; i-- {
+		if branch[i].ProjectVersion != "" {
+			return branch[i].ProjectVersion
+		}
+	}
+	return ""
+}
+
+// AppendMessages appends the messages to the active branch, each one being the child of the previous one, and
+// makes the last one the leaf.
+func (c *Conversation) AppendMessages(messages ...ConversationMessage) {
+	for _, msg := range messages {
+		msg.ParentID = c.LeafID
+		c.Messages = append(c.Messages, msg)
+		c.LeafID = msg.ID
+	}
+}
+
+// SwitchBranch makes active the latest branch containing the message messageID: the leaf becomes the most recent
+// descendant of the message. It returns false if the conversation has no such message.
+func (c *Conversation) SwitchBranch(messageID string) bool {
+	if c.Message(messageID) == nil {
+		return false
+	}
+	// Messages are appended in order of creation, so the latest child of a message is the last one
+	latestChild := map[string]string{}
+	for _, msg := range c.Messages {
+		latestChild[msg.ParentID] = msg.ID
+	}
+	leaf := messageID
+	for depth := 0; depth < len(c.Messages); depth++ {
+		child, ok := latestChild[leaf]
+		if !ok {
+			break
+		}
+		leaf = child
+	}
+	c.LeafID = leaf
+	return true
+}
+
+// MessageAlternatives returns, for each message of the active branch that has siblings, the message and its siblings
+// in order of creation. Siblings are the messages with the same parent, ignoring context messages, so that an edited
+// message stays an alternative of the original even if the project changed in between. The result is keyed by the
+// index of the message in the active branch.
+func (c *Conversation) MessageAlternatives() map[int][]bson.M {
+	byID := make(map[string]*ConversationMessage, len(c.Messages))
+	isContext := make(map[string]bool, len(c.Messages))
+	for i := range c.Messages {
+		byID[c.Messages[i].ID] = &c.Messages[i]
+		isContext[c.Messages[i].ID] = c.Messages[i].isContext()
+	}
+	// exchangeParentID returns the id of the nearest ancestor of the message that is not a context message
+	exchangeParentID := func(msg *ConversationMessage) string {
+		for depth := 0; depth < len(c.Messages); depth++ {
+			parent, ok := byID[msg.ParentID]
+			if !ok {
+				return ""
+			}
+			if !isContext[parent.ID] {
+				return parent.ID
+			}
+			msg = parent
+		}
+		return ""
+	}
+
+	siblings := map[string][]*ConversationMessage{}
+	for i := range c.Messages {
+		msg := &c.Messages[i]
+		if !isContext[msg.ID] {
+			parentID := exchangeParentID(msg)
+			siblings[parentID] = append(siblings[parentID], msg)
+		}
+	}
+
+	alternatives := map[int][]bson.M{}
+	for i, msg := range c.ActiveBranch() {
+		if isContext[msg.ID] {
+			continue
+		}
+		if group := siblings[exchangeParentID(msg)]; len(group) > 1 {
+			for _, sibling := range group {
+				alternatives[i] = append(alternatives[i], sibling.Inapp)
+			}
+		}
+	}
+	return alternatives
+}
+
+// NeedsMessageTree returns whether the conversation is a chat v2 conversation created before the message tree.
+func (c *Conversation) NeedsMessageTree() bool {
+	return len(c.Messages) == 0 && len(c.OpenaiChatHistoryCompletion) > 0
+}
+
+// BuildMessageTree converts the histories of a chat v2 conversation created before the message tree into a
+// branch of Messages, and clears them from c. They are kept in the database, see ChatServiceV2.GetConversationV2. The messages sent to the model are matched with the user turns of the
+// in-app history, so that earlier turns can still be edited. If they cannot be matched, they are all attached to
+// the last message.
+func (c *Conversation) BuildMessageTree() {
+	openaiHistory := c.OpenaiChatHistoryCompletion
+	defer func() {
+		c.InappChatHistory, c.OpenaiChatHistoryCompletion = nil, nil
+	}()
+	if len(openaiHistory) > 0 && openaiHistory[0].OfSystem != nil {
+		c.SystemPrompt = openaiHistory[0].OfSystem.Content.OfString.Value
+		openaiHistory = openaiHistory[1:]
+	}
+
+	c.Messages, c.LeafID = nil, ""
+	var userMessages []int // The indexes of the user messages in Messages
+	for _, inapp := range c.InappChatHistory {
+		id := InappMessageID(inapp)
+		if id == "" {
+			id = "pd_msg_" + bson.NewObjectID().Hex()
+		}
+		c.AppendMessages(ConversationMessage{ID: id, Inapp: inapp})
+		if c.Messages[len(c.Messages)-1].IsUser() {
+			userMessages = append(userMessages, len(c.Messages)-1)
+		}
+	}
+	if len(c.Messages) == 0 {
+		return
+	}
+	if len(userMessages) > 0 {
+		c.Messages[userMessages[len(userMessages)-1]].ProjectVersion = c.ProjectVersion
+	}
+
+	// Each turn starts with a user message in both histories
+	var openaiTurns []int
+	for i, msg := range openaiHistory {
+		if msg.OfUser != nil {
+			openaiTurns = append(openaiTurns, i)
+		}
+	}
+	if len(openaiTurns) != len(userMessages) || len(openaiTurns) == 0 || userMessages[0] != 0 {
+		last := &c.Messages[len(c.Messages)-1]
+		last.Openai = openaiHistory
+		last.Compacted = true
+		return
+	}
+	for turn, start := range openaiTurns {
+		end, last := len(openaiHistory), len(c.Messages)-1
+		if turn+1 < len(openaiTurns) {
+			end, last = openaiTurns[turn+1], userMessages[turn+1]-1
+		}
+		if turn == 0 {
+			start = 0 // Messages before the first user message, if any
+		}
+		user := &c.Messages[userMessages[turn]]
+		user.Openai = openaiHistory[start : openaiTurns[turn]+1]
+		if last == userMessages[turn] {
+			user.Openai = openaiHistory[start:end]
+		} else {
+			c.Messages[last].Openai = openaiHistory[openaiTurns[turn]+1 : end]
+		}
+	}
+}
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
 // LLMProviderConfig holds the configuration for LLM API calls.
 // If both Endpoint and APIKey are empty, the system default will be used.
 // If IsCustomModel is true, the user-requested slug with corresponding
@@ -7,6 +13,7 @@ package models
 type LLMProviderConfig struct {
 	Endpoint      string
 	APIKey        string
+	APIKeyAAD     string // The data the encrypted APIKey is bound to, see APIKeyAAD
 	ModelName     string
 	IsCustomModel bool
 }
@@ -15,3 +22,21 @@ type LLMProviderConfig struct {
 func (c *LLMProviderConfig) IsCustom() bool {
 	return c != nil && c.APIKey != ""
 }
+
+// NewCustomModelLLMProvider returns the configuration of the calls to the custom model of the user. The custom
+// model is always called over HTTPS, and the system default is used in place of the PaperDebugger endpoints.
+func NewCustomModelLLMProvider(userID bson.ObjectID, customModel *CustomModel) *LLMProviderConfig {
+	endpoint := strings.ToLower(customModel.BaseUrl)
+	if strings.Contains(endpoint, "paperdebugger.com") {
+		endpoint = ""
+	}
+	if endpoint != "" && !strings.HasPrefix(endpoint, "https://") {
+		endpoint = "https://" + strings.Replace(endpoint, "http://", "", 1)
+	}
+	return &LLMProviderConfig{
+		APIKey:        customModel.APIKey,
+		APIKeyAAD:     APIKeyAAD(userID, &customModel.Id),
+		Endpoint:      endpoint,
+		IsCustomModel: true,
+	}
+}
@@ -0,0 +1,31 @@
+package models_test
+
+import (
+	"testing"
+
+	"paperdebugger/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestNewCustomModelLLMProvider(t *testing.T) {
+	userID := bson.NewObjectID()
+	settings := &models.Settings{CustomModels: []models.CustomModel{
+		{Id: bson.NewObjectID(), Slug: "local", BaseUrl: "http://LLM.example.com/v1", APIKey: "key"},
+		{Id: bson.NewObjectID(), Slug: "hosted", BaseUrl: "https://api.paperdebugger.com/v1"},
+	}}
+
+	local := settings.FindCustomModel(settings.CustomModels[0].Id.Hex())
+	assert.Equal(t, &settings.CustomModels[0], local)
+	provider := models.NewCustomModelLLMProvider(userID, local)
+	assert.Equal(t, "https://llm.example.com/v1", provider.Endpoint)
+	assert.Equal(t, "key", provider.APIKey)
+	assert.Equal(t, models.APIKeyAAD(userID, &local.Id), provider.APIKeyAAD)
+	assert.True(t, provider.IsCustomModel)
+
+	hosted := models.NewCustomModelLLMProvider(userID, &settings.CustomModels[1])
+	assert.Empty(t, hosted.Endpoint)
+
+	assert.Nil(t, settings.FindCustomModel(bson.NewObjectID().Hex()))
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"crypto/sha1"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -31,6 +34,7 @@ type Project struct {
 	Name         string                `bson:"name"`
 	RootDocID    string                `bson:"root_doc_id"`
 	Docs         []ProjectDoc          `bson:"docs"`
+	FilePaths    []string              `bson:"file_paths"` // Paths of the files that are not docs, e.g. images
 	Category     ClassifyPaperResponse `bson:"category,omitempty"`
 	Instructions string                `bson:"instructions"`
 }
@@ -40,6 +44,69 @@ func (u Project) CollectionName() string {
 }
 
 func (u *Project) GetFullContent() (string, error) {
+	docs, rootDoc, err := u.latexpandInput()
+	if err != nil {
+		return "", err
+	}
+	return tex.Latexpand(docs, rootDoc)
+}
+
+// GetFullContentWithSourceMap is GetFullContent, and also returns the map from the full content to the docs.
+func (u *Project) GetFullContentWithSourceMap() (string, *tex.SourceMap, error) {
+	docs, rootDoc, err := u.latexpandInput()
+	if err != nil {
+		return "", nil, err
+	}
+	return tex.LatexpandWithSourceMap(docs, rootDoc)
+}
+
+// GetPlainText returns the full content rendered as plain text, with the user macros expanded, for the features
+// that only need the prose of the paper.
+func (u *Project) GetPlainText() (string, error) {
+	fullContent, err := u.GetFullContent()
+	if err != nil {
+		return "", err
+	}
+	return tex.Detex(tex.ExpandMacros(fullContent)), nil
+}
+
+// GetFullContentWithMacros is GetFullContent with the user macros given as set by mode: expanded in the full
+// content, or listed in the returned glossary.
+func (u *Project) GetFullContentWithMacros(mode MacroExpansion) (string, string, error) {
+	fullContent, err := u.GetFullContent()
+	if err != nil {
+		return "", "", err
+	}
+	switch mode {
+	case MacroExpansionExpand:
+		return tex.ExpandMacros(fullContent), "", nil
+	case MacroExpansionGlossary:
+		return fullContent, tex.MacroGlossary(tex.CollectMacros(fullContent)), nil
+	default:
+		return fullContent, "", nil
+	}
+}
+
+// ExpandContent returns the full content with its source map, and the includes that could not be expanded.
+func (u *Project) ExpandContent() (*tex.Expansion, error) {
+	docs, rootDoc, err := u.latexpandInput()
+	if err != nil {
+		return nil, err
+	}
+	return tex.Expand(docs, rootDoc)
+}
+
+// Lint runs the static checks of tex.Lint on the project. Graphics are only checked if the files of the project
+// were synced.
+func (u *Project) Lint() ([]tex.LintFinding, error) {
+	docs, rootDoc, err := u.latexpandInput()
+	if err != nil {
+		return nil, err
+	}
+	return tex.Lint(docs, rootDoc, u.FilePaths)
+}
+
+func (u *Project) latexpandInput() (map[string]string, string, error) {
 	docs := make(map[string]string)
 	for _, doc := range u.Docs {
 		docs[doc.Filepath] = strings.Join(doc.Lines, "\n")
@@ -48,11 +115,28 @@ func (u *Project) GetFullContent() (string, error) {
 		return doc.ID == u.RootDocID
 	})
 	if !ok {
-		return "", shared.ErrInternal("root doc not found")
+		return nil, "", shared.ErrInternal("root doc not found")
 	}
-	return tex.Latexpand(docs, rootDoc.Filepath)
+	return docs, rootDoc.Filepath, nil
 }
 
 func (u *Project) IsOutOfDate() bool {
 	return u.UpdatedAt.Time().Before(time.Now().Add(-time.Minute * 30))
 }
+
+// ContentVersion identifies the content of the project. It changes whenever a doc is
+// added, removed or updated, or the root doc changes.
+func (u *Project) ContentVersion() string {
+	keys := make([]string, len(u.Docs))
+	for i, doc := range u.Docs {
+		keys[i] = fmt.Sprintf("%s:%d:%s", doc.ID, doc.Version, doc.Filepath)
+	}
+	sort.Strings(keys)
+
+	h := sha1.New()
+	h.Write([]byte(u.RootDocID))
+	for _, key := range keys {
+		h.Write([]byte("\n" + key))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
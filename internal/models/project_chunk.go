@@ -0,0 +1,21 @@
+package models
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// ProjectChunk is a retrievable piece of the expanded LaTeX of a project version,
+// used when the full document RAG setting is enabled.
+type ProjectChunk struct {
+	BaseModel      `bson:",inline"`
+	UserID         bson.ObjectID `bson:"user_id"`
+	ProjectID      string        `bson:"project_id"`
+	ProjectVersion string        `bson:"project_version"`
+	Index          int           `bson:"index"`
+	Section        string        `bson:"section"`
+	Text           string        `bson:"text"`
+	EmbeddingModel string        `bson:"embedding_model,omitempty"`
+	Embedding      []float64     `bson:"embedding,omitempty"`
+}
+
+func (c ProjectChunk) CollectionName() string {
+	return "project_chunks"
+}
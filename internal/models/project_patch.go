@@ -0,0 +1,151 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+
+	"paperdebugger/internal/libs/shared"
+)
+
+type DocPatchType int
+
+const (
+	DocPatchEdit DocPatchType = iota + 1
+	DocPatchAdd
+	DocPatchRemove
+	DocPatchRename
+)
+
+// DocLineEdit replaces DeleteCount lines from StartLine with Lines. Lines are numbered from 0 in the base version.
+type DocLineEdit struct {
+	StartLine   int
+	DeleteCount int
+	Lines       []string
+}
+
+// DocPatch is a change to a doc of a project, applied to the BaseVersion of the doc.
+type DocPatch struct {
+	Type        DocPatchType
+	DocID       string
+	BaseVersion int
+	Version     int
+	Filepath    string        // For DocPatchAdd and DocPatchRename
+	Lines       []string      // For DocPatchAdd
+	Edits       []DocLineEdit // For DocPatchEdit, must not overlap
+}
+
+// ApplyDocPatches applies the patches to the docs of the project, and changes the root doc if rootDocID is not empty.
+// The project is left unchanged if an error is returned. Patches whose base version is not the current version
+// of the doc fail with ErrDocVersionConflict, other invalid patches with ErrBadRequest.
+func (u *Project) ApplyDocPatches(patches []DocPatch, rootDocID string) error {
+	docs := make([]ProjectDoc, len(u.Docs))
+	copy(docs, u.Docs)
+	index := map[string]int{}
+	for i, doc := range docs {
+		index[doc.ID] = i
+	}
+
+	patched := map[string]bool{}
+	removed := map[string]bool{}
+	for _, patch := range patches {
+		if patch.DocID == "" {
+			return shared.ErrBadRequest("doc_id is required")
+		}
+		if patched[patch.DocID] {
+			return shared.ErrBadRequest(fmt.Sprintf("doc %s is patched more than once", patch.DocID))
+		}
+		patched[patch.DocID] = true
+
+		if patch.Type == DocPatchAdd {
+			if _, ok := index[patch.DocID]; ok {
+				return shared.ErrDocVersionConflict(fmt.Sprintf("doc %s already exists", patch.DocID))
+			}
+			if patch.Filepath == "" {
+				return shared.ErrBadRequest("filepath is required")
+			}
+			index[patch.DocID] = len(docs)
+			docs = append(docs, ProjectDoc{
+				ID:       patch.DocID,
+				Version:  patch.Version,
+				Filepath: patch.Filepath,
+				Lines:    patch.Lines,
+			})
+			continue
+		}
+
+		i, ok := index[patch.DocID]
+		if !ok {
+			return shared.ErrDocVersionConflict(fmt.Sprintf("doc %s does not exist", patch.DocID))
+		}
+		doc := docs[i]
+		if doc.Version != patch.BaseVersion {
+			return shared.ErrDocVersionConflict(fmt.Sprintf("doc %s is at version %d, not %d", doc.ID, doc.Version, patch.BaseVersion))
+		}
+		if patch.Type != DocPatchRemove && patch.Version < doc.Version {
+			return shared.ErrBadRequest("doc version is less than existing doc version")
+		}
+
+		switch patch.Type {
+		case DocPatchEdit:
+			lines, err := applyLineEdits(doc.Lines, patch.Edits)
+			if err != nil {
+				return err
+			}
+			doc.Lines = lines
+		case DocPatchRemove:
+			removed[doc.ID] = true
+		case DocPatchRename:
+			if patch.Filepath == "" {
+				return shared.ErrBadRequest("filepath is required")
+			}
+			doc.Filepath = patch.Filepath
+		default:
+			return shared.ErrBadRequest(fmt.Sprintf("unknown patch type %d", patch.Type))
+		}
+		doc.Version = patch.Version
+		docs[i] = doc
+	}
+
+	result := make([]ProjectDoc, 0, len(docs))
+	filepaths := map[string]bool{}
+	for _, doc := range docs {
+		if removed[doc.ID] {
+			continue
+		}
+		if filepaths[doc.Filepath] {
+			return shared.ErrBadRequest(fmt.Sprintf("more than one doc at %s", doc.Filepath))
+		}
+		filepaths[doc.Filepath] = true
+		result = append(result, doc)
+	}
+
+	if rootDocID == "" {
+		rootDocID = u.RootDocID
+	}
+	if _, ok := index[rootDocID]; !ok || removed[rootDocID] {
+		return shared.ErrBadRequest("root doc not found")
+	}
+
+	u.Docs = result
+	u.RootDocID = rootDocID
+	return nil
+}
+
+// applyLineEdits returns the lines with the edits applied, without modifying them.
+func applyLineEdits(lines []string, edits []DocLineEdit) ([]string, error) {
+	sorted := make([]DocLineEdit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartLine < sorted[j].StartLine })
+
+	result := make([]string, 0, len(lines))
+	next := 0 // First line of the base version not copied to the result yet
+	for _, edit := range sorted {
+		if edit.StartLine < next || edit.DeleteCount < 0 || edit.StartLine+edit.DeleteCount > len(lines) {
+			return nil, shared.ErrBadRequest(fmt.Sprintf("invalid edit of lines %d to %d", edit.StartLine, edit.StartLine+edit.DeleteCount))
+		}
+		result = append(result, lines[next:edit.StartLine]...)
+		result = append(result, edit.Lines...)
+		next = edit.StartLine + edit.DeleteCount
+	}
+	return append(result, lines[next:]...), nil
+}
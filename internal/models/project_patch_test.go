@@ -0,0 +1,112 @@
+package models_test
+
+import (
+	"testing"
+
+	"paperdebugger/internal/models"
+	sharedv1 "paperdebugger/pkg/gen/api/shared/v1"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newPatchTestProject() *models.Project {
+	return &models.Project{
+		RootDocID: "main",
+		Docs: []models.ProjectDoc{
+			{ID: "main", Version: 3, Filepath: "main.tex", Lines: []string{"a", "b", "c", "d"}},
+			{ID: "intro", Version: 1, Filepath: "intro.tex", Lines: []string{"intro"}},
+		},
+	}
+}
+
+func TestApplyDocPatches(t *testing.T) {
+	project := newPatchTestProject()
+	err := project.ApplyDocPatches([]models.DocPatch{
+		{
+			Type: models.DocPatchEdit, DocID: "main", BaseVersion: 3, Version: 5,
+			Edits: []models.DocLineEdit{
+				{StartLine: 3, DeleteCount: 1, Lines: []string{"D"}},
+				{StartLine: 0, DeleteCount: 0, Lines: []string{"first"}},
+				{StartLine: 1, DeleteCount: 2},
+			},
+		},
+		{Type: models.DocPatchRename, DocID: "intro", BaseVersion: 1, Version: 1, Filepath: "sections/intro.tex"},
+		{Type: models.DocPatchAdd, DocID: "appendix", Version: 1, Filepath: "appendix.tex", Lines: []string{"appendix"}},
+	}, "")
+	assert.NoError(t, err)
+
+	assert.Len(t, project.Docs, 3)
+	assert.Equal(t, []string{"first", "a", "D"}, project.Docs[0].Lines)
+	assert.Equal(t, 5, project.Docs[0].Version)
+	assert.Equal(t, "sections/intro.tex", project.Docs[1].Filepath)
+	assert.Equal(t, "appendix.tex", project.Docs[2].Filepath)
+
+	assert.NoError(t, project.ApplyDocPatches([]models.DocPatch{
+		{Type: models.DocPatchRemove, DocID: "main", BaseVersion: 5},
+	}, "appendix"))
+	assert.Len(t, project.Docs, 2)
+	assert.Equal(t, "appendix", project.RootDocID)
+}
+
+func TestApplyDocPatches_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		patches []models.DocPatch
+		code    sharedv1.ErrorCode
+	}{
+		{
+			name:    "stale base version",
+			patches: []models.DocPatch{{Type: models.DocPatchEdit, DocID: "main", BaseVersion: 2, Version: 4}},
+			code:    sharedv1.ErrorCode_ERROR_CODE_DOC_VERSION_CONFLICT,
+		},
+		{
+			name:    "unknown doc",
+			patches: []models.DocPatch{{Type: models.DocPatchRename, DocID: "missing", BaseVersion: 1, Version: 1, Filepath: "x.tex"}},
+			code:    sharedv1.ErrorCode_ERROR_CODE_DOC_VERSION_CONFLICT,
+		},
+		{
+			name:    "existing doc added",
+			patches: []models.DocPatch{{Type: models.DocPatchAdd, DocID: "intro", Version: 1, Filepath: "x.tex"}},
+			code:    sharedv1.ErrorCode_ERROR_CODE_DOC_VERSION_CONFLICT,
+		},
+		{
+			name:    "version decreases",
+			patches: []models.DocPatch{{Type: models.DocPatchEdit, DocID: "main", BaseVersion: 3, Version: 2}},
+			code:    sharedv1.ErrorCode_ERROR_CODE_BAD_REQUEST,
+		},
+		{
+			name: "overlapping edits",
+			patches: []models.DocPatch{{Type: models.DocPatchEdit, DocID: "main", BaseVersion: 3, Version: 4, Edits: []models.DocLineEdit{
+				{StartLine: 0, DeleteCount: 2},
+				{StartLine: 1, DeleteCount: 1},
+			}}},
+			code: sharedv1.ErrorCode_ERROR_CODE_BAD_REQUEST,
+		},
+		{
+			name:    "edit out of range",
+			patches: []models.DocPatch{{Type: models.DocPatchEdit, DocID: "main", BaseVersion: 3, Version: 4, Edits: []models.DocLineEdit{{StartLine: 3, DeleteCount: 2}}}},
+			code:    sharedv1.ErrorCode_ERROR_CODE_BAD_REQUEST,
+		},
+		{
+			name:    "duplicate filepath",
+			patches: []models.DocPatch{{Type: models.DocPatchRename, DocID: "intro", BaseVersion: 1, Version: 1, Filepath: "main.tex"}},
+			code:    sharedv1.ErrorCode_ERROR_CODE_BAD_REQUEST,
+		},
+		{
+			name:    "root doc removed",
+			patches: []models.DocPatch{{Type: models.DocPatchRemove, DocID: "main", BaseVersion: 3}},
+			code:    sharedv1.ErrorCode_ERROR_CODE_BAD_REQUEST,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := newPatchTestProject()
+			err := project.ApplyDocPatches(tt.patches, "")
+			assert.Equal(t, codes.Code(tt.code), status.Code(err))
+			assert.Equal(t, newPatchTestProject(), project)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ProjectRevision is a snapshot of the docs of a project, recorded when UpsertProject changes its content.
+// The content of the docs is stored in ProjectBlobs, so that docs unchanged between revisions are not duplicated.
+type ProjectRevision struct {
+	BaseModel      `bson:",inline"`
+	UserID         bson.ObjectID        `bson:"user_id"`
+	ProjectID      string               `bson:"project_id"`
+	Revision       int                  `bson:"revision"` // 1 for the first revision of the project
+	ContentVersion string               `bson:"content_version"`
+	RootDocID      string               `bson:"root_doc_id"`
+	Docs           []ProjectRevisionDoc `bson:"docs"`
+}
+
+type ProjectRevisionDoc struct {
+	ID       string `bson:"id"`
+	Version  int    `bson:"version"`
+	Filepath string `bson:"filepath"`
+	BlobHash string `bson:"blob_hash"`
+}
+
+func (r ProjectRevision) CollectionName() string {
+	return "project_revisions"
+}
+
+// ProjectBlob is the content of a doc, addressed by its hash. Blobs are not garbage-collected yet, they are kept
+// after the revisions and the projects using them are deleted.
+type ProjectBlob struct {
+	BaseModel `bson:",inline"`
+	UserID    bson.ObjectID `bson:"user_id"`
+	Hash      string        `bson:"hash"`
+	Lines     []string      `bson:"lines"`
+}
+
+func (b ProjectBlob) CollectionName() string {
+	return "project_blobs"
+}
+
+// BlobHash returns the hash addressing the content of the doc in ProjectBlobs.
+func (d *ProjectDoc) BlobHash() string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(d.Lines, "\n"))))
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/libs/textdiff"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// proposedEditDiffContext is the number of unchanged lines around the change of a proposed edit diff.
+const proposedEditDiffContext = 3
+
+// The status of a proposed edit
+type ProposedEditStatus string
+
+const (
+	ProposedEditPending  ProposedEditStatus = "pending"
+	ProposedEditAccepted ProposedEditStatus = "accepted"
+	ProposedEditRejected ProposedEditStatus = "rejected"
+)
+
+// ProposedEdit is a change to a doc proposed by the assistant. It is not applied to the project by the server: the
+// user applies it in the editor by accepting it, or rejects it.
+type ProposedEdit struct {
+	BaseModel      `bson:",inline"`
+	UserID         bson.ObjectID      `bson:"user_id"`
+	ProjectID      string             `bson:"project_id"`
+	ConversationID string             `bson:"conversation_id"`
+	ToolCallID     string             `bson:"tool_call_id"`
+	DocID          string             `bson:"doc_id"`
+	DocVersion     int                `bson:"doc_version"` // The version of the doc the edit was proposed for
+	DocPath        string             `bson:"doc_path"`
+	StartLine      int                `bson:"start_line"` // The first replaced line, starting at 1
+	OldLines       []string           `bson:"old_lines"`  // The replaced lines
+	NewLines       []string           `bson:"new_lines"`
+	Diff           string             `bson:"diff"` // Unified diff of the doc
+	Status         ProposedEditStatus `bson:"status"`
+}
+
+func (e ProposedEdit) CollectionName() string {
+	return "proposed_edits"
+}
+
+// NewLineRangeEdit returns a pending edit replacing the lines startLine to endLine of the doc, numbered from 1 and
+// endLine included, with the lines of replacement. A trailing line break of the replacement is ignored, and an
+// empty replacement deletes the lines.
+func NewLineRangeEdit(doc *ProjectDoc, startLine int, endLine int, replacement string) (*ProposedEdit, error) {
+	if startLine < 1 || endLine < startLine || endLine > len(doc.Lines) {
+		return nil, shared.ErrBadRequest(fmt.Sprintf("invalid line range %d-%d, %s has %d lines", startLine, endLine, doc.Filepath, len(doc.Lines)))
+	}
+	var newLines []string
+	if replacement != "" {
+		newLines = strings.Split(strings.TrimSuffix(replacement, "\n"), "\n")
+	}
+	return newProposedEdit(doc, startLine-1, endLine, newLines), nil
+}
+
+// NewAnchorEdit returns a pending edit replacing the anchor text with replacement. The anchor may span several
+// lines, and must occur exactly once in the doc.
+func NewAnchorEdit(doc *ProjectDoc, anchor string, replacement string) (*ProposedEdit, error) {
+	if anchor == "" {
+		return nil, shared.ErrBadRequest("anchor text is required")
+	}
+	content := strings.Join(doc.Lines, "\n")
+	offset := strings.Index(content, anchor)
+	if offset < 0 {
+		return nil, shared.ErrBadRequest(fmt.Sprintf("anchor text not found in %s", doc.Filepath))
+	}
+	if count := strings.Count(content, anchor); count > 1 {
+		return nil, shared.ErrBadRequest(fmt.Sprintf("anchor text found %d times in %s, it must be unique", count, doc.Filepath))
+	}
+
+	start := strings.Count(content[:offset], "\n")
+	end := start + strings.Count(anchor, "\n") + 1
+	lineOffset := strings.LastIndex(content[:offset], "\n") + 1
+	oldText := strings.Join(doc.Lines[start:end], "\n")
+	newText := oldText[:offset-lineOffset] + replacement + oldText[offset-lineOffset+len(anchor):]
+	return newProposedEdit(doc, start, end, strings.Split(newText, "\n")), nil
+}
+
+// newProposedEdit returns a pending edit replacing the lines start to end of the doc, numbered from 0 and end
+// excluded, with newLines.
+func newProposedEdit(doc *ProjectDoc, start int, end int, newLines []string) *ProposedEdit {
+	edit := &ProposedEdit{
+		DocID:      doc.ID,
+		DocVersion: doc.Version,
+		DocPath:    doc.Filepath,
+		StartLine:  start + 1,
+		OldLines:   slices.Clone(doc.Lines[start:end]),
+		NewLines:   newLines,
+		Status:     ProposedEditPending,
+	}
+	edit.Diff = textdiff.Unified("a/"+doc.Filepath, "b/"+doc.Filepath, doc.Lines, edit.Apply(doc.Lines), proposedEditDiffContext)
+	return edit
+}
+
+// Apply returns the lines of the doc with the edit applied, without modifying them.
+func (e *ProposedEdit) Apply(lines []string) []string {
+	start := e.StartLine - 1
+	result := make([]string, 0, len(lines)-len(e.OldLines)+len(e.NewLines))
+	result = append(result, lines[:start]...)
+	result = append(result, e.NewLines...)
+	return append(result, lines[start+len(e.OldLines):]...)
+}
+
+// AppliesTo returns whether the edit can still be applied to the doc: it is the version the edit was proposed for,
+// or a later version in which the replaced lines are unchanged.
+func (e *ProposedEdit) AppliesTo(doc *ProjectDoc) bool {
+	if doc.ID != e.DocID {
+		return false
+	}
+	if doc.Version == e.DocVersion {
+		return true
+	}
+	start := e.StartLine - 1
+	if start < 0 || start+len(e.OldLines) > len(doc.Lines) {
+		return false
+	}
+	return slices.Equal(doc.Lines[start:start+len(e.OldLines)], e.OldLines)
+}
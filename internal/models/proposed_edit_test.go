@@ -0,0 +1,79 @@
+package models_test
+
+import (
+	"testing"
+
+	"paperdebugger/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newProposedEditTestDoc() *models.ProjectDoc {
+	return &models.ProjectDoc{
+		ID:       "main",
+		Version:  4,
+		Filepath: "main.tex",
+		Lines:    []string{`\section{Intro}`, "We show that", "it works.", "", "The end."},
+	}
+}
+
+func TestNewLineRangeEdit(t *testing.T) {
+	doc := newProposedEditTestDoc()
+	edit, err := models.NewLineRangeEdit(doc, 2, 3, "We prove that it works.\n")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, edit.StartLine)
+	assert.Equal(t, []string{"We show that", "it works."}, edit.OldLines)
+	assert.Equal(t, []string{"We prove that it works."}, edit.NewLines)
+	assert.Equal(t, models.ProposedEditPending, edit.Status)
+	assert.Equal(t, 4, edit.DocVersion)
+	assert.Equal(t, "--- a/main.tex\n+++ b/main.tex\n@@ -1,5 +1,4 @@\n \\section{Intro}\n-We show that\n-it works.\n+We prove that it works.\n \n The end.\n", edit.Diff)
+	assert.Equal(t, []string{`\section{Intro}`, "We prove that it works.", "", "The end."}, edit.Apply(doc.Lines))
+
+	edit, err = models.NewLineRangeEdit(doc, 4, 4, "")
+	assert.NoError(t, err)
+	assert.Empty(t, edit.NewLines)
+	assert.Len(t, edit.Apply(doc.Lines), 4)
+
+	_, err = models.NewLineRangeEdit(doc, 3, 6, "x")
+	assert.Error(t, err)
+	_, err = models.NewLineRangeEdit(doc, 3, 2, "x")
+	assert.Error(t, err)
+}
+
+func TestNewAnchorEdit(t *testing.T) {
+	doc := newProposedEditTestDoc()
+	edit, err := models.NewAnchorEdit(doc, "show that\nit", "prove that it")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, edit.StartLine)
+	assert.Equal(t, []string{"We show that", "it works."}, edit.OldLines)
+	assert.Equal(t, []string{"We prove that it works."}, edit.NewLines)
+
+	edit, err = models.NewAnchorEdit(doc, "end", "beginning")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, edit.StartLine)
+	assert.Equal(t, []string{"The beginning."}, edit.NewLines)
+
+	_, err = models.NewAnchorEdit(doc, "missing", "x")
+	assert.Error(t, err)
+	_, err = models.NewAnchorEdit(doc, "t", "x")
+	assert.ErrorContains(t, err, "must be unique")
+}
+
+func TestProposedEditAppliesTo(t *testing.T) {
+	doc := newProposedEditTestDoc()
+	edit, err := models.NewLineRangeEdit(doc, 2, 2, "We prove that")
+	assert.NoError(t, err)
+	assert.True(t, edit.AppliesTo(doc))
+
+	// A later version is fine as long as the replaced lines are unchanged
+	changed := newProposedEditTestDoc()
+	changed.Version = 5
+	changed.Lines[4] = "The very end."
+	assert.True(t, edit.AppliesTo(changed))
+
+	changed.Lines[1] = "We claim that"
+	assert.False(t, edit.AppliesTo(changed))
+
+	changed.Lines = changed.Lines[:1]
+	assert.False(t, edit.AppliesTo(changed))
+}
@@ -6,16 +6,19 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-// HourlyUsage tracks cost per user, per project, per hour.
+// HourlyUsage tracks cost per user, per project, per model, per hour.
 // Each document represents one hour bucket of usage.
 type HourlyUsage struct {
-	ID          bson.ObjectID `bson:"_id"`
-	UserID      bson.ObjectID `bson:"user_id"`
-	ProjectID   string        `bson:"project_id"`
-	HourBucket  bson.DateTime `bson:"hour_bucket"`  // Timestamp truncated to the hour
-	SuccessCost float64       `bson:"success_cost"` // Cost in USD for successful requests
-	FailedCost  float64       `bson:"failed_cost"`  // Cost in USD for failed requests
-	UpdatedAt   bson.DateTime `bson:"updated_at"`
+	ID               bson.ObjectID `bson:"_id"`
+	UserID           bson.ObjectID `bson:"user_id"`
+	ProjectID        string        `bson:"project_id"`
+	ModelSlug        string        `bson:"model_slug"`        // Empty for usage tracked before models were recorded
+	HourBucket       bson.DateTime `bson:"hour_bucket"`       // Timestamp truncated to the hour
+	SuccessCost      float64       `bson:"success_cost"`      // Cost in USD for successful requests
+	FailedCost       float64       `bson:"failed_cost"`       // Cost in USD for failed requests
+	PromptTokens     int64         `bson:"prompt_tokens"`     // Prompt tokens of all requests
+	CompletionTokens int64         `bson:"completion_tokens"` // Completion tokens of all requests
+	UpdatedAt        bson.DateTime `bson:"updated_at"`
 }
 
 func (u HourlyUsage) CollectionName() string {
@@ -25,13 +28,15 @@ func (u HourlyUsage) CollectionName() string {
 // WeeklyUsage tracks cost per user, per project, per week.
 // Each document represents one week bucket of usage.
 type WeeklyUsage struct {
-	ID          bson.ObjectID `bson:"_id"`
-	UserID      bson.ObjectID `bson:"user_id"`
-	ProjectID   string        `bson:"project_id"`
-	WeekBucket  bson.DateTime `bson:"week_bucket"`  // Timestamp truncated to the week (Monday)
-	SuccessCost float64       `bson:"success_cost"` // Cost in USD for successful requests
-	FailedCost  float64       `bson:"failed_cost"`  // Cost in USD for failed requests
-	UpdatedAt   bson.DateTime `bson:"updated_at"`
+	ID               bson.ObjectID `bson:"_id"`
+	UserID           bson.ObjectID `bson:"user_id"`
+	ProjectID        string        `bson:"project_id"`
+	WeekBucket       bson.DateTime `bson:"week_bucket"`       // Timestamp truncated to the week (Monday)
+	SuccessCost      float64       `bson:"success_cost"`      // Cost in USD for successful requests
+	FailedCost       float64       `bson:"failed_cost"`       // Cost in USD for failed requests
+	PromptTokens     int64         `bson:"prompt_tokens"`     // Prompt tokens of all requests
+	CompletionTokens int64         `bson:"completion_tokens"` // Completion tokens of all requests
+	UpdatedAt        bson.DateTime `bson:"updated_at"`
 }
 
 func (u WeeklyUsage) CollectionName() string {
@@ -41,12 +46,14 @@ func (u WeeklyUsage) CollectionName() string {
 // LifetimeUsage tracks total cost per user, per project, across all time.
 // Each document represents the cumulative usage for a user-project pair.
 type LifetimeUsage struct {
-	ID          bson.ObjectID `bson:"_id"`
-	UserID      bson.ObjectID `bson:"user_id"`
-	ProjectID   string        `bson:"project_id"`
-	SuccessCost float64       `bson:"success_cost"` // Total cost in USD for successful requests
-	FailedCost  float64       `bson:"failed_cost"`  // Total cost in USD for failed requests
-	UpdatedAt   bson.DateTime `bson:"updated_at"`
+	ID               bson.ObjectID `bson:"_id"`
+	UserID           bson.ObjectID `bson:"user_id"`
+	ProjectID        string        `bson:"project_id"`
+	SuccessCost      float64       `bson:"success_cost"`      // Total cost in USD for successful requests
+	FailedCost       float64       `bson:"failed_cost"`       // Total cost in USD for failed requests
+	PromptTokens     int64         `bson:"prompt_tokens"`     // Total prompt tokens of all requests
+	CompletionTokens int64         `bson:"completion_tokens"` // Total completion tokens of all requests
+	UpdatedAt        bson.DateTime `bson:"updated_at"`
 }
 
 func (u LifetimeUsage) CollectionName() string {
@@ -18,15 +18,48 @@ type CustomModel struct {
 }
 
 type Settings struct {
-	ShowShortcutsAfterSelection  bool          `bson:"show_shortcuts_after_selection"`
-	FullWidthPaperDebuggerButton bool          `bson:"full_width_paper_debugger_button"`
-	EnableCitationSuggestion     bool          `bson:"enable_citation_suggestion"`
-	FullDocumentRag              bool          `bson:"full_document_rag"`
-	ShowedOnboarding             bool          `bson:"showed_onboarding"`
-	OpenAIAPIKey                 string        `bson:"openai_api_key"`
-	CustomModels                 []CustomModel `bson:"custom_models"`
+	ShowShortcutsAfterSelection  bool           `bson:"show_shortcuts_after_selection"`
+	FullWidthPaperDebuggerButton bool           `bson:"full_width_paper_debugger_button"`
+	EnableCitationSuggestion     bool           `bson:"enable_citation_suggestion"`
+	FullDocumentRag              bool           `bson:"full_document_rag"`
+	ShowedOnboarding             bool           `bson:"showed_onboarding"`
+	OpenAIAPIKey                 string         `bson:"openai_api_key"`
+	CustomModels                 []CustomModel  `bson:"custom_models"`
+	MacroExpansion               MacroExpansion `bson:"macro_expansion"`
+	// ChatMacroGlossary lists the user macros in the system prompt of chat. Chat never expands the macros, since
+	// the edits proposed by the model must match the source of the docs.
+	ChatMacroGlossary bool `bson:"chat_macro_glossary"`
 }
 
+// FindCustomModel returns the custom model of the user with the ID in hex, or nil.
+func (s *Settings) FindCustomModel(id string) *CustomModel {
+	for i := range s.CustomModels {
+		if s.CustomModels[i].Id.Hex() == id {
+			return &s.CustomModels[i]
+		}
+	}
+	return nil
+}
+
+// APIKeyAAD returns the data the encrypted API key of the user is bound to, see secret.Keyring.Encrypt. It names
+// Settings.OpenAIAPIKey if customModelID is nil, and the API key of the custom model otherwise.
+func APIKeyAAD(userID bson.ObjectID, customModelID *bson.ObjectID) string {
+	if customModelID == nil {
+		return "users/" + userID.Hex() + "/settings.openai_api_key"
+	}
+	return "users/" + userID.Hex() + "/settings.custom_models/" + customModelID.Hex() + "/api_key"
+}
+
+// MacroExpansion is how the user macros of the paper are given to the paper scorer, see Settings.ChatMacroGlossary
+// for chat.
+type MacroExpansion string
+
+const (
+	MacroExpansionNone     MacroExpansion = ""
+	MacroExpansionExpand   MacroExpansion = "expand"   // The uses of the macros are replaced by their bodies
+	MacroExpansionGlossary MacroExpansion = "glossary" // The macros are listed next to the paper
+)
+
 type User struct {
 	BaseModel    `bson:",inline"`
 	Email        string        `bson:"email,unique"`
@@ -35,6 +68,12 @@ type User struct {
 	LastLogin    bson.DateTime `bson:"last_login"`
 	Settings     Settings      `bson:"settings"`
 	Instructions string        `bson:"instructions"`
+
+	// WeeklySpendLimit overrides cfg.UserWeeklySpendLimit for this user when set, 0 means unlimited.
+	// It is set with the set-spend-limit command.
+	WeeklySpendLimit *float64 `bson:"weekly_spend_limit,omitempty"`
+	// IsAdmin gives access to the usage reports of all users, it is granted with the grant-admin command.
+	IsAdmin bool `bson:"is_admin,omitempty"`
 }
 
 func (u User) CollectionName() string {
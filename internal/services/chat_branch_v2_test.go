@@ -0,0 +1,221 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func toBSON(t *testing.T, msg *chatv2.Message) bson.M {
+	jsonBytes, err := protojson.Marshal(msg)
+	assert.NoError(t, err)
+	var bsonMsg bson.M
+	assert.NoError(t, bson.UnmarshalExtJSON(jsonBytes, true, &bsonMsg))
+	return bsonMsg
+}
+
+func userMsg(t *testing.T, id string, content string) bson.M {
+	return toBSON(t, &chatv2.Message{
+		MessageId: id,
+		Payload:   &chatv2.MessagePayload{MessageType: &chatv2.MessagePayload_User{User: &chatv2.MessageTypeUser{Content: content}}},
+	})
+}
+
+func assistantMsg(t *testing.T, id string, content string) bson.M {
+	return toBSON(t, &chatv2.Message{
+		MessageId: id,
+		Payload:   &chatv2.MessagePayload{MessageType: &chatv2.MessagePayload_Assistant{Assistant: &chatv2.MessageTypeAssistant{Content: content}}},
+	})
+}
+
+// newTwoTurnConversation returns a conversation with two user turns, the second one with a tool call.
+func newTwoTurnConversation(t *testing.T) *models.Conversation {
+	return &models.Conversation{
+		InappChatHistory: []bson.M{
+			userMsg(t, "u1", "first"),
+			assistantMsg(t, "a1", "answer 1"),
+			userMsg(t, "u2", "second"),
+			toBSON(t, &chatv2.Message{
+				MessageId: "t2",
+				Payload:   &chatv2.MessagePayload{MessageType: &chatv2.MessagePayload_ToolCall{ToolCall: &chatv2.MessageTypeToolCall{Name: "read_file"}}},
+			}),
+			assistantMsg(t, "a2", "answer 2"),
+		},
+		OpenaiChatHistoryCompletion: []openai.ChatCompletionMessageParamUnion{
+			openai.SystemMessage("system"),
+			openai.UserMessage("first"),
+			openai.AssistantMessage("answer 1"),
+			openai.UserMessage("second"),
+			{OfAssistant: &openai.ChatCompletionAssistantMessageParam{}},
+			openai.ToolMessage("file content", "call_1"),
+			openai.AssistantMessage("answer 2"),
+		},
+	}
+}
+
+// newTwoTurnTree returns the conversation of newTwoTurnConversation as a message tree.
+func newTwoTurnTree(t *testing.T) *models.Conversation {
+	conversation := newTwoTurnConversation(t)
+	conversation.BuildMessageTree()
+	return conversation
+}
+
+func activeBranchIDs(conversation *models.Conversation) []string {
+	var ids []string
+	for _, msg := range conversation.ActiveBranch() {
+		ids = append(ids, msg.ID)
+	}
+	return ids
+}
+
+func TestBuildMessageTree(t *testing.T) {
+	legacy := newTwoTurnConversation(t)
+	openaiHistory := legacy.OpenaiChatHistoryCompletion
+
+	conversation := newTwoTurnConversation(t)
+	conversation.ProjectVersion = "v1"
+	assert.True(t, conversation.NeedsMessageTree())
+	conversation.BuildMessageTree()
+	assert.False(t, conversation.NeedsMessageTree())
+	assert.Empty(t, conversation.InappChatHistory)
+	assert.Empty(t, conversation.OpenaiChatHistoryCompletion)
+
+	assert.Equal(t, "system", conversation.SystemPrompt)
+	assert.Equal(t, "a2", conversation.LeafID)
+	assert.Equal(t, []string{"u1", "a1", "u2", "t2", "a2"}, activeBranchIDs(conversation))
+	assert.Equal(t, openaiHistory, conversation.OpenaiHistory())
+	assert.Equal(t, "v1", conversation.BranchProjectVersion())
+
+	// Each turn keeps its own messages, so that earlier turns can be edited.
+	assert.Len(t, conversation.Message("u2").Openai, 1)
+	assert.Len(t, conversation.Message("a2").Openai, 3)
+}
+
+func TestGetConversationV2_KeepsLegacyHistories(t *testing.T) {
+	os.Setenv("PD_MONGO_URI", "mongodb://localhost:27017")
+	dbInstance, err := db.NewDB(cfg.GetCfg(), logger.GetLogger())
+	if err != nil {
+		t.Fatalf("failed to connect to test db: %v", err)
+	}
+	chatServiceV1 := services.NewChatService(dbInstance, cfg.GetCfg(), logger.GetLogger())
+	chatServiceV2 := services.NewChatServiceV2(dbInstance, cfg.GetCfg(), logger.GetLogger())
+	ctx := context.Background()
+
+	legacy := newTwoTurnConversation(t)
+	legacy.ID = bson.NewObjectID()
+	legacy.UserID = bson.NewObjectID()
+	collection := dbInstance.Database("paperdebugger").Collection(legacy.CollectionName())
+	_, err = collection.InsertOne(ctx, legacy)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = collection.DeleteOne(ctx, bson.M{"_id": legacy.ID})
+	})
+
+	conversation, err := chatServiceV2.GetConversationV2(ctx, legacy.UserID, legacy.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"u1", "a1", "u2", "t2", "a2"}, activeBranchIDs(conversation))
+
+	// The tree is written next to the histories, which chat v1 still reads.
+	stored, err := chatServiceV1.GetConversation(ctx, legacy.UserID, legacy.ID)
+	assert.NoError(t, err)
+	assert.Len(t, stored.Messages, 5)
+	assert.Len(t, stored.InappChatHistory, 5)
+	assert.Len(t, stored.OpenaiChatHistoryCompletion, 7)
+}
+
+func TestBuildMessageTree_Unmatched(t *testing.T) {
+	conversation := newTwoTurnConversation(t)
+
+	// The first turn has been summarized.
+	conversation.OpenaiChatHistoryCompletion = append([]openai.ChatCompletionMessageParamUnion{
+		openai.SystemMessage("system"),
+		openai.SystemMessage("summary"),
+	}, conversation.OpenaiChatHistoryCompletion[3:]...)
+	openaiHistory := conversation.OpenaiChatHistoryCompletion
+
+	conversation.BuildMessageTree()
+	assert.Equal(t, openaiHistory, conversation.OpenaiHistory())
+	assert.True(t, conversation.Message("a2").Compacted)
+}
+
+func TestBranchForEditV2(t *testing.T) {
+	s := &services.ChatServiceV2{}
+	conversation := newTwoTurnTree(t)
+
+	assert.Error(t, s.BranchForEditV2(conversation, "a2"))
+	assert.Error(t, s.BranchForEditV2(conversation, "missing"))
+
+	assert.NoError(t, s.BranchForEditV2(conversation, "u2"))
+	assert.Equal(t, "a1", conversation.LeafID)
+	assert.Len(t, conversation.OpenaiHistory(), 3)
+
+	// Append the edited message, it becomes an alternative to u2.
+	conversation.AppendMessages(models.ConversationMessage{ID: "u2-edited", Inapp: userMsg(t, "u2-edited", "second, edited")})
+	assert.Len(t, conversation.Messages, 6)
+	alternatives := conversation.MessageAlternatives()
+	assert.Len(t, alternatives, 1)
+	assert.Len(t, alternatives[2], 2)
+	assert.Equal(t, "u2", models.InappMessageID(alternatives[2][0]))
+	assert.Equal(t, "u2-edited", models.InappMessageID(alternatives[2][1]))
+
+	// Switch back to the original branch, the leaf is the latest message of the branch.
+	assert.True(t, conversation.SwitchBranch("u2"))
+	assert.Equal(t, []string{"u1", "a1", "u2", "t2", "a2"}, activeBranchIDs(conversation))
+	assert.Len(t, conversation.OpenaiHistory(), 7)
+	assert.False(t, conversation.SwitchBranch("missing"))
+
+	// Switching to the first message follows the latest branch.
+	assert.True(t, conversation.SwitchBranch("u1"))
+	assert.Equal(t, "u2-edited", conversation.LeafID)
+}
+
+func TestBranchForRegenerateV2(t *testing.T) {
+	s := &services.ChatServiceV2{}
+	conversation := newTwoTurnTree(t)
+
+	// Any message of the turn can be used.
+	assert.NoError(t, s.BranchForRegenerateV2(conversation, "t2"))
+	assert.Equal(t, "u2", conversation.LeafID)
+	assert.Len(t, conversation.OpenaiHistory(), 4)
+	assert.Error(t, s.BranchForRegenerateV2(conversation, "missing"))
+
+	// The regenerated response becomes an alternative to the previous one.
+	conversation.AppendMessages(models.ConversationMessage{ID: "a2-bis", Inapp: assistantMsg(t, "a2-bis", "answer 2, again")})
+	alternatives := conversation.MessageAlternatives()
+	assert.Len(t, alternatives[3], 2)
+	assert.Equal(t, "t2", models.InappMessageID(alternatives[3][0]))
+	assert.Equal(t, "a2-bis", models.InappMessageID(alternatives[3][1]))
+}
+
+func TestBranchV2_CompactedTurn(t *testing.T) {
+	s := &services.ChatServiceV2{}
+	conversation := newTwoTurnTree(t)
+
+	// The first turn has been summarized after the second user message.
+	conversation.LeafID = "u2"
+	conversation.AppendMessages(models.ConversationMessage{
+		ID:        "c2",
+		Inapp:     toBSON(t, &chatv2.Message{MessageId: "c2", Payload: &chatv2.MessagePayload{MessageType: &chatv2.MessagePayload_Compaction{Compaction: &chatv2.MessageTypeCompaction{}}}}),
+		Openai:    []openai.ChatCompletionMessageParamUnion{openai.SystemMessage("summary"), openai.UserMessage("second")},
+		Compacted: true,
+	})
+	assert.Len(t, conversation.OpenaiHistory(), 3)
+
+	// Earlier turns can still be edited, with the full history.
+	assert.NoError(t, s.BranchForEditV2(conversation, "u2"))
+	assert.Len(t, conversation.OpenaiHistory(), 3)
+	assert.NoError(t, s.BranchForEditV2(conversation, "u1"))
+	assert.Len(t, conversation.OpenaiHistory(), 1)
+}
@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshProjectV2(t *testing.T) {
+	s := &services.ChatServiceV2{}
+	conversation := newTwoTurnConversation(t)
+	conversation.ProjectVersion = "v1"
+	conversation.BuildMessageTree()
+
+	// The project did not change.
+	inappMsg, openaiMsg := s.RefreshProjectV2(conversation, "v1", "", "system v1")
+	assert.Nil(t, inappMsg)
+	assert.Nil(t, openaiMsg)
+	assert.Equal(t, "system", conversation.SystemPrompt)
+
+	// The system prompt is rebuilt, and the model is told what changed.
+	diff := "--- a/main.tex\n+++ b/main.tex\n@@ -1,2 +1,2 @@\n \\section{Intro}\n-Old sentence.\n+New sentence.\n"
+	inappMsg, openaiMsg = s.RefreshProjectV2(conversation, "v2", diff, "system v2")
+	assert.NotNil(t, inappMsg)
+	assert.Equal(t, "system v2", conversation.SystemPrompt)
+	assert.Equal(t, "v2", conversation.ProjectVersion)
+
+	refresh := inappMsg.GetPayload().GetProjectRefresh()
+	assert.Equal(t, "v1", refresh.GetFromVersion())
+	assert.Equal(t, "v2", refresh.GetToVersion())
+	assert.Equal(t, diff, refresh.GetDiff())
+	assert.Contains(t, openaiMsg.OfSystem.Content.OfString.Value, "+New sentence.")
+
+	// Each branch is compared with the version of its latest user message.
+	conversation.LeafID = "a1"
+	conversation.AppendMessages(models.ConversationMessage{ID: "u3", Inapp: userMsg(t, "u3", "third"), ProjectVersion: "v2"})
+	assert.Equal(t, "v2", conversation.BranchProjectVersion())
+	assert.True(t, conversation.SwitchBranch("a2"))
+	assert.Equal(t, "v1", conversation.BranchProjectVersion())
+}
+
+func TestRefreshProjectV2_UnknownVersion(t *testing.T) {
+	s := &services.ChatServiceV2{}
+
+	// Conversations created before the version was recorded are refreshed silently.
+	conversation := &models.Conversation{SystemPrompt: "system"}
+	inappMsg, _ := s.RefreshProjectV2(conversation, "v1", "", "system v1")
+	assert.Nil(t, inappMsg)
+	assert.Equal(t, "system v1", conversation.SystemPrompt)
+
+	// The user message records the version it was sent with.
+	conversation.AppendMessages(models.ConversationMessage{ID: "u1", ProjectVersion: "v1"})
+
+	// The diff is unknown or too long, the model is told that the project changed without it.
+	inappMsg, openaiMsg := s.RefreshProjectV2(conversation, "v2", strings.Repeat("+line\n", 10000), "system v2")
+	assert.NotNil(t, inappMsg)
+	assert.Empty(t, inappMsg.GetPayload().GetProjectRefresh().GetDiff())
+	assert.NotContains(t, openaiMsg.OfSystem.Content.OfString.Value, "```diff")
+}
@@ -11,9 +11,11 @@ import (
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/shared"
 	"paperdebugger/internal/models"
 	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
 
+	"github.com/google/uuid"
 	"github.com/openai/openai-go/v3"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -53,6 +55,10 @@ type ChatServiceV2 struct {
 	conversationCollection *mongo.Collection
 }
 
+// maxProjectRefreshDiffLength is the length above which the changes are not sent to the model.
+// The system prompt still has the current version of the paper.
+const maxProjectRefreshDiffLength = 20000
+
 // define default conversation title
 const DefaultConversationTitleV2 = "New Conversation ."
 
@@ -64,7 +70,10 @@ func NewChatServiceV2(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *ChatServi
 	}
 }
 
-func (s *ChatServiceV2) GetSystemPromptV2(ctx context.Context, fullContent string, projectInstructions string, userInstructions string, conversationType chatv2.ConversationType) (string, error) {
+// GetSystemPromptV2 renders the system prompt. When retrievalEnabled is true, the full content is
+// not inlined, and the relevant chunks are attached to the latest user message by GetPrompt instead.
+// macroGlossary lists the user macros of the paper, it is empty unless the user asked for it.
+func (s *ChatServiceV2) GetSystemPromptV2(ctx context.Context, fullContent string, macroGlossary string, retrievalEnabled bool, projectInstructions string, userInstructions string, conversationType chatv2.ConversationType) (string, error) {
 	var tmpl *template.Template
 	switch conversationType {
 	case chatv2.ConversationType_CONVERSATION_TYPE_DEBUG:
@@ -74,8 +83,10 @@ func (s *ChatServiceV2) GetSystemPromptV2(ctx context.Context, fullContent strin
 	}
 
 	var systemPromptBuffer bytes.Buffer
-	if err := tmpl.Execute(&systemPromptBuffer, map[string]string{
+	if err := tmpl.Execute(&systemPromptBuffer, map[string]any{
 		"FullContent":         fullContent,
+		"MacroGlossary":       macroGlossary,
+		"RetrievalEnabled":    retrievalEnabled,
 		"ProjectInstructions": projectInstructions,
 		"UserInstructions":    userInstructions,
 	}); err != nil {
@@ -84,7 +95,7 @@ func (s *ChatServiceV2) GetSystemPromptV2(ctx context.Context, fullContent strin
 	return strings.TrimSpace(systemPromptBuffer.String()), nil
 }
 
-func (s *ChatServiceV2) GetPrompt(ctx context.Context, content string, selectedText string, surrounding string, conversationType chatv2.ConversationType) (string, error) {
+func (s *ChatServiceV2) GetPrompt(ctx context.Context, content string, selectedText string, surrounding string, retrievedChunks []models.ProjectChunk, conversationType chatv2.ConversationType) (string, error) {
 	var tmpl *template.Template
 	switch conversationType {
 	case chatv2.ConversationType_CONVERSATION_TYPE_DEBUG:
@@ -94,44 +105,81 @@ func (s *ChatServiceV2) GetPrompt(ctx context.Context, content string, selectedT
 	}
 
 	var userPromptBuffer bytes.Buffer
-	if err := tmpl.Execute(&userPromptBuffer, map[string]string{
-		"UserInput":    content,
-		"SelectedText": selectedText,
-		"Surrounding":  surrounding,
+	if err := tmpl.Execute(&userPromptBuffer, map[string]any{
+		"UserInput":       content,
+		"SelectedText":    selectedText,
+		"Surrounding":     surrounding,
+		"RetrievedChunks": retrievedChunks,
 	}); err != nil {
 		return "", err
 	}
 	return strings.TrimSpace(userPromptBuffer.String()), nil
 }
 
-func (s *ChatServiceV2) InsertConversationToDBV2(ctx context.Context, userID bson.ObjectID, projectID string, modelSlug string, inappChatHistory []*chatv2.Message, openaiChatHistory []openai.ChatCompletionMessageParamUnion) (*models.Conversation, error) {
-	// Convert protobuf messages to BSON
-	bsonMessages := make([]bson.M, len(inappChatHistory))
-	for i := range inappChatHistory {
-		jsonBytes, err := protojson.Marshal(inappChatHistory[i])
-		if err != nil {
-			return nil, err
-		}
-		var bsonMsg bson.M
-		if err := bson.UnmarshalExtJSON(jsonBytes, true, &bsonMsg); err != nil {
-			return nil, err
-		}
-		bsonMessages[i] = bsonMsg
+// RefreshProjectV2 rebuilds the system prompt if the project changed since it was built, so that the model reasons
+// over the current text rather than the one of the first turn. It returns the messages telling the model what
+// changed since the latest turn of the active branch, to insert before the user message of the turn, or nil if
+// nothing changed.
+// diff is the diff of the project since that turn, see ProjectService.DiffProjectVersions, or "" if it is unknown.
+func (s *ChatServiceV2) RefreshProjectV2(conversation *models.Conversation, version string, diff string, systemPrompt string) (*chatv2.Message, *openai.ChatCompletionMessageParamUnion) {
+	if conversation.ProjectVersion != version {
+		conversation.SystemPrompt = systemPrompt
+		conversation.ProjectVersion = version
+	}
+	previousVersion := conversation.BranchProjectVersion()
+	if previousVersion == version {
+		return nil, nil
+	}
+	// Conversations created before the version was recorded may not have changed, the model is not told about it.
+	if previousVersion == "" {
+		return nil, nil
 	}
 
+	if len(diff) > maxProjectRefreshDiffLength {
+		diff = ""
+	}
+
+	prompt := "The paper has been edited since the previous turn. Earlier messages may refer to text that no longer exists, always rely on the current version of the paper."
+	if diff != "" {
+		prompt += "\n\nChanges since the previous turn:\n\n```diff\n" + diff + "```"
+	}
+
+	inappMessage := &chatv2.Message{
+		MessageId: "pd_msg_project_refresh_" + uuid.New().String(),
+		Payload: &chatv2.MessagePayload{
+			MessageType: &chatv2.MessagePayload_ProjectRefresh{
+				ProjectRefresh: &chatv2.MessageTypeProjectRefresh{
+					FromVersion: previousVersion,
+					ToVersion:   version,
+					Diff:        diff,
+				},
+			},
+		},
+		Timestamp: time.Now().Unix(),
+	}
+	openaiMessage := openai.SystemMessage(prompt)
+	return inappMessage, &openaiMessage
+}
+
+// InsertConversationToDBV2 creates a conversation whose active branch is the messages, each one being the child of
+// the previous one.
+func (s *ChatServiceV2) InsertConversationToDBV2(ctx context.Context, conversationID bson.ObjectID, userID bson.ObjectID, projectID string, modelSlug string, systemPrompt string, retrievalEnabled bool, messages []models.ConversationMessage, projectVersion string) (*models.Conversation, error) {
 	conversation := &models.Conversation{
 		BaseModel: models.BaseModel{
-			ID:        bson.NewObjectID(),
+			ID:        conversationID,
 			CreatedAt: bson.NewDateTimeFromTime(time.Now()),
 			UpdatedAt: bson.NewDateTimeFromTime(time.Now()),
 		},
-		UserID:                      userID,
-		ProjectID:                   projectID,
-		Title:                       DefaultConversationTitleV2,
-		ModelSlug:                   modelSlug,
-		InappChatHistory:            bsonMessages,
-		OpenaiChatHistoryCompletion: openaiChatHistory,
+		UserID:           userID,
+		ProjectID:        projectID,
+		Title:            DefaultConversationTitleV2,
+		ModelSlug:        modelSlug,
+		SystemPrompt:     systemPrompt,
+		RetrievalEnabled: retrievalEnabled,
+		ProjectVersion:   projectVersion,
+		Messages:         []models.ConversationMessage{},
 	}
+	conversation.AppendMessages(messages...)
 	_, err := s.conversationCollection.InsertOne(ctx, conversation)
 	if err != nil {
 		return nil, err
@@ -146,8 +194,11 @@ func (s *ChatServiceV2) ListConversationsV2(ctx context.Context, userID bson.Obj
 	)
 	opts := options.Find().
 		SetProjection(bson.M{
-			"inapp_chat_history":  0,
-			"openai_chat_history": 0,
+			"inapp_chat_history":             0,
+			"openai_chat_history":            0,
+			"openai_chat_history_completion": 0,
+			"messages":                       0,
+			"system_prompt":                  0,
 		}).
 		SetSort(bson.M{"updated_at": -1}).
 		SetLimit(50)
@@ -174,20 +225,63 @@ func (s *ChatServiceV2) GetConversationV2(ctx context.Context, userID bson.Objec
 	if err != nil {
 		return nil, err
 	}
+
+	if conversation.NeedsMessageTree() {
+		conversation.BuildMessageTree()
+		// Only written if no other request did it concurrently, the histories are then built the same way.
+		// The legacy histories are kept, so that the conversation can still be read with chat v1 and after a rollback.
+		_, err := s.conversationCollection.UpdateOne(ctx,
+			bson.M{"_id": conversation.ID, "messages": nil},
+			bson.M{
+				"$set": bson.M{
+					"messages":      conversation.Messages,
+					"leaf_id":       conversation.LeafID,
+					"system_prompt": conversation.SystemPrompt,
+				},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return conversation, nil
 }
 
-func (s *ChatServiceV2) UpdateConversationV2(conversation *models.Conversation) error {
+// AppendMessagesV2 appends the messages to the active branch of the conversation, see
+// models.Conversation.AppendMessages, and writes them with the system prompt. Only these fields are written, so
+// that concurrent changes to other messages are kept.
+func (s *ChatServiceV2) AppendMessagesV2(ctx context.Context, conversation *models.Conversation, messages ...models.ConversationMessage) error {
+	first := len(conversation.Messages)
+	conversation.AppendMessages(messages...)
 	conversation.UpdatedAt = bson.NewDateTimeFromTime(time.Now())
+
 	filter := db.MergeFilters(
 		bson.M{"_id": conversation.ID},
 		db.NotDeleted(),
 	)
-	_, err := s.conversationCollection.UpdateOne(
-		context.Background(),
-		filter,
-		bson.M{"$set": conversation},
+	_, err := s.conversationCollection.UpdateOne(ctx, filter, bson.M{
+		"$push": bson.M{"messages": bson.M{"$each": conversation.Messages[first:]}},
+		"$set": bson.M{
+			"leaf_id":           conversation.LeafID,
+			"system_prompt":     conversation.SystemPrompt,
+			"retrieval_enabled": conversation.RetrievalEnabled,
+			"project_version":   conversation.ProjectVersion,
+			"updated_at":        conversation.UpdatedAt,
+		},
+	})
+	return err
+}
+
+// SetActiveBranchV2 writes the leaf of the active branch of the conversation.
+func (s *ChatServiceV2) SetActiveBranchV2(ctx context.Context, conversation *models.Conversation) error {
+	conversation.UpdatedAt = bson.NewDateTimeFromTime(time.Now())
+	filter := db.MergeFilters(
+		bson.M{"_id": conversation.ID},
+		db.NotDeleted(),
 	)
+	_, err := s.conversationCollection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{"leaf_id": conversation.LeafID, "updated_at": conversation.UpdatedAt},
+	})
 	return err
 }
 
@@ -218,3 +312,85 @@ func (s *ChatServiceV2) DeleteConversationV2(ctx context.Context, userID bson.Ob
 	)
 	return err
 }
+
+// BranchForEditV2 moves the leaf of the conversation to the parent of the user message messageID, so that the
+// edited message appended by the caller becomes its sibling.
+func (s *ChatServiceV2) BranchForEditV2(conversation *models.Conversation, messageID string) error {
+	msg := conversation.Message(messageID)
+	if msg == nil {
+		return shared.ErrRecordNotFound("message not found in conversation")
+	}
+	if !msg.IsUser() {
+		return shared.ErrBadRequest("only user messages can be edited")
+	}
+	conversation.LeafID = msg.ParentID
+	return nil
+}
+
+// BranchForRegenerateV2 moves the leaf of the conversation to the user message of the turn containing messageID,
+// so that the regenerated response becomes a sibling of the previous one.
+func (s *ChatServiceV2) BranchForRegenerateV2(conversation *models.Conversation, messageID string) error {
+	if conversation.Message(messageID) == nil {
+		return shared.ErrRecordNotFound("message not found in conversation")
+	}
+	previousLeaf := conversation.LeafID
+	conversation.LeafID = messageID
+	branch := conversation.ActiveBranch()
+	for i := len(branch) - 1; i >= 0; i-- {
+		if branch[i].IsUser() {
+			conversation.LeafID = branch[i].ID
+			return nil
+		}
+	}
+	conversation.LeafID = previousLeaf
+	return shared.ErrBadRequest("message does not belong to a user turn")
+}
+
+// proposedEditMessageV2 returns the message messageID of the conversation, checking that it is the message of the
+// proposed edit editID.
+func proposedEditMessageV2(conversation *models.Conversation, messageID string, editID string) (*chatv2.Message, error) {
+	msg := conversation.Message(messageID)
+	if msg == nil {
+		return nil, shared.ErrBadRequest("message_id not found in conversation")
+	}
+	m := models.InappMessageV2(msg.Inapp)
+	if m == nil || m.GetPayload().GetProposedEdit().GetEditId() != editID {
+		return nil, shared.ErrBadRequest("message_id is not the message of the proposed edit")
+	}
+	return m, nil
+}
+
+// CheckProposedEditV2 checks that the message messageID of the conversation is the message of the proposed edit.
+func (s *ChatServiceV2) CheckProposedEditV2(conversation *models.Conversation, messageID string, editID string) error {
+	_, err := proposedEditMessageV2(conversation, messageID, editID)
+	return err
+}
+
+// SetProposedEditV2 replaces the proposed edit of the message messageID of the conversation, e.g. to record that
+// the user accepted it. Only this message is written.
+func (s *ChatServiceV2) SetProposedEditV2(ctx context.Context, conversation *models.Conversation, messageID string, edit *chatv2.MessageTypeProposedEdit) error {
+	m, err := proposedEditMessageV2(conversation, messageID, edit.GetEditId())
+	if err != nil {
+		return err
+	}
+	m.Payload.MessageType = &chatv2.MessagePayload_ProposedEdit{ProposedEdit: edit}
+
+	jsonBytes, err := protojson.Marshal(m)
+	if err != nil {
+		return err
+	}
+	var bsonMsg bson.M
+	if err := bson.UnmarshalExtJSON(jsonBytes, true, &bsonMsg); err != nil {
+		return err
+	}
+	conversation.Message(messageID).Inapp = bsonMsg
+
+	filter := db.MergeFilters(
+		bson.M{"_id": conversation.ID, "messages.id": messageID},
+		db.NotDeleted(),
+	)
+	_, err = s.conversationCollection.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{"messages.$.inapp": bsonMsg, "updated_at": bson.NewDateTimeFromTime(time.Now())},
+	})
+	return err
+}
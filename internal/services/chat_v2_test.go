@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSystemPromptV2_FullDocumentRag(t *testing.T) {
+	s := &services.ChatServiceV2{}
+	ctx := context.Background()
+
+	full, err := s.GetSystemPromptV2(ctx, "FULL PAPER", "", false, "", "", chatv2.ConversationType_CONVERSATION_TYPE_UNSPECIFIED)
+	assert.NoError(t, err)
+	assert.Contains(t, full, "## current_paper_content (enclosed in triple quotes)\n\n\"\"\"\nFULL PAPER\n\"\"\"")
+
+	rag, err := s.GetSystemPromptV2(ctx, "FULL PAPER", "", true, "", "", chatv2.ConversationType_CONVERSATION_TYPE_UNSPECIFIED)
+	assert.NoError(t, err)
+	assert.NotContains(t, rag, "FULL PAPER")
+	assert.Contains(t, rag, "relevant_paper_excerpts")
+	assert.NotContains(t, rag, "user_macros")
+
+	glossary, err := s.GetSystemPromptV2(ctx, "FULL PAPER", "\\method = FooNet", false, "", "", chatv2.ConversationType_CONVERSATION_TYPE_UNSPECIFIED)
+	assert.NoError(t, err)
+	assert.Contains(t, glossary, "## user_macros")
+	assert.Contains(t, glossary, "\\method = FooNet")
+}
+
+func TestGetPromptV2_RetrievedChunks(t *testing.T) {
+	s := &services.ChatServiceV2{}
+	ctx := context.Background()
+
+	prompt, err := s.GetPrompt(ctx, "What is the method?", "", "", nil, chatv2.ConversationType_CONVERSATION_TYPE_UNSPECIFIED)
+	assert.NoError(t, err)
+	assert.Equal(t, "What is the method?", prompt)
+
+	prompt, err = s.GetPrompt(ctx, "What is the method?", "", "", []models.ProjectChunk{
+		{Section: "Method", Text: "We use attention."},
+	}, chatv2.ConversationType_CONVERSATION_TYPE_UNSPECIFIED)
+	assert.NoError(t, err)
+	assert.Contains(t, prompt, "% section: Method\n\"\"\"\nWe use attention.\n\"\"\"")
+	assert.Contains(t, prompt, "\"\"\"\n\nWhat is the method?")
+}
@@ -4,21 +4,23 @@ import (
 	"context"
 	"crypto/sha1"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/contextutil"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
-	"paperdebugger/internal/libs/stringutil"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/libs/tex"
 	"paperdebugger/internal/models"
 	projectv1 "paperdebugger/pkg/gen/api/project/v1"
 	"strings"
-	"sync"
 	"time"
 	"unicode/utf8"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type ReverseCommentService struct {
@@ -53,7 +55,8 @@ func (c *CommentResponse) UnmarshalJSON(data []byte) error {
 }
 
 const (
-	NoMatchPosition = -1
+	NoMatchPosition     = -1
+	minAnchorSimilarity = 0.65 // The minimum similarity of the span of a comment to its anchor text
 )
 
 func NewReverseCommentService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger, projectService *ProjectService) *ReverseCommentService {
@@ -65,23 +68,6 @@ func NewReverseCommentService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger, pr
 	}
 }
 
-// isSectionHeader checks if a line is a valid LaTeX section header
-func isSectionHeader(line string) bool {
-	// Skip comment lines
-	if strings.HasPrefix(strings.TrimSpace(line), "%") {
-		return false
-	}
-
-	// Check for section commands
-	sectionTypes := []string{"\\section{", "\\subsection{", "\\subsubsection{"}
-	for _, sectionType := range sectionTypes {
-		if strings.Contains(line, sectionType) {
-			return true
-		}
-	}
-	return false
-}
-
 // generateDocSHA1 generates a SHA1 hash for the document content
 func generateDocSHA1(content string) string {
 	h := sha1.New()
@@ -89,176 +75,58 @@ func generateDocSHA1(content string) string {
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
-// findBestMatchPosition finds the position in the document that best matches the anchor text
-// using Levenshtein distance with a similarity threshold
+// findBestMatchPosition finds the rune position and the text of the span of the document that best matches the
+// anchor text, tolerating typos and differences of LaTeX markup.
 func (s *ReverseCommentService) findBestMatchPosition(docContent, anchorText string) (int, string) {
-	if anchorText == "" {
-		return NoMatchPosition, ""
-	}
-
-	if len(anchorText) > len(docContent) {
+	match, ok := tex.MatchAnchor(docContent, anchorText, minAnchorSimilarity)
+	if !ok {
 		return NoMatchPosition, ""
 	}
-
-	// Try exact match first
-	if pos, match := exactMatchPosition(docContent, anchorText); pos != NoMatchPosition {
-		return pos, match
-	}
-
-	// Fallback to fuzzy match
-	return s.fuzzyMatchPosition(docContent, anchorText)
+	return match.Start, match.Text
 }
 
-// exactMatchPosition returns the rune position and text if anchorText is found exactly in docContent
-func exactMatchPosition(docContent, anchorText string) (int, string) {
-	if strings.Contains(docContent, anchorText) {
-		position := strings.Index(docContent, anchorText)
-		actualPosition := utf8.RuneCountInString(docContent[:position])
-		return actualPosition, anchorText
+// findTargetDocBySection searches for the doc containing the target section. The section is searched in the
+// expanded project, so that sections spread over included docs are found: the doc is the one containing the anchor
+// text in the section, or else the one containing the first line of the section after its header.
+func (s *ReverseCommentService) findTargetDocBySection(project *models.Project, targetSectionName string, anchorText string) *models.ProjectDoc {
+	fullContent, sourceMap, err := project.GetFullContentWithSourceMap()
+	if err != nil {
+		return findDocBySectionHeader(project, targetSectionName)
 	}
-	return NoMatchPosition, ""
-}
 
-// fuzzyMatchPosition uses Levenshtein similarity to find the best window in docContent
-func (s *ReverseCommentService) fuzzyMatchPosition(docContent, anchorText string) (int, string) {
-	windowSize := len(anchorText)
-	chunkSize := 1000 // Number of windows each goroutine will process
-	numChunks := (len(docContent) - windowSize) / chunkSize
-	if numChunks == 0 {
-		numChunks = 1
-	}
-
-	type matchResult struct {
-		position    int
-		similarity  float64
-		matchedText string
-	}
-
-	resultChan := make(chan matchResult, numChunks)
-	var wg sync.WaitGroup
-
-	for i := 0; i < numChunks; i++ {
-		wg.Add(1)
-		go func(chunkIndex int) {
-			defer wg.Done()
-			start := chunkIndex * chunkSize
-			end := start + chunkSize
-			if end > len(docContent)-windowSize {
-				end = len(docContent) - windowSize
-			}
-
-			bestResult := matchResult{position: -1, similarity: 0}
-
-			defer func() {
-				if r := recover(); r != nil {
-					s.logger.Error("panic recovered in fuzzyMatchPosition goroutine",
-						"error", r,
-						"chunk_index", chunkIndex,
-						"start", start,
-						"end", end)
-					resultChan <- matchResult{position: -1, similarity: 0}
-				}
-			}()
-
-			for j := start; j < end; j++ {
-				window := docContent[j : j+windowSize]
-				similarity := stringutil.Similarity(window, anchorText)
-
-				if similarity > bestResult.similarity {
-					bestResult = matchResult{
-						position:    j,
-						similarity:  similarity,
-						matchedText: window,
-					}
-				}
-			}
-
-			resultChan <- bestResult
-		}(i)
-	}
-
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	bestPosition := -1
-	bestSimilarity := 0.0
-	bestMatchedText := ""
-
-	for result := range resultChan {
-		if result.similarity > bestSimilarity {
-			bestSimilarity = result.similarity
-			bestPosition = result.position
-			bestMatchedText = result.matchedText
+	outline := tex.ParseOutline(fullContent)
+	index := outline.FindSection(targetSectionName)
+	if index < 0 {
+		return findDocBySectionHeader(project, targetSectionName)
+	}
+	// The section body includes its subsections
+	sectionStart, sectionEnd := outline.Sections[index].End, outline.SectionEnd(index, len(fullContent))
+
+	body := fullContent[sectionStart:sectionEnd]
+	target := sectionStart + len(body) - len(strings.TrimLeft(body, " \t\n")) // The first line after the header
+	if anchorText = strings.TrimSpace(anchorText); anchorText != "" {
+		if i := strings.Index(strings.ToLower(body), strings.ToLower(anchorText)); i >= 0 {
+			target = sectionStart + i
 		}
 	}
-
-	if bestSimilarity < 0.65 {
-		return -1, ""
+	if target == len(fullContent) {
+		target-- // The section is empty, fall back to its header
 	}
-
-	actualPosition := utf8.RuneCountInString(docContent[:bestPosition])
-	return actualPosition, bestMatchedText
-}
-
-// findTargetDocBySection searches for a document containing the target section name
-// It handles both direct section definitions and sections with input statements
-func (s *ReverseCommentService) findTargetDocBySection(project *models.Project, targetSectionName string) *models.ProjectDoc {
-	// First pass: look for direct section matches
-	for _, doc := range project.Docs {
-		for i, line := range doc.Lines {
-			if isSectionHeader(line) && strings.Contains(
-				strings.ToLower(line),
-				strings.ToLower(targetSectionName),
-			) {
-				// Check if this section is followed by an input statement
-				if i+1 < len(doc.Lines) {
-					nextLine := strings.TrimSpace(doc.Lines[i+1])
-					if strings.HasPrefix(nextLine, "\\input{") {
-						// Extract the input file name
-						inputFile := strings.TrimPrefix(nextLine, "\\input{")
-						inputFile = strings.TrimSuffix(inputFile, "}")
-
-						// Look for the input file in project docs
-						for _, inputDoc := range project.Docs {
-							if strings.HasSuffix(inputDoc.Filepath, inputFile+".tex") {
-								return &inputDoc
-							}
-						}
-					}
-				}
-				// If no input statement or input file not found, return current doc
-				return &doc
-			}
-		}
+	location, ok := sourceMap.Lookup(target)
+	if !ok {
+		return nil
 	}
+	return findDocByPath(project, location.Filepath)
+}
 
-	// Second pass: look for input statements that might contain the section
-	for _, doc := range project.Docs {
-		for _, line := range doc.Lines {
-			if strings.HasPrefix(strings.TrimSpace(line), "\\input{") {
-				inputFile := strings.TrimPrefix(strings.TrimSpace(line), "\\input{")
-				inputFile = strings.TrimSuffix(inputFile, "}")
-
-				// Look for the input file in project docs
-				for _, inputDoc := range project.Docs {
-					if strings.HasSuffix(inputDoc.Filepath, inputFile+".tex") {
-						// Check if this input file contains the target section
-						for _, inputLine := range inputDoc.Lines {
-							if isSectionHeader(inputLine) && strings.Contains(
-								strings.ToLower(inputLine),
-								strings.ToLower(targetSectionName),
-							) {
-								return &inputDoc
-							}
-						}
-					}
-				}
-			}
+// findDocBySectionHeader returns the first doc containing the header of the target section, for projects that
+// cannot be expanded or whose section is not included from the root doc.
+func findDocBySectionHeader(project *models.Project, targetSectionName string) *models.ProjectDoc {
+	for i, doc := range project.Docs {
+		if tex.ParseOutline(strings.Join(doc.Lines, "\n")).FindSection(targetSectionName) >= 0 {
+			return &project.Docs[i]
 		}
 	}
-
 	return nil
 }
 
@@ -288,7 +156,7 @@ func (s *ReverseCommentService) ReverseComments(ctx context.Context, comments *p
 
 	for _, comment := range comments.Results {
 		// Find the target document using the new function
-		targetDoc := s.findTargetDocBySection(project, comment.Section)
+		targetDoc := s.findTargetDocBySection(project, comment.Section, comment.AnchorText)
 		if targetDoc == nil {
 			s.logger.Info("target doc not found", "comment", comment)
 			continue
@@ -319,6 +187,98 @@ func (s *ReverseCommentService) ReverseComments(ctx context.Context, comments *p
 	return requests, nil
 }
 
+// lintImportance is the importance of the comments of the findings of each lint rule.
+var lintImportance = map[tex.LintRule]models.ImportanceLevel{
+	tex.LintUndefinedReference: models.ImportanceLevelHigh,
+	tex.LintDuplicateLabel:     models.ImportanceLevelHigh,
+	tex.LintMissingCitation:    models.ImportanceLevelHigh,
+	tex.LintMissingGraphics:    models.ImportanceLevelHigh,
+	tex.LintUnresolvedInclude:  models.ImportanceLevelMedium,
+	tex.LintUnusedLabel:        models.ImportanceLevelLow,
+}
+
+// lintCommentKey identifies the comment of a lint finding, so that linting the project again does not add it twice.
+type lintCommentKey struct {
+	docID         string
+	quotePosition int
+	comment       string
+}
+
+// LintComments adds the lint findings of the project as comments, quoting the command of each finding. The findings
+// that already have an open comment are skipped, only the comments added are returned.
+func (s *ReverseCommentService) LintComments(ctx context.Context, project *models.Project, findings []tex.LintFinding) ([]*projectv1.OverleafComment, error) {
+	cursor, err := s.commentCollection.Find(ctx, bson.M{
+		"user_id":              project.UserID,
+		"project_id":           project.ProjectID,
+		"is_added_to_overleaf": bson.M{"$in": []models.CommentStatus{models.CommentStatusNoAction, models.CommentStatusAccepted}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	openComments := []models.Comment{}
+	if err := cursor.All(ctx, &openComments); err != nil {
+		return nil, err
+	}
+	existing := map[lintCommentKey]bool{}
+	for _, comment := range openComments {
+		existing[lintCommentKey{comment.DocID, comment.QuotePosition, comment.Comment}] = true
+	}
+
+	requests := []*projectv1.OverleafComment{}
+	for _, finding := range findings {
+		targetDoc := findDocByPath(project, finding.Location.Filepath)
+		if targetDoc == nil || finding.Location.Line < 1 || finding.Location.Line > len(targetDoc.Lines) {
+			s.logger.Info("target doc not found", "finding", finding.String())
+			continue
+		}
+
+		// The command is quoted where it starts, or the whole line if it spans several lines
+		line := targetDoc.Lines[finding.Location.Line-1]
+		column := min(max(finding.Location.Column-1, 0), len(line))
+		quoteText := finding.Command
+		if !strings.HasPrefix(line[column:], quoteText) {
+			quoteText = strings.TrimSpace(line)
+			column = strings.Index(line, quoteText)
+		}
+		quotePosition := utf8.RuneCountInString(line[:column])
+		for _, previous := range targetDoc.Lines[:finding.Location.Line-1] {
+			quotePosition += utf8.RuneCountInString(previous) + 1
+		}
+
+		importance := lintImportance[finding.Rule]
+		comment := &projectv1.PaperScoreCommentEntry{
+			Section:    finding.Section,
+			AnchorText: quoteText,
+			Weakness:   fmt.Sprintf(`🔍 %s: %s`, importance, finding.Message),
+			Importance: string(importance),
+		}
+		key := lintCommentKey{targetDoc.ID, quotePosition, comment.Weakness}
+		if existing[key] {
+			continue
+		}
+		existing[key] = true
+
+		docSHA1 := generateDocSHA1(strings.Join(targetDoc.Lines, "\n"))
+		commentRecord := s.createCommentRecord(project.UserID, project.ProjectID, targetDoc, docSHA1, quotePosition, quoteText, comment)
+		one, err := s.commentCollection.InsertOne(ctx, commentRecord)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, toOverleafComment(one.InsertedID.(bson.ObjectID), project.ProjectID, targetDoc, docSHA1, quotePosition, quoteText, comment))
+	}
+	return requests, nil
+}
+
+// findDocByPath returns the doc of the project at path, or nil.
+func findDocByPath(project *models.Project, path string) *models.ProjectDoc {
+	for i := range project.Docs {
+		if project.Docs[i].Filepath == path {
+			return &project.Docs[i]
+		}
+	}
+	return nil
+}
+
 // createCommentRecord creates a models.Comment from the provided data
 func (s *ReverseCommentService) createCommentRecord(userID bson.ObjectID, projectId string, targetDoc *models.ProjectDoc, docSHA1 string, quotePosition int, matchedText string, comment *projectv1.PaperScoreCommentEntry) *models.Comment {
 	return &models.Comment{
@@ -370,12 +330,125 @@ func (s *ReverseCommentService) GetComment(ctx context.Context, userID bson.Obje
 		"user_id":    userID,
 		"project_id": projectID,
 	}).Decode(comment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, shared.ErrRecordNotFound("comment not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// AddCommentReplies appends the replies to the discussion of the comment and returns the updated comment.
+func (s *ReverseCommentService) AddCommentReplies(ctx context.Context, userID bson.ObjectID, projectID string, commentID bson.ObjectID, replies ...models.CommentReply) (*models.Comment, error) {
+	now := bson.NewDateTimeFromTime(time.Now())
+	for i := range replies {
+		replies[i].ID = bson.NewObjectID()
+		replies[i].CreatedAt = now
+	}
+
+	comment := &models.Comment{}
+	err := s.commentCollection.FindOneAndUpdate(ctx, bson.M{
+		"_id":        commentID,
+		"user_id":    userID,
+		"project_id": projectID,
+	}, bson.M{
+		"$push": bson.M{"replies": bson.M{"$each": replies}},
+		"$set":  bson.M{"updated_at": now},
+	}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(comment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, shared.ErrRecordNotFound("comment not found")
+	}
 	if err != nil {
 		return nil, err
 	}
 	return comment, nil
 }
 
+// CommentFilter selects the comments of a project. Empty fields match all comments.
+type CommentFilter struct {
+	DocID      string
+	Section    string
+	Importance []models.ImportanceLevel
+	Statuses   []models.CommentStatus
+	Orphaned   *bool
+}
+
+func (f CommentFilter) query(userID bson.ObjectID, projectID string) bson.M {
+	query := bson.M{"user_id": userID, "project_id": projectID}
+	if f.DocID != "" {
+		query["doc_id"] = f.DocID
+	}
+	if f.Section != "" {
+		query["section"] = f.Section
+	}
+	if len(f.Importance) > 0 {
+		query["importance_level"] = bson.M{"$in": f.Importance}
+	}
+	if len(f.Statuses) > 0 {
+		query["is_added_to_overleaf"] = bson.M{"$in": f.Statuses}
+	}
+	if f.Orphaned != nil {
+		query["orphaned"] = *f.Orphaned
+	}
+	return query
+}
+
+// ListComments returns a page of the comments of the project matching the filter, in order of doc path and quote
+// position, and the number of comments matching the filter. All the comments from offset are returned if limit is 0.
+func (s *ReverseCommentService) ListComments(ctx context.Context, userID bson.ObjectID, projectID string, filter CommentFilter, limit int, offset int) ([]models.Comment, int64, error) {
+	query := filter.query(userID, projectID)
+	total, err := s.commentCollection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "doc_path", Value: 1}, {Key: "quote_position", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := s.commentCollection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments := []models.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// SetCommentsStatus sets the status of the comments of the project and returns them. Either all the comments are
+// found and updated, or none is.
+func (s *ReverseCommentService) SetCommentsStatus(ctx context.Context, userID bson.ObjectID, projectID string, commentIDs []bson.ObjectID, status models.CommentStatus) ([]models.Comment, error) {
+	query := bson.M{"_id": bson.M{"$in": commentIDs}, "user_id": userID, "project_id": projectID}
+	count, err := s.commentCollection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if count != int64(len(commentIDs)) {
+		return nil, shared.ErrRecordNotFound("comment not found")
+	}
+
+	if _, err := s.commentCollection.UpdateMany(ctx, query, bson.M{"$set": bson.M{
+		"is_added_to_overleaf": status,
+		"updated_at":           bson.NewDateTimeFromTime(time.Now()),
+	}}); err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.commentCollection.Find(ctx, query, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	comments := []models.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
 func (s *ReverseCommentService) UpdateComment(ctx context.Context, userID bson.ObjectID, projectID string, commentID bson.ObjectID, comment *models.Comment) error {
 	_, err := s.commentCollection.UpdateOne(ctx, bson.M{
 		"_id":        commentID,
@@ -384,3 +457,74 @@ func (s *ReverseCommentService) UpdateComment(ctx context.Context, userID bson.O
 	}, bson.M{"$set": comment})
 	return err
 }
+
+// ReanchorComments relocates the quotes of the open comments of the project whose doc changed since they were
+// anchored, see Comment.Reanchor. The comments whose quote disappeared, or whose doc was removed, are orphaned.
+// It returns the number of comments relocated and orphaned.
+func (s *ReverseCommentService) ReanchorComments(ctx context.Context, project *models.Project) (int, int, error) {
+	cursor, err := s.commentCollection.Find(ctx, bson.M{
+		"user_id":              project.UserID,
+		"project_id":           project.ProjectID,
+		"is_added_to_overleaf": bson.M{"$in": []models.CommentStatus{models.CommentStatusNoAction, models.CommentStatusAccepted}},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	comments := []models.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return 0, 0, err
+	}
+
+	docs := map[string]*models.ProjectDoc{}
+	for i := range project.Docs {
+		docs[project.Docs[i].ID] = &project.Docs[i]
+	}
+
+	reanchored, orphaned := 0, 0
+	for i := range comments {
+		comment := &comments[i]
+		doc, ok := docs[comment.DocID]
+		switch {
+		case !ok && comment.Orphaned:
+			continue
+		case !ok:
+			comment.Orphaned = true
+		case comment.DocVersion == doc.Version:
+			continue
+		default:
+			comment.Reanchor(doc, minAnchorSimilarity)
+			comment.DocSHA1 = generateDocSHA1(strings.Join(doc.Lines, "\n"))
+		}
+
+		if comment.Orphaned {
+			orphaned++
+		} else {
+			reanchored++
+		}
+		if _, err := s.commentCollection.UpdateOne(ctx, bson.M{"_id": comment.ID}, bson.M{"$set": bson.M{
+			"doc_version":    comment.DocVersion,
+			"doc_sha1":       comment.DocSHA1,
+			"doc_path":       comment.DocPath,
+			"quote_position": comment.QuotePosition,
+			"quote_text":     comment.QuoteText,
+			"orphaned":       comment.Orphaned,
+			"updated_at":     bson.NewDateTimeFromTime(time.Now()),
+		}}); err != nil {
+			return reanchored, orphaned, err
+		}
+	}
+	return reanchored, orphaned, nil
+}
+
+// ReanchorCommentsOrLog re-anchors the comments of the project after its docs changed. Failures are logged, as the
+// project was already updated.
+func (s *ReverseCommentService) ReanchorCommentsOrLog(ctx context.Context, project *models.Project) {
+	reanchored, orphaned, err := s.ReanchorComments(ctx, project)
+	if err != nil {
+		s.logger.Error("Failed to re-anchor comments", "error", err, "projectID", project.ProjectID)
+		return
+	}
+	if reanchored > 0 || orphaned > 0 {
+		s.logger.Info("Re-anchored comments", "projectID", project.ProjectID, "reanchored", reanchored, "orphaned", orphaned)
+	}
+}
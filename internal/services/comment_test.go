@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/tex"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCommentLifecycle(t *testing.T) {
+	os.Setenv("PD_MONGO_URI", "mongodb://localhost:27017")
+	dbInstance, err := db.NewDB(cfg.GetCfg(), logger.GetLogger())
+	if err != nil {
+		t.Fatalf("failed to connect to test db: %v", err)
+	}
+	ps := services.NewProjectService(dbInstance, cfg.GetCfg(), logger.GetLogger())
+	cs := services.NewReverseCommentService(dbInstance, cfg.GetCfg(), logger.GetLogger(), ps)
+	ctx := context.Background()
+
+	userID := bson.NewObjectID()
+	projectID := "test-project-" + bson.NewObjectID().Hex()
+	t.Cleanup(func() {
+		_, _ = cs.CommentCollection().DeleteMany(ctx, bson.M{"user_id": userID})
+	})
+
+	insert := func(docPath string, position int, importance models.ImportanceLevel) bson.ObjectID {
+		comment := models.Comment{
+			BaseModel:       models.BaseModel{ID: bson.NewObjectID(), CreatedAt: bson.NewDateTimeFromTime(time.Now())},
+			UserID:          userID,
+			ProjectID:       projectID,
+			DocID:           docPath,
+			DocPath:         docPath,
+			QuotePosition:   position,
+			ImportanceLevel: importance,
+			Section:         "Introduction",
+		}
+		_, err := cs.CommentCollection().InsertOne(ctx, comment)
+		assert.NoError(t, err)
+		return comment.ID
+	}
+	second := insert("main.tex", 20, models.ImportanceLevelLow)
+	first := insert("main.tex", 10, models.ImportanceLevelHigh)
+	other := insert("intro.tex", 5, models.ImportanceLevelHigh)
+
+	comments, total, err := cs.ListComments(ctx, userID, projectID, services.CommentFilter{}, 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []bson.ObjectID{other, first}, []bson.ObjectID{comments[0].ID, comments[1].ID})
+
+	comments, total, err = cs.ListComments(ctx, userID, projectID, services.CommentFilter{
+		DocID:      "main.tex",
+		Importance: []models.ImportanceLevel{models.ImportanceLevelHigh},
+	}, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, first, comments[0].ID)
+
+	resolved, err := cs.SetCommentsStatus(ctx, userID, projectID, []bson.ObjectID{first, second}, models.CommentStatusResolved)
+	assert.NoError(t, err)
+	assert.Len(t, resolved, 2)
+	for _, comment := range resolved {
+		assert.Equal(t, models.CommentStatusResolved, comment.IsAddedToOverleaf)
+	}
+
+	comments, _, err = cs.ListComments(ctx, userID, projectID, services.CommentFilter{
+		Statuses: []models.CommentStatus{models.CommentStatusNoAction},
+	}, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, other, comments[0].ID)
+
+	// A missing comment fails the whole update
+	_, err = cs.SetCommentsStatus(ctx, userID, projectID, []bson.ObjectID{other, bson.NewObjectID()}, models.CommentStatusRejected)
+	assert.Error(t, err)
+	comment, err := cs.GetComment(ctx, userID, projectID, other)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CommentStatusNoAction, comment.IsAddedToOverleaf)
+
+	comment, err = cs.AddCommentReplies(ctx, userID, projectID, other,
+		models.CommentReply{Role: models.CommentReplyRoleUser, Content: "Why?"},
+		models.CommentReply{Role: models.CommentReplyRoleAssistant, Content: "Because."},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, comment.Replies, 2)
+	assert.Equal(t, "Because.", comment.Replies[1].Content)
+	assert.False(t, comment.Replies[0].ID.IsZero())
+}
+
+func TestReanchorComments(t *testing.T) {
+	os.Setenv("PD_MONGO_URI", "mongodb://localhost:27017")
+	dbInstance, err := db.NewDB(cfg.GetCfg(), logger.GetLogger())
+	if err != nil {
+		t.Fatalf("failed to connect to test db: %v", err)
+	}
+	ps := services.NewProjectService(dbInstance, cfg.GetCfg(), logger.GetLogger())
+	cs := services.NewReverseCommentService(dbInstance, cfg.GetCfg(), logger.GetLogger(), ps)
+	ctx := context.Background()
+
+	userID := bson.NewObjectID()
+	projectID := "test-project-" + bson.NewObjectID().Hex()
+	t.Cleanup(func() {
+		_, _ = cs.CommentCollection().DeleteMany(ctx, bson.M{"user_id": userID})
+	})
+
+	insert := func(docID string, quote string) bson.ObjectID {
+		comment := models.Comment{
+			BaseModel:  models.BaseModel{ID: bson.NewObjectID()},
+			UserID:     userID,
+			ProjectID:  projectID,
+			DocID:      docID,
+			DocVersion: 1,
+			QuoteText:  quote,
+		}
+		_, err := cs.CommentCollection().InsertOne(ctx, comment)
+		assert.NoError(t, err)
+		return comment.ID
+	}
+	moved := insert("main", "It works.")
+	removed := insert("main", "Old sentence.")
+	deleted := insert("appendix", "Appendix.")
+
+	project := &models.Project{
+		UserID:    userID,
+		ProjectID: projectID,
+		Docs:      []models.ProjectDoc{{ID: "main", Version: 2, Filepath: "main.tex", Lines: []string{"New intro.", "It works."}}},
+	}
+	reanchored, orphaned, err := cs.ReanchorComments(ctx, project)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reanchored)
+	assert.Equal(t, 2, orphaned)
+
+	comment, err := cs.GetComment(ctx, userID, projectID, moved)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, comment.QuotePosition)
+	assert.Equal(t, 2, comment.DocVersion)
+	for _, id := range []bson.ObjectID{removed, deleted} {
+		comment, err := cs.GetComment(ctx, userID, projectID, id)
+		assert.NoError(t, err)
+		assert.True(t, comment.Orphaned)
+	}
+
+	// Comments are only re-anchored once per version
+	reanchored, orphaned, err = cs.ReanchorComments(ctx, project)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, reanchored+orphaned)
+}
+
+func TestLintComments_SkipsExisting(t *testing.T) {
+	os.Setenv("PD_MONGO_URI", "mongodb://localhost:27017")
+	dbInstance, err := db.NewDB(cfg.GetCfg(), logger.GetLogger())
+	if err != nil {
+		t.Fatalf("failed to connect to test db: %v", err)
+	}
+	ps := services.NewProjectService(dbInstance, cfg.GetCfg(), logger.GetLogger())
+	cs := services.NewReverseCommentService(dbInstance, cfg.GetCfg(), logger.GetLogger(), ps)
+	ctx := context.Background()
+
+	userID := bson.NewObjectID()
+	projectID := "test-project-" + bson.NewObjectID().Hex()
+	t.Cleanup(func() {
+		_, _ = cs.CommentCollection().DeleteMany(ctx, bson.M{"user_id": userID})
+	})
+
+	project := &models.Project{
+		UserID:    userID,
+		ProjectID: projectID,
+		Docs:      []models.ProjectDoc{{ID: "main", Version: 1, Filepath: "main.tex", Lines: []string{"See \\ref{fig:a}."}}},
+	}
+	findings := []tex.LintFinding{{
+		Rule:     tex.LintUndefinedReference,
+		Key:      "fig:a",
+		Command:  "\\ref{fig:a}",
+		Message:  "undefined reference fig:a",
+		Location: tex.SourceLocation{Filepath: "main.tex", Line: 1, Column: 5},
+	}}
+
+	comments, err := cs.LintComments(ctx, project, findings)
+	assert.NoError(t, err)
+	assert.Len(t, comments, 1)
+
+	again, err := cs.LintComments(ctx, project, findings)
+	assert.NoError(t, err)
+	assert.Empty(t, again)
+
+	// The finding is commented again once its comment is resolved
+	commentID, err := bson.ObjectIDFromHex(comments[0].GetCommentId())
+	assert.NoError(t, err)
+	_, err = cs.SetCommentsStatus(ctx, userID, projectID, []bson.ObjectID{commentID}, models.CommentStatusResolved)
+	assert.NoError(t, err)
+	again, err = cs.LintComments(ctx, project, findings)
+	assert.NoError(t, err)
+	assert.Len(t, again, 1)
+}
@@ -12,6 +12,7 @@ import (
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/shared"
 	"paperdebugger/internal/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -20,7 +21,9 @@ import (
 
 type ProjectService struct {
 	BaseService
-	projectCollection *mongo.Collection
+	projectCollection  *mongo.Collection
+	revisionCollection *mongo.Collection
+	blobCollection     *mongo.Collection
 }
 
 type ClassifyPaperRequest struct {
@@ -29,9 +32,16 @@ type ClassifyPaperRequest struct {
 
 func NewProjectService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *ProjectService {
 	base := NewBaseService(db, cfg, logger)
+	revisionCollection := base.db.Collection((models.ProjectRevision{}).CollectionName())
+	blobCollection := base.db.Collection((models.ProjectBlob{}).CollectionName())
+	if err := createProjectRevisionIndexes(revisionCollection, blobCollection); err != nil {
+		logger.Error("Failed to create indexes for project revisions", err)
+	}
 	return &ProjectService{
-		BaseService:       base,
-		projectCollection: base.db.Collection((models.Project{}).CollectionName()),
+		BaseService:        base,
+		projectCollection:  base.db.Collection((models.Project{}).CollectionName()),
+		revisionCollection: revisionCollection,
+		blobCollection:     blobCollection,
 	}
 }
 
@@ -51,6 +61,7 @@ func (s *ProjectService) UpsertProject(ctx context.Context, userID bson.ObjectID
 		if err != nil {
 			return nil, err
 		}
+		s.recordProjectRevisionOrLog(ctx, project)
 		return project, nil
 	} else {
 		for _, doc := range project.Docs {
@@ -70,10 +81,46 @@ func (s *ProjectService) UpsertProject(ctx context.Context, userID bson.ObjectID
 		if err != nil {
 			return nil, err
 		}
+		s.recordProjectRevisionOrLog(ctx, project)
 		return project, nil
 	}
 }
 
+// PatchProjectDocs applies the patches to the docs of the project, see Project.ApplyDocPatches.
+// The project is only updated if it was not changed concurrently, otherwise ErrDocVersionConflict is returned.
+func (s *ProjectService) PatchProjectDocs(ctx context.Context, userID bson.ObjectID, projectID string, patches []models.DocPatch, rootDocID string) (*models.Project, error) {
+	project, err := s.GetProject(ctx, userID, projectID)
+	if err == mongo.ErrNoDocuments {
+		return nil, shared.ErrRecordNotFound("project not found, it must be synced with UpsertProject first")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := project.ApplyDocPatches(patches, rootDocID); err != nil {
+		return nil, err
+	}
+
+	previousUpdatedAt := project.UpdatedAt
+	project.UpdatedAt = bson.NewDateTimeFromTime(time.Now())
+	result, err := s.projectCollection.UpdateOne(ctx,
+		bson.M{"_id": project.ID, "updated_at": previousUpdatedAt},
+		bson.M{"$set": bson.M{
+			"docs":        project.Docs,
+			"root_doc_id": project.RootDocID,
+			"updated_at":  project.UpdatedAt,
+		}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, shared.ErrDocVersionConflict("project was changed concurrently")
+	}
+	s.recordProjectRevisionOrLog(ctx, project)
+	return project, nil
+}
+
 func (s *ProjectService) GetProject(ctx context.Context, userID bson.ObjectID, projectID string) (*models.Project, error) {
 	result := s.projectCollection.FindOne(ctx, bson.M{"user_id": userID, "project_id": projectID})
 	if result.Err() != nil {
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/libs/textdiff"
+	"paperdebugger/internal/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// projectDiffContext is the number of unchanged lines around the changes of a revision diff.
+const projectDiffContext = 3
+
+func createProjectRevisionIndexes(revisionCollection *mongo.Collection, blobCollection *mongo.Collection) error {
+	_, err := revisionCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "project_id", Value: 1},
+			{Key: "revision", Value: -1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = revisionCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "project_id", Value: 1},
+			{Key: "content_version", Value: 1},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = blobCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "hash", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// recordProjectRevisionOrLog records a revision of the project. The project is already saved,
+// so a failure only leaves a gap in the history.
+func (s *ProjectService) recordProjectRevisionOrLog(ctx context.Context, project *models.Project) {
+	if err := s.recordProjectRevision(ctx, project); err != nil {
+		s.logger.Error("Failed to record project revision", "error", err, "projectID", project.ProjectID)
+	}
+}
+
+// maxProjectRevisionAttempts is the number of revision numbers tried when upserts of the project race.
+const maxProjectRevisionAttempts = 5
+
+// recordProjectRevision records a new revision of the project if its content changed since the latest revision.
+// Blobs are shared by the revisions of all the projects of the user, and are never removed, see ProjectBlob.
+func (s *ProjectService) recordProjectRevision(ctx context.Context, project *models.Project) error {
+	next, err := s.nextProjectRevision(ctx, project)
+	if err != nil || next == 0 {
+		return err
+	}
+
+	now := bson.NewDateTimeFromTime(time.Now())
+	docs := make([]models.ProjectRevisionDoc, len(project.Docs))
+	for i, doc := range project.Docs {
+		hash := doc.BlobHash()
+		_, err := s.blobCollection.UpdateOne(ctx,
+			bson.M{"user_id": project.UserID, "hash": hash},
+			bson.M{"$setOnInsert": bson.M{
+				"_id":        bson.NewObjectID(),
+				"created_at": now,
+				"updated_at": now,
+				"lines":      doc.Lines,
+			}},
+			options.UpdateOne().SetUpsert(true),
+		)
+		if err != nil && !mongo.IsDuplicateKeyError(err) { // Inserted concurrently
+			return err
+		}
+		docs[i] = models.ProjectRevisionDoc{
+			ID:       doc.ID,
+			Version:  doc.Version,
+			Filepath: doc.Filepath,
+			BlobHash: hash,
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		revision := &models.ProjectRevision{
+			BaseModel: models.BaseModel{
+				ID:        bson.NewObjectID(),
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			UserID:         project.UserID,
+			ProjectID:      project.ProjectID,
+			Revision:       next,
+			ContentVersion: project.ContentVersion(),
+			RootDocID:      project.RootDocID,
+			Docs:           docs,
+		}
+		_, err = s.revisionCollection.InsertOne(ctx, revision)
+		if err == nil || !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		if attempt == maxProjectRevisionAttempts {
+			return fmt.Errorf("failed to record a revision after %d attempts: %w", attempt, err)
+		}
+
+		// A concurrent upsert recorded this revision number, the content is recorded as the next one
+		next, err = s.nextProjectRevision(ctx, project)
+		if err != nil || next == 0 {
+			return err
+		}
+	}
+}
+
+// nextProjectRevision returns the number of the next revision of the project, or 0 if the latest revision has the
+// content of the project.
+func (s *ProjectService) nextProjectRevision(ctx context.Context, project *models.Project) (int, error) {
+	latest, err := s.findProjectRevision(ctx, project.UserID, project.ProjectID, 0)
+	if err == mongo.ErrNoDocuments {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if latest.ContentVersion == project.ContentVersion() {
+		return 0, nil
+	}
+	return latest.Revision + 1, nil
+}
+
+// ListProjectRevisions returns the latest revisions of the project older than beforeRevision, newest first.
+// All the revisions are listed if beforeRevision is 0.
+func (s *ProjectService) ListProjectRevisions(ctx context.Context, userID bson.ObjectID, projectID string, beforeRevision int, limit int) ([]models.ProjectRevision, error) {
+	filter := bson.M{"user_id": userID, "project_id": projectID}
+	if beforeRevision != 0 {
+		filter["revision"] = bson.M{"$lt": beforeRevision}
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"revision": -1}).
+		SetLimit(int64(limit))
+	cursor, err := s.revisionCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []models.ProjectRevision{}
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// GetProjectRevision returns a revision of the project, or the latest one if revision is 0.
+func (s *ProjectService) GetProjectRevision(ctx context.Context, userID bson.ObjectID, projectID string, revision int) (*models.ProjectRevision, error) {
+	result, err := s.findProjectRevision(ctx, userID, projectID, revision)
+	if err == mongo.ErrNoDocuments {
+		if revision == 0 {
+			return nil, shared.ErrRecordNotFound("project has no revision")
+		}
+		return nil, shared.ErrRecordNotFound(fmt.Sprintf("revision %d not found", revision))
+	}
+	return result, err
+}
+
+func (s *ProjectService) findProjectRevision(ctx context.Context, userID bson.ObjectID, projectID string, revision int) (*models.ProjectRevision, error) {
+	filter := bson.M{"user_id": userID, "project_id": projectID}
+	if revision != 0 {
+		filter["revision"] = revision
+	}
+
+	result := &models.ProjectRevision{}
+	opts := options.FindOne().SetSort(bson.M{"revision": -1})
+	if err := s.revisionCollection.FindOne(ctx, filter, opts).Decode(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetProjectRevisionDoc returns a doc as it was at a revision of the project.
+func (s *ProjectService) GetProjectRevisionDoc(ctx context.Context, projectRevision *models.ProjectRevision, docID string) (*models.ProjectDoc, error) {
+	for _, doc := range projectRevision.Docs {
+		if doc.ID != docID {
+			continue
+		}
+		blobs, err := s.getProjectBlobs(ctx, projectRevision.UserID, []string{doc.BlobHash})
+		if err != nil {
+			return nil, err
+		}
+		return &models.ProjectDoc{
+			ID:       doc.ID,
+			Version:  doc.Version,
+			Filepath: doc.Filepath,
+			Lines:    blobs[doc.BlobHash],
+		}, nil
+	}
+	return nil, shared.ErrRecordNotFound(fmt.Sprintf("doc %s not found in revision %d", docID, projectRevision.Revision))
+}
+
+// DiffProjectRevisions returns the unified diff of the docs changed between two revisions of a project.
+// Docs are matched by ID, so that renamed docs are diffed with their previous path.
+func (s *ProjectService) DiffProjectRevisions(ctx context.Context, from *models.ProjectRevision, to *models.ProjectRevision) (string, error) {
+	type docChange struct {
+		before, after *models.ProjectRevisionDoc
+	}
+	changes := map[string]*docChange{}
+	for i := range from.Docs {
+		changes[from.Docs[i].ID] = &docChange{before: &from.Docs[i]}
+	}
+	for i := range to.Docs {
+		doc := &to.Docs[i]
+		if change, ok := changes[doc.ID]; ok {
+			change.after = doc
+		} else {
+			changes[doc.ID] = &docChange{after: doc}
+		}
+	}
+
+	var hashes []string
+	var sorted []*docChange
+	for _, change := range changes {
+		if change.before != nil && change.after != nil && change.before.BlobHash == change.after.BlobHash && change.before.Filepath == change.after.Filepath {
+			continue
+		}
+		if change.before != nil {
+			hashes = append(hashes, change.before.BlobHash)
+		}
+		if change.after != nil {
+			hashes = append(hashes, change.after.BlobHash)
+		}
+		sorted = append(sorted, change)
+	}
+	path := func(change *docChange) string {
+		if change.after != nil {
+			return change.after.Filepath
+		}
+		return change.before.Filepath
+	}
+	sort.Slice(sorted, func(i, j int) bool { return path(sorted[i]) < path(sorted[j]) })
+
+	blobs, err := s.getProjectBlobs(ctx, to.UserID, hashes)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, change := range sorted {
+		oldName, newName := "/dev/null", "/dev/null"
+		var oldLines, newLines []string
+		if change.before != nil {
+			oldName = "a/" + change.before.Filepath
+			oldLines = blobs[change.before.BlobHash]
+		}
+		if change.after != nil {
+			newName = "b/" + change.after.Filepath
+			newLines = blobs[change.after.BlobHash]
+		}
+		fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+		sb.WriteString(textdiff.Unified("", "", oldLines, newLines, projectDiffContext))
+	}
+	return sb.String(), nil
+}
+
+// DiffProjectVersions returns the unified diff of the docs changed between two versions of a project, see
+// Project.ContentVersion. It fails with ErrRecordNotFound if a version has no revision.
+func (s *ProjectService) DiffProjectVersions(ctx context.Context, userID bson.ObjectID, projectID string, fromVersion string, toVersion string) (string, error) {
+	revisions := make([]*models.ProjectRevision, 2)
+	for i, version := range []string{fromVersion, toVersion} {
+		revision := &models.ProjectRevision{}
+		opts := options.FindOne().SetSort(bson.M{"revision": -1})
+		err := s.revisionCollection.FindOne(ctx, bson.M{"user_id": userID, "project_id": projectID, "content_version": version}, opts).Decode(revision)
+		if err == mongo.ErrNoDocuments {
+			return "", shared.ErrRecordNotFound(fmt.Sprintf("no revision of version %s", version))
+		}
+		if err != nil {
+			return "", err
+		}
+		revisions[i] = revision
+	}
+	return s.DiffProjectRevisions(ctx, revisions[0], revisions[1])
+}
+
+// getProjectBlobs returns the lines of the blobs, by hash.
+func (s *ProjectService) getProjectBlobs(ctx context.Context, userID bson.ObjectID, hashes []string) (map[string][]string, error) {
+	result := map[string][]string{}
+	if len(hashes) == 0 {
+		return result, nil
+	}
+
+	cursor, err := s.blobCollection.Find(ctx, bson.M{"user_id": userID, "hash": bson.M{"$in": hashes}})
+	if err != nil {
+		return nil, err
+	}
+	var blobs []models.ProjectBlob
+	if err := cursor.All(ctx, &blobs); err != nil {
+		return nil, err
+	}
+	for _, blob := range blobs {
+		result[blob.Hash] = blob.Lines
+	}
+	for _, hash := range hashes {
+		if _, ok := result[hash]; !ok {
+			return nil, shared.ErrInternal(fmt.Sprintf("blob %s not found", hash))
+		}
+	}
+	return result, nil
+}
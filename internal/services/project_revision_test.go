@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestProjectRevisions(t *testing.T) {
+	os.Setenv("PD_MONGO_URI", "mongodb://localhost:27017")
+	dbInstance, err := db.NewDB(cfg.GetCfg(), logger.GetLogger())
+	if err != nil {
+		t.Fatalf("failed to connect to test db: %v", err)
+	}
+	ps := services.NewProjectService(dbInstance, cfg.GetCfg(), logger.GetLogger())
+	database := dbInstance.Database("paperdebugger")
+	ctx := context.Background()
+
+	userID := bson.NewObjectID()
+	projectID := "test-project-" + bson.NewObjectID().Hex()
+	t.Cleanup(func() {
+		_, _ = database.Collection(models.Project{}.CollectionName()).DeleteMany(ctx, bson.M{"user_id": userID})
+		_, _ = database.Collection(models.ProjectRevision{}.CollectionName()).DeleteMany(ctx, bson.M{"user_id": userID})
+		_, _ = database.Collection(models.ProjectBlob{}.CollectionName()).DeleteMany(ctx, bson.M{"user_id": userID})
+	})
+
+	upsert := func(docs ...models.ProjectDoc) {
+		_, err := ps.UpsertProject(ctx, userID, projectID, &models.Project{Name: "Test", RootDocID: "main", Docs: docs})
+		assert.NoError(t, err)
+	}
+	main := models.ProjectDoc{ID: "main", Version: 1, Filepath: "main.tex", Lines: []string{"\\input{intro}", "Old line."}}
+	intro := models.ProjectDoc{ID: "intro", Version: 1, Filepath: "intro.tex", Lines: []string{"Introduction."}}
+	upsert(main, intro)
+	upsert(main, intro) // Unchanged, no revision is recorded
+
+	main.Version, main.Lines = 2, []string{"\\input{sections/intro}", "New line."}
+	intro.Filepath = "sections/intro.tex"
+	upsert(main, intro)
+
+	revisions, err := ps.ListProjectRevisions(ctx, userID, projectID, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, 2, revisions[0].Revision)
+	assert.Equal(t, 1, revisions[1].Revision)
+
+	older, err := ps.ListProjectRevisions(ctx, userID, projectID, 2, 10)
+	assert.NoError(t, err)
+	assert.Len(t, older, 1)
+	assert.Equal(t, 1, older[0].Revision)
+
+	// The unchanged intro is stored once.
+	blobs, err := database.Collection(models.ProjectBlob{}.CollectionName()).CountDocuments(ctx, bson.M{"user_id": userID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), blobs)
+
+	doc, err := ps.GetProjectRevisionDoc(ctx, &revisions[1], "main")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"\\input{intro}", "Old line."}, doc.Lines)
+
+	diff, err := ps.DiffProjectRevisions(ctx, &revisions[1], &revisions[0])
+	assert.NoError(t, err)
+	assert.Equal(t, `--- a/main.tex
++++ b/main.tex
+@@ -1,2 +1,2 @@
+-\input{intro}
+-Old line.
++\input{sections/intro}
++New line.
+--- a/intro.tex
++++ b/sections/intro.tex
+`, diff)
+
+	latest, err := ps.GetProjectRevision(ctx, userID, projectID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, latest.Revision)
+	_, err = ps.GetProjectRevision(ctx, userID, projectID, 3)
+	assert.Error(t, err)
+}
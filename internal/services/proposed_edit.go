@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/shared"
+	"paperdebugger/internal/models"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type ProposedEditService struct {
+	BaseService
+	proposedEditCollection *mongo.Collection
+}
+
+func NewProposedEditService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *ProposedEditService {
+	base := NewBaseService(db, cfg, logger)
+	return &ProposedEditService{
+		BaseService:            base,
+		proposedEditCollection: base.db.Collection((models.ProposedEdit{}).CollectionName()),
+	}
+}
+
+func (s *ProposedEditService) CreateProposedEdit(ctx context.Context, edit *models.ProposedEdit) (*models.ProposedEdit, error) {
+	if edit == nil {
+		return nil, errors.New("proposed edit cannot be nil")
+	}
+
+	edit.BaseModel = models.BaseModel{
+		ID:        bson.NewObjectID(),
+		CreatedAt: bson.NewDateTimeFromTime(time.Now()),
+		UpdatedAt: bson.NewDateTimeFromTime(time.Now()),
+	}
+	if _, err := s.proposedEditCollection.InsertOne(ctx, edit); err != nil {
+		return nil, err
+	}
+	return edit, nil
+}
+
+func (s *ProposedEditService) GetProposedEdit(ctx context.Context, userID bson.ObjectID, projectID string, editID bson.ObjectID) (*models.ProposedEdit, error) {
+	edit := &models.ProposedEdit{}
+	err := s.proposedEditCollection.FindOne(ctx, bson.M{
+		"_id":        editID,
+		"user_id":    userID,
+		"project_id": projectID,
+	}).Decode(edit)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, shared.ErrRecordNotFound("proposed edit not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return edit, nil
+}
+
+// ResolveProposedEdit records that the user accepted or rejected a pending edit. An edit is resolved only once.
+func (s *ProposedEditService) ResolveProposedEdit(ctx context.Context, userID bson.ObjectID, projectID string, editID bson.ObjectID, status models.ProposedEditStatus) (*models.ProposedEdit, error) {
+	if status != models.ProposedEditAccepted && status != models.ProposedEditRejected {
+		return nil, shared.ErrBadRequest(fmt.Sprintf("invalid proposed edit status %q", status))
+	}
+
+	edit := &models.ProposedEdit{}
+	err := s.proposedEditCollection.FindOneAndUpdate(ctx, bson.M{
+		"_id":        editID,
+		"user_id":    userID,
+		"project_id": projectID,
+		"status":     models.ProposedEditPending,
+	}, bson.M{"$set": bson.M{
+		"status":     status,
+		"updated_at": bson.NewDateTimeFromTime(time.Now()),
+	}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(edit)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		existing, getErr := s.GetProposedEdit(ctx, userID, projectID, editID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		return nil, shared.ErrBadRequest(fmt.Sprintf("proposed edit is already %s", existing.Status))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return edit, nil
+}
+
+var proposedEditStatuses = map[models.ProposedEditStatus]chatv2.ProposedEditStatus{
+	models.ProposedEditPending:  chatv2.ProposedEditStatus_PROPOSED_EDIT_STATUS_PENDING,
+	models.ProposedEditAccepted: chatv2.ProposedEditStatus_PROPOSED_EDIT_STATUS_ACCEPTED,
+	models.ProposedEditRejected: chatv2.ProposedEditStatus_PROPOSED_EDIT_STATUS_REJECTED,
+}
+
+// ToProposedEditMessage returns the in-app message of the edit.
+func ToProposedEditMessage(edit *models.ProposedEdit) *chatv2.MessageTypeProposedEdit {
+	return &chatv2.MessageTypeProposedEdit{
+		EditId:     edit.ID.Hex(),
+		DocId:      edit.DocID,
+		FilePath:   edit.DocPath,
+		DocVersion: int32(edit.DocVersion),
+		StartLine:  int32(edit.StartLine),
+		OldLines:   edit.OldLines,
+		NewLines:   edit.NewLines,
+		Diff:       edit.Diff,
+		Status:     proposedEditStatuses[edit.Status],
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/rag"
+	"paperdebugger/internal/models"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	// DefaultRetrievalTopK is the number of chunks injected into each user turn.
+	DefaultRetrievalTopK = 8
+	// embeddingBatchSize is the number of chunks embedded per /embeddings request.
+	embeddingBatchSize = 64
+)
+
+// RetrievalService indexes the expanded LaTeX of a project in chunks and retrieves the
+// chunks relevant to a user turn. The index is persisted per project content version.
+type RetrievalService struct {
+	BaseService
+	chunkCollection *mongo.Collection
+	embeddingClient *openai.Client // nil when embeddings are not configured
+}
+
+func NewRetrievalService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *RetrievalService {
+	base := NewBaseService(db, cfg, logger)
+	service := &RetrievalService{
+		BaseService:     base,
+		chunkCollection: base.db.Collection((models.ProjectChunk{}).CollectionName()),
+	}
+	if cfg.EmbeddingModel != "" {
+		client := openai.NewClient(
+			option.WithBaseURL(cfg.EmbeddingBaseURL),
+			option.WithAPIKey(cfg.EmbeddingAPIKey),
+		)
+		service.embeddingClient = &client
+	}
+	return service
+}
+
+// Retrieve returns up to k chunks of the project relevant to the query, in document order.
+// It uses embeddings when configured, and falls back to BM25 otherwise or on failure.
+func (s *RetrievalService) Retrieve(ctx context.Context, userID bson.ObjectID, project *models.Project, query string, k int) ([]models.ProjectChunk, error) {
+	chunks, err := s.getOrBuildIndex(ctx, userID, project)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	ragChunks := make([]rag.Chunk, len(chunks))
+	for i, chunk := range chunks {
+		ragChunks[i] = rag.Chunk{Index: i, Section: chunk.Section, Text: chunk.Text}
+	}
+
+	var scores []float64
+	if s.embeddingClient != nil && chunks[0].EmbeddingModel == s.cfg.EmbeddingModel {
+		scores, err = s.embeddingScores(ctx, chunks, query)
+		if err != nil {
+			s.logger.Error("failed to score chunks by embeddings, falling back to BM25", "error", err, "projectID", project.ProjectID)
+		}
+	}
+	if scores == nil {
+		scores = rag.NewBM25Index(ragChunks).Score(query)
+	}
+
+	top := rag.TopK(ragChunks, scores, k)
+	sort.Slice(top, func(i, j int) bool { return top[i].Index < top[j].Index })
+
+	result := make([]models.ProjectChunk, len(top))
+	for i, chunk := range top {
+		result[i] = chunks[chunk.Index]
+	}
+	return result, nil
+}
+
+func (s *RetrievalService) embeddingScores(ctx context.Context, chunks []models.ProjectChunk, query string) ([]float64, error) {
+	embeddings, err := s.embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	scores := make([]float64, len(chunks))
+	for i, chunk := range chunks {
+		scores[i] = rag.CosineSimilarity(embeddings[0], chunk.Embedding)
+	}
+	return scores, nil
+}
+
+// getOrBuildIndex returns the chunks of the current project version, building and
+// persisting them on first use. Chunks of previous versions are removed.
+func (s *RetrievalService) getOrBuildIndex(ctx context.Context, userID bson.ObjectID, project *models.Project) ([]models.ProjectChunk, error) {
+	version := project.ContentVersion()
+	filter := bson.M{"user_id": userID, "project_id": project.ProjectID, "project_version": version}
+
+	cursor, err := s.chunkCollection.Find(ctx, filter, options.Find().SetSort(bson.M{"index": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var chunks []models.ProjectChunk
+	if err := cursor.All(ctx, &chunks); err != nil {
+		return nil, err
+	}
+	if len(chunks) > 0 {
+		return chunks, nil
+	}
+
+	fullContent, err := project.GetFullContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get full content: %w", err)
+	}
+
+	now := bson.NewDateTimeFromTime(time.Now())
+	for _, chunk := range rag.SplitChunks(fullContent, rag.DefaultChunkSize) {
+		chunks = append(chunks, models.ProjectChunk{
+			BaseModel: models.BaseModel{
+				ID:        bson.NewObjectID(),
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			UserID:         userID,
+			ProjectID:      project.ProjectID,
+			ProjectVersion: version,
+			Index:          chunk.Index,
+			Section:        chunk.Section,
+			Text:           chunk.Text,
+		})
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	if s.embeddingClient != nil {
+		if err := s.embedChunks(ctx, chunks); err != nil {
+			// BM25 still works without embeddings, drop the partial ones.
+			s.logger.Error("failed to embed project chunks", "error", err, "projectID", project.ProjectID)
+			for i := range chunks {
+				chunks[i].Embedding = nil
+				chunks[i].EmbeddingModel = ""
+			}
+		}
+	}
+
+	// Upsert by (user, project, version, index) so that concurrent builds do not duplicate chunks.
+	writes := make([]mongo.WriteModel, len(chunks))
+	for i, chunk := range chunks {
+		writes[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"user_id": userID, "project_id": project.ProjectID, "project_version": version, "index": chunk.Index}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"updated_at":      chunk.UpdatedAt,
+					"section":         chunk.Section,
+					"text":            chunk.Text,
+					"embedding_model": chunk.EmbeddingModel,
+					"embedding":       chunk.Embedding,
+				},
+				"$setOnInsert": bson.M{"_id": chunk.ID, "created_at": chunk.CreatedAt},
+			}).
+			SetUpsert(true)
+	}
+	if _, err := s.chunkCollection.BulkWrite(ctx, writes); err != nil {
+		return nil, err
+	}
+
+	// Only the chunks built before the project reached this version are removed, the chunks of a newer version
+	// may be being built concurrently.
+	_, err = s.chunkCollection.DeleteMany(ctx, bson.M{
+		"user_id":         userID,
+		"project_id":      project.ProjectID,
+		"project_version": bson.M{"$ne": version},
+		"created_at":      bson.M{"$lt": project.UpdatedAt},
+	})
+	if err != nil {
+		s.logger.Error("failed to delete stale project chunks", "error", err, "projectID", project.ProjectID)
+	}
+
+	return chunks, nil
+}
+
+func (s *RetrievalService) embedChunks(ctx context.Context, chunks []models.ProjectChunk) error {
+	for start := 0; start < len(chunks); start += embeddingBatchSize {
+		end := min(start+embeddingBatchSize, len(chunks))
+		texts := make([]string, 0, end-start)
+		for _, chunk := range chunks[start:end] {
+			texts = append(texts, chunk.Section+"\n"+chunk.Text)
+		}
+
+		embeddings, err := s.embed(ctx, texts)
+		if err != nil {
+			return err
+		}
+		for i := range embeddings {
+			chunks[start+i].Embedding = embeddings[i]
+			chunks[start+i].EmbeddingModel = s.cfg.EmbeddingModel
+		}
+	}
+	return nil
+}
+
+func (s *RetrievalService) embed(ctx context.Context, texts []string) ([][]float64, error) {
+	resp, err := s.embeddingClient.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Model: openai.EmbeddingModel(s.cfg.EmbeddingModel),
+		Input: openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: texts},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(resp.Data))
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for _, data := range resp.Data {
+		if int(data.Index) >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range", data.Index)
+		}
+		embeddings[data.Index] = data.Embedding
+	}
+	return embeddings, nil
+}
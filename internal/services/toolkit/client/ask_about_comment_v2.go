@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"paperdebugger/internal/models"
+
+	"github.com/openai/openai-go/v3"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AskAboutCommentV2 answers a question of the user about a review comment, grounded in the quoted passage, its
+// section and the text around it. The previous replies of the discussion are given as context. The model may read
+// the rest of the project, but has no tool to edit it. The cost is tracked like any other completion.
+func (a *AIClientV2) AskAboutCommentV2(ctx context.Context, userID bson.ObjectID, projectID string, comment *models.Comment, quoteContext string, question string, modelSlug string, llmProvider *models.LLMProviderConfig, customModel *models.CustomModel) (string, UsageCost, error) {
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("File: %s\n", comment.DocPath))
+	if comment.Section != "" {
+		message.WriteString(fmt.Sprintf("Section: %s\n", comment.Section))
+	}
+	message.WriteString(fmt.Sprintf("\nText around the quoted passage:\n<context>\n%s\n</context>\n", quoteContext))
+	message.WriteString(fmt.Sprintf("\nQuoted passage:\n<quote>\n%s\n</quote>\n", comment.QuoteText))
+	message.WriteString(fmt.Sprintf("\nReview comment (importance %s):\n%s\n", comment.ImportanceLevel, comment.Comment))
+	if len(comment.Replies) > 0 {
+		message.WriteString("\nDiscussion so far:\n")
+		for _, reply := range comment.Replies {
+			role := "Reviewer"
+			if reply.Role == models.CommentReplyRoleAssistant {
+				role = "Assistant"
+			}
+			message.WriteString(fmt.Sprintf("%s: %s\n", role, reply.Content))
+		}
+	}
+	message.WriteString(fmt.Sprintf("\nQuestion of the reviewer:\n%s", question))
+
+	_, resp, usage, err := a.ReadOnlyChatCompletionV2(ctx, userID, projectID, modelSlug, OpenAIChatHistory{
+		openai.SystemMessage("You are an assistant that explains the review comments you made on a LaTeX paper. Answer the question of the reviewer about the comment, based on the quoted passage and the text around it. Be specific and concise, and quote the paper when it helps. Do not edit the paper, answer with text only."),
+		openai.UserMessage(message.String()),
+	}, llmProvider, customModel)
+	if err != nil {
+		return "", usage, err
+	}
+
+	for i := len(resp) - 1; i >= 0; i-- {
+		if answer := strings.TrimSpace(resp[i].Payload.GetAssistant().GetContent()); answer != "" {
+			return answer, usage, nil
+		}
+	}
+	return "", usage, fmt.Errorf("empty answer")
+}
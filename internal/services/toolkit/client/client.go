@@ -4,6 +4,7 @@ import (
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/secret"
 	"paperdebugger/internal/models"
 	"paperdebugger/internal/services"
 	"paperdebugger/internal/services/toolkit/handler"
@@ -20,6 +21,7 @@ type AIClient struct {
 
 	reverseCommentService *services.ReverseCommentService
 	projectService        *services.ProjectService
+	keyring               *secret.Keyring
 	cfg                   *cfg.Cfg
 	logger                *logger.Logger
 }
@@ -32,14 +34,14 @@ func (a *AIClient) GetOpenAIClient(llmConfig *models.LLMProviderConfig) *openai.
 
 	if llmConfig != nil {
 		Endpoint = llmConfig.Endpoint
-		APIKey = llmConfig.APIKey
+		APIKey = a.decryptAPIKey(llmConfig)
 	}
 
 	if Endpoint == "" {
 		Endpoint = a.cfg.OpenAIBaseURL
 	}
 
-	if APIKey == "" {
+	if llmConfig == nil || llmConfig.APIKey == "" {
 		APIKey = a.cfg.OpenAIAPIKey
 	}
 
@@ -57,6 +59,7 @@ func NewAIClient(
 
 	reverseCommentService *services.ReverseCommentService,
 	projectService *services.ProjectService,
+	keyring *secret.Keyring,
 	cfg *cfg.Cfg,
 	logger *logger.Logger,
 ) *AIClient {
@@ -78,9 +81,21 @@ func NewAIClient(
 
 		reverseCommentService: reverseCommentService,
 		projectService:        projectService,
+		keyring:               keyring,
 		cfg:                   cfg,
 		logger:                logger,
 	}
 
 	return client
 }
+
+// decryptAPIKey decrypts the stored API key of the user, API keys are only decrypted to build a client.
+// It returns an empty API key if it fails, so that the request fails.
+func (a *AIClient) decryptAPIKey(llmConfig *models.LLMProviderConfig) string {
+	decrypted, err := a.keyring.Decrypt(llmConfig.APIKey, llmConfig.APIKeyAAD)
+	if err != nil {
+		a.logger.Error("Failed to decrypt API key", "error", err)
+		return ""
+	}
+	return decrypted
+}
@@ -4,23 +4,35 @@ import (
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/secret"
 	"paperdebugger/internal/models"
 	"paperdebugger/internal/services"
+	toolCallRecordDB "paperdebugger/internal/services/toolkit/db"
 	"paperdebugger/internal/services/toolkit/handler"
+	"paperdebugger/internal/services/toolkit/registry"
 
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// DefaultModelSlugV2 is the model of the completions whose request does not name one.
+const DefaultModelSlugV2 = "gpt-5.2"
+
+// UtilityModelSlugV2 is the cheap model of the titles and summaries generated with the shared API key.
+const UtilityModelSlugV2 = "gpt-5-nano"
+
 type AIClientV2 struct {
-	toolCallHandler        *handler.ToolCallHandlerV2
-	db                     *mongo.Database
-	functionCallCollection *mongo.Collection
+	toolCallHandler         *handler.ToolCallHandlerV2
+	readOnlyToolCallHandler *handler.ToolCallHandlerV2 // The tools that only read the project, see initializeReadOnlyToolkitV2
+	toolLessCallHandler     *handler.ToolCallHandlerV2 // No tools, for the completions that only transform text
+	db                      *mongo.Database
+	functionCallCollection  *mongo.Collection
 
 	reverseCommentService *services.ReverseCommentService
 	projectService        *services.ProjectService
 	usageService          *services.UsageService
+	keyring               *secret.Keyring
 	cfg                   *cfg.Cfg
 	logger                *logger.Logger
 }
@@ -31,11 +43,13 @@ type AIClientV2 struct {
 // When a user provides their own API key, use the /openai endpoint instead of /openrouter.
 func (a *AIClientV2) GetOpenAIClient(llmConfig *models.LLMProviderConfig) *openai.Client {
 	var Endpoint string = llmConfig.Endpoint
-	var APIKey string = llmConfig.APIKey
+	var APIKey string = a.decryptAPIKey(llmConfig)
 
+	// The stored API key is checked rather than the decrypted one, so that a key failing to decrypt
+	// does not fall back to the default API key.
 	if !llmConfig.IsCustomModel {
 		if Endpoint == "" {
-			if APIKey != "" {
+			if llmConfig.APIKey != "" {
 				// User provided their own API key, use the OpenAI-compatible endpoint
 				Endpoint = a.cfg.OpenAIBaseURL // standard openai base url
 			} else {
@@ -44,7 +58,7 @@ func (a *AIClientV2) GetOpenAIClient(llmConfig *models.LLMProviderConfig) *opena
 			}
 		}
 
-		if APIKey == "" {
+		if llmConfig.APIKey == "" {
 			APIKey = a.cfg.InferenceAPIKey
 		}
 	}
@@ -63,7 +77,9 @@ func NewAIClientV2(
 
 	reverseCommentService *services.ReverseCommentService,
 	projectService *services.ProjectService,
+	proposedEditService *services.ProposedEditService,
 	usageService *services.UsageService,
+	keyring *secret.Keyring,
 	cfg *cfg.Cfg,
 	logger *logger.Logger,
 ) *AIClientV2 {
@@ -100,11 +116,15 @@ func NewAIClientV2(
 		logger,
 	)
 
-	toolRegistry := initializeToolkitV2(db, projectService, cfg, logger)
-	toolCallHandler := handler.NewToolCallHandlerV2(toolRegistry)
+	toolRegistry := initializeToolkitV2(db, projectService, proposedEditService, cfg, logger)
+	toolCallHandler := handler.NewToolCallHandlerV2(toolRegistry, toolCallRecordDB.NewToolCallRecordDB(db), logger)
+	readOnlyToolCallHandler := handler.NewToolCallHandlerV2(initializeReadOnlyToolkitV2(projectService, cfg), toolCallRecordDB.NewToolCallRecordDB(db), logger)
+	toolLessCallHandler := handler.NewToolCallHandlerV2(registry.NewToolRegistryV2(cfg.ToolCallTimeout), toolCallRecordDB.NewToolCallRecordDB(db), logger)
 
 	client := &AIClientV2{
-		toolCallHandler: toolCallHandler,
+		toolCallHandler:         toolCallHandler,
+		readOnlyToolCallHandler: readOnlyToolCallHandler,
+		toolLessCallHandler:     toolLessCallHandler,
 
 		db:                     database,
 		functionCallCollection: database.Collection((models.FunctionCall{}).CollectionName()),
@@ -112,9 +132,21 @@ func NewAIClientV2(
 		reverseCommentService: reverseCommentService,
 		projectService:        projectService,
 		usageService:          usageService,
+		keyring:               keyring,
 		cfg:                   cfg,
 		logger:                logger,
 	}
 
 	return client
 }
+
+// decryptAPIKey decrypts the stored API key of the user, API keys are only decrypted to build a client.
+// It returns an empty API key if it fails, so that the request fails.
+func (a *AIClientV2) decryptAPIKey(llmConfig *models.LLMProviderConfig) string {
+	decrypted, err := a.keyring.Decrypt(llmConfig.APIKey, llmConfig.APIKeyAAD)
+	if err != nil {
+		a.logger.Error("Failed to decrypt API key", "error", err)
+		return ""
+	}
+	return decrypted
+}
@@ -0,0 +1,253 @@
+package client
+
+/*
+This file contains the context compaction of the chat history sent to the language model.
+
+Before each request, the history is checked against the context window of the model. If it does not fit,
+it is compacted in two stages:
+ 1. The outputs of tool calls made before the most recent turns are replaced by a short placeholder.
+ 2. If the history is still too long, the turns before the most recent ones are summarized by a cheap model.
+
+Only the history sent to the language model is compacted, the in-app chat history is kept as-is.
+*/
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"paperdebugger/internal/models"
+	"strings"
+
+	"github.com/openai/openai-go/v3"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const (
+	// charsPerToken is the average number of characters per token used by the estimator.
+	charsPerToken = 4
+	// compactionThreshold is the fraction of the input budget above which the history is compacted.
+	compactionThreshold = 0.8
+	// maxCompletionReserve is the largest fraction of the context window reserved for the completion.
+	maxCompletionReserve = 0.5
+	// minCompactionBudget is the smallest history budget, in tokens, whatever the context window.
+	minCompactionBudget = 1024
+	// keepRecentTurns is the number of most recent user turns that are never compacted.
+	keepRecentTurns = 2
+	// maxSummaryInputChars caps the characters (runes) of each message rendered into the summarization prompt.
+	maxSummaryInputChars = 4000
+
+	droppedToolOutputPlaceholder = "[tool output removed to save context, call the tool again if needed]"
+	conversationSummaryPrefix    = "Summary of the earlier part of this conversation:\n"
+)
+
+// CompactionV2 describes how the chat history was condensed.
+type CompactionV2 struct {
+	Summary            string
+	SummarizedMessages int
+	DroppedToolOutputs int
+	TokensBefore       int64
+	TokensAfter        int64
+}
+
+// chatMessageFields is the subset of a chat completion message needed by the compaction.
+type chatMessageFields struct {
+	Role      string          `json:"role"`
+	Content   json.RawMessage `json:"content"`
+	ToolCalls []struct {
+		Function struct {
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		} `json:"function"`
+	} `json:"tool_calls"`
+}
+
+func parseChatMessage(message openai.ChatCompletionMessageParamUnion) chatMessageFields {
+	var fields chatMessageFields
+	raw, err := json.Marshal(message)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(raw, &fields)
+	return fields
+}
+
+// text returns the textual content of the message, joining content parts if needed.
+func (m chatMessageFields) text() string {
+	var content string
+	if err := json.Unmarshal(m.Content, &content); err == nil {
+		return content
+	}
+	var parts []struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(m.Content, &parts); err == nil {
+		texts := make([]string, 0, len(parts))
+		for _, part := range parts {
+			texts = append(texts, part.Text)
+		}
+		return strings.Join(texts, "\n")
+	}
+	return ""
+}
+
+// EstimateTokensV2 estimates the number of tokens of the chat history.
+// It is a rough character based estimate, which errs on the side of overcounting.
+func EstimateTokensV2(messages OpenAIChatHistory) int64 {
+	var total int64
+	for _, message := range messages {
+		raw, err := json.Marshal(message)
+		if err != nil {
+			continue
+		}
+		total += int64(len(raw))/charsPerToken + 4 // per message overhead
+	}
+	return total
+}
+
+// recentTurnsStart returns the index of the first message of the most recent keepRecentTurns user turns.
+func recentTurnsStart(messages OpenAIChatHistory) int {
+	turns := 0
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].OfUser != nil {
+			turns++
+			if turns == keepRecentTurns {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// leadingSystemMessages returns the number of system or developer messages at the beginning of the history.
+func leadingSystemMessages(messages OpenAIChatHistory) int {
+	n := 0
+	for n < len(messages) && (messages[n].OfSystem != nil || messages[n].OfDeveloper != nil) {
+		n++
+	}
+	return n
+}
+
+// DropStaleToolOutputsV2 replaces the outputs of the tool calls made before the most recent turns
+// with a placeholder. It returns the new history and the number of outputs dropped.
+func DropStaleToolOutputsV2(messages OpenAIChatHistory) (OpenAIChatHistory, int) {
+	end := recentTurnsStart(messages)
+	result := make(OpenAIChatHistory, len(messages))
+	copy(result, messages)
+
+	dropped := 0
+	for i := 0; i < end; i++ {
+		tool := result[i].OfTool
+		if tool == nil {
+			continue
+		}
+		if len(parseChatMessage(result[i]).text()) <= len(droppedToolOutputPlaceholder) {
+			continue
+		}
+		result[i] = openai.ToolMessage(droppedToolOutputPlaceholder, tool.ToolCallID)
+		dropped++
+	}
+	return result, dropped
+}
+
+// compactionBudget returns the number of tokens of history above which it is compacted. The completion reserve is
+// capped, so that models whose maximum output is as large as their context window still have room for the history.
+func compactionBudget(contextWindow int64, maxCompletionTokens int64) int64 {
+	reserve := min(maxCompletionTokens, int64(float64(contextWindow)*maxCompletionReserve))
+	return max(int64(float64(contextWindow-reserve)*compactionThreshold), minCompactionBudget)
+}
+
+// CompactChatHistoryV2 compacts the chat history if it does not fit in the context window of the model.
+// It returns the history unchanged and a nil compaction if no compaction is needed.
+func (a *AIClientV2) CompactChatHistoryV2(ctx context.Context, userID bson.ObjectID, projectID string, modelSlug string, messages OpenAIChatHistory, contextWindow int64, llmProvider *models.LLMProviderConfig, customModel *models.CustomModel) (OpenAIChatHistory, *CompactionV2, error) {
+	params := getDefaultParamsV2(modelSlug, a.toolCallHandler.Registry, customModel)
+	budget := compactionBudget(contextWindow, params.MaxCompletionTokens.Value)
+
+	tokensBefore := EstimateTokensV2(messages)
+	if tokensBefore <= budget {
+		return messages, nil, nil
+	}
+
+	compaction := &CompactionV2{TokensBefore: tokensBefore}
+	messages, compaction.DroppedToolOutputs = DropStaleToolOutputsV2(messages)
+	compaction.TokensAfter = EstimateTokensV2(messages)
+
+	if compaction.TokensAfter > budget {
+		start := leadingSystemMessages(messages)
+		end := recentTurnsStart(messages)
+		if end > start {
+			summary, err := a.summarizeChatHistoryV2(ctx, userID, projectID, modelSlug, messages[start:end], llmProvider, customModel)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to summarize chat history: %w", err)
+			}
+
+			compacted := make(OpenAIChatHistory, 0, start+1+len(messages)-end)
+			compacted = append(compacted, messages[:start]...)
+			// The summary is a system message, so that each user message still starts a turn.
+			compacted = append(compacted, openai.SystemMessage(conversationSummaryPrefix+summary))
+			compacted = append(compacted, messages[end:]...)
+
+			messages = compacted
+			compaction.Summary = summary
+			compaction.SummarizedMessages = end - start
+			compaction.TokensAfter = EstimateTokensV2(messages)
+		}
+	}
+
+	if compaction.DroppedToolOutputs == 0 && compaction.SummarizedMessages == 0 {
+		// Nothing left to compact, the most recent turns alone exceed the budget.
+		return messages, nil, nil
+	}
+	return messages, compaction, nil
+}
+
+// renderChatHistoryForSummary renders the chat history as a plain text transcript.
+func renderChatHistoryForSummary(messages OpenAIChatHistory) string {
+	var sb strings.Builder
+	for _, message := range messages {
+		fields := parseChatMessage(message)
+		text := fields.text()
+		if runes := []rune(text); len(runes) > maxSummaryInputChars {
+			text = string(runes[:maxSummaryInputChars]) + " [truncated]"
+		}
+
+		switch fields.Role {
+		case "user":
+			sb.WriteString("User: " + text + "\n")
+		case "assistant":
+			if text != "" {
+				sb.WriteString("Assistant: " + text + "\n")
+			}
+			for _, toolCall := range fields.ToolCalls {
+				sb.WriteString(fmt.Sprintf("Assistant called tool '%s' with %s\n", toolCall.Function.Name, toolCall.Function.Arguments))
+			}
+		case "tool":
+			sb.WriteString("Tool result: " + text + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func (a *AIClientV2) summarizeChatHistoryV2(ctx context.Context, userID bson.ObjectID, projectID string, modelSlug string, messages OpenAIChatHistory, llmProvider *models.LLMProviderConfig, customModel *models.CustomModel) (string, error) {
+	message := fmt.Sprintf("%s\nSummarize the above conversation between a user and an assistant helping with a LaTeX paper. Keep the user's requests and preferences, the decisions made, the edits proposed or applied, and any facts about the paper that are still relevant. Drop small talk and verbatim tool outputs. Be concise, use bullet points, and give the summary only.", renderChatHistoryForSummary(messages))
+
+	// Default model if user is not using their own
+	modelToUse := UtilityModelSlugV2
+	if llmProvider.IsCustomModel {
+		modelToUse = modelSlug
+	}
+
+	// The transcript is all the summarizer needs, it has no tool to read or edit the project
+	_, resp, _, err := a.ToolLessChatCompletionV2(ctx, userID, projectID, modelToUse, OpenAIChatHistory{
+		openai.SystemMessage("You are a helpful assistant that summarizes conversations."),
+		openai.UserMessage(message),
+	}, llmProvider, customModel)
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(resp) - 1; i >= 0; i-- {
+		if summary := strings.TrimSpace(resp[i].Payload.GetAssistant().GetContent()); summary != "" {
+			return summary, nil
+		}
+	}
+	return "", fmt.Errorf("empty summary")
+}
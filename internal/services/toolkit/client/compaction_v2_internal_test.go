@@ -0,0 +1,29 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderChatHistoryForSummary_TruncatesRunes(t *testing.T) {
+	text := strings.Repeat("論", maxSummaryInputChars+10)
+	rendered := renderChatHistoryForSummary(OpenAIChatHistory{openai.UserMessage(text)})
+
+	assert.True(t, utf8.ValidString(rendered))
+	assert.Equal(t, "User: "+strings.Repeat("論", maxSummaryInputChars)+" [truncated]\n", rendered)
+}
+
+func TestCompactionBudget(t *testing.T) {
+	assert.Equal(t, int64((128000-4000)*compactionThreshold), compactionBudget(128000, 4000))
+
+	// Custom models commonly set their maximum output to their context window.
+	budget := compactionBudget(32000, 32000)
+	assert.Equal(t, int64(32000*(1-maxCompletionReserve)*compactionThreshold), budget)
+	assert.Equal(t, budget, compactionBudget(32000, 64000))
+
+	assert.Equal(t, int64(minCompactionBudget), compactionBudget(0, 4000))
+}
@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"paperdebugger/internal/services/toolkit/client"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func toolTurn(question string, toolCallID string, output string) client.OpenAIChatHistory {
+	return client.OpenAIChatHistory{
+		openai.UserMessage(question),
+		{
+			OfAssistant: &openai.ChatCompletionAssistantMessageParam{
+				ToolCalls: []openai.ChatCompletionMessageToolCallUnionParam{
+					{
+						OfFunction: &openai.ChatCompletionMessageFunctionToolCallParam{
+							ID: toolCallID,
+							Function: openai.ChatCompletionMessageFunctionToolCallFunctionParam{
+								Name:      "read_file",
+								Arguments: `{"path":"main.tex"}`,
+							},
+						},
+					},
+				},
+			},
+		},
+		openai.ToolMessage(output, toolCallID),
+		openai.AssistantMessage("Done."),
+	}
+}
+
+func TestEstimateTokensV2(t *testing.T) {
+	short := client.OpenAIChatHistory{openai.UserMessage("hello")}
+	long := client.OpenAIChatHistory{openai.UserMessage(strings.Repeat("word ", 1000))}
+
+	assert.Greater(t, client.EstimateTokensV2(short), int64(0))
+	assert.Greater(t, client.EstimateTokensV2(long), int64(1000))
+	assert.Equal(t, int64(0), client.EstimateTokensV2(nil))
+}
+
+func TestDropStaleToolOutputsV2(t *testing.T) {
+	bigOutput := strings.Repeat("\\section{Intro} lorem ipsum ", 200)
+
+	messages := client.OpenAIChatHistory{openai.SystemMessage("system")}
+	messages = append(messages, toolTurn("first", "call_1", bigOutput)...)
+	messages = append(messages, toolTurn("second", "call_2", bigOutput)...)
+	messages = append(messages, toolTurn("third", "call_3", bigOutput)...)
+
+	compacted, dropped := client.DropStaleToolOutputsV2(messages)
+	assert.Equal(t, 1, dropped)
+	assert.Len(t, compacted, len(messages))
+	assert.Less(t, client.EstimateTokensV2(compacted), client.EstimateTokensV2(messages))
+
+	// Only the tool output of the first turn is dropped, the two most recent turns are kept.
+	assert.Equal(t, "call_1", compacted[3].OfTool.ToolCallID)
+	assert.NotContains(t, compacted[3].OfTool.Content.OfString.Value, "lorem")
+	assert.Contains(t, compacted[7].OfTool.Content.OfString.Value, "lorem")
+	assert.Contains(t, compacted[11].OfTool.Content.OfString.Value, "lorem")
+
+	// The input history is not modified.
+	assert.Contains(t, messages[3].OfTool.Content.OfString.Value, "lorem")
+}
+
+func TestDropStaleToolOutputsV2_NothingToDrop(t *testing.T) {
+	messages := client.OpenAIChatHistory{openai.SystemMessage("system")}
+	messages = append(messages, toolTurn("only", "call_1", strings.Repeat("x", 1000))...)
+
+	compacted, dropped := client.DropStaleToolOutputsV2(messages)
+	assert.Equal(t, 0, dropped)
+	assert.Equal(t, messages, compacted)
+}
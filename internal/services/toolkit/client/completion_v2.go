@@ -4,23 +4,50 @@ import (
 	"context"
 	"encoding/json"
 	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
 	"paperdebugger/internal/services/toolkit/handler"
-	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
-// UsageCost holds cost information from a completion.
+// UsageCost holds cost and token information from a completion.
 type UsageCost struct {
-	Cost float64
+	Cost             float64
+	PromptTokens     int64
+	CompletionTokens int64
 }
 
 // define []openai.ChatCompletionMessageParamUnion as OpenAIChatHistory
 
+// Reasons of the IncompleteIndicator sent when a turn is stopped before the model finished.
+const (
+	IncompleteReasonCancelled    = "cancelled"      // Cancelled by the user
+	IncompleteReasonTurnTimeout  = "turn_timeout"   // The turn took longer than cfg.TurnTimeout
+	IncompleteReasonTurnMaxCost  = "turn_max_cost"  // The turn cost more than cfg.TurnMaxCost
+	IncompleteReasonMaxToolCalls = "max_tool_calls" // The model requested more than cfg.MaxToolCallsPerTurn tool calls
+)
+
+// turnStopReason returns why the turn context is done, or "" if it is not.
+func turnStopReason(ctx context.Context, turnCtx context.Context) string {
+	if ctx.Err() != nil {
+		return IncompleteReasonCancelled
+	}
+	if turnCtx.Err() != nil {
+		return IncompleteReasonTurnTimeout
+	}
+	return ""
+}
+
+// usesSharedAPIKey returns whether the completions are paid with the shared API key, see GetOpenAIClient.
+func usesSharedAPIKey(llmProvider *models.LLMProviderConfig) bool {
+	return !llmProvider.IsCustomModel && llmProvider.APIKey == ""
+}
+
 // ChatCompletion orchestrates a chat completion process with a language model (e.g., GPT), handling tool calls and message history management.
 //
 // Parameters:
@@ -42,12 +69,23 @@ func (a *AIClientV2) ChatCompletionV2(ctx context.Context, userID bson.ObjectID,
 	return openaiChatHistory, inappChatHistory, usage, nil
 }
 
+// ReadOnlyChatCompletionV2 is ChatCompletionV2 with only the tools that read the project: the model cannot propose
+// edits or call paid tools.
+func (a *AIClientV2) ReadOnlyChatCompletionV2(ctx context.Context, userID bson.ObjectID, projectID string, modelSlug string, messages OpenAIChatHistory, llmProvider *models.LLMProviderConfig, customModel *models.CustomModel) (OpenAIChatHistory, AppChatHistory, UsageCost, error) {
+	return a.chatCompletionStreamV2(ctx, a.readOnlyToolCallHandler, nil, userID, projectID, "", modelSlug, messages, llmProvider, customModel)
+}
+
+// ToolLessChatCompletionV2 is ChatCompletionV2 without any tool, for completions that only transform the messages.
+func (a *AIClientV2) ToolLessChatCompletionV2(ctx context.Context, userID bson.ObjectID, projectID string, modelSlug string, messages OpenAIChatHistory, llmProvider *models.LLMProviderConfig, customModel *models.CustomModel) (OpenAIChatHistory, AppChatHistory, UsageCost, error) {
+	return a.chatCompletionStreamV2(ctx, a.toolLessCallHandler, nil, userID, projectID, "", modelSlug, messages, llmProvider, customModel)
+}
+
 // ChatCompletionStream orchestrates a streaming chat completion process with a language model (e.g., GPT), handling tool calls, message history management, and real-time streaming of responses to the client.
 //
 // Parameters:
 //
 //	ctx: The context for controlling cancellation and deadlines.
-//	callbackStream: The gRPC stream to which incremental responses are sent in real time.
+//	callbackStream: The stream to which incremental responses are sent in real time.
 //	conversationId: The unique identifier for the conversation session in PaperDebugger.
 //	languageModel: The language model to use for completion (e.g., GPT-3.5, GPT-4).
 //	messages: The full chat history (as input) to send to the language model.
@@ -64,12 +102,27 @@ func (a *AIClientV2) ChatCompletionV2(ctx context.Context, userID bson.ObjectID,
 //   - If tool calls are required, it handles them and appends the results to the chat history, then continues the loop.
 //   - If no tool calls are needed, it appends the assistant's response and exits the loop.
 //   - Finally, it returns the updated chat histories, accumulated cost, and any error encountered.
-func (a *AIClientV2) ChatCompletionStreamV2(ctx context.Context, callbackStream chatv2.ChatService_CreateConversationMessageStreamServer, userID bson.ObjectID, projectID string, conversationId string, modelSlug string, messages OpenAIChatHistory, llmProvider *models.LLMProviderConfig, customModel *models.CustomModel) (OpenAIChatHistory, AppChatHistory, UsageCost, error) {
+//   - If the user or the project has exceeded its weekly usage quota, it returns a QuotaExceeded error before starting.
+//   - If ctx is cancelled, or the turn exceeds its time, cost or tool call budget, it stops, sends an
+//     IncompleteIndicator with the reason, and returns the chat histories produced so far without error.
+func (a *AIClientV2) ChatCompletionStreamV2(ctx context.Context, callbackStream handler.StreamSenderV2, userID bson.ObjectID, projectID string, conversationId string, modelSlug string, messages OpenAIChatHistory, llmProvider *models.LLMProviderConfig, customModel *models.CustomModel) (OpenAIChatHistory, AppChatHistory, UsageCost, error) {
+	return a.chatCompletionStreamV2(ctx, a.toolCallHandler, callbackStream, userID, projectID, conversationId, modelSlug, messages, llmProvider, customModel)
+}
+
+// chatCompletionStreamV2 is ChatCompletionStreamV2 with the tools of toolCallHandler.
+func (a *AIClientV2) chatCompletionStreamV2(ctx context.Context, toolCallHandler *handler.ToolCallHandlerV2, callbackStream handler.StreamSenderV2, userID bson.ObjectID, projectID string, conversationId string, modelSlug string, messages OpenAIChatHistory, llmProvider *models.LLMProviderConfig, customModel *models.CustomModel) (OpenAIChatHistory, AppChatHistory, UsageCost, error) {
 	openaiChatHistory := messages
 	inappChatHistory := AppChatHistory{}
 	usage := UsageCost{}
 	success := false // Track whether the request completed successfully
 
+	// Usage quotas only apply to the shared API key, like the usage tracking below
+	if !userID.IsZero() && !llmProvider.IsCustomModel {
+		if err := a.usageService.CheckQuota(ctx, userID, projectID); err != nil {
+			return nil, nil, usage, err
+		}
+	}
+
 	streamHandler := handler.NewStreamHandlerV2(callbackStream, conversationId, modelSlug)
 
 	streamHandler.SendInitialization()
@@ -84,19 +137,40 @@ func (a *AIClientV2) ChatCompletionStreamV2(ctx context.Context, callbackStream
 			// Use a detached context since the request context may be canceled
 			trackCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			if err := a.usageService.TrackUsage(trackCtx, userID, projectID, usage.Cost, success); err != nil {
+			record := services.UsageRecord{
+				ModelSlug:        modelSlug,
+				Cost:             usage.Cost,
+				PromptTokens:     usage.PromptTokens,
+				CompletionTokens: usage.CompletionTokens,
+				Success:          success,
+			}
+			if err := a.usageService.TrackUsage(trackCtx, userID, projectID, record); err != nil {
 				a.logger.Error("Error while tracking usage", "error", err)
 			}
 		}
 	}()
 
 	oaiClient := a.GetOpenAIClient(llmProvider)
-	params := getDefaultParamsV2(modelSlug, a.toolCallHandler.Registry, customModel)
+	params := getDefaultParamsV2(modelSlug, toolCallHandler.Registry, customModel)
+	if len(params.Tools) == 0 {
+		// Providers reject parallel_tool_calls in requests without tools
+		params.Tools = nil
+		params.ParallelToolCalls = param.Opt[bool]{}
+	}
+
+	turnCtx, cancelTurn := context.WithTimeout(ctx, a.cfg.TurnTimeout)
+	defer cancelTurn()
+	toolCallCount := 0
 
 	for {
+		if reason := turnStopReason(ctx, turnCtx); reason != "" {
+			// Stopped between tool calls and the next request
+			streamHandler.SendIncompleteIndicator(reason, "")
+			break
+		}
 		params.Messages = openaiChatHistory
 		// var openaiOutput OpenAIChatHistory
-		stream := oaiClient.Chat.Completions.NewStreaming(context.Background(), params)
+		stream := oaiClient.Chat.Completions.NewStreaming(turnCtx, params)
 
 		reasoning_content := ""
 		answer_content := ""
@@ -122,6 +196,8 @@ func (a *AIClientV2) ChatCompletionStreamV2(ctx context.Context, callbackStream
 
 			// Capture cost from any chunk that has usage data (OpenRouter sends usage in a separate chunk after FinishReason)
 			if chunk.Usage.PromptTokens > 0 || chunk.Usage.CompletionTokens > 0 {
+				usage.PromptTokens += chunk.Usage.PromptTokens
+				usage.CompletionTokens += chunk.Usage.CompletionTokens
 				if costField, ok := chunk.Usage.JSON.ExtraFields["cost"]; ok {
 					if cost, err := strconv.ParseFloat(costField.Raw(), 64); err == nil {
 						usage.Cost += cost
@@ -220,15 +296,37 @@ func (a *AIClientV2) ChatCompletionStreamV2(ctx context.Context, callbackStream
 		}
 
 		if err := stream.Err(); err != nil {
-			return nil, nil, usage, err
+			reason := turnStopReason(ctx, turnCtx)
+			if reason == "" {
+				return nil, nil, usage, err
+			}
+			// Keep the partial answer. Unfinished tool calls are discarded.
+			if answer_content != "" {
+				appendAssistantTextResponseV2(&openaiChatHistory, &inappChatHistory, answer_content, answer_content_id, modelSlug)
+			}
+			streamHandler.SendIncompleteIndicator(reason, answer_content_id)
+			break
 		}
 
 		if answer_content != "" {
 			appendAssistantTextResponseV2(&openaiChatHistory, &inappChatHistory, answer_content, answer_content_id, modelSlug)
 		}
 
+		// The requested tool calls are not executed if they exceed the budget of the turn
+		if len(toolCalls) > 0 && toolCallCount+len(toolCalls) > a.cfg.MaxToolCallsPerTurn {
+			streamHandler.SendIncompleteIndicator(IncompleteReasonMaxToolCalls, answer_content_id)
+			break
+		}
+		// Neither are they if the turn used up its cost budget, which only applies to the shared API key. It is checked
+		// before the calls, so that the turn does not end with tool results the model never answered.
+		if len(toolCalls) > 0 && usesSharedAPIKey(llmProvider) && usage.Cost >= a.cfg.TurnMaxCost {
+			streamHandler.SendIncompleteIndicator(IncompleteReasonTurnMaxCost, answer_content_id)
+			break
+		}
+		toolCallCount += len(toolCalls)
+
 		// Execute the calls (if any), return incremental data
-		openaiToolHistory, inappToolHistory, err := a.toolCallHandler.HandleToolCallsV2(ctx, toolCalls, streamHandler)
+		openaiToolHistory, inappToolHistory, err := toolCallHandler.HandleToolCallsV2(turnCtx, toolCalls, streamHandler)
 		if err != nil {
 			return nil, nil, usage, err
 		}
@@ -30,7 +30,9 @@ func setupTestClient(t *testing.T) (*client.AIClientV2, *services.ProjectService
 		dbInstance,
 		&services.ReverseCommentService{},
 		projectService,
+		services.NewProposedEditService(dbInstance, cfg.GetCfg(), logger.GetLogger()),
 		usageService,
+		nil,
 		cfg.GetCfg(),
 		logger.GetLogger(),
 	)
@@ -23,6 +23,7 @@ func TestGetConversationTitle_Case1(t *testing.T) {
 		dbInstance,
 		&services.ReverseCommentService{},
 		&services.ProjectService{},
+		nil,
 		cfg.GetCfg(),
 		logger.GetLogger(),
 	)
@@ -75,6 +76,7 @@ func TestGetConversationTitle_Case2(t *testing.T) {
 		dbInstance,
 		&services.ReverseCommentService{},
 		&services.ProjectService{},
+		nil,
 		cfg.GetCfg(),
 		logger.GetLogger(),
 	)
@@ -150,6 +152,7 @@ func TestGetConversationTitle_Case3(t *testing.T) {
 		dbInstance,
 		&services.ReverseCommentService{},
 		&services.ProjectService{},
+		nil,
 		cfg.GetCfg(),
 		logger.GetLogger(),
 	)
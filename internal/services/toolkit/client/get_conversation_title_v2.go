@@ -31,7 +31,7 @@ func (a *AIClientV2) GetConversationTitleV2(ctx context.Context, userID bson.Obj
 	message = fmt.Sprintf("%s\nBased on above conversation, generate a short, clear, and descriptive title that summarizes the main topic or purpose of the discussion. The title should be concise, specific, and use natural language. Avoid vague or generic titles. Use abbreviation and short words if possible. Use 3-5 words if possible. Give me the title only, no other text including any other words.", message)
 
 	// Default model if user is not using their own
-	modelToUse := "gpt-5-nano"
+	modelToUse := UtilityModelSlugV2
 	if llmProvider.IsCustomModel {
 		modelToUse = modelSlug
 	}
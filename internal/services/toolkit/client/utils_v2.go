@@ -132,41 +132,24 @@ func CheckOpenAIWorksV2(oaiClient openaiv3.Client, baseUrl string, model string,
 func initializeToolkitV2(
 	db *db.DB,
 	projectService *services.ProjectService,
+	proposedEditService *services.ProposedEditService,
 	cfg *cfg.Cfg,
 	logger *logger.Logger,
 ) *registry.ToolRegistryV2 {
-	toolRegistry := registry.NewToolRegistryV2()
+	toolRegistry := registry.NewToolRegistryV2(cfg.ToolCallTimeout)
 
-	// Register static file tools (create/delete don't need ProjectService - they're placeholder only)
+	// Edits are proposed to the user as diffs rather than applied, see propose_edit below.
+	// The placeholder create/delete file and folder tools are not registered.
 	// toolRegistry.Register("create_file", filetools.CreateFileToolDescriptionV2, filetools.CreateFileTool)
 	// toolRegistry.Register("delete_file", filetools.DeleteFileToolDescriptionV2, filetools.DeleteFileTool)
 	// toolRegistry.Register("create_folder", filetools.CreateFolderToolDescriptionV2, filetools.CreateFolderTool)
 	// toolRegistry.Register("delete_folder", filetools.DeleteFolderToolDescriptionV2, filetools.DeleteFolderTool)
 
-	// Register file tools with ProjectService injection
-	readFileTool := filetools.NewReadFileTool(projectService)
-	toolRegistry.Register("read_file", filetools.ReadFileToolDescriptionV2, readFileTool.Call)
-
-	listFolderTool := filetools.NewListFolderTool(projectService)
-	toolRegistry.Register("list_folder", filetools.ListFolderToolDescriptionV2, listFolderTool.Call)
-
-	searchStringFromTheFileTool := filetools.NewSearchStringTool(projectService)
-	toolRegistry.Register("searchStringFromTheFile", filetools.SearchStringToolDescriptionV2, searchStringFromTheFileTool.Call)
-
-	searchFileTool := filetools.NewSearchFileTool(projectService)
-	toolRegistry.Register("search_file", filetools.SearchFileToolDescriptionV2, searchFileTool.Call)
+	registerReadOnlyToolsV2(toolRegistry, projectService)
 
-	// Register LaTeX tools with ProjectService injection
-	documentStructureTool := latextools.NewDocumentStructureTool(projectService)
-	toolRegistry.Register("get_document_structure", latextools.GetDocumentStructureToolDescriptionV2, documentStructureTool.Call)
-
-	toolRegistry.Register("locate_section", latextools.LocateSectionToolDescriptionV2, latextools.LocateSectionTool)
-
-	readSectionSourceTool := latextools.NewReadSectionSourceTool(projectService)
-	toolRegistry.Register("read_section_source", latextools.ReadSectionSourceToolDescriptionV2, readSectionSourceTool.Call)
-
-	readSourceLineRangeTool := latextools.NewReadSourceLineRangeTool(projectService)
-	toolRegistry.Register("read_source_line_range", latextools.ReadSourceLineRangeToolDescriptionV2, readSourceLineRangeTool.Call)
+	// Proposing an edit records it, so it is not run concurrently with other tools
+	proposeEditTool := filetools.NewProposeEditTool(projectService, proposedEditService)
+	toolRegistry.Register("propose_edit", filetools.ProposeEditToolDescriptionV2, proposeEditTool.Call)
 
 	// Load tools dynamically from backend
 	xtraMCPLoader := xtramcp.NewXtraMCPLoaderV2(db, projectService, cfg.XtraMCPURI)
@@ -187,3 +170,47 @@ func initializeToolkitV2(
 
 	return toolRegistry
 }
+
+// initializeReadOnlyToolkitV2 returns a registry of the tools that only read the project, for the completions that
+// must not propose edits or call paid tools.
+func initializeReadOnlyToolkitV2(projectService *services.ProjectService, cfg *cfg.Cfg) *registry.ToolRegistryV2 {
+	toolRegistry := registry.NewToolRegistryV2(cfg.ToolCallTimeout)
+	registerReadOnlyToolsV2(toolRegistry, projectService)
+	return toolRegistry
+}
+
+// registerReadOnlyToolsV2 registers the file and LaTeX tools, which only read the project.
+func registerReadOnlyToolsV2(toolRegistry *registry.ToolRegistryV2, projectService *services.ProjectService) {
+	// Register file tools with ProjectService injection
+	// The file and LaTeX tools only read the project, so the model can call them in parallel
+	readFileTool := filetools.NewReadFileTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("read_file", filetools.ReadFileToolDescriptionV2, readFileTool.Call)
+
+	listFolderTool := filetools.NewListFolderTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("list_folder", filetools.ListFolderToolDescriptionV2, listFolderTool.Call)
+
+	searchStringFromTheFileTool := filetools.NewSearchStringTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("searchStringFromTheFile", filetools.SearchStringToolDescriptionV2, searchStringFromTheFileTool.Call)
+
+	searchFileTool := filetools.NewSearchFileTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("search_file", filetools.SearchFileToolDescriptionV2, searchFileTool.Call)
+
+	// Register LaTeX tools with ProjectService injection
+	documentStructureTool := latextools.NewDocumentStructureTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("get_document_structure", latextools.GetDocumentStructureToolDescriptionV2, documentStructureTool.Call)
+
+	locateSectionTool := latextools.NewLocateSectionTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("locate_section", latextools.LocateSectionToolDescriptionV2, locateSectionTool.Call)
+
+	readSectionSourceTool := latextools.NewReadSectionSourceTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("read_section_source", latextools.ReadSectionSourceToolDescriptionV2, readSectionSourceTool.Call)
+
+	readSourceLineRangeTool := latextools.NewReadSourceLineRangeTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("read_source_line_range", latextools.ReadSourceLineRangeToolDescriptionV2, readSourceLineRangeTool.Call)
+
+	readPlainTextTool := latextools.NewReadPlainTextTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("read_plain_text", latextools.ReadPlainTextToolDescriptionV2, readPlainTextTool.Call)
+
+	latexLintTool := latextools.NewLatexLintTool(projectService)
+	toolRegistry.RegisterConcurrencySafe("latex_lint", latextools.LatexLintToolDescriptionV2, latexLintTool.Call)
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"testing"
+
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/services"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitializeReadOnlyToolkitV2(t *testing.T) {
+	toolRegistry := initializeReadOnlyToolkitV2(&services.ProjectService{}, &cfg.Cfg{})
+
+	var names []string
+	for _, tool := range toolRegistry.GetTools() {
+		names = append(names, tool.OfFunction.Function.Name)
+	}
+	assert.Contains(t, names, "read_file")
+	assert.Contains(t, names, "read_section_source")
+	assert.NotContains(t, names, "propose_edit")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/models"
 	"paperdebugger/internal/services/toolkit"
@@ -14,6 +15,9 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+// ErrCoolDown is wrapped by the errors of CheckCoolDown when its throttle rejects the call.
+var ErrCoolDown = errors.New("cool down")
+
 type ToolCallRecordDB struct {
 	collection *mongo.Collection
 }
@@ -100,11 +104,11 @@ func (r *ToolCallRecordDB) CheckCoolDown(ctx context.Context, toolName string, u
 
 	if coolDownPeriod {
 		if record.FunctionStatus == models.FunctionCallStatusSuccess {
-			return errors.New("last function call is successful, please wait for " + coolDownTime.String() + " to call this function again")
+			return fmt.Errorf("%w: last function call is successful, please wait for %s to call this function again", ErrCoolDown, coolDownTime)
 		} else if record.FunctionStatus == models.FunctionCallStatusPending {
-			return errors.New("last function call is pending, please wait for " + coolDownTime.String() + " to call this function again")
+			return fmt.Errorf("%w: last function call is pending, please wait for %s to call this function again", ErrCoolDown, coolDownTime)
 		} else if record.FunctionStatus == models.FunctionCallStatusError {
-			return errors.New("last function call is error, please wait for " + coolDownTime.String() + " to call this function again")
+			return fmt.Errorf("%w: last function call is error, please wait for %s to call this function again", ErrCoolDown, coolDownTime)
 		} else if record.FunctionStatus == models.FunctionCallStatusTimeout {
 			return nil // by pass the cool down time here, because the function is timeout, so we can call it again
 		}
@@ -126,25 +130,63 @@ func (r *ToolCallRecordDB) OnTimeout(ctx context.Context, record *models.Functio
 	return nil
 }
 
+// RecordTimeout marks the pending records of the tool call as timed out. Tools that do not keep records
+// get a new record with the timeout status.
+func (r *ToolCallRecordDB) RecordTimeout(ctx context.Context, toolCallId string, functionName string, functionArgs string) error {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"tool_call_id": toolCallId, "function_status": models.FunctionCallStatusPending},
+		bson.M{"$set": bson.M{"function_status": models.FunctionCallStatusTimeout, "updated_at": bson.NewDateTimeFromTime(time.Now())}},
+	)
+	if err != nil {
+		return errors.New("failed to update function call record: " + err.Error())
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	// The tool may have finished its record after the timeout
+	count, err := r.collection.CountDocuments(ctx, bson.M{"tool_call_id": toolCallId})
+	if err != nil {
+		return errors.New("failed to count function call records: " + err.Error())
+	}
+	if count > 0 {
+		return nil
+	}
+
+	functionParams := map[string]any{}
+	_ = json.Unmarshal([]byte(functionArgs), &functionParams)
+	record, err := r.Create(ctx, toolCallId, functionName, functionParams)
+	if err != nil {
+		return err
+	}
+	return r.OnTimeout(ctx, record)
+}
+
+// OnError marks the record as failed, unless it is no longer pending, see OnSuccess.
 func (r *ToolCallRecordDB) OnError(ctx context.Context, record *models.FunctionCall, err error) error {
 	record.FunctionError = err.Error()
 	record.FunctionStatus = models.FunctionCallStatusError
 	// record.FunctionResult = "" // do not reset the result here, because the result may be saved during the function call
 	record.UpdatedAt = bson.NewDateTimeFromTime(time.Now())
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": record.ID}, bson.M{"$set": record})
+	_, err = r.collection.UpdateOne(ctx, pendingRecordFilter(record), bson.M{"$set": record})
 	if err != nil {
 		return errors.New("failed to update function call record: " + err.Error())
 	}
 	return nil
 }
 
+// OnSuccess records the result, unless the record is no longer pending: a tool that outlived its timeout
+// does not overwrite the timeout status recorded by RecordTimeout.
 func (r *ToolCallRecordDB) OnSuccess(ctx context.Context, record *models.FunctionCall, result string) error {
 	record.FunctionResult = result
 	record.FunctionStatus = models.FunctionCallStatusSuccess
 	record.UpdatedAt = bson.NewDateTimeFromTime(time.Now())
-	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": record.ID}, bson.M{"$set": record})
+	_, err := r.collection.UpdateOne(ctx, pendingRecordFilter(record), bson.M{"$set": record})
 	if err != nil {
 		return errors.New("failed to update function call record: " + err.Error())
 	}
 	return nil
 }
+
+func pendingRecordFilter(record *models.FunctionCall) bson.M {
+	return bson.M{"_id": record.ID, "function_status": models.FunctionCallStatusPending}
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// streamBufferRetention is how long the events of a finished generation are kept for reconnecting clients.
+const streamBufferRetention = time.Minute
+
+// maxStreamBufferEvents bounds the events kept by a generation. When it is reached, the oldest half is dropped:
+// clients reconnecting after them miss the start of the response, which is written to the conversation anyway.
+const maxStreamBufferEvents = 8192
+
+var ErrGenerationInProgress = errors.New("a response is already being generated for this conversation")
+
+// StreamSenderV2 is the part of the gRPC stream used to send the events of a generation.
+type StreamSenderV2 interface {
+	Send(*chatv2.CreateConversationMessageStreamResponse) error
+}
+
+// StreamBufferV2 records the events of an in-flight generation, so that it can outlive the client stream
+// that started it, and clients can reconnect and replay the events they missed.
+type StreamBufferV2 struct {
+	UserID bson.ObjectID
+
+	mu      sync.Mutex
+	events  []*chatv2.CreateConversationMessageStreamResponse
+	dropped int64         // The number of events dropped from the start of events
+	notify  chan struct{} // Closed and replaced when an event is added
+	done    chan struct{} // Closed when the generation is finished
+	cancel  context.CancelFunc
+}
+
+// Send assigns the next sequence number to the event and records it.
+func (b *StreamBufferV2) Send(event *chatv2.CreateConversationMessageStreamResponse) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	event.Sequence = b.dropped + int64(len(b.events)) + 1
+	b.events = append(b.events, event)
+	if len(b.events) >= maxStreamBufferEvents {
+		drop := len(b.events) / 2
+		b.events = slices.Clone(b.events[drop:])
+		b.dropped += int64(drop)
+	}
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return nil
+}
+
+// Cancel stops the generation. The events produced so far are kept.
+func (b *StreamBufferV2) Cancel() {
+	b.cancel()
+}
+
+// Done is closed when the generation is finished, and its result is written to the database.
+func (b *StreamBufferV2) Done() <-chan struct{} {
+	return b.done
+}
+
+// Follow sends the events from fromSequence to the stream until the generation is finished. If they were dropped,
+// it starts from the oldest event kept.
+// It returns early without error if the client disconnects, the generation keeps running.
+func (b *StreamBufferV2) Follow(ctx context.Context, stream StreamSenderV2, fromSequence int64) error {
+	next := max(fromSequence, 1)
+	for {
+		b.mu.Lock()
+		next = max(next, b.dropped+1)
+		var events []*chatv2.CreateConversationMessageStreamResponse
+		if index := next - b.dropped - 1; index < int64(len(b.events)) {
+			events = b.events[index:]
+		}
+		notify := b.notify
+		finished := b.isFinished()
+		b.mu.Unlock()
+
+		for _, event := range events {
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+			next++
+		}
+		if finished {
+			return nil
+		}
+		if len(events) > 0 {
+			continue
+		}
+
+		select {
+		case <-notify:
+		case <-b.done:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (b *StreamBufferV2) isFinished() bool {
+	select {
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// StreamRegistryV2 keeps track of the in-flight generations by conversation.
+// Generations live in the memory of the instance running them, clients must reconnect to the same instance.
+type StreamRegistryV2 struct {
+	mu      sync.Mutex
+	buffers map[string]*StreamBufferV2
+}
+
+func NewStreamRegistryV2() *StreamRegistryV2 {
+	return &StreamRegistryV2{
+		buffers: map[string]*StreamBufferV2{},
+	}
+}
+
+// Start registers a new generation for the conversation. It fails if a generation is already in progress.
+func (r *StreamRegistryV2) Start(conversationID string, userID bson.ObjectID, cancel context.CancelFunc) (*StreamBufferV2, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if buffer, ok := r.buffers[conversationID]; ok && !buffer.isFinished() {
+		return nil, ErrGenerationInProgress
+	}
+
+	buffer := &StreamBufferV2{
+		UserID: userID,
+		notify: make(chan struct{}),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	r.buffers[conversationID] = buffer
+	return buffer, nil
+}
+
+// Finish marks the generation as finished. Its events are kept for a while for reconnecting clients.
+func (r *StreamRegistryV2) Finish(conversationID string, buffer *StreamBufferV2) {
+	buffer.mu.Lock()
+	close(buffer.done)
+	buffer.mu.Unlock()
+
+	time.AfterFunc(streamBufferRetention, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.buffers[conversationID] == buffer {
+			delete(r.buffers, conversationID)
+		}
+	})
+}
+
+// Release removes a generation that was started but never ran, such as when the message could not be written.
+func (r *StreamRegistryV2) Release(conversationID string, buffer *StreamBufferV2) {
+	buffer.mu.Lock()
+	close(buffer.done)
+	buffer.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.buffers[conversationID] == buffer {
+		delete(r.buffers, conversationID)
+	}
+}
+
+// Get returns the current or last generation of the conversation, or nil if there is none.
+func (r *StreamRegistryV2) Get(conversationID string) *StreamBufferV2 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buffers[conversationID]
+}
+
+// IsGenerating reports whether a generation is in progress for the conversation.
+func (r *StreamRegistryV2) IsGenerating(conversationID string) bool {
+	buffer := r.Get(conversationID)
+	return buffer != nil && !buffer.isFinished()
+}
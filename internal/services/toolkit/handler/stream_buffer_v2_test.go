@@ -0,0 +1,136 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"paperdebugger/internal/services/toolkit/handler"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type recordingStream struct {
+	mu     sync.Mutex
+	events []*chatv2.CreateConversationMessageStreamResponse
+}
+
+func (s *recordingStream) Send(event *chatv2.CreateConversationMessageStreamResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingStream) deltas() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deltas := []string{}
+	for _, event := range s.events {
+		deltas = append(deltas, event.GetMessageChunk().GetDelta())
+	}
+	return deltas
+}
+
+func chunk(delta string) *chatv2.CreateConversationMessageStreamResponse {
+	return &chatv2.CreateConversationMessageStreamResponse{
+		ResponsePayload: &chatv2.CreateConversationMessageStreamResponse_MessageChunk{
+			MessageChunk: &chatv2.MessageChunk{Delta: delta},
+		},
+	}
+}
+
+func TestStreamBufferV2_FollowAndResume(t *testing.T) {
+	registry := handler.NewStreamRegistryV2()
+	buffer, err := registry.Start("conv", bson.NewObjectID(), func() {})
+	assert.NoError(t, err)
+
+	_, err = registry.Start("conv", bson.NewObjectID(), func() {})
+	assert.ErrorIs(t, err, handler.ErrGenerationInProgress)
+	assert.True(t, registry.IsGenerating("conv"))
+
+	buffer.Send(chunk("a"))
+	buffer.Send(chunk("b"))
+
+	// A client following from the start receives the buffered and the new events.
+	live := &recordingStream{}
+	followDone := make(chan error)
+	go func() { followDone <- buffer.Follow(context.Background(), live, 0) }()
+
+	buffer.Send(chunk("c"))
+	registry.Finish("conv", buffer)
+
+	select {
+	case err := <-followDone:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Follow did not return after the generation finished")
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, live.deltas())
+	assert.Equal(t, int64(3), live.events[2].GetSequence())
+
+	// A reconnecting client replays the events it missed.
+	resumed := &recordingStream{}
+	assert.NoError(t, registry.Get("conv").Follow(context.Background(), resumed, 2))
+	assert.Equal(t, []string{"b", "c"}, resumed.deltas())
+
+	assert.False(t, registry.IsGenerating("conv"))
+	_, err = registry.Start("conv", bson.NewObjectID(), func() {})
+	assert.NoError(t, err)
+}
+
+func TestStreamBufferV2_ClientDisconnect(t *testing.T) {
+	registry := handler.NewStreamRegistryV2()
+	cancelled := false
+	buffer, err := registry.Start("conv", bson.NewObjectID(), func() { cancelled = true })
+	assert.NoError(t, err)
+
+	// The client going away does not cancel the generation.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, buffer.Follow(ctx, &recordingStream{}, 0))
+	assert.False(t, cancelled)
+
+	buffer.Cancel()
+	assert.True(t, cancelled)
+}
+
+func TestStreamBufferV2_Release(t *testing.T) {
+	registry := handler.NewStreamRegistryV2()
+	buffer, err := registry.Start("conv", bson.NewObjectID(), func() {})
+	assert.NoError(t, err)
+
+	// A released generation can be started again right away.
+	registry.Release("conv", buffer)
+	assert.False(t, registry.IsGenerating("conv"))
+	assert.Nil(t, registry.Get("conv"))
+	_, err = registry.Start("conv", bson.NewObjectID(), func() {})
+	assert.NoError(t, err)
+}
+
+func TestStreamBufferV2_DropsOldEvents(t *testing.T) {
+	registry := handler.NewStreamRegistryV2()
+	buffer, err := registry.Start("conv", bson.NewObjectID(), func() {})
+	assert.NoError(t, err)
+
+	for i := 1; i <= 10000; i++ {
+		buffer.Send(chunk(fmt.Sprint(i)))
+	}
+	registry.Finish("conv", buffer)
+
+	// The latest events are replayed.
+	resumed := &recordingStream{}
+	assert.NoError(t, buffer.Follow(context.Background(), resumed, 9999))
+	assert.Equal(t, []string{"9999", "10000"}, resumed.deltas())
+
+	// The oldest ones were dropped, the replay starts from the oldest event kept.
+	replayed := &recordingStream{}
+	assert.NoError(t, buffer.Follow(context.Background(), replayed, 0))
+	assert.Less(t, len(replayed.events), 8192)
+	assert.Equal(t, "10000", replayed.deltas()[len(replayed.events)-1])
+	assert.Equal(t, int64(10000-len(replayed.events)+1), replayed.events[0].GetSequence())
+}
@@ -8,13 +8,13 @@ import (
 )
 
 type StreamHandlerV2 struct {
-	callbackStream chatv2.ChatService_CreateConversationMessageStreamServer
+	callbackStream StreamSenderV2
 	conversationId string
 	modelSlug      string
 }
 
 func NewStreamHandlerV2(
-	callbackStream chatv2.ChatService_CreateConversationMessageStreamServer,
+	callbackStream StreamSenderV2,
 	conversationId string,
 	modelSlug string,
 ) *StreamHandlerV2 {
@@ -272,3 +272,26 @@ func (h *StreamHandlerV2) SendToolCallEnd(toolCall openai.FinishedChatCompletion
 		},
 	})
 }
+
+// SendMessage sends a complete message that is not streamed, e.g. one added by a tool call.
+func (h *StreamHandlerV2) SendMessage(messageId string, payload *chatv2.MessagePayload) {
+	if h.callbackStream == nil {
+		return
+	}
+	h.callbackStream.Send(&chatv2.CreateConversationMessageStreamResponse{
+		ResponsePayload: &chatv2.CreateConversationMessageStreamResponse_StreamPartBegin{
+			StreamPartBegin: &chatv2.StreamPartBegin{
+				MessageId: messageId,
+				Payload:   payload,
+			},
+		},
+	})
+	h.callbackStream.Send(&chatv2.CreateConversationMessageStreamResponse{
+		ResponsePayload: &chatv2.CreateConversationMessageStreamResponse_StreamPartEnd{
+			StreamPartEnd: &chatv2.StreamPartEnd{
+				MessageId: messageId,
+				Payload:   payload,
+			},
+		},
+	})
+}
@@ -3,10 +3,15 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/services/toolkit"
+	toolCallRecordDB "paperdebugger/internal/services/toolkit/db"
 	"paperdebugger/internal/services/toolkit/registry"
 	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/openai/openai-go/v3"
@@ -15,15 +20,22 @@ import (
 // ToolCallHandler is responsible for handling tool calls by dispatching them to the appropriate tool registry
 // and managing the chat history for both OpenAI and in-app chat systems.
 type ToolCallHandlerV2 struct {
-	Registry *registry.ToolRegistryV2 // Registry containing available tools for function calls
+	Registry         *registry.ToolRegistryV2 // Registry containing available tools for function calls
+	toolCallRecordDB *toolCallRecordDB.ToolCallRecordDB
+	logger           *logger.Logger
 }
 
-func NewToolCallHandlerV2(toolRegistry *registry.ToolRegistryV2) *ToolCallHandlerV2 {
+func NewToolCallHandlerV2(toolRegistry *registry.ToolRegistryV2, toolCallRecordDB *toolCallRecordDB.ToolCallRecordDB, logger *logger.Logger) *ToolCallHandlerV2 {
 	return &ToolCallHandlerV2{
-		Registry: toolRegistry,
+		Registry:         toolRegistry,
+		toolCallRecordDB: toolCallRecordDB,
+		logger:           logger,
 	}
 }
 
+// maxParallelToolCalls is the maximum number of tool calls of a batch running at the same time.
+const maxParallelToolCalls = 4
+
 type OpenAIChatHistory []openai.ChatCompletionMessageParamUnion
 type AppChatHistory []chatv2.Message
 
@@ -68,12 +80,16 @@ func (h *ToolCallHandlerV2) HandleToolCallsV2(ctx context.Context, toolCalls []o
 	})
 
 	// Iterate over each output item to process tool calls
-	for _, toolCall := range toolCalls {
-		if streamHandler != nil {
-			streamHandler.SendToolCallBegin(toolCall)
+	// Consecutive tool calls that are safe to run concurrently are executed together as a batch,
+	// the results are still processed in the order of the calls.
+	results := make([]toolCallResult, len(toolCalls))
+	batchEnd := 0
+	for i, toolCall := range toolCalls {
+		if i == batchEnd {
+			batchEnd = h.nextBatchEnd(toolCalls, i)
+			h.runBatch(ctx, toolCalls[i:batchEnd], results[i:batchEnd], streamHandler)
 		}
-
-		toolResult, err := h.Registry.Call(ctx, toolCall.ID, toolCall.Name, []byte(toolCall.Arguments))
+		toolResult, err := results[i].result, results[i].err
 
 		// Try to parse as XtraMCP ToolResult format
 		// This allows XtraMCP tools to use the new format while other tools continue with existing behavior
@@ -240,8 +256,83 @@ func (h *ToolCallHandlerV2) HandleToolCallsV2(ctx context.Context, toolCalls []o
 			},
 			Timestamp: time.Now().Unix(),
 		})
+
+		// Messages added by the tool are shown after its result
+		for j, payload := range results[i].messages {
+			messageId := fmt.Sprintf("openai_toolCall[%d]_%s_message[%d]", toolCall.Index, toolCall.ID, j)
+			if streamHandler != nil {
+				streamHandler.SendMessage(messageId, payload)
+			}
+			inappChatHistory = append(inappChatHistory, chatv2.Message{
+				MessageId: messageId,
+				Payload:   payload,
+				Timestamp: time.Now().Unix(),
+			})
+		}
 	}
 
 	// Return both chat histories and nil error (no error aggregation in this implementation)
 	return openaiChatHistory, inappChatHistory, nil
 }
+
+type toolCallResult struct {
+	result   string
+	err      error
+	messages []*chatv2.MessagePayload // Added by the tool, see toolkit.AddToolMessage
+}
+
+// nextBatchEnd returns the end of the batch of tool calls starting at start. A batch is either a single tool call,
+// or consecutive tool calls that are all safe to run concurrently.
+func (h *ToolCallHandlerV2) nextBatchEnd(toolCalls []openai.FinishedChatCompletionToolCall, start int) int {
+	end := start + 1
+	if !h.Registry.IsConcurrencySafe(toolCalls[start].Name) {
+		return end
+	}
+	for end < len(toolCalls) && h.Registry.IsConcurrencySafe(toolCalls[end].Name) {
+		end++
+	}
+	return end
+}
+
+// runBatch sends the begin events of the batch in order, then runs its tool calls with at most
+// maxParallelToolCalls at a time. The results are written to results in the order of the batch.
+func (h *ToolCallHandlerV2) runBatch(ctx context.Context, batch []openai.FinishedChatCompletionToolCall, results []toolCallResult, streamHandler *StreamHandlerV2) {
+	if streamHandler != nil {
+		for _, toolCall := range batch {
+			streamHandler.SendToolCallBegin(toolCall)
+		}
+	}
+
+	if len(batch) == 1 {
+		results[0] = h.callTool(ctx, batch[0])
+		return
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxParallelToolCalls)
+	for i, toolCall := range batch {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i] = h.callTool(ctx, toolCall)
+		}()
+	}
+	wg.Wait()
+}
+
+func (h *ToolCallHandlerV2) callTool(ctx context.Context, toolCall openai.FinishedChatCompletionToolCall) toolCallResult {
+	ctx, messages := toolkit.WithToolMessages(ctx)
+	toolResult, err := h.Registry.Call(ctx, toolCall.ID, toolCall.Name, []byte(toolCall.Arguments))
+	if errors.Is(err, registry.ErrToolTimeout) {
+		if recordErr := h.toolCallRecordDB.RecordTimeout(ctx, toolCall.ID, toolCall.Name, toolCall.Arguments); recordErr != nil {
+			h.logger.Error("Failed to record tool call timeout", "error", recordErr, "tool", toolCall.Name)
+		}
+	}
+	result := toolCallResult{result: toolResult, err: err}
+	if err == nil {
+		result.messages = messages.Payloads()
+	}
+	return result
+}
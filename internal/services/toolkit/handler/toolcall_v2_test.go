@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"paperdebugger/internal/services/toolkit"
+	"paperdebugger/internal/services/toolkit/handler"
+	"paperdebugger/internal/services/toolkit/registry"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleToolCallsV2_Parallel(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	slow := func(result string) func(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+		return func(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return result, "", nil
+		}
+	}
+
+	r := registry.NewToolRegistryV2(0)
+	r.RegisterConcurrencySafe("read_a", openai.ChatCompletionToolUnionParam{}, slow("a"))
+	r.RegisterConcurrencySafe("read_b", openai.ChatCompletionToolUnionParam{}, slow("b"))
+	r.Register("write", openai.ChatCompletionToolUnionParam{}, slow("w"))
+	assert.True(t, r.IsConcurrencySafe("read_a"))
+	assert.False(t, r.IsConcurrencySafe("write"))
+
+	h := handler.NewToolCallHandlerV2(r, nil, nil)
+	toolCalls := []openai.FinishedChatCompletionToolCall{
+		{ID: "call_1", ChatCompletionMessageFunctionToolCallFunction: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "read_a"}},
+		{ID: "call_2", ChatCompletionMessageFunctionToolCallFunction: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "read_b"}},
+		{ID: "call_3", ChatCompletionMessageFunctionToolCallFunction: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "read_a"}},
+		{ID: "call_4", ChatCompletionMessageFunctionToolCallFunction: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "write"}},
+	}
+
+	openaiChatHistory, inappChatHistory, err := h.HandleToolCallsV2(context.Background(), toolCalls, nil)
+	assert.NoError(t, err)
+	assert.Greater(t, maxRunning.Load(), int32(1))
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+
+	// The results are in the order of the tool calls, whatever order they finished in.
+	assert.Len(t, openaiChatHistory, 5)
+	expected := []string{"a", "b", "a", "w"}
+	for i, result := range expected {
+		tool := openaiChatHistory[i+1].OfTool
+		assert.Equal(t, toolCalls[i].ID, tool.ToolCallID)
+		assert.Equal(t, result, tool.Content.OfArrayOfContentParts[0].Text)
+	}
+	assert.Len(t, inappChatHistory, len(toolCalls))
+}
+
+func TestHandleToolCallsV2_ToolMessages(t *testing.T) {
+	r := registry.NewToolRegistryV2(0)
+	r.Register("propose", openai.ChatCompletionToolUnionParam{}, func(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+		assert.True(t, toolkit.AddToolMessage(ctx, &chatv2.MessagePayload{
+			MessageType: &chatv2.MessagePayload_ProposedEdit{
+				ProposedEdit: &chatv2.MessageTypeProposedEdit{EditId: "edit_1"},
+			},
+		}))
+		return "proposed", "", nil
+	})
+
+	h := handler.NewToolCallHandlerV2(r, nil, nil)
+	toolCalls := []openai.FinishedChatCompletionToolCall{
+		{ID: "call_1", ChatCompletionMessageFunctionToolCallFunction: openai.ChatCompletionMessageFunctionToolCallFunction{Name: "propose"}},
+	}
+
+	openaiChatHistory, inappChatHistory, err := h.HandleToolCallsV2(context.Background(), toolCalls, nil)
+	assert.NoError(t, err)
+	assert.Len(t, openaiChatHistory, 2)
+
+	// The message added by the tool follows its result, and is not sent to the model
+	assert.Len(t, inappChatHistory, 2)
+	assert.NotNil(t, inappChatHistory[0].Payload.GetToolCall())
+	assert.Equal(t, "openai_toolCall[0]_call_1_message[0]", inappChatHistory[1].MessageId)
+	assert.Equal(t, "edit_1", inappChatHistory[1].Payload.GetProposedEdit().GetEditId())
+
+	assert.False(t, toolkit.AddToolMessage(context.Background(), &chatv2.MessagePayload{}))
+}
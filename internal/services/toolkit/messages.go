@@ -0,0 +1,43 @@
+package toolkit
+
+import (
+	"context"
+	"sync"
+
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+)
+
+type toolMessagesKey struct{}
+
+// ToolMessages are the in-app messages added by a tool call, shown after its result. They let a tool show the user
+// more than its result, e.g. an edit the user can accept or reject.
+type ToolMessages struct {
+	mu       sync.Mutex
+	payloads []*chatv2.MessagePayload
+}
+
+// WithToolMessages returns a context collecting the messages added by the tool call it is passed to.
+func WithToolMessages(ctx context.Context) (context.Context, *ToolMessages) {
+	messages := &ToolMessages{}
+	return context.WithValue(ctx, toolMessagesKey{}, messages), messages
+}
+
+// AddToolMessage adds a message to the conversation after the result of the tool call. It returns false if the
+// messages of the tool call are not collected.
+func AddToolMessage(ctx context.Context, payload *chatv2.MessagePayload) bool {
+	messages, ok := ctx.Value(toolMessagesKey{}).(*ToolMessages)
+	if !ok {
+		return false
+	}
+	messages.mu.Lock()
+	defer messages.mu.Unlock()
+	messages.payloads = append(messages.payloads, payload)
+	return true
+}
+
+// Payloads returns the messages added so far, in order.
+func (m *ToolMessages) Payloads() []*chatv2.MessagePayload {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*chatv2.MessagePayload(nil), m.payloads...)
+}
@@ -3,28 +3,50 @@ package registry
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"paperdebugger/internal/services/toolkit"
+	"time"
 
 	"github.com/openai/openai-go/v3"
 	"github.com/samber/lo"
 )
 
+// ErrToolTimeout is returned by Call when a tool does not finish within the timeout of the registry.
+var ErrToolTimeout = errors.New("tool call timed out")
+
 type ToolRegistryV2 struct {
 	tools       map[string]toolkit.ToolHandler
 	description map[string]openai.ChatCompletionToolUnionParam
+	timeout     time.Duration // 0 means no timeout
+	concurrent  map[string]bool
 }
 
-func NewToolRegistryV2() *ToolRegistryV2 {
+func NewToolRegistryV2(timeout time.Duration) *ToolRegistryV2 {
 	return &ToolRegistryV2{
 		tools:       make(map[string]toolkit.ToolHandler),
 		description: make(map[string]openai.ChatCompletionToolUnionParam),
+		timeout:     timeout,
+		concurrent:  make(map[string]bool),
 	}
 }
 
 func (r *ToolRegistryV2) Register(name string, description openai.ChatCompletionToolUnionParam, handler toolkit.ToolHandler) {
 	r.tools[name] = handler
 	r.description[name] = description
+	delete(r.concurrent, name)
+}
+
+// RegisterConcurrencySafe registers a tool that can run concurrently with other such tools,
+// typically because it only reads the project.
+func (r *ToolRegistryV2) RegisterConcurrencySafe(name string, description openai.ChatCompletionToolUnionParam, handler toolkit.ToolHandler) {
+	r.Register(name, description, handler)
+	r.concurrent[name] = true
+}
+
+// IsConcurrencySafe reports whether the tool was registered with RegisterConcurrencySafe.
+func (r *ToolRegistryV2) IsConcurrencySafe(name string) bool {
+	return r.concurrent[name]
 }
 
 func (r *ToolRegistryV2) Call(ctx context.Context, toolCallId string, toolCallName string, toolCallArgs json.RawMessage) (result string, err error) {
@@ -32,7 +54,7 @@ func (r *ToolRegistryV2) Call(ctx context.Context, toolCallId string, toolCallNa
 	if !ok {
 		return "", fmt.Errorf("unknown tool: %s", toolCallName)
 	}
-	result, furtherInstruction, err := handler(ctx, toolCallId, toolCallArgs)
+	result, furtherInstruction, err := r.callWithTimeout(ctx, handler, toolCallId, toolCallArgs)
 	if err != nil {
 		return result, err
 	}
@@ -44,6 +66,39 @@ func (r *ToolRegistryV2) Call(ctx context.Context, toolCallId string, toolCallNa
 	}
 }
 
+// callWithTimeout runs the tool, and gives up waiting for it once the timeout is reached.
+// The context of the tool is cancelled, so that tools honoring it stop as well. Tools ignoring it keep running in
+// the background, their records are then left with the timeout status, see ToolCallRecordDB.OnSuccess.
+func (r *ToolRegistryV2) callWithTimeout(ctx context.Context, handler toolkit.ToolHandler, toolCallId string, toolCallArgs json.RawMessage) (string, string, error) {
+	if r.timeout <= 0 {
+		return handler(ctx, toolCallId, toolCallArgs)
+	}
+
+	toolCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	type output struct {
+		result             string
+		furtherInstruction string
+		err                error
+	}
+	done := make(chan output, 1)
+	go func() {
+		result, furtherInstruction, err := handler(toolCtx, toolCallId, toolCallArgs)
+		done <- output{result, furtherInstruction, err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.result, out.furtherInstruction, out.err
+	case <-toolCtx.Done():
+		if ctx.Err() != nil {
+			return "", "", ctx.Err()
+		}
+		return "", "", fmt.Errorf("%w after %s", ErrToolTimeout, r.timeout)
+	}
+}
+
 func (r *ToolRegistryV2) GetTools() []openai.ChatCompletionToolUnionParam {
 	return lo.Values(r.description)
 }
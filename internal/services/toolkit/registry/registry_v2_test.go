@@ -0,0 +1,40 @@
+package registry_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"paperdebugger/internal/services/toolkit/registry"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolRegistryV2_Timeout(t *testing.T) {
+	r := registry.NewToolRegistryV2(50 * time.Millisecond)
+	r.Register("fast", openai.ChatCompletionToolUnionParam{}, func(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+		return "ok", "", nil
+	})
+	r.Register("hung", openai.ChatCompletionToolUnionParam{}, func(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+		<-ctx.Done()
+		return "", "", ctx.Err()
+	})
+
+	result, err := r.Call(context.Background(), "call_1", "fast", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", result)
+
+	_, err = r.Call(context.Background(), "call_2", "hung", nil)
+	assert.ErrorIs(t, err, registry.ErrToolTimeout)
+
+	// A cancelled turn is not reported as a tool timeout.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = r.Call(ctx, "call_3", "hung", nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = r.Call(context.Background(), "call_4", "unknown", nil)
+	assert.Error(t, err)
+}
@@ -155,6 +155,7 @@ func TestChatCompletion_SingleRoundChat_NotCallTool(t *testing.T) {
 		dbInstance,
 		&services.ReverseCommentService{},
 		&services.ProjectService{},
+		nil,
 		cfg.GetCfg(),
 		logger.GetLogger(),
 	)
@@ -224,6 +225,7 @@ func TestChatCompletion_TwoRoundChat_NotCallTool(t *testing.T) {
 		dbInstance,
 		&services.ReverseCommentService{},
 		&services.ProjectService{},
+		nil,
 		cfg.GetCfg(),
 		logger.GetLogger(),
 	)
@@ -322,6 +324,7 @@ func TestChatCompletion_OneRoundChat_CallOneTool_MessageAfterToolCall(t *testing
 		dbInstance,
 		&services.ReverseCommentService{},
 		&services.ProjectService{},
+		nil,
 		cfg.GetCfg(),
 		logger.GetLogger(),
 	)
@@ -400,6 +403,7 @@ func TestChatCompletion_OneRoundChat_CallOneTool_AlwaysException(t *testing.T) {
 		dbInstance,
 		&services.ReverseCommentService{},
 		&services.ProjectService{},
+		nil,
 		cfg.GetCfg(),
 		logger.GetLogger(),
 	)
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	"paperdebugger/internal/services/toolkit"
+	chatv2 "paperdebugger/pkg/gen/api/chat/v2"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+var ProposeEditToolDescriptionV2 = openai.ChatCompletionToolUnionParam{
+	OfFunction: &openai.ChatCompletionFunctionToolParam{
+		Function: openai.FunctionDefinitionParam{
+			Name:        "propose_edit",
+			Description: param.NewOpt("Proposes an edit of a file of the project. The edit is shown to the user as a diff that they can accept or reject, it is not applied to the file by this tool. The edited span is either a line range or an anchor text that occurs exactly once in the file. Read the file first, so that the line numbers and the anchor text match its current content."),
+			Parameters: openai.FunctionParameters{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"path": map[string]any{
+						"type":        "string",
+						"description": "The path of the file to edit.",
+					},
+					"start_line": map[string]any{
+						"type":        "integer",
+						"description": "The first line (1-indexed) to replace. Use with end_line, or use anchor_text instead.",
+					},
+					"end_line": map[string]any{
+						"type":        "integer",
+						"description": "The last line (1-indexed, inclusive) to replace.",
+					},
+					"anchor_text": map[string]any{
+						"type":        "string",
+						"description": "The exact text to replace, copied from the file. It may span several lines, and must occur exactly once in the file.",
+					},
+					"replacement": map[string]any{
+						"type":        "string",
+						"description": "The new text of the span. An empty replacement of a line range deletes the lines.",
+					},
+				},
+				"required": []string{"path", "replacement"},
+			},
+		},
+	},
+}
+
+type ProposeEditArgs struct {
+	Path        string `json:"path"`
+	StartLine   *int   `json:"start_line,omitempty"`
+	EndLine     *int   `json:"end_line,omitempty"`
+	AnchorText  string `json:"anchor_text,omitempty"`
+	Replacement string `json:"replacement"`
+}
+
+type ProposeEditTool struct {
+	projectService      *services.ProjectService
+	proposedEditService *services.ProposedEditService
+}
+
+func NewProposeEditTool(projectService *services.ProjectService, proposedEditService *services.ProposedEditService) *ProposeEditTool {
+	return &ProposeEditTool{
+		projectService:      projectService,
+		proposedEditService: proposedEditService,
+	}
+}
+
+func (t *ProposeEditTool) Call(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+	var getArgs ProposeEditArgs
+
+	if err := json.Unmarshal(args, &getArgs); err != nil {
+		return "", "", err
+	}
+
+	// Get project from context
+	actor, projectId, conversationId := toolkit.GetActorProjectConversationID(ctx)
+	if actor == nil || projectId == "" {
+		return "", "", fmt.Errorf("failed to get actor or project id from context")
+	}
+
+	project, err := t.projectService.GetProject(ctx, actor.ID, projectId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get project: %w", err)
+	}
+
+	doc, docs := findDocByPathSuffix(project, getArgs.Path)
+	if len(docs) > 1 {
+		return fmt.Sprintf("Ambiguous path %s, it matches %s. Give the full path of the file.", getArgs.Path, strings.Join(docs, ", ")), "", nil
+	}
+	if doc == nil {
+		return fmt.Sprintf("File not found: %s", getArgs.Path), "", nil
+	}
+
+	// The span is validated against the current version of the doc, which the edit records
+	var edit *models.ProposedEdit
+	switch {
+	case getArgs.AnchorText != "" && (getArgs.StartLine != nil || getArgs.EndLine != nil):
+		return "Either a line range or an anchor text must be given, not both", "", nil
+	case getArgs.AnchorText != "":
+		edit, err = models.NewAnchorEdit(doc, getArgs.AnchorText, getArgs.Replacement)
+	case getArgs.StartLine != nil && getArgs.EndLine != nil:
+		edit, err = models.NewLineRangeEdit(doc, *getArgs.StartLine, *getArgs.EndLine, getArgs.Replacement)
+	default:
+		return "Either start_line and end_line, or anchor_text is required", "", nil
+	}
+	if err != nil {
+		return fmt.Sprintf("Invalid edit: %v", err), "", nil
+	}
+	if edit.Diff == "" {
+		return "The replacement is identical to the current text, no edit was proposed", "", nil
+	}
+
+	edit.UserID = actor.ID
+	edit.ProjectID = projectId
+	edit.ConversationID = conversationId
+	edit.ToolCallID = toolCallId
+	edit, err = t.proposedEditService.CreateProposedEdit(ctx, edit)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to save proposed edit: %w", err)
+	}
+
+	toolkit.AddToolMessage(ctx, &chatv2.MessagePayload{
+		MessageType: &chatv2.MessagePayload_ProposedEdit{
+			ProposedEdit: services.ToProposedEditMessage(edit),
+		},
+	})
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Proposed edit %s of %s, lines %d-%d of version %d of the file. ", edit.ID.Hex(), edit.DocPath, edit.StartLine, edit.StartLine+len(edit.OldLines)-1, edit.DocVersion))
+	result.WriteString("The user will review it, it is not applied until they accept it.\n\n")
+	result.WriteString("```diff\n" + edit.Diff + "```")
+	return result.String(), "", nil
+}
+
+// findDocByPathSuffix returns the doc of the project at path, or else the only doc whose path ends with path, or
+// nil. When several docs end with path, it returns nil and their paths.
+func findDocByPathSuffix(project *models.Project, path string) (*models.ProjectDoc, []string) {
+	targetPath := normalizePath(path)
+	var match *models.ProjectDoc
+	var matches []string
+	for i := range project.Docs {
+		docPath := normalizePath(project.Docs[i].Filepath)
+		if docPath == targetPath {
+			return &project.Docs[i], nil
+		}
+		if strings.HasSuffix(docPath, "/"+targetPath) {
+			match = &project.Docs[i]
+			matches = append(matches, project.Docs[i].Filepath)
+		}
+	}
+	if len(matches) > 1 {
+		return nil, matches
+	}
+	return match, matches
+}
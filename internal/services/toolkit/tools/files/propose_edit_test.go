@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"paperdebugger/internal/accesscontrol"
+	"paperdebugger/internal/libs/cfg"
+	"paperdebugger/internal/libs/contextutil"
+	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/models"
+	"paperdebugger/internal/services"
+	"paperdebugger/internal/services/toolkit"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func newIntroProject() *models.Project {
+	return &models.Project{
+		Docs: []models.ProjectDoc{
+			{ID: "main", Version: 1, Filepath: "main.tex", Lines: []string{`\input{a/intro}`, `\input{b/intro}`}},
+			{ID: "a", Version: 1, Filepath: "a/intro.tex", Lines: []string{"First intro."}},
+			{ID: "b", Version: 1, Filepath: "b/intro.tex", Lines: []string{"Second intro."}},
+			{ID: "c", Version: 1, Filepath: "chapters/conclusion.tex", Lines: []string{"We conclude."}},
+		},
+	}
+}
+
+func TestFindDocByPathSuffix(t *testing.T) {
+	project := newIntroProject()
+
+	doc, matches := findDocByPathSuffix(project, "/a/intro.tex")
+	assert.Equal(t, "a", doc.ID)
+	assert.Empty(t, matches)
+
+	doc, _ = findDocByPathSuffix(project, "conclusion.tex")
+	assert.Equal(t, "c", doc.ID)
+
+	// A suffix matching several docs is rejected
+	doc, matches = findDocByPathSuffix(project, "intro.tex")
+	assert.Nil(t, doc)
+	assert.Equal(t, []string{"a/intro.tex", "b/intro.tex"}, matches)
+
+	doc, matches = findDocByPathSuffix(project, "missing.tex")
+	assert.Nil(t, doc)
+	assert.Empty(t, matches)
+}
+
+func TestProposeEditTool(t *testing.T) {
+	os.Setenv("PD_MONGO_URI", "mongodb://localhost:27017")
+	dbInstance, err := db.NewDB(cfg.GetCfg(), logger.GetLogger())
+	if err != nil {
+		t.Skipf("MongoDB not available: %v", err)
+	}
+	projectService := services.NewProjectService(dbInstance, cfg.GetCfg(), logger.GetLogger())
+	proposedEditService := services.NewProposedEditService(dbInstance, cfg.GetCfg(), logger.GetLogger())
+	tool := NewProposeEditTool(projectService, proposedEditService)
+
+	userID := bson.NewObjectID()
+	projectID := "test-propose-edit-" + bson.NewObjectID().Hex()
+	_, err = projectService.UpsertProject(context.Background(), userID, projectID, newIntroProject())
+	assert.NoError(t, err)
+
+	ctx := contextutil.SetActor(context.Background(), &accesscontrol.Actor{ID: userID})
+	ctx = contextutil.SetProjectID(ctx, projectID)
+	ctx = contextutil.SetConversationID(ctx, bson.NewObjectID().Hex())
+	ctx, messages := toolkit.WithToolMessages(ctx)
+	call := func(args ProposeEditArgs) string {
+		raw, err := json.Marshal(args)
+		assert.NoError(t, err)
+		result, _, err := tool.Call(ctx, "call-"+bson.NewObjectID().Hex(), raw)
+		assert.NoError(t, err)
+		return result
+	}
+
+	// The model is asked for the full path instead of editing either doc
+	result := call(ProposeEditArgs{Path: "intro.tex", AnchorText: "intro.", Replacement: "introduction."})
+	assert.Contains(t, result, "Ambiguous path intro.tex")
+	assert.Empty(t, messages.Payloads())
+
+	result = call(ProposeEditArgs{Path: "b/intro.tex", AnchorText: "Second intro.", Replacement: "Second introduction."})
+	assert.Contains(t, result, "of b/intro.tex, lines 1-1 of version 1")
+	assert.Len(t, messages.Payloads(), 1)
+	edit := messages.Payloads()[0].GetProposedEdit()
+	assert.Equal(t, "b", edit.GetDocId())
+
+	result = call(ProposeEditArgs{Path: "conclusion.tex", AnchorText: "We conclude.", Replacement: "We conclude."})
+	assert.Equal(t, "The replacement is identical to the current text, no edit was proposed", result)
+	result = call(ProposeEditArgs{Path: "missing.tex", AnchorText: "x", Replacement: "y"})
+	assert.Equal(t, "File not found: missing.tex", result)
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"strings"
 
+	"paperdebugger/internal/libs/tex"
 	"paperdebugger/internal/services"
 	"paperdebugger/internal/services/toolkit"
 
@@ -39,11 +39,21 @@ func NewDocumentStructureTool(projectService *services.ProjectService) *Document
 }
 
 type sectionEntry struct {
-	Level       int // 0=part, 1=chapter, 2=section, 3=subsection, 4=subsubsection
-	Title       string
-	LineNumber  int
-	FilePath    string
-	FullContent string // The expanded content line
+	Level      int // 0=part, 1=chapter, 2=section, 3=subsection, 4=subsubsection
+	Title      string
+	LineNumber int    // Line in the expanded content
+	Offset     int    // Offset of the title in the expanded content
+	FilePath   string // Doc of the section, set by locateSections
+	SourceLine int    // Line in the doc, set by locateSections
+	Section    tex.Section
+}
+
+// location cites the section in its doc, or in the expanded content if it could not be located.
+func (s sectionEntry) location() string {
+	if s.FilePath == "" {
+		return fmt.Sprintf("line %d", s.LineNumber)
+	}
+	return fmt.Sprintf("%s:%d", s.FilePath, s.SourceLine)
 }
 
 func (t *DocumentStructureTool) Call(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
@@ -59,79 +69,103 @@ func (t *DocumentStructureTool) Call(ctx context.Context, toolCallId string, arg
 	}
 
 	// Get the full expanded content
-	fullContent, err := project.GetFullContent()
+	expansion, err := project.ExpandContent()
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get full content: %w", err)
 	}
 
 	// Parse the LaTeX to extract sections
-	sections := parseLaTeXSections(fullContent)
+	sections := parseLaTeXSections(expansion.Content)
+	locateSections(sections, expansion.SourceMap)
 
+	var result strings.Builder
 	if len(sections) == 0 {
-		return "No sections found in the document.", "", nil
+		result.WriteString("No sections found in the document.\n")
+	} else {
+		// Build a hierarchical output
+		result.WriteString("Document Structure:\n\n")
+
+		for _, sec := range sections {
+			indent := strings.Repeat("  ", sec.Level)
+			levelName := getLevelName(sec.Level)
+			result.WriteString(fmt.Sprintf("%s%s: %s (%s)\n", indent, levelName, sec.Title, sec.location()))
+		}
 	}
 
-	// Build a hierarchical output
-	var result strings.Builder
-	result.WriteString("Document Structure:\n\n")
-
-	for _, sec := range sections {
-		indent := strings.Repeat("  ", sec.Level)
-		levelName := getLevelName(sec.Level)
-		result.WriteString(fmt.Sprintf("%s%s: %s (line %d)\n", indent, levelName, sec.Title, sec.LineNumber))
+	// Includes that could not be expanded may hide sections, so they are reported
+	if len(expansion.Warnings) > 0 {
+		result.WriteString("\nUnresolved includes:\n")
+		for _, warning := range expansion.Warnings {
+			result.WriteString(fmt.Sprintf("- %s\n", warning))
+		}
 	}
 
 	return result.String(), "", nil
 }
 
+// maxSectionLevel is the lowest level of the sections listed by the tools: \subsubsection.
+const maxSectionLevel = 4
+
 // parseLaTeXSections extracts section information from LaTeX content
 func parseLaTeXSections(content string) []sectionEntry {
 	var sections []sectionEntry
-
-	// Regex patterns for different section levels
-	patterns := []struct {
-		level   int
-		pattern *regexp.Regexp
-	}{
-		{0, regexp.MustCompile(`(?m)^[^%]*\\part\*?\{([^}]*)\}`)},
-		{1, regexp.MustCompile(`(?m)^[^%]*\\chapter\*?\{([^}]*)\}`)},
-		{2, regexp.MustCompile(`(?m)^[^%]*\\section\*?\{([^}]*)\}`)},
-		{3, regexp.MustCompile(`(?m)^[^%]*\\subsection\*?\{([^}]*)\}`)},
-		{4, regexp.MustCompile(`(?m)^[^%]*\\subsubsection\*?\{([^}]*)\}`)},
+	for _, section := range tex.ParseOutline(content).Sections {
+		title := section.PlainTitle()
+		if section.Level > maxSectionLevel || title == "" {
+			continue
+		}
+		sections = append(sections, sectionEntry{
+			Level:      section.Level,
+			Title:      title,
+			LineNumber: section.Line,
+			Offset:     section.TitleStart,
+			Section:    section,
+		})
 	}
+	return sections
+}
 
-	lines := strings.Split(content, "\n")
-
-	for lineNum, line := range lines {
-		for _, p := range patterns {
-			matches := p.pattern.FindStringSubmatch(line)
-			if matches != nil && len(matches) > 1 {
-				title := strings.TrimSpace(matches[1])
-				// Clean up the title (remove LaTeX commands within)
-				title = cleanLaTeXTitle(title)
-				if title != "" {
-					sections = append(sections, sectionEntry{
-						Level:       p.level,
-						Title:       title,
-						LineNumber:  lineNum + 1, // 1-indexed
-						FullContent: line,
-					})
-				}
-				break // Only match one pattern per line
-			}
+// locateSections sets the doc and line of the sections from the source map of the expanded content.
+func locateSections(sections []sectionEntry, sourceMap *tex.SourceMap) {
+	for i := range sections {
+		if location, ok := sourceMap.Lookup(sections[i].Offset); ok {
+			sections[i].FilePath = location.Filepath
+			sections[i].SourceLine = location.Line
 		}
 	}
+}
 
-	return sections
+// findSection returns the index of the section whose title matches title, as matched by tex.Outline.FindSection,
+// or -1.
+func findSection(sections []sectionEntry, title string) int {
+	outline := &tex.Outline{}
+	for _, sec := range sections {
+		outline.Sections = append(outline.Sections, sec.Section)
+	}
+	return outline.FindSection(title)
 }
 
-// cleanLaTeXTitle removes or simplifies LaTeX commands in titles
-func cleanLaTeXTitle(title string) string {
-	// Remove common LaTeX commands
-	title = regexp.MustCompile(`\\[a-zA-Z]+\{([^}]*)\}`).ReplaceAllString(title, "$1")
-	title = regexp.MustCompile(`\\[a-zA-Z]+`).ReplaceAllString(title, "")
-	title = strings.TrimSpace(title)
-	return title
+// sectionNotFound returns the tool result for a missing section, listing the available sections as a hint.
+func sectionNotFound(sections []sectionEntry, title string) string {
+	var availableTitles []string
+	for _, sec := range sections {
+		availableTitles = append(availableTitles, sec.Title)
+	}
+	return fmt.Sprintf("Section '%s' not found. Available sections: %s", title, strings.Join(availableTitles, ", "))
+}
+
+// sectionLineRange returns the range of lines of the expanded content, 0-indexed and end excluded, of the section
+// at index: up to the next section of the same or a higher level, or the end of the content.
+func sectionLineRange(sections []sectionEntry, index int, lineCount int) (int, int) {
+	start := min(sections[index].LineNumber-1, lineCount-1)
+	end := lineCount
+	for i := index + 1; i < len(sections); i++ {
+		if sections[i].Level <= sections[index].Level {
+			end = sections[i].LineNumber - 1
+			break
+		}
+	}
+	return start, end
 }
 
 // getLevelName returns a human-readable name for the section level
@@ -0,0 +1,86 @@
+package latex
+
+import (
+	"strings"
+	"testing"
+
+	"paperdebugger/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSectionsProject() *models.Project {
+	return &models.Project{
+		RootDocID: "main",
+		Docs: []models.ProjectDoc{
+			{ID: "main", Filepath: "main.tex", Lines: []string{
+				`\documentclass{article}`,
+				`\begin{document}`,
+				`\section{Introduction}`,
+				`We introduce.`,
+				`\input{method}`,
+				`% \section{Old}`,
+				`\section{Conclusion}`,
+				`We conclude.`,
+				`\end{document}`,
+			}},
+			{ID: "method", Filepath: "method.tex", Lines: []string{
+				`\section{Method \& Setup}`,
+				`\subsection{Data}`,
+				`\paragraph{Cleaning}`,
+				`We clean.`,
+			}},
+		},
+	}
+}
+
+func TestParseLaTeXSections(t *testing.T) {
+	fullContent, sourceMap, err := newSectionsProject().GetFullContentWithSourceMap()
+	assert.NoError(t, err)
+
+	sections := parseLaTeXSections(fullContent)
+	locateSections(sections, sourceMap)
+
+	// Paragraphs and commented-out sections are not listed
+	var titles []string
+	for _, sec := range sections {
+		titles = append(titles, sec.Title)
+	}
+	assert.Equal(t, []string{"Introduction", "Method & Setup", "Data", "Conclusion"}, titles)
+	assert.Equal(t, "main.tex:3", sections[0].location())
+	assert.Equal(t, "method.tex:1", sections[1].location())
+	assert.Equal(t, "method.tex:2", sections[2].location())
+	assert.Equal(t, "main.tex:7", sections[3].location())
+}
+
+func TestFindSection(t *testing.T) {
+	sections := parseLaTeXSections(`\section{Introduction}` + "\n" + `\section{Method \& Setup}` + "\n" + `\subsection{Data}`)
+
+	assert.Equal(t, 0, findSection(sections, "introduction"))
+	assert.Equal(t, 1, findSection(sections, `Method \& Setup`))
+	assert.Equal(t, 1, findSection(sections, "method"))
+	assert.Equal(t, 2, findSection(sections, "2.1 Data"))
+	assert.Equal(t, -1, findSection(sections, "Results"))
+	assert.Equal(t, "Section 'Results' not found. Available sections: Introduction, Method & Setup, Data", sectionNotFound(sections, "Results"))
+}
+
+func TestSectionLineRange(t *testing.T) {
+	fullContent, _, err := newSectionsProject().GetFullContentWithSourceMap()
+	assert.NoError(t, err)
+	lines := strings.Split(fullContent, "\n")
+	sections := parseLaTeXSections(fullContent)
+
+	// A section includes its subsections, up to the next section
+	start, end := sectionLineRange(sections, 1, len(lines))
+	assert.Equal(t, `\section{Method \& Setup}`, lines[start])
+	assert.Equal(t, "We clean.", lines[end-1])
+
+	start, end = sectionLineRange(sections, 2, len(lines))
+	assert.Equal(t, `\subsection{Data}`, lines[start])
+	assert.Equal(t, "We clean.", lines[end-1])
+
+	// The last section ends with the content
+	start, end = sectionLineRange(sections, 3, len(lines))
+	assert.Equal(t, `\section{Conclusion}`, lines[start])
+	assert.Equal(t, len(lines), end)
+}
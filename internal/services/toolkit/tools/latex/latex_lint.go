@@ -0,0 +1,68 @@
+package latex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"paperdebugger/internal/services"
+	"paperdebugger/internal/services/toolkit"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+var LatexLintToolDescriptionV2 = openai.ChatCompletionToolUnionParam{
+	OfFunction: &openai.ChatCompletionFunctionToolParam{
+		Function: openai.FunctionDefinitionParam{
+			Name:        "latex_lint",
+			Description: param.NewOpt("Checks the LaTeX source of the paper before submission. Reports references to undefined labels, unused and duplicate labels, citation keys without bibliography entry, \\includegraphics files missing from the project, and includes that could not be resolved. Each finding is given with its file and line."),
+			Parameters: openai.FunctionParameters{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+	},
+}
+
+type LatexLintTool struct {
+	projectService *services.ProjectService
+}
+
+func NewLatexLintTool(projectService *services.ProjectService) *LatexLintTool {
+	return &LatexLintTool{
+		projectService: projectService,
+	}
+}
+
+func (t *LatexLintTool) Call(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+	// Get project from context
+	actor, projectId, _ := toolkit.GetActorProjectConversationID(ctx)
+	if actor == nil || projectId == "" {
+		return "", "", fmt.Errorf("failed to get actor or project id from context")
+	}
+
+	project, err := t.projectService.GetProject(ctx, actor.ID, projectId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get project: %w", err)
+	}
+
+	findings, err := project.Lint()
+	if err != nil {
+		return fmt.Sprintf("Failed to lint the project: %v", err), "", nil
+	}
+	if len(findings) == 0 {
+		return "No problems found.", "", nil
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Found %d problems:\n", len(findings)))
+	for _, finding := range findings {
+		result.WriteString(fmt.Sprintf("- [%s] %s\n", finding.Rule, finding.String()))
+	}
+	if project.FilePaths == nil {
+		result.WriteString("\nGraphics were not checked, as the files of the project are unknown.\n")
+	}
+	return result.String(), "", nil
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+
+	"paperdebugger/internal/services"
+	"paperdebugger/internal/services/toolkit"
 
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/packages/param"
@@ -13,7 +17,7 @@ var LocateSectionToolDescriptionV2 = openai.ChatCompletionToolUnionParam{
 	OfFunction: &openai.ChatCompletionFunctionToolParam{
 		Function: openai.FunctionDefinitionParam{
 			Name:        "locate_section",
-			Description: param.NewOpt("Locates a specific section by its title and returns the exact position (file path + line number range). Locates a specific section by its title and returns the file path and line number range."),
+			Description: param.NewOpt("Locates a specific section by its title and returns the exact position (file path + line number range)."),
 			Parameters: openai.FunctionParameters{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -32,13 +36,91 @@ type LocateSectionArgs struct {
 	Title string `json:"title"`
 }
 
-func LocateSectionTool(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+type LocateSectionTool struct {
+	projectService *services.ProjectService
+}
+
+func NewLocateSectionTool(projectService *services.ProjectService) *LocateSectionTool {
+	return &LocateSectionTool{
+		projectService: projectService,
+	}
+}
+
+func (t *LocateSectionTool) Call(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+	var getArgs LocateSectionArgs
+
+	if err := json.Unmarshal(args, &getArgs); err != nil {
+		return "", "", err
+	}
+
+	// Get project from context
+	actor, projectId, _ := toolkit.GetActorProjectConversationID(ctx)
+	if actor == nil || projectId == "" {
+		return "", "", fmt.Errorf("failed to get actor or project id from context")
+	}
+
+	project, err := t.projectService.GetProject(ctx, actor.ID, projectId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get project: %w", err)
+	}
+
+	fullContent, sourceMap, err := project.GetFullContentWithSourceMap()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get full content: %w", err)
+	}
+
+	sections := parseLaTeXSections(fullContent)
+	locateSections(sections, sourceMap)
+	targetIndex := findSection(sections, getArgs.Title)
+	if targetIndex < 0 {
+		return sectionNotFound(sections, getArgs.Title), "", nil
+	}
+	targetSection := sections[targetIndex]
+
+	lines := strings.Split(fullContent, "\n")
+	startLine, endLine := sectionLineRange(sections, targetIndex, len(lines))
+	if targetSection.FilePath == "" {
+		return fmt.Sprintf("Section '%s' spans lines %d-%d of the expanded document.", targetSection.Title, startLine+1, endLine), "", nil
+	}
+
+	// The section may span several docs when it includes other docs, each of them is listed with its line range.
+	type docRange struct {
+		filepath  string
+		startLine int
+		endLine   int
+	}
+	var ranges []docRange
+	offset := 0
+	for i := 0; i < endLine; i++ {
+		if i >= startLine {
+			// Included docs may start in the middle of a line, all the parts of the line are located.
+			for _, location := range sourceMap.LookupRange(offset, offset+len(lines[i])) {
+				last := len(ranges) - 1
+				if last >= 0 && ranges[last].filepath == location.Filepath && location.Line >= ranges[last].startLine {
+					ranges[last].endLine = max(ranges[last].endLine, location.Line)
+					continue
+				}
+				ranges = append(ranges, docRange{filepath: location.Filepath, startLine: location.Line, endLine: location.Line})
+			}
+		}
+		offset += len(lines[i]) + 1
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Section '%s' starts at %s.\n", targetSection.Title, targetSection.location()))
+	for _, r := range ranges {
+		result.WriteString(fmt.Sprintf("- %s: lines %d-%d\n", r.filepath, r.startLine, r.endLine))
+	}
+	return result.String(), "", nil
+}
+
+// LocateSectionToolLegacy for backward compatibility (standalone function)
+func LocateSectionToolLegacy(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
 	var getArgs LocateSectionArgs
 
 	if err := json.Unmarshal(args, &getArgs); err != nil {
 		return "", "", err
 	}
 
-	// TODO: Implement actual section location logic
-	return "", "", fmt.Errorf("locate_section tool is not yet implemented: cannot locate section '%s'", getArgs.Title)
+	return fmt.Sprintf(`[WARNING] locate_section tool not properly initialized. Requested section: '%s'`, getArgs.Title), "", nil
 }
@@ -0,0 +1,91 @@
+package latex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"paperdebugger/internal/libs/tex"
+	"paperdebugger/internal/services"
+	"paperdebugger/internal/services/toolkit"
+
+	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/packages/param"
+)
+
+var ReadPlainTextToolDescriptionV2 = openai.ChatCompletionToolUnionParam{
+	OfFunction: &openai.ChatCompletionFunctionToolParam{
+		Function: openai.FunctionDefinitionParam{
+			Name:        "read_plain_text",
+			Description: param.NewOpt("Reads the paper, or one of its sections, as plain text without LaTeX markup. Math is replaced by [math] and [equation] placeholders, citations and references by [cite: key] and [ref: label] markers, and figures and tables by their captions. Use it to read the prose, and the LaTeX source tools to edit it."),
+			Parameters: openai.FunctionParameters{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"section": map[string]any{
+						"type":        "string",
+						"description": "The title of the section to read (e.g., 'Introduction'). Leave empty to read the whole paper.",
+					},
+				},
+			},
+		},
+	},
+}
+
+type ReadPlainTextArgs struct {
+	Section string `json:"section"`
+}
+
+type ReadPlainTextTool struct {
+	projectService *services.ProjectService
+}
+
+func NewReadPlainTextTool(projectService *services.ProjectService) *ReadPlainTextTool {
+	return &ReadPlainTextTool{
+		projectService: projectService,
+	}
+}
+
+func (t *ReadPlainTextTool) Call(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
+	var getArgs ReadPlainTextArgs
+
+	if len(args) > 0 {
+		if err := json.Unmarshal(args, &getArgs); err != nil {
+			return "", "", err
+		}
+	}
+
+	// Get project from context
+	actor, projectId, _ := toolkit.GetActorProjectConversationID(ctx)
+	if actor == nil || projectId == "" {
+		return "", "", fmt.Errorf("failed to get actor or project id from context")
+	}
+
+	project, err := t.projectService.GetProject(ctx, actor.ID, projectId)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get project: %w", err)
+	}
+
+	if strings.TrimSpace(getArgs.Section) == "" {
+		plainText, err := project.GetPlainText()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get plain text: %w", err)
+		}
+		return plainText, "", nil
+	}
+
+	fullContent, err := project.GetFullContent()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get full content: %w", err)
+	}
+	fullContent = tex.ExpandMacros(fullContent)
+
+	sections := parseLaTeXSections(fullContent)
+	targetIndex := findSection(sections, getArgs.Section)
+	if targetIndex < 0 {
+		return sectionNotFound(sections, getArgs.Section), "", nil
+	}
+	lines := strings.Split(fullContent, "\n")
+	startLine, endLine := sectionLineRange(sections, targetIndex, len(lines))
+	return tex.Detex(strings.Join(lines[startLine:endLine], "\n")), "", nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"regexp"
 	"strings"
 
 	"paperdebugger/internal/services"
@@ -66,63 +65,37 @@ func (t *ReadSectionSourceTool) Call(ctx context.Context, toolCallId string, arg
 	}
 
 	// Get the full expanded content
-	fullContent, err := project.GetFullContent()
+	fullContent, sourceMap, err := project.GetFullContentWithSourceMap()
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get full content: %w", err)
 	}
 
 	// Parse sections to find the requested one
 	sections := parseLaTeXSections(fullContent)
+	locateSections(sections, sourceMap)
 	lines := strings.Split(fullContent, "\n")
 
-	// Find the section with matching title (fuzzy match)
-	searchTitle := strings.ToLower(strings.TrimSpace(getArgs.Title))
-	var targetSection *sectionEntry
-	var targetIndex int
-
-	for i, sec := range sections {
-		sectionTitle := strings.ToLower(sec.Title)
-		if sectionTitle == searchTitle || strings.Contains(sectionTitle, searchTitle) || strings.Contains(searchTitle, sectionTitle) {
-			targetSection = &sections[i]
-			targetIndex = i
-			break
-		}
+	targetIndex := findSection(sections, getArgs.Title)
+	if targetIndex < 0 {
+		return sectionNotFound(sections, getArgs.Title), "", nil
 	}
+	targetSection := sections[targetIndex]
+	startLine, endLine := sectionLineRange(sections, targetIndex, len(lines))
 
-	if targetSection == nil {
-		// List available sections as a hint
-		var availableTitles []string
-		for _, sec := range sections {
-			availableTitles = append(availableTitles, sec.Title)
-		}
-		return fmt.Sprintf("Section '%s' not found. Available sections: %s", getArgs.Title, strings.Join(availableTitles, ", ")), "", nil
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Section: %s (%s)\n\n", targetSection.Title, targetSection.location()))
+	offset := 0
+	for i := 0; i < startLine; i++ {
+		offset += len(lines[i]) + 1
 	}
-
-	// Determine the end of this section (start of next same-or-higher level section or end of document)
-	startLine := targetSection.LineNumber - 1 // 0-indexed
-	endLine := len(lines)
-
-	for i := targetIndex + 1; i < len(sections); i++ {
-		if sections[i].Level <= targetSection.Level {
-			endLine = sections[i].LineNumber - 1 // End before the next section
-			break
+	for i := startLine; i < endLine; i++ {
+		// Each line is cited in the doc it comes from, included docs being spliced in
+		if location, ok := sourceMap.Lookup(offset); ok {
+			result.WriteString(fmt.Sprintf("%s:%d: %s\n", location.Filepath, location.Line, lines[i]))
+		} else {
+			result.WriteString(fmt.Sprintf("%4d: %s\n", i+1, lines[i]))
 		}
-	}
-
-	// Extract the section content
-	if startLine >= len(lines) {
-		startLine = len(lines) - 1
-	}
-	if endLine > len(lines) {
-		endLine = len(lines)
-	}
-
-	sectionLines := lines[startLine:endLine]
-
-	var result strings.Builder
-	result.WriteString(fmt.Sprintf("Section: %s (lines %d-%d)\n\n", targetSection.Title, startLine+1, endLine))
-	for i, line := range sectionLines {
-		result.WriteString(fmt.Sprintf("%4d: %s\n", startLine+1+i, line))
+		offset += len(lines[i]) + 1
 	}
 
 	return result.String(), "", nil
@@ -140,44 +113,3 @@ func ReadSectionSourceToolLegacy(ctx context.Context, toolCallId string, args js
 
 	return fmt.Sprintf(`[WARNING] read_section_source tool not properly initialized. Requested section: '%s'`, getArgs.Title), "", nil
 }
-
-// Helper function to re-parse sections (since we can't import from same package)
-func parseSectionsForReadSection(content string) []sectionEntry {
-	var sections []sectionEntry
-
-	patterns := []struct {
-		level   int
-		pattern *regexp.Regexp
-	}{
-		{0, regexp.MustCompile(`(?m)^[^%]*\\part\*?\{([^}]*)\}`)},
-		{1, regexp.MustCompile(`(?m)^[^%]*\\chapter\*?\{([^}]*)\}`)},
-		{2, regexp.MustCompile(`(?m)^[^%]*\\section\*?\{([^}]*)\}`)},
-		{3, regexp.MustCompile(`(?m)^[^%]*\\subsection\*?\{([^}]*)\}`)},
-		{4, regexp.MustCompile(`(?m)^[^%]*\\subsubsection\*?\{([^}]*)\}`)},
-	}
-
-	lines := strings.Split(content, "\n")
-
-	for lineNum, line := range lines {
-		for _, p := range patterns {
-			matches := p.pattern.FindStringSubmatch(line)
-			if matches != nil && len(matches) > 1 {
-				title := strings.TrimSpace(matches[1])
-				title = regexp.MustCompile(`\\[a-zA-Z]+\{([^}]*)\}`).ReplaceAllString(title, "$1")
-				title = regexp.MustCompile(`\\[a-zA-Z]+`).ReplaceAllString(title, "")
-				title = strings.TrimSpace(title)
-				if title != "" {
-					sections = append(sections, sectionEntry{
-						Level:       p.level,
-						Title:       title,
-						LineNumber:  lineNum + 1,
-						FullContent: line,
-					})
-				}
-				break
-			}
-		}
-	}
-
-	return sections
-}
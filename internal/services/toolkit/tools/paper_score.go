@@ -8,21 +8,27 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
+	"paperdebugger/internal/models"
 	"paperdebugger/internal/services"
 	"paperdebugger/internal/services/toolkit"
 	toolCallRecordDB "paperdebugger/internal/services/toolkit/db"
 	projectv1 "paperdebugger/pkg/gen/api/project/v1"
+	"strings"
 	"time"
 
 	"github.com/openai/openai-go/v2/packages/param"
 	"github.com/openai/openai-go/v2/responses"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 type PaperScoreTool struct {
 	Description      responses.ToolUnionParam
 	toolCallRecordDB *toolCallRecordDB.ToolCallRecordDB
 	projectService   *services.ProjectService
+	userService      *services.UserService
 	coolDownTime     time.Duration
 	baseURL          string
 	client           *http.Client
@@ -36,61 +42,71 @@ var PaperScoreToolDescription = responses.ToolUnionParam{
 	},
 }
 
-func NewPaperScoreTool(db *db.DB, projectService *services.ProjectService) *PaperScoreTool {
+func NewPaperScoreTool(db *db.DB, projectService *services.ProjectService, userService *services.UserService, cfg *cfg.Cfg) *PaperScoreTool {
 	toolCallRecordDB := toolCallRecordDB.NewToolCallRecordDB(db)
 	return &PaperScoreTool{
 		Description:      PaperScoreToolDescription,
 		toolCallRecordDB: toolCallRecordDB,
 		projectService:   projectService,
+		userService:      userService,
 		coolDownTime:     5 * time.Minute,
-		baseURL:          "http://paperdebugger-mcp-server:8000/paper-score",
+		baseURL:          cfg.MCPServerURL + "/paper-score",
 		client:           &http.Client{},
 	}
 }
 
 func (t *PaperScoreTool) Call(ctx context.Context, toolCallId string, args json.RawMessage) (string, string, error) {
-	fullContent, category, err := t.prepare(ctx)
+	resp, err := t.Run(ctx, toolCallId)
 	if err != nil {
 		return "", "", err
 	}
 
+	// Return the JSON format to LLM. Do not return details and suggestions here, because they are already included in the function call record.
+	responseJSON, err := json.Marshal(map[string]any{
+		"score":      resp.Score,
+		"percentile": resp.Percentile,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal paper score result to LLM: %v", err)
+	}
+
+	furtherInstruction := "Then, call the paper_score_comment function to get the actionable comment for the paper score."
+	return string(responseJSON), furtherInstruction, nil
+}
+
+// Run scores the paper of the project in the context and records the result against the conversation in the context.
+// It is shared by the paper_score tool and the RunProjectPaperScore API.
+func (t *PaperScoreTool) Run(ctx context.Context, toolCallId string) (*projectv1.PaperScoreResult, error) {
+	fullContent, category, err := t.prepare(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create function call record
 	record, err := t.toolCallRecordDB.Create(ctx, toolCallId, *t.Description.GetName(), map[string]any{
 		"latexSource": fullContent,
 		"category":    category,
 	})
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
 	resp, err := t.ScorePaper(fullContent, category)
 	if err != nil {
 		err = fmt.Errorf("failed to score paper: %v", err)
 		t.toolCallRecordDB.OnError(ctx, record, err)
-		return "", "", err
+		return nil, err
 	}
 
 	rawJson, err := json.Marshal(resp)
 	if err != nil {
 		err = fmt.Errorf("failed to marshal paper score result: %v, rawJson: %v", err, string(rawJson))
 		t.toolCallRecordDB.OnError(ctx, record, err)
-		return "", "", err
+		return nil, err
 	}
 	t.toolCallRecordDB.OnSuccess(ctx, record, string(rawJson))
 
-	// Return the JSON format to LLM. Do not return details and suggestions here, because they are already included in rawJson.
-	responseJSON, err := json.Marshal(map[string]any{
-		"score":      resp.Score,
-		"percentile": resp.Percentile,
-	})
-	if err != nil {
-		err = fmt.Errorf("failed to marshal paper score result to LLM: %v, rawJson: %v", err, string(rawJson))
-		t.toolCallRecordDB.OnError(ctx, record, err)
-		return "", "", err
-	}
-
-	furtherInstruction := "Then, call the paper_score_comment function to get the actionable comment for the paper score."
-	return string(responseJSON), furtherInstruction, nil
+	return resp, nil
 }
 
 func (t *PaperScoreTool) prepare(ctx context.Context) (fullContent string, category string, err error) {
@@ -101,22 +117,22 @@ func (t *PaperScoreTool) prepare(ctx context.Context) (fullContent string, categ
 
 	project, err := t.projectService.GetProject(ctx, actor.ID, projectId)
 	if err != nil {
-		return "", "", errors.New("failed to get project: " + err.Error())
+		return "", "", fmt.Errorf("failed to get project: %w", err)
 	}
 
-	fullContent, err = project.GetFullContent()
+	fullContent, err = getScoredContent(ctx, t.userService, actor.ID, project)
 	if err != nil {
 		return "", "", errors.New("failed to get paper full content: " + err.Error())
 	}
 
 	projectCategory, err := t.projectService.GetProjectCategory(ctx, actor.ID, projectId)
 	if err != nil {
-		return "", "", errors.New("failed to get paper category: " + err.Error())
+		return "", "", fmt.Errorf("failed to get paper category: %w", err)
 	}
 
 	err = t.toolCallRecordDB.CheckCoolDown(ctx, *t.Description.GetName(), actor.ID.Hex(), projectId, t.coolDownTime)
 	if err != nil {
-		return "", "", errors.New("cool down: " + err.Error())
+		return "", "", err
 	}
 
 	return fullContent, projectCategory.Category, nil
@@ -162,3 +178,21 @@ func (t *PaperScoreTool) ScorePaper(latexSource string, category string) (*proje
 
 	return &scores, nil
 }
+
+// getScoredContent returns the full content of the project sent to the scorer, with the user macros as set by the
+// user. The scorer only takes the LaTeX source, so the glossary is prepended to it as comments.
+func getScoredContent(ctx context.Context, userService *services.UserService, userID bson.ObjectID, project *models.Project) (string, error) {
+	settings, err := userService.GetUserSettings(ctx, userID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return "", err
+	}
+	mode := models.MacroExpansionNone
+	if settings != nil {
+		mode = settings.MacroExpansion
+	}
+	fullContent, macroGlossary, err := project.GetFullContentWithMacros(mode)
+	if err != nil || macroGlossary == "" {
+		return fullContent, err
+	}
+	return "% User macros:\n% " + strings.ReplaceAll(macroGlossary, "\n", "\n% ") + "\n" + fullContent, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/models"
 	"paperdebugger/internal/services"
@@ -20,6 +21,13 @@ import (
 	"github.com/openai/openai-go/v2/responses"
 )
 
+var (
+	// ErrPaperScoreNotFound is returned when the comments are requested before the paper is scored.
+	ErrPaperScoreNotFound = errors.New("paper score is not found")
+	// ErrPaperScoreNotReady is returned when the latest paper score did not succeed.
+	ErrPaperScoreNotReady = errors.New("paper score is not ready")
+)
+
 type PaperScoreCommentRequest struct {
 	LatexSource      string                      `json:"latexSource"`
 	PaperScoreResult *projectv1.PaperScoreResult `json:"paperScoreResult"`
@@ -29,13 +37,14 @@ type PaperScoreCommentTool struct {
 	Description           responses.ToolUnionParam
 	toolCallRecordDB      *toolCallRecordDB.ToolCallRecordDB
 	projectService        *services.ProjectService
+	userService           *services.UserService
 	reverseCommentService *services.ReverseCommentService
 	coolDownTime          time.Duration
 	baseURL               string
 	client                *http.Client
 }
 
-func NewPaperScoreCommentTool(db *db.DB, projectService *services.ProjectService, reverseCommentService *services.ReverseCommentService) *PaperScoreCommentTool {
+func NewPaperScoreCommentTool(db *db.DB, projectService *services.ProjectService, userService *services.UserService, reverseCommentService *services.ReverseCommentService, cfg *cfg.Cfg) *PaperScoreCommentTool {
 	toolCallRecordDB := toolCallRecordDB.NewToolCallRecordDB(db)
 	paperScoreCommentToolDescription := responses.ToolUnionParam{
 		OfFunction: &responses.FunctionToolParam{
@@ -48,9 +57,10 @@ func NewPaperScoreCommentTool(db *db.DB, projectService *services.ProjectService
 		Description:           paperScoreCommentToolDescription,
 		toolCallRecordDB:      toolCallRecordDB,
 		projectService:        projectService,
+		userService:           userService,
 		reverseCommentService: reverseCommentService,
 		coolDownTime:          5 * time.Minute,
-		baseURL:               "http://paperdebugger-mcp-server:8000/paper-score-comments",
+		baseURL:               cfg.MCPServerURL + "/paper-score-comments",
 		client:                &http.Client{},
 	}
 }
@@ -62,37 +72,56 @@ func (t *PaperScoreCommentTool) Call(ctx context.Context, toolCallId string, arg
 
 	// If there are multiple functions calling at the same time, we can consider returning a unique ID, and then let LLM pass this ID.
 	// But this optimization can be considered later, because there is only one function call now.
+	_, overleafComments, err := t.Run(ctx, toolCallId)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Return the JSON to LLM, the repeated PaperScoreCommentResult is the comments.
+	responseJSON, err := json.Marshal(overleafComments)
+	if err != nil {
+		return "", "", errors.New("failed to marshal paper score comment response: " + err.Error())
+	}
+	return string(responseJSON), "", nil
+}
+
+// Run generates the comments for the latest paper score of the project in the context, and anchors them
+// in the project docs. It is shared by the paper_score_comment tool and the RunProjectPaperScoreComment API.
+func (t *PaperScoreCommentTool) Run(ctx context.Context, toolCallId string) (*projectv1.PaperScoreCommentResult, []*projectv1.OverleafComment, error) {
 	actor, projectId, conversationID := toolkit.GetActorProjectConversationID(ctx)
 	if actor == nil || projectId == "" || conversationID == "" {
-		return "", "", errors.New("Failed to get actor, project id, or conversation id")
+		return nil, nil, errors.New("Failed to get actor, project id, or conversation id")
 	}
 
 	fullContent, _, err := t.prepare(ctx)
 	if err != nil {
-		return "", "", err
+		return nil, nil, err
 	}
 
 	paperScoreRecord, err := t.toolCallRecordDB.GetLatest(ctx, "paper_score", actor.ID.Hex(), projectId)
 	if err != nil {
-		return "", "", err
+		return nil, nil, err
+	}
+	if paperScoreRecord == nil {
+		return nil, nil, fmt.Errorf("%w, paper score comments cannot be generated. please run the paper score function first.", ErrPaperScoreNotFound)
 	}
 
 	if paperScoreRecord.FunctionStatus != models.FunctionCallStatusSuccess {
 		switch paperScoreRecord.FunctionStatus {
 		case models.FunctionCallStatusError:
-			return "", "", errors.New("paper score is error, paper score comments cannot be generated. please rerun the paper score function.")
+			return nil, nil, fmt.Errorf("%w: paper score is error, paper score comments cannot be generated. please rerun the paper score function.", ErrPaperScoreNotReady)
 		case models.FunctionCallStatusPending:
-			return "", "", errors.New("paper score is pending, paper score comments cannot be generated. please wait for a few minutes and try again.")
+			return nil, nil, fmt.Errorf("%w: paper score is pending, paper score comments cannot be generated. please wait for a few minutes and try again.", ErrPaperScoreNotReady)
 		case models.FunctionCallStatusTimeout:
-			return "", "", errors.New("paper score is timeout, paper score comments cannot be generated. please rerun the paper score function.")
+			return nil, nil, fmt.Errorf("%w: paper score is timeout, paper score comments cannot be generated. please rerun the paper score function.", ErrPaperScoreNotReady)
 		default:
-			return "", "", errors.New("paper score is not completed, paper score comments cannot be generated. please try again.")
+			return nil, nil, fmt.Errorf("%w: paper score is not completed, paper score comments cannot be generated. please try again.", ErrPaperScoreNotReady)
 		}
 	}
 
 	paperScoreResult, err := t.unmarshalPaperScoreResult(paperScoreRecord.FunctionResult)
 	if err != nil {
-		return "", "", errors.New("failed to unmarshal paper score result: " + err.Error())
+		return nil, nil, errors.New("failed to unmarshal paper score result: " + err.Error())
 	}
 
 	record, err := t.toolCallRecordDB.Create(ctx, toolCallId, *t.Description.GetName(), map[string]any{
@@ -100,14 +129,14 @@ func (t *PaperScoreCommentTool) Call(ctx context.Context, toolCallId string, arg
 		"paperScoreResult": paperScoreResult,
 	})
 	if err != nil {
-		return "", "", errors.New("failed to create paper score comment record: " + err.Error())
+		return nil, nil, errors.New("failed to create paper score comment record: " + err.Error())
 	}
 
 	paperScoreCommentResult, overleafComments, err := t.execute(ctx, fullContent, paperScoreResult)
 	if err != nil {
 		err = errors.New("failed to execute paper score comment: " + err.Error())
 		t.toolCallRecordDB.OnError(ctx, record, err)
-		return "", "", err
+		return nil, nil, err
 	}
 
 	functionCallResultJson, err := json.Marshal(map[string]any{
@@ -115,16 +144,11 @@ func (t *PaperScoreCommentTool) Call(ctx context.Context, toolCallId string, arg
 		"comments":                overleafComments,
 	})
 	if err != nil {
-		return "", "", errors.New("failed to marshal paper score comment result: " + err.Error())
+		return nil, nil, errors.New("failed to marshal paper score comment result: " + err.Error())
 	}
 	t.toolCallRecordDB.OnSuccess(ctx, record, string(functionCallResultJson))
 
-	// Return the JSON to LLM, the repeated PaperScoreCommentResult is the comments.
-	responseJSON, err := json.Marshal(overleafComments)
-	if err != nil {
-		return "", "", errors.New("failed to marshal paper score comment response: " + err.Error())
-	}
-	return string(responseJSON), "", nil
+	return paperScoreCommentResult, overleafComments, nil
 }
 
 func (t *PaperScoreCommentTool) prepare(ctx context.Context) (fullContent string, category string, err error) {
@@ -135,22 +159,22 @@ func (t *PaperScoreCommentTool) prepare(ctx context.Context) (fullContent string
 
 	project, err := t.projectService.GetProject(ctx, actor.ID, projectId)
 	if err != nil {
-		return "", "", errors.New("Failed to get project: " + err.Error())
+		return "", "", fmt.Errorf("Failed to get project: %w", err)
 	}
 
-	fullContent, err = project.GetFullContent()
+	fullContent, err = getScoredContent(ctx, t.userService, actor.ID, project)
 	if err != nil {
 		return "", "", errors.New("Failed to get paper full content: " + err.Error())
 	}
 
 	projectCategory, err := t.projectService.GetProjectCategory(ctx, actor.ID, projectId)
 	if err != nil {
-		return "", "", errors.New("Failed to get paper category: " + err.Error())
+		return "", "", fmt.Errorf("Failed to get paper category: %w", err)
 	}
 
 	err = t.toolCallRecordDB.CheckCoolDown(ctx, *t.Description.GetName(), actor.ID.Hex(), projectId, t.coolDownTime)
 	if err != nil {
-		return "", "", errors.New("CoolDown: " + err.Error())
+		return "", "", err
 	}
 
 	return fullContent, projectCategory.Explanation, nil
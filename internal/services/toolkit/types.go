@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 )
 
+// ToolHandler runs a tool call. Handlers must return when ctx is done: on timeout, the registry stops waiting for
+// them but cannot stop them, and their result is discarded.
 type ToolHandler func(ctx context.Context, toolCallId string, args json.RawMessage) (result string, furtherInstruction string, err error)
 
 type ToolRegistry interface {
@@ -2,11 +2,13 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/shared"
 	"paperdebugger/internal/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -14,11 +16,15 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+// HourlyUsageRetention is how long hourly usages are kept, the reports cannot start earlier.
+const HourlyUsageRetention = 14 * 24 * time.Hour
+
 type UsageService struct {
 	BaseService
 	hourlyCollection   *mongo.Collection
 	weeklyCollection   *mongo.Collection
 	lifetimeCollection *mongo.Collection
+	userCollection     *mongo.Collection
 }
 
 func NewUsageService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *UsageService {
@@ -28,12 +34,17 @@ func NewUsageService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *UsageServi
 	lifetimeCollection := base.db.Collection((models.LifetimeUsage{}).CollectionName())
 
 	// Hourly usage indexes
+	// Hourly usages used to be unique per user, project and hour, they are now also per model.
+	if err := hourlyCollection.Indexes().DropOne(context.Background(), "user_id_1_project_id_1_hour_bucket_1"); err == nil {
+		logger.Info("Dropped the legacy unique index of hourly_usages collection")
+	}
 	hourlyIndexModels := []mongo.IndexModel{
 		{
 			Keys: bson.D{
 				{Key: "user_id", Value: 1},
 				{Key: "project_id", Value: 1},
 				{Key: "hour_bucket", Value: 1},
+				{Key: "model_slug", Value: 1},
 			},
 			Options: options.Index().SetUnique(true),
 		},
@@ -47,7 +58,7 @@ func NewUsageService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *UsageServi
 			Keys: bson.D{
 				{Key: "hour_bucket", Value: 1},
 			},
-			Options: options.Index().SetExpireAfterSeconds(14 * 24 * 60 * 60), // 2 weeks TTL
+			Options: options.Index().SetExpireAfterSeconds(int32(HourlyUsageRetention.Seconds())),
 		},
 	}
 	_, err := hourlyCollection.Indexes().CreateMany(context.Background(), hourlyIndexModels)
@@ -108,47 +119,59 @@ func NewUsageService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *UsageServi
 		hourlyCollection:   hourlyCollection,
 		weeklyCollection:   weeklyCollection,
 		lifetimeCollection: lifetimeCollection,
+		userCollection:     base.db.Collection((models.User{}).CollectionName()),
 	}
 }
 
-// TrackUsage increments cost for a user/project in hourly, weekly, and lifetime buckets.
+// UsageRecord is the usage of a single completion request.
+type UsageRecord struct {
+	ModelSlug        string
+	Cost             float64 // in USD
+	PromptTokens     int64
+	CompletionTokens int64
+	Success          bool // Whether the request completed successfully
+}
+
+// TrackUsage increments cost and tokens for a user/project in hourly, weekly, and lifetime buckets.
+// Hourly buckets are also per model, to break the spend down by model.
 // Uses upsert to create or update the usage records atomically.
-// The success parameter indicates whether the request completed successfully.
 // We will be charging only for successful requests, but we track failed requests for monitoring.
-func (s *UsageService) TrackUsage(ctx context.Context, userID bson.ObjectID, projectID string, cost float64, success bool) error {
-	if cost == 0 {
+func (s *UsageService) TrackUsage(ctx context.Context, userID bson.ObjectID, projectID string, record UsageRecord) error {
+	if record.Cost == 0 {
 		return nil
 	}
 
 	now := time.Now()
 
 	// Track hourly usage
-	if err := s.trackHourlyUsage(ctx, userID, projectID, cost, success, now); err != nil {
+	if err := s.trackHourlyUsage(ctx, userID, projectID, record, now); err != nil {
 		return err
 	}
 
 	// Track weekly usage
-	if err := s.trackWeeklyUsage(ctx, userID, projectID, cost, success, now); err != nil {
+	if err := s.trackWeeklyUsage(ctx, userID, projectID, record, now); err != nil {
 		return err
 	}
 
 	// Track lifetime usage
-	if err := s.trackLifetimeUsage(ctx, userID, projectID, cost, success, now); err != nil {
+	if err := s.trackLifetimeUsage(ctx, userID, projectID, record, now); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *UsageService) upsertUsage(ctx context.Context, collection *mongo.Collection, filter bson.M, cost float64, success bool, now time.Time) error {
+func (s *UsageService) upsertUsage(ctx context.Context, collection *mongo.Collection, filter bson.M, record UsageRecord, now time.Time) error {
 	costField := "failed_cost"
-	if success {
+	if record.Success {
 		costField = "success_cost"
 	}
 
 	update := bson.M{
 		"$inc": bson.M{
-			costField: cost,
+			costField:           record.Cost,
+			"prompt_tokens":     record.PromptTokens,
+			"completion_tokens": record.CompletionTokens,
 		},
 		"$set": bson.M{
 			"updated_at": bson.NewDateTimeFromTime(now),
@@ -163,28 +186,211 @@ func (s *UsageService) upsertUsage(ctx context.Context, collection *mongo.Collec
 	return err
 }
 
-func (s *UsageService) trackHourlyUsage(ctx context.Context, userID bson.ObjectID, projectID string, cost float64, success bool, now time.Time) error {
+func (s *UsageService) trackHourlyUsage(ctx context.Context, userID bson.ObjectID, projectID string, record UsageRecord, now time.Time) error {
 	filter := bson.M{
 		"user_id":     userID,
 		"project_id":  projectID,
 		"hour_bucket": bson.NewDateTimeFromTime(models.TruncateToHour(now)),
+		"model_slug":  record.ModelSlug,
 	}
-	return s.upsertUsage(ctx, s.hourlyCollection, filter, cost, success, now)
+	return s.upsertUsage(ctx, s.hourlyCollection, filter, record, now)
 }
 
-func (s *UsageService) trackWeeklyUsage(ctx context.Context, userID bson.ObjectID, projectID string, cost float64, success bool, now time.Time) error {
+func (s *UsageService) trackWeeklyUsage(ctx context.Context, userID bson.ObjectID, projectID string, record UsageRecord, now time.Time) error {
 	filter := bson.M{
 		"user_id":     userID,
 		"project_id":  projectID,
 		"week_bucket": bson.NewDateTimeFromTime(models.TruncateToWeek(now)),
 	}
-	return s.upsertUsage(ctx, s.weeklyCollection, filter, cost, success, now)
+	return s.upsertUsage(ctx, s.weeklyCollection, filter, record, now)
 }
 
-func (s *UsageService) trackLifetimeUsage(ctx context.Context, userID bson.ObjectID, projectID string, cost float64, success bool, now time.Time) error {
+func (s *UsageService) trackLifetimeUsage(ctx context.Context, userID bson.ObjectID, projectID string, record UsageRecord, now time.Time) error {
 	filter := bson.M{
 		"user_id":    userID,
 		"project_id": projectID,
 	}
-	return s.upsertUsage(ctx, s.lifetimeCollection, filter, cost, success, now)
+	return s.upsertUsage(ctx, s.lifetimeCollection, filter, record, now)
+}
+
+// CheckQuota returns a QuotaExceeded error if the user, or the project across all its users, has reached
+// its weekly spend limit. Failed requests count towards the quota as well, since they were paid for.
+// Requests using the user's own API key are not tracked, and must not be checked.
+func (s *UsageService) CheckQuota(ctx context.Context, userID bson.ObjectID, projectID string) error {
+	weekBucket := bson.NewDateTimeFromTime(models.TruncateToWeek(time.Now()))
+
+	userLimit, err := s.userWeeklySpendLimit(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if userLimit > 0 {
+		spent, err := s.weeklySpend(ctx, bson.M{"user_id": userID, "week_bucket": weekBucket})
+		if err != nil {
+			return err
+		}
+		if spent >= userLimit {
+			return shared.ErrQuotaExceeded(fmt.Sprintf("weekly usage quota of $%.2f exceeded, use your own API key or wait until next week", userLimit))
+		}
+	}
+
+	if projectLimit := s.cfg.ProjectWeeklySpendLimit; projectLimit > 0 && projectID != "" {
+		spent, err := s.weeklySpend(ctx, bson.M{"project_id": projectID, "week_bucket": weekBucket})
+		if err != nil {
+			return err
+		}
+		if spent >= projectLimit {
+			return shared.ErrQuotaExceeded(fmt.Sprintf("weekly usage quota of $%.2f for this project exceeded, use your own API key or wait until next week", projectLimit))
+		}
+	}
+
+	return nil
+}
+
+// userWeeklySpendLimit returns the weekly spend limit of the user, or the default one if the user has none.
+func (s *UsageService) userWeeklySpendLimit(ctx context.Context, userID bson.ObjectID) (float64, error) {
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"weekly_spend_limit": 1})
+	err := s.userCollection.FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, err
+	}
+	if user.WeeklySpendLimit != nil {
+		return *user.WeeklySpendLimit, nil
+	}
+	return s.cfg.UserWeeklySpendLimit, nil
+}
+
+// weeklySpend returns the total cost, successful or not, of the weekly usages matching the filter.
+func (s *UsageService) weeklySpend(ctx context.Context, filter bson.M) (float64, error) {
+	cursor, err := s.weeklyCollection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          nil,
+			"success_cost": bson.M{"$sum": "$success_cost"},
+			"failed_cost":  bson.M{"$sum": "$failed_cost"},
+		}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var results []struct {
+		SuccessCost float64 `bson:"success_cost"`
+		FailedCost  float64 `bson:"failed_cost"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].SuccessCost + results[0].FailedCost, nil
+}
+
+// Keys by which AggregateUsage groups the hourly usages.
+const (
+	UsageGroupByUser    = "user_id"
+	UsageGroupByProject = "project_id"
+	UsageGroupByModel   = "model_slug"
+)
+
+// UsageTotals is the cost and tokens of a set of usage records.
+type UsageTotals struct {
+	SuccessCost      float64 `bson:"success_cost"`
+	FailedCost       float64 `bson:"failed_cost"`
+	PromptTokens     int64   `bson:"prompt_tokens"`
+	CompletionTokens int64   `bson:"completion_tokens"`
+}
+
+// UsageGroup is the usage of one user, project or model.
+type UsageGroup struct {
+	Key         string `bson:"_id"`
+	UsageTotals `bson:",inline"`
+}
+
+// UsageFilter selects the hourly usages of a report. Zero fields do not filter.
+type UsageFilter struct {
+	UserID    bson.ObjectID
+	ProjectID string
+	Start     time.Time // Inclusive, truncated to the hour
+	End       time.Time // Exclusive
+}
+
+func (f UsageFilter) match() bson.M {
+	match := bson.M{}
+	if !f.UserID.IsZero() {
+		match["user_id"] = f.UserID
+	}
+	if f.ProjectID != "" {
+		match["project_id"] = f.ProjectID
+	}
+	bucket := bson.M{}
+	if !f.Start.IsZero() {
+		bucket["$gte"] = bson.NewDateTimeFromTime(models.TruncateToHour(f.Start))
+	}
+	if !f.End.IsZero() {
+		bucket["$lt"] = bson.NewDateTimeFromTime(f.End)
+	}
+	if len(bucket) > 0 {
+		match["hour_bucket"] = bucket
+	}
+	return match
+}
+
+// AggregateUsage sums the hourly usages matching the filter by user, project or model, most expensive first.
+// Hourly usages are kept for HourlyUsageRetention, older usage is not included. A limit of 0 returns all groups.
+func (s *UsageService) AggregateUsage(ctx context.Context, filter UsageFilter, groupBy string, limit int) ([]UsageGroup, error) {
+	var key any
+	switch groupBy {
+	case UsageGroupByUser:
+		key = bson.M{"$toString": "$user_id"}
+	case UsageGroupByProject, UsageGroupByModel:
+		key = bson.M{"$ifNull": bson.A{"$" + groupBy, ""}}
+	default:
+		return nil, fmt.Errorf("unknown usage group: %s", groupBy)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter.match()}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               key,
+			"success_cost":      bson.M{"$sum": "$success_cost"},
+			"failed_cost":       bson.M{"$sum": "$failed_cost"},
+			"prompt_tokens":     bson.M{"$sum": "$prompt_tokens"},
+			"completion_tokens": bson.M{"$sum": "$completion_tokens"},
+		}}},
+		{{Key: "$addFields", Value: bson.M{"total_cost": bson.M{"$add": bson.A{"$success_cost", "$failed_cost"}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total_cost", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+	}
+
+	cursor, err := s.hourlyCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	groups := []UsageGroup{}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// ListHourlyUsages returns the hourly usages matching the filter, oldest first.
+func (s *UsageService) ListHourlyUsages(ctx context.Context, filter UsageFilter) ([]models.HourlyUsage, error) {
+	opts := options.Find().SetSort(bson.D{
+		{Key: "hour_bucket", Value: 1},
+		{Key: "user_id", Value: 1},
+		{Key: "project_id", Value: 1},
+		{Key: "model_slug", Value: 1},
+	})
+	cursor, err := s.hourlyCollection.Find(ctx, filter.match(), opts)
+	if err != nil {
+		return nil, err
+	}
+	usages := []models.HourlyUsage{}
+	if err := cursor.All(ctx, &usages); err != nil {
+		return nil, err
+	}
+	return usages, nil
 }
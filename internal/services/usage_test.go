@@ -11,10 +11,13 @@ import (
 	"paperdebugger/internal/libs/logger"
 	"paperdebugger/internal/models"
 	"paperdebugger/internal/services"
+	sharedv1 "paperdebugger/pkg/gen/api/shared/v1"
 
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func setupTestUsageService(t *testing.T) (*services.UsageService, *mongo.Database) {
@@ -46,7 +49,7 @@ func TestTrackUsage_FailedCompletion(t *testing.T) {
 		_, _ = database.Collection(models.LifetimeUsage{}.CollectionName()).DeleteMany(ctx, filter)
 	})
 
-	err := us.TrackUsage(ctx, userID, projectID, cost, false)
+	err := us.TrackUsage(ctx, userID, projectID, services.UsageRecord{Cost: cost, Success: false})
 	assert.NoError(t, err)
 
 	now := time.Now()
@@ -102,8 +105,8 @@ func TestTrackUsage_FailedThenSuccess(t *testing.T) {
 		_, _ = database.Collection(models.LifetimeUsage{}.CollectionName()).DeleteMany(ctx, filter)
 	})
 
-	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, failedCost, false))
-	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, successCost, true))
+	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, services.UsageRecord{Cost: failedCost, Success: false}))
+	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, services.UsageRecord{Cost: successCost, Success: true}))
 
 	var lifetime models.LifetimeUsage
 	err := database.Collection(models.LifetimeUsage{}.CollectionName()).FindOne(ctx, bson.M{
@@ -124,7 +127,7 @@ func TestTrackUsage_ZeroCostNoOp(t *testing.T) {
 	userID := bson.NewObjectID()
 	projectID := "test-project-" + bson.NewObjectID().Hex()
 
-	err := us.TrackUsage(ctx, userID, projectID, 0, false)
+	err := us.TrackUsage(ctx, userID, projectID, services.UsageRecord{Cost: 0, Success: false})
 	assert.NoError(t, err)
 
 	count, err := database.Collection(models.LifetimeUsage{}.CollectionName()).CountDocuments(ctx, bson.M{
@@ -134,3 +137,101 @@ func TestTrackUsage_ZeroCostNoOp(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, int64(0), count)
 }
+
+// TestCheckQuota verifies that the weekly quota counts both failed and
+// successful costs, and that a per-user override replaces the default limit.
+func TestCheckQuota(t *testing.T) {
+	t.Setenv("USER_WEEKLY_SPEND_LIMIT", "5")
+	us, database := setupTestUsageService(t)
+	ctx := context.Background()
+
+	userID := bson.NewObjectID()
+	projectID := "test-project-" + bson.NewObjectID().Hex()
+	defaultLimit := cfg.GetCfg().UserWeeklySpendLimit
+
+	t.Cleanup(func() {
+		filter := bson.M{"user_id": userID, "project_id": projectID}
+		_, _ = database.Collection(models.HourlyUsage{}.CollectionName()).DeleteMany(ctx, filter)
+		_, _ = database.Collection(models.WeeklyUsage{}.CollectionName()).DeleteMany(ctx, filter)
+		_, _ = database.Collection(models.LifetimeUsage{}.CollectionName()).DeleteMany(ctx, filter)
+		_, _ = database.Collection(models.User{}.CollectionName()).DeleteOne(ctx, bson.M{"_id": userID})
+	})
+
+	assert.NoError(t, us.CheckQuota(ctx, userID, projectID))
+
+	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, services.UsageRecord{Cost: defaultLimit / 2, Success: true}))
+	assert.NoError(t, us.CheckQuota(ctx, userID, projectID))
+
+	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, services.UsageRecord{Cost: defaultLimit / 2, Success: false}))
+	err := us.CheckQuota(ctx, userID, projectID)
+	assert.Error(t, err)
+	assert.Equal(t, codes.Code(sharedv1.ErrorCode_ERROR_CODE_QUOTA_EXCEEDED), status.Code(err))
+
+	// A limit of 0 on the user lifts the quota.
+	unlimited := 0.0
+	_, err = database.Collection(models.User{}.CollectionName()).InsertOne(ctx, models.User{
+		BaseModel:        models.BaseModel{ID: userID},
+		Email:            userID.Hex() + "@example.com",
+		WeeklySpendLimit: &unlimited,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, us.CheckQuota(ctx, userID, projectID))
+
+	// The override is set and removed with the set-spend-limit command.
+	dbInstance, err := db.NewDB(cfg.GetCfg(), logger.GetLogger())
+	assert.NoError(t, err)
+	userService := services.NewUserService(dbInstance, nil, cfg.GetCfg(), logger.GetLogger())
+	lowLimit := defaultLimit / 2
+	assert.NoError(t, userService.SetUserWeeklySpendLimit(ctx, userID.Hex()+"@example.com", &lowLimit))
+	assert.Error(t, us.CheckQuota(ctx, userID, projectID))
+	assert.NoError(t, userService.SetUserWeeklySpendLimit(ctx, userID.Hex()+"@example.com", &unlimited))
+	assert.NoError(t, us.CheckQuota(ctx, userID, projectID))
+	assert.NoError(t, userService.SetUserWeeklySpendLimit(ctx, userID.Hex()+"@example.com", nil))
+	assert.Error(t, us.CheckQuota(ctx, userID, projectID))
+}
+
+// TestAggregateUsage verifies that hourly usages are recorded per model, with
+// their tokens, and can be grouped by model or project.
+func TestAggregateUsage(t *testing.T) {
+	us, database := setupTestUsageService(t)
+	ctx := context.Background()
+
+	userID := bson.NewObjectID()
+	projectID := "test-project-" + bson.NewObjectID().Hex()
+
+	t.Cleanup(func() {
+		filter := bson.M{"user_id": userID, "project_id": projectID}
+		_, _ = database.Collection(models.HourlyUsage{}.CollectionName()).DeleteMany(ctx, filter)
+		_, _ = database.Collection(models.WeeklyUsage{}.CollectionName()).DeleteMany(ctx, filter)
+		_, _ = database.Collection(models.LifetimeUsage{}.CollectionName()).DeleteMany(ctx, filter)
+	})
+
+	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, services.UsageRecord{ModelSlug: "model-a", Cost: 0.01, PromptTokens: 100, CompletionTokens: 10, Success: true}))
+	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, services.UsageRecord{ModelSlug: "model-b", Cost: 0.03, PromptTokens: 300, CompletionTokens: 30, Success: true}))
+	assert.NoError(t, us.TrackUsage(ctx, userID, projectID, services.UsageRecord{ModelSlug: "model-a", Cost: 0.01, PromptTokens: 100, CompletionTokens: 10, Success: false}))
+
+	filter := services.UsageFilter{UserID: userID, Start: time.Now().Add(-time.Hour), End: time.Now().Add(time.Minute)}
+
+	byModel, err := us.AggregateUsage(ctx, filter, services.UsageGroupByModel, 0)
+	assert.NoError(t, err)
+	assert.Len(t, byModel, 2)
+	assert.Equal(t, "model-b", byModel[0].Key)
+	assert.Equal(t, "model-a", byModel[1].Key)
+	assert.InDelta(t, 0.01, byModel[1].SuccessCost, 1e-9)
+	assert.InDelta(t, 0.01, byModel[1].FailedCost, 1e-9)
+	assert.Equal(t, int64(200), byModel[1].PromptTokens)
+
+	byProject, err := us.AggregateUsage(ctx, filter, services.UsageGroupByProject, 0)
+	assert.NoError(t, err)
+	assert.Len(t, byProject, 1)
+	assert.Equal(t, projectID, byProject[0].Key)
+	assert.Equal(t, int64(50), byProject[0].CompletionTokens)
+
+	var lifetime models.LifetimeUsage
+	err = database.Collection(models.LifetimeUsage{}.CollectionName()).FindOne(ctx, bson.M{
+		"user_id":    userID,
+		"project_id": projectID,
+	}).Decode(&lifetime)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), lifetime.PromptTokens)
+}
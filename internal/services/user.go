@@ -2,28 +2,34 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/secret"
 	"paperdebugger/internal/libs/shared"
 	"paperdebugger/internal/models"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type UserService struct {
 	BaseService
 	userCollection *mongo.Collection
+	keyring        *secret.Keyring
 }
 
-func NewUserService(db *db.DB, cfg *cfg.Cfg, logger *logger.Logger) *UserService {
+func NewUserService(db *db.DB, keyring *secret.Keyring, cfg *cfg.Cfg, logger *logger.Logger) *UserService {
 	base := NewBaseService(db, cfg, logger)
 	return &UserService{
 		BaseService:    base,
 		userCollection: base.db.Collection((models.User{}).CollectionName()),
+		keyring:        keyring,
 	}
 }
 
@@ -80,6 +86,62 @@ func (s *UserService) GetUserByID(ctx context.Context, userID bson.ObjectID) (*m
 	return &user, nil
 }
 
+// GetUserEmails returns the emails of the users, by ID. Users that do not exist anymore are left out.
+func (s *UserService) GetUserEmails(ctx context.Context, userIDs []bson.ObjectID) (map[bson.ObjectID]string, error) {
+	emails := map[bson.ObjectID]string{}
+	if len(userIDs) == 0 {
+		return emails, nil
+	}
+
+	opts := options.Find().SetProjection(bson.M{"email": 1})
+	cursor, err := s.userCollection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		emails[user.ID] = user.Email
+	}
+	return emails, nil
+}
+
+// SetUserAdmin grants or revokes the admin role of the user with the email, see models.User.IsAdmin.
+func (s *UserService) SetUserAdmin(ctx context.Context, email string, isAdmin bool) error {
+	result, err := s.userCollection.UpdateOne(ctx, bson.M{"email": email}, bson.M{
+		"$set": bson.M{"is_admin": isAdmin, "updated_at": bson.NewDateTimeFromTime(time.Now())},
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return shared.ErrRecordNotFound(fmt.Sprintf("user %s not found", email))
+	}
+	return nil
+}
+
+// SetUserWeeklySpendLimit sets the weekly spend limit of the user with the email, see models.User.WeeklySpendLimit.
+// A nil limit removes it, the user then has the default limit.
+func (s *UserService) SetUserWeeklySpendLimit(ctx context.Context, email string, limit *float64) error {
+	update := bson.M{"$set": bson.M{"weekly_spend_limit": limit, "updated_at": bson.NewDateTimeFromTime(time.Now())}}
+	if limit == nil {
+		update = bson.M{
+			"$set":   bson.M{"updated_at": bson.NewDateTimeFromTime(time.Now())},
+			"$unset": bson.M{"weekly_spend_limit": ""},
+		}
+	}
+	result, err := s.userCollection.UpdateOne(ctx, bson.M{"email": email}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return shared.ErrRecordNotFound(fmt.Sprintf("user %s not found", email))
+	}
+	return nil
+}
+
 func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	result := s.userCollection.FindOne(ctx, bson.M{"email": email})
 	if result.Err() != nil {
@@ -102,7 +164,13 @@ func (s *UserService) GetUserSettings(ctx context.Context, userID bson.ObjectID)
 	return &user.Settings, nil
 }
 
+// UpdateUserSettings replaces the settings of the user. The API keys are encrypted before being stored,
+// and API keys set to secret.MaskedValue keep their stored value.
 func (s *UserService) UpdateUserSettings(ctx context.Context, userID bson.ObjectID, settings models.Settings) (*models.Settings, error) {
+	if err := s.encryptAPIKeys(ctx, userID, &settings); err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{"_id": userID}
 	update := bson.M{
 		"$set": bson.M{
@@ -156,3 +224,121 @@ func (s *UserService) UpsertUserInstructions(ctx context.Context, userID bson.Ob
 
 	return instructions, nil
 }
+
+// encryptAPIKeys encrypts the new API keys of the settings, and restores the stored value of the masked ones.
+func (s *UserService) encryptAPIKeys(ctx context.Context, userID bson.ObjectID, settings *models.Settings) error {
+	var stored *models.Settings
+	storedAPIKey := func(customModelID *bson.ObjectID) (string, error) {
+		if stored == nil {
+			var err error
+			if stored, err = s.GetUserSettings(ctx, userID); err != nil {
+				return "", err
+			}
+		}
+		if customModelID == nil {
+			return stored.OpenAIAPIKey, nil
+		}
+		for _, m := range stored.CustomModels {
+			if m.Id == *customModelID {
+				return m.APIKey, nil
+			}
+		}
+		return "", nil
+	}
+
+	seal := func(apiKey string, customModelID *bson.ObjectID) (string, error) {
+		if apiKey == secret.MaskedValue {
+			return storedAPIKey(customModelID)
+		}
+		return s.keyring.Encrypt(apiKey, models.APIKeyAAD(userID, customModelID))
+	}
+
+	var err error
+	if settings.OpenAIAPIKey, err = seal(settings.OpenAIAPIKey, nil); err != nil {
+		return err
+	}
+	for i := range settings.CustomModels {
+		m := &settings.CustomModels[i]
+		if m.APIKey, err = seal(m.APIKey, &m.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReencryptAPIKeys encrypts the API keys of all users with the current master key, that is the plaintext
+// API keys and the ones encrypted with a previous master key or version. It returns the number of users updated.
+// When dryRun is true, the users to update are only counted.
+// A user is only updated if its API keys were not changed concurrently, they are then already encrypted with the
+// current master key.
+func (s *UserService) ReencryptAPIKeys(ctx context.Context, dryRun bool) (int, error) {
+	if !s.keyring.Enabled() {
+		return 0, errors.New("no master key configured")
+	}
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"settings.openai_api_key": bson.M{"$nin": bson.A{"", nil}}},
+		bson.M{"settings.custom_models.api_key": bson.M{"$nin": bson.A{"", nil}}},
+	}}
+	opts := options.Find().SetProjection(bson.M{"settings": 1})
+	cursor, err := s.userCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	updated := 0
+	for cursor.Next(ctx) {
+		var user models.User
+		if err := cursor.Decode(&user); err != nil {
+			return updated, err
+		}
+
+		// Only the API keys to re-encrypt are written, if they still have the value they were read with, so that
+		// concurrent changes to the settings are kept
+		settings := user.Settings
+		filter := bson.A{bson.M{"_id": user.ID}}
+		update := bson.M{}
+		arrayFilters := bson.A{}
+		if s.keyring.NeedsReencryption(settings.OpenAIAPIKey) {
+			reencrypted, err := s.keyring.Reencrypt(settings.OpenAIAPIKey, models.APIKeyAAD(user.ID, nil))
+			if err != nil {
+				return updated, fmt.Errorf("failed to re-encrypt API key of user %s: %w", user.ID.Hex(), err)
+			}
+			filter = append(filter, bson.M{"settings.openai_api_key": settings.OpenAIAPIKey})
+			update["settings.openai_api_key"] = reencrypted
+		}
+		for i, m := range settings.CustomModels {
+			if !s.keyring.NeedsReencryption(m.APIKey) {
+				continue
+			}
+			reencrypted, err := s.keyring.Reencrypt(m.APIKey, models.APIKeyAAD(user.ID, &m.Id))
+			if err != nil {
+				return updated, fmt.Errorf("failed to re-encrypt API key of user %s: %w", user.ID.Hex(), err)
+			}
+			filter = append(filter, bson.M{"settings.custom_models": bson.M{"$elemMatch": bson.M{"_id": m.Id, "api_key": m.APIKey}}})
+			update[fmt.Sprintf("settings.custom_models.$[m%d].api_key", i)] = reencrypted
+			arrayFilters = append(arrayFilters, bson.M{fmt.Sprintf("m%d._id", i): m.Id})
+		}
+		if len(update) == 0 {
+			continue
+		}
+
+		if !dryRun {
+			updateOpts := options.UpdateOne()
+			if len(arrayFilters) > 0 {
+				updateOpts.SetArrayFilters(arrayFilters)
+			}
+			result, err := s.userCollection.UpdateOne(ctx, bson.M{"$and": filter}, bson.M{"$set": update}, updateOpts)
+			if err != nil {
+				return updated, err
+			}
+			if result.MatchedCount == 0 {
+				s.logger.Info("Skipped re-encrypting the API keys of a user updated concurrently", "userID", user.ID.Hex())
+				continue
+			}
+		}
+		updated++
+	}
+	return updated, cursor.Err()
+}
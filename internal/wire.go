@@ -9,12 +9,15 @@ import (
 	"paperdebugger/internal/api/chat"
 	"paperdebugger/internal/api/comment"
 	"paperdebugger/internal/api/project"
+	"paperdebugger/internal/api/usage"
 	"paperdebugger/internal/api/user"
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/secret"
 	"paperdebugger/internal/services"
 	aiclient "paperdebugger/internal/services/toolkit/client"
+	"paperdebugger/internal/services/toolkit/tools"
 
 	"github.com/google/wire"
 )
@@ -32,6 +35,7 @@ var Set = wire.NewSet(
 	user.NewUserServer,
 	project.NewProjectServer,
 	comment.NewCommentServer,
+	usage.NewUsageServer,
 
 	aiclient.NewAIClient,
 	aiclient.NewAIClientV2,
@@ -44,7 +48,13 @@ var Set = wire.NewSet(
 	services.NewPromptService,
 	services.NewOAuthService,
 	services.NewUsageService,
+	services.NewRetrievalService,
+	services.NewProposedEditService,
 
+	tools.NewPaperScoreTool,
+	tools.NewPaperScoreCommentTool,
+
+	secret.NewKeyring,
 	cfg.GetCfg,
 	logger.GetLogger,
 	db.NewDB,
@@ -13,12 +13,15 @@ import (
 	"paperdebugger/internal/api/chat"
 	"paperdebugger/internal/api/comment"
 	"paperdebugger/internal/api/project"
+	"paperdebugger/internal/api/usage"
 	"paperdebugger/internal/api/user"
 	"paperdebugger/internal/libs/cfg"
 	"paperdebugger/internal/libs/db"
 	"paperdebugger/internal/libs/logger"
+	"paperdebugger/internal/libs/secret"
 	"paperdebugger/internal/services"
 	"paperdebugger/internal/services/toolkit/client"
+	"paperdebugger/internal/services/toolkit/tools"
 )
 
 // Injectors from wire.go:
@@ -30,23 +33,32 @@ func InitializeApp() (*api.Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	userService := services.NewUserService(dbDB, cfgCfg, loggerLogger)
+	keyring, err := secret.NewKeyring(cfgCfg)
+	if err != nil {
+		return nil, err
+	}
+	userService := services.NewUserService(dbDB, keyring, cfgCfg, loggerLogger)
 	tokenService := services.NewTokenService(dbDB, cfgCfg, loggerLogger)
 	authServiceServer := auth.NewAuthServer(tokenService, userService, cfgCfg, loggerLogger)
 	projectService := services.NewProjectService(dbDB, cfgCfg, loggerLogger)
 	reverseCommentService := services.NewReverseCommentService(dbDB, cfgCfg, loggerLogger, projectService)
-	aiClient := client.NewAIClient(dbDB, reverseCommentService, projectService, cfgCfg, loggerLogger)
+	aiClient := client.NewAIClient(dbDB, reverseCommentService, projectService, keyring, cfgCfg, loggerLogger)
 	chatService := services.NewChatService(dbDB, cfgCfg, loggerLogger)
 	chatServiceServer := chat.NewChatServer(aiClient, chatService, projectService, userService, loggerLogger, cfgCfg)
+	proposedEditService := services.NewProposedEditService(dbDB, cfgCfg, loggerLogger)
 	usageService := services.NewUsageService(dbDB, cfgCfg, loggerLogger)
-	aiClientV2 := client.NewAIClientV2(dbDB, reverseCommentService, projectService, usageService, cfgCfg, loggerLogger)
+	aiClientV2 := client.NewAIClientV2(dbDB, reverseCommentService, projectService, proposedEditService, usageService, keyring, cfgCfg, loggerLogger)
 	chatServiceV2 := services.NewChatServiceV2(dbDB, cfgCfg, loggerLogger)
-	chatv2ChatServiceServer := chat.NewChatServerV2(aiClientV2, chatServiceV2, projectService, userService, loggerLogger, cfgCfg)
+	retrievalService := services.NewRetrievalService(dbDB, cfgCfg, loggerLogger)
+	chatv2ChatServiceServer := chat.NewChatServerV2(aiClientV2, chatServiceV2, projectService, userService, retrievalService, proposedEditService, loggerLogger, cfgCfg)
 	promptService := services.NewPromptService(dbDB, cfgCfg, loggerLogger)
 	userServiceServer := user.NewUserServer(userService, promptService, cfgCfg, loggerLogger)
-	projectServiceServer := project.NewProjectServer(projectService, loggerLogger, cfgCfg)
-	commentServiceServer := comment.NewCommentServer(projectService, chatService, reverseCommentService, loggerLogger, cfgCfg)
-	grpcServer := api.NewGrpcServer(userService, cfgCfg, authServiceServer, chatServiceServer, chatv2ChatServiceServer, userServiceServer, projectServiceServer, commentServiceServer)
+	paperScoreTool := tools.NewPaperScoreTool(dbDB, projectService, userService, cfgCfg)
+	paperScoreCommentTool := tools.NewPaperScoreCommentTool(dbDB, projectService, userService, reverseCommentService, cfgCfg)
+	projectServiceServer := project.NewProjectServer(projectService, chatServiceV2, reverseCommentService, paperScoreTool, paperScoreCommentTool, loggerLogger, cfgCfg)
+	commentServiceServer := comment.NewCommentServer(projectService, chatService, reverseCommentService, userService, aiClientV2, loggerLogger, cfgCfg)
+	usageServiceServer := usage.NewUsageServer(usageService, userService, cfgCfg, loggerLogger)
+	grpcServer := api.NewGrpcServer(userService, cfgCfg, authServiceServer, chatServiceServer, chatv2ChatServiceServer, userServiceServer, projectServiceServer, commentServiceServer, usageServiceServer)
 	oAuthService := services.NewOAuthService(dbDB, cfgCfg, loggerLogger)
 	oAuthHandler := auth.NewOAuthHandler(oAuthService)
 	ginServer := api.NewGinServer(cfgCfg, oAuthHandler)
@@ -56,4 +68,4 @@ func InitializeApp() (*api.Server, error) {
 
 // wire.go:
 
-var Set = wire.NewSet(api.NewServer, api.NewGrpcServer, api.NewGinServer, auth.NewOAuthHandler, auth.NewAuthServer, chat.NewChatServer, chat.NewChatServerV2, user.NewUserServer, project.NewProjectServer, comment.NewCommentServer, client.NewAIClient, client.NewAIClientV2, services.NewReverseCommentService, services.NewChatService, services.NewChatServiceV2, services.NewTokenService, services.NewUserService, services.NewProjectService, services.NewPromptService, services.NewOAuthService, services.NewUsageService, cfg.GetCfg, logger.GetLogger, db.NewDB)
+var Set = wire.NewSet(api.NewServer, api.NewGrpcServer, api.NewGinServer, auth.NewOAuthHandler, auth.NewAuthServer, chat.NewChatServer, chat.NewChatServerV2, user.NewUserServer, project.NewProjectServer, comment.NewCommentServer, usage.NewUsageServer, client.NewAIClient, client.NewAIClientV2, services.NewReverseCommentService, services.NewChatService, services.NewChatServiceV2, services.NewTokenService, services.NewUserService, services.NewProjectService, services.NewPromptService, services.NewOAuthService, services.NewUsageService, services.NewRetrievalService, services.NewProposedEditService, tools.NewPaperScoreTool, tools.NewPaperScoreCommentTool, secret.NewKeyring, cfg.GetCfg, logger.GetLogger, db.NewDB)
@@ -22,6 +22,58 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ProposedEditStatus int32
+
+const (
+	ProposedEditStatus_PROPOSED_EDIT_STATUS_UNSPECIFIED ProposedEditStatus = 0
+	ProposedEditStatus_PROPOSED_EDIT_STATUS_PENDING     ProposedEditStatus = 1
+	ProposedEditStatus_PROPOSED_EDIT_STATUS_ACCEPTED    ProposedEditStatus = 2
+	ProposedEditStatus_PROPOSED_EDIT_STATUS_REJECTED    ProposedEditStatus = 3
+)
+
+// Enum value maps for ProposedEditStatus.
+var (
+	ProposedEditStatus_name = map[int32]string{
+		0: "PROPOSED_EDIT_STATUS_UNSPECIFIED",
+		1: "PROPOSED_EDIT_STATUS_PENDING",
+		2: "PROPOSED_EDIT_STATUS_ACCEPTED",
+		3: "PROPOSED_EDIT_STATUS_REJECTED",
+	}
+	ProposedEditStatus_value = map[string]int32{
+		"PROPOSED_EDIT_STATUS_UNSPECIFIED": 0,
+		"PROPOSED_EDIT_STATUS_PENDING":     1,
+		"PROPOSED_EDIT_STATUS_ACCEPTED":    2,
+		"PROPOSED_EDIT_STATUS_REJECTED":    3,
+	}
+)
+
+func (x ProposedEditStatus) Enum() *ProposedEditStatus {
+	p := new(ProposedEditStatus)
+	*p = x
+	return p
+}
+
+func (x ProposedEditStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProposedEditStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_chat_v2_chat_proto_enumTypes[0].Descriptor()
+}
+
+func (ProposedEditStatus) Type() protoreflect.EnumType {
+	return &file_chat_v2_chat_proto_enumTypes[0]
+}
+
+func (x ProposedEditStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProposedEditStatus.Descriptor instead.
+func (ProposedEditStatus) EnumDescriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{0}
+}
+
 type ConversationType int32
 
 const (
@@ -52,11 +104,11 @@ func (x ConversationType) String() string {
 }
 
 func (ConversationType) Descriptor() protoreflect.EnumDescriptor {
-	return file_chat_v2_chat_proto_enumTypes[0].Descriptor()
+	return file_chat_v2_chat_proto_enumTypes[1].Descriptor()
 }
 
 func (ConversationType) Type() protoreflect.EnumType {
-	return &file_chat_v2_chat_proto_enumTypes[0]
+	return &file_chat_v2_chat_proto_enumTypes[1]
 }
 
 func (x ConversationType) Number() protoreflect.EnumNumber {
@@ -65,7 +117,7 @@ func (x ConversationType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ConversationType.Descriptor instead.
 func (ConversationType) EnumDescriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{0}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{1}
 }
 
 type MessageTypeToolCall struct {
@@ -293,12 +345,13 @@ func (x *MessageTypeAssistant) GetReasoning() string {
 }
 
 type MessageTypeUser struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
-	SelectedText  *string                `protobuf:"bytes,2,opt,name=selected_text,json=selectedText,proto3,oneof" json:"selected_text,omitempty"`
-	Surrounding   *string                `protobuf:"bytes,7,opt,name=surrounding,proto3,oneof" json:"surrounding,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Content        string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	SelectedText   *string                `protobuf:"bytes,2,opt,name=selected_text,json=selectedText,proto3,oneof" json:"selected_text,omitempty"`
+	Surrounding    *string                `protobuf:"bytes,7,opt,name=surrounding,proto3,oneof" json:"surrounding,omitempty"`
+	ProjectVersion string                 `protobuf:"bytes,8,opt,name=project_version,json=projectVersion,proto3" json:"project_version,omitempty"` // Version of the project the turn was answered against, empty in debug mode
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *MessageTypeUser) Reset() {
@@ -352,6 +405,264 @@ func (x *MessageTypeUser) GetSurrounding() string {
 	return ""
 }
 
+func (x *MessageTypeUser) GetProjectVersion() string {
+	if x != nil {
+		return x.ProjectVersion
+	}
+	return ""
+}
+
+// Recorded when older turns are condensed to fit the model's context window.
+// The condensed turns stay visible in the in-app history, only the history
+// sent to the model is compacted.
+type MessageTypeCompaction struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Summary            string                 `protobuf:"bytes,1,opt,name=summary,proto3" json:"summary,omitempty"` // Empty if no turns were summarized
+	SummarizedMessages int32                  `protobuf:"varint,2,opt,name=summarized_messages,json=summarizedMessages,proto3" json:"summarized_messages,omitempty"`
+	DroppedToolOutputs int32                  `protobuf:"varint,3,opt,name=dropped_tool_outputs,json=droppedToolOutputs,proto3" json:"dropped_tool_outputs,omitempty"`
+	TokensBefore       int64                  `protobuf:"varint,4,opt,name=tokens_before,json=tokensBefore,proto3" json:"tokens_before,omitempty"` // Estimated
+	TokensAfter        int64                  `protobuf:"varint,5,opt,name=tokens_after,json=tokensAfter,proto3" json:"tokens_after,omitempty"`    // Estimated
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *MessageTypeCompaction) Reset() {
+	*x = MessageTypeCompaction{}
+	mi := &file_chat_v2_chat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageTypeCompaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageTypeCompaction) ProtoMessage() {}
+
+func (x *MessageTypeCompaction) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageTypeCompaction.ProtoReflect.Descriptor instead.
+func (*MessageTypeCompaction) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MessageTypeCompaction) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *MessageTypeCompaction) GetSummarizedMessages() int32 {
+	if x != nil {
+		return x.SummarizedMessages
+	}
+	return 0
+}
+
+func (x *MessageTypeCompaction) GetDroppedToolOutputs() int32 {
+	if x != nil {
+		return x.DroppedToolOutputs
+	}
+	return 0
+}
+
+func (x *MessageTypeCompaction) GetTokensBefore() int64 {
+	if x != nil {
+		return x.TokensBefore
+	}
+	return 0
+}
+
+func (x *MessageTypeCompaction) GetTokensAfter() int64 {
+	if x != nil {
+		return x.TokensAfter
+	}
+	return 0
+}
+
+// Recorded when the project changed since the previous turn. The system prompt is
+// rebuilt from the current project, and the model is told what changed.
+type MessageTypeProjectRefresh struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromVersion   string                 `protobuf:"bytes,1,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	ToVersion     string                 `protobuf:"bytes,2,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
+	Diff          string                 `protobuf:"bytes,3,opt,name=diff,proto3" json:"diff,omitempty"` // Unified diff of the LaTeX source, empty if unknown or too large
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageTypeProjectRefresh) Reset() {
+	*x = MessageTypeProjectRefresh{}
+	mi := &file_chat_v2_chat_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageTypeProjectRefresh) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageTypeProjectRefresh) ProtoMessage() {}
+
+func (x *MessageTypeProjectRefresh) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageTypeProjectRefresh.ProtoReflect.Descriptor instead.
+func (*MessageTypeProjectRefresh) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MessageTypeProjectRefresh) GetFromVersion() string {
+	if x != nil {
+		return x.FromVersion
+	}
+	return ""
+}
+
+func (x *MessageTypeProjectRefresh) GetToVersion() string {
+	if x != nil {
+		return x.ToVersion
+	}
+	return ""
+}
+
+func (x *MessageTypeProjectRefresh) GetDiff() string {
+	if x != nil {
+		return x.Diff
+	}
+	return ""
+}
+
+// Recorded when the assistant proposes an edit of a doc. The edit is not applied
+// to the project, the user accepts or rejects it.
+type MessageTypeProposedEdit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EditId        string                 `protobuf:"bytes,1,opt,name=edit_id,json=editId,proto3" json:"edit_id,omitempty"`
+	DocId         string                 `protobuf:"bytes,2,opt,name=doc_id,json=docId,proto3" json:"doc_id,omitempty"`
+	FilePath      string                 `protobuf:"bytes,3,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	DocVersion    int32                  `protobuf:"varint,4,opt,name=doc_version,json=docVersion,proto3" json:"doc_version,omitempty"` // Version of the doc the edit was proposed for
+	StartLine     int32                  `protobuf:"varint,5,opt,name=start_line,json=startLine,proto3" json:"start_line,omitempty"`    // First replaced line, starting at 1
+	OldLines      []string               `protobuf:"bytes,6,rep,name=old_lines,json=oldLines,proto3" json:"old_lines,omitempty"`
+	NewLines      []string               `protobuf:"bytes,7,rep,name=new_lines,json=newLines,proto3" json:"new_lines,omitempty"`
+	Diff          string                 `protobuf:"bytes,8,opt,name=diff,proto3" json:"diff,omitempty"` // Unified diff of the doc
+	Status        ProposedEditStatus     `protobuf:"varint,9,opt,name=status,proto3,enum=chat.v2.ProposedEditStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageTypeProposedEdit) Reset() {
+	*x = MessageTypeProposedEdit{}
+	mi := &file_chat_v2_chat_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageTypeProposedEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageTypeProposedEdit) ProtoMessage() {}
+
+func (x *MessageTypeProposedEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageTypeProposedEdit.ProtoReflect.Descriptor instead.
+func (*MessageTypeProposedEdit) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MessageTypeProposedEdit) GetEditId() string {
+	if x != nil {
+		return x.EditId
+	}
+	return ""
+}
+
+func (x *MessageTypeProposedEdit) GetDocId() string {
+	if x != nil {
+		return x.DocId
+	}
+	return ""
+}
+
+func (x *MessageTypeProposedEdit) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *MessageTypeProposedEdit) GetDocVersion() int32 {
+	if x != nil {
+		return x.DocVersion
+	}
+	return 0
+}
+
+func (x *MessageTypeProposedEdit) GetStartLine() int32 {
+	if x != nil {
+		return x.StartLine
+	}
+	return 0
+}
+
+func (x *MessageTypeProposedEdit) GetOldLines() []string {
+	if x != nil {
+		return x.OldLines
+	}
+	return nil
+}
+
+func (x *MessageTypeProposedEdit) GetNewLines() []string {
+	if x != nil {
+		return x.NewLines
+	}
+	return nil
+}
+
+func (x *MessageTypeProposedEdit) GetDiff() string {
+	if x != nil {
+		return x.Diff
+	}
+	return ""
+}
+
+func (x *MessageTypeProposedEdit) GetStatus() ProposedEditStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProposedEditStatus_PROPOSED_EDIT_STATUS_UNSPECIFIED
+}
+
 type MessageTypeUnknown struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Description   string                 `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
@@ -361,7 +672,7 @@ type MessageTypeUnknown struct {
 
 func (x *MessageTypeUnknown) Reset() {
 	*x = MessageTypeUnknown{}
-	mi := &file_chat_v2_chat_proto_msgTypes[5]
+	mi := &file_chat_v2_chat_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -373,7 +684,7 @@ func (x *MessageTypeUnknown) String() string {
 func (*MessageTypeUnknown) ProtoMessage() {}
 
 func (x *MessageTypeUnknown) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[5]
+	mi := &file_chat_v2_chat_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -386,7 +697,7 @@ func (x *MessageTypeUnknown) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageTypeUnknown.ProtoReflect.Descriptor instead.
 func (*MessageTypeUnknown) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{5}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *MessageTypeUnknown) GetDescription() string {
@@ -406,6 +717,9 @@ type MessagePayload struct {
 	//	*MessagePayload_ToolCallPrepareArguments
 	//	*MessagePayload_ToolCall
 	//	*MessagePayload_Unknown
+	//	*MessagePayload_Compaction
+	//	*MessagePayload_ProjectRefresh
+	//	*MessagePayload_ProposedEdit
 	MessageType   isMessagePayload_MessageType `protobuf_oneof:"message_type"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -413,7 +727,7 @@ type MessagePayload struct {
 
 func (x *MessagePayload) Reset() {
 	*x = MessagePayload{}
-	mi := &file_chat_v2_chat_proto_msgTypes[6]
+	mi := &file_chat_v2_chat_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -425,7 +739,7 @@ func (x *MessagePayload) String() string {
 func (*MessagePayload) ProtoMessage() {}
 
 func (x *MessagePayload) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[6]
+	mi := &file_chat_v2_chat_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -438,7 +752,7 @@ func (x *MessagePayload) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessagePayload.ProtoReflect.Descriptor instead.
 func (*MessagePayload) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{6}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *MessagePayload) GetMessageType() isMessagePayload_MessageType {
@@ -502,6 +816,33 @@ func (x *MessagePayload) GetUnknown() *MessageTypeUnknown {
 	return nil
 }
 
+func (x *MessagePayload) GetCompaction() *MessageTypeCompaction {
+	if x != nil {
+		if x, ok := x.MessageType.(*MessagePayload_Compaction); ok {
+			return x.Compaction
+		}
+	}
+	return nil
+}
+
+func (x *MessagePayload) GetProjectRefresh() *MessageTypeProjectRefresh {
+	if x != nil {
+		if x, ok := x.MessageType.(*MessagePayload_ProjectRefresh); ok {
+			return x.ProjectRefresh
+		}
+	}
+	return nil
+}
+
+func (x *MessagePayload) GetProposedEdit() *MessageTypeProposedEdit {
+	if x != nil {
+		if x, ok := x.MessageType.(*MessagePayload_ProposedEdit); ok {
+			return x.ProposedEdit
+		}
+	}
+	return nil
+}
+
 type isMessagePayload_MessageType interface {
 	isMessagePayload_MessageType()
 }
@@ -530,6 +871,18 @@ type MessagePayload_Unknown struct {
 	Unknown *MessageTypeUnknown `protobuf:"bytes,6,opt,name=unknown,proto3,oneof"`
 }
 
+type MessagePayload_Compaction struct {
+	Compaction *MessageTypeCompaction `protobuf:"bytes,7,opt,name=compaction,proto3,oneof"`
+}
+
+type MessagePayload_ProjectRefresh struct {
+	ProjectRefresh *MessageTypeProjectRefresh `protobuf:"bytes,8,opt,name=project_refresh,json=projectRefresh,proto3,oneof"`
+}
+
+type MessagePayload_ProposedEdit struct {
+	ProposedEdit *MessageTypeProposedEdit `protobuf:"bytes,9,opt,name=proposed_edit,json=proposedEdit,proto3,oneof"`
+}
+
 func (*MessagePayload_System) isMessagePayload_MessageType() {}
 
 func (*MessagePayload_User) isMessagePayload_MessageType() {}
@@ -542,6 +895,12 @@ func (*MessagePayload_ToolCall) isMessagePayload_MessageType() {}
 
 func (*MessagePayload_Unknown) isMessagePayload_MessageType() {}
 
+func (*MessagePayload_Compaction) isMessagePayload_MessageType() {}
+
+func (*MessagePayload_ProjectRefresh) isMessagePayload_MessageType() {}
+
+func (*MessagePayload_ProposedEdit) isMessagePayload_MessageType() {}
+
 type Message struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
@@ -553,7 +912,7 @@ type Message struct {
 
 func (x *Message) Reset() {
 	*x = Message{}
-	mi := &file_chat_v2_chat_proto_msgTypes[7]
+	mi := &file_chat_v2_chat_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -565,7 +924,7 @@ func (x *Message) String() string {
 func (*Message) ProtoMessage() {}
 
 func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[7]
+	mi := &file_chat_v2_chat_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -578,7 +937,7 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Message.ProtoReflect.Descriptor instead.
 func (*Message) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{7}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Message) GetMessageId() string {
@@ -602,20 +961,78 @@ func (x *Message) GetTimestamp() int64 {
 	return 0
 }
 
-type Conversation struct {
-	state     protoimpl.MessageState `protogen:"open.v1"`
-	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+// The messages at the same position of the conversation in different branches.
+// All of them follow the same parent message.
+type MessageAlternatives struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // The message in the active branch
+	Alternatives  []*Message             `protobuf:"bytes,2,rep,name=alternatives,proto3" json:"alternatives,omitempty"`            // Including message_id, oldest branch first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageAlternatives) Reset() {
+	*x = MessageAlternatives{}
+	mi := &file_chat_v2_chat_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageAlternatives) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageAlternatives) ProtoMessage() {}
+
+func (x *MessageAlternatives) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageAlternatives.ProtoReflect.Descriptor instead.
+func (*MessageAlternatives) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *MessageAlternatives) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *MessageAlternatives) GetAlternatives() []*Message {
+	if x != nil {
+		return x.Alternatives
+	}
+	return nil
+}
+
+type Conversation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Title     string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
 	ModelSlug string                 `protobuf:"bytes,3,opt,name=model_slug,json=modelSlug,proto3" json:"model_slug,omitempty"`
 	// If list conversations, then messages length is 0.
-	Messages      []*Message `protobuf:"bytes,4,rep,name=messages,proto3" json:"messages,omitempty"`
+	// Only the messages of the active branch are returned.
+	Messages []*Message `protobuf:"bytes,4,rep,name=messages,proto3" json:"messages,omitempty"`
+	BranchId string     `protobuf:"bytes,5,opt,name=branch_id,json=branchId,proto3" json:"branch_id,omitempty"` // The id of the last message of the active branch
+	// The positions of the active branch where other branches diverge.
+	Alternatives  []*MessageAlternatives `protobuf:"bytes,6,rep,name=alternatives,proto3" json:"alternatives,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Conversation) Reset() {
 	*x = Conversation{}
-	mi := &file_chat_v2_chat_proto_msgTypes[8]
+	mi := &file_chat_v2_chat_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -627,7 +1044,7 @@ func (x *Conversation) String() string {
 func (*Conversation) ProtoMessage() {}
 
 func (x *Conversation) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[8]
+	mi := &file_chat_v2_chat_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -640,7 +1057,7 @@ func (x *Conversation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Conversation.ProtoReflect.Descriptor instead.
 func (*Conversation) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{8}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *Conversation) GetId() string {
@@ -671,6 +1088,20 @@ func (x *Conversation) GetMessages() []*Message {
 	return nil
 }
 
+func (x *Conversation) GetBranchId() string {
+	if x != nil {
+		return x.BranchId
+	}
+	return ""
+}
+
+func (x *Conversation) GetAlternatives() []*MessageAlternatives {
+	if x != nil {
+		return x.Alternatives
+	}
+	return nil
+}
+
 type ListConversationsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProjectId     *string                `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3,oneof" json:"project_id,omitempty"`
@@ -680,7 +1111,7 @@ type ListConversationsRequest struct {
 
 func (x *ListConversationsRequest) Reset() {
 	*x = ListConversationsRequest{}
-	mi := &file_chat_v2_chat_proto_msgTypes[9]
+	mi := &file_chat_v2_chat_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -692,7 +1123,7 @@ func (x *ListConversationsRequest) String() string {
 func (*ListConversationsRequest) ProtoMessage() {}
 
 func (x *ListConversationsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[9]
+	mi := &file_chat_v2_chat_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -705,7 +1136,7 @@ func (x *ListConversationsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListConversationsRequest.ProtoReflect.Descriptor instead.
 func (*ListConversationsRequest) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{9}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ListConversationsRequest) GetProjectId() string {
@@ -725,7 +1156,7 @@ type ListConversationsResponse struct {
 
 func (x *ListConversationsResponse) Reset() {
 	*x = ListConversationsResponse{}
-	mi := &file_chat_v2_chat_proto_msgTypes[10]
+	mi := &file_chat_v2_chat_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -737,7 +1168,7 @@ func (x *ListConversationsResponse) String() string {
 func (*ListConversationsResponse) ProtoMessage() {}
 
 func (x *ListConversationsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[10]
+	mi := &file_chat_v2_chat_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -750,7 +1181,7 @@ func (x *ListConversationsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListConversationsResponse.ProtoReflect.Descriptor instead.
 func (*ListConversationsResponse) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{10}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ListConversationsResponse) GetConversations() []*Conversation {
@@ -769,7 +1200,7 @@ type GetConversationRequest struct {
 
 func (x *GetConversationRequest) Reset() {
 	*x = GetConversationRequest{}
-	mi := &file_chat_v2_chat_proto_msgTypes[11]
+	mi := &file_chat_v2_chat_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -781,7 +1212,7 @@ func (x *GetConversationRequest) String() string {
 func (*GetConversationRequest) ProtoMessage() {}
 
 func (x *GetConversationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[11]
+	mi := &file_chat_v2_chat_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -794,7 +1225,7 @@ func (x *GetConversationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetConversationRequest.ProtoReflect.Descriptor instead.
 func (*GetConversationRequest) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{11}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetConversationRequest) GetConversationId() string {
@@ -813,7 +1244,7 @@ type GetConversationResponse struct {
 
 func (x *GetConversationResponse) Reset() {
 	*x = GetConversationResponse{}
-	mi := &file_chat_v2_chat_proto_msgTypes[12]
+	mi := &file_chat_v2_chat_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -825,7 +1256,7 @@ func (x *GetConversationResponse) String() string {
 func (*GetConversationResponse) ProtoMessage() {}
 
 func (x *GetConversationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[12]
+	mi := &file_chat_v2_chat_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -838,7 +1269,7 @@ func (x *GetConversationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetConversationResponse.ProtoReflect.Descriptor instead.
 func (*GetConversationResponse) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{12}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetConversationResponse) GetConversation() *Conversation {
@@ -858,7 +1289,7 @@ type UpdateConversationRequest struct {
 
 func (x *UpdateConversationRequest) Reset() {
 	*x = UpdateConversationRequest{}
-	mi := &file_chat_v2_chat_proto_msgTypes[13]
+	mi := &file_chat_v2_chat_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -870,7 +1301,7 @@ func (x *UpdateConversationRequest) String() string {
 func (*UpdateConversationRequest) ProtoMessage() {}
 
 func (x *UpdateConversationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[13]
+	mi := &file_chat_v2_chat_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -883,7 +1314,7 @@ func (x *UpdateConversationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateConversationRequest.ProtoReflect.Descriptor instead.
 func (*UpdateConversationRequest) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{13}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *UpdateConversationRequest) GetConversationId() string {
@@ -909,7 +1340,7 @@ type UpdateConversationResponse struct {
 
 func (x *UpdateConversationResponse) Reset() {
 	*x = UpdateConversationResponse{}
-	mi := &file_chat_v2_chat_proto_msgTypes[14]
+	mi := &file_chat_v2_chat_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -921,7 +1352,7 @@ func (x *UpdateConversationResponse) String() string {
 func (*UpdateConversationResponse) ProtoMessage() {}
 
 func (x *UpdateConversationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[14]
+	mi := &file_chat_v2_chat_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -934,7 +1365,7 @@ func (x *UpdateConversationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateConversationResponse.ProtoReflect.Descriptor instead.
 func (*UpdateConversationResponse) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{14}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *UpdateConversationResponse) GetConversation() *Conversation {
@@ -944,6 +1375,102 @@ func (x *UpdateConversationResponse) GetConversation() *Conversation {
 	return nil
 }
 
+type SwitchConversationBranchRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	MessageId      string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // Switch to the latest branch containing this message
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SwitchConversationBranchRequest) Reset() {
+	*x = SwitchConversationBranchRequest{}
+	mi := &file_chat_v2_chat_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwitchConversationBranchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwitchConversationBranchRequest) ProtoMessage() {}
+
+func (x *SwitchConversationBranchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwitchConversationBranchRequest.ProtoReflect.Descriptor instead.
+func (*SwitchConversationBranchRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SwitchConversationBranchRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SwitchConversationBranchRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type SwitchConversationBranchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conversation  *Conversation          `protobuf:"bytes,1,opt,name=conversation,proto3" json:"conversation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SwitchConversationBranchResponse) Reset() {
+	*x = SwitchConversationBranchResponse{}
+	mi := &file_chat_v2_chat_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwitchConversationBranchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwitchConversationBranchResponse) ProtoMessage() {}
+
+func (x *SwitchConversationBranchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwitchConversationBranchResponse.ProtoReflect.Descriptor instead.
+func (*SwitchConversationBranchResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SwitchConversationBranchResponse) GetConversation() *Conversation {
+	if x != nil {
+		return x.Conversation
+	}
+	return nil
+}
+
 type DeleteConversationRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
@@ -953,7 +1480,7 @@ type DeleteConversationRequest struct {
 
 func (x *DeleteConversationRequest) Reset() {
 	*x = DeleteConversationRequest{}
-	mi := &file_chat_v2_chat_proto_msgTypes[15]
+	mi := &file_chat_v2_chat_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -965,7 +1492,7 @@ func (x *DeleteConversationRequest) String() string {
 func (*DeleteConversationRequest) ProtoMessage() {}
 
 func (x *DeleteConversationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[15]
+	mi := &file_chat_v2_chat_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -978,7 +1505,7 @@ func (x *DeleteConversationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteConversationRequest.ProtoReflect.Descriptor instead.
 func (*DeleteConversationRequest) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{15}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *DeleteConversationRequest) GetConversationId() string {
@@ -996,7 +1523,7 @@ type DeleteConversationResponse struct {
 
 func (x *DeleteConversationResponse) Reset() {
 	*x = DeleteConversationResponse{}
-	mi := &file_chat_v2_chat_proto_msgTypes[16]
+	mi := &file_chat_v2_chat_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1008,7 +1535,7 @@ func (x *DeleteConversationResponse) String() string {
 func (*DeleteConversationResponse) ProtoMessage() {}
 
 func (x *DeleteConversationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[16]
+	mi := &file_chat_v2_chat_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1021,7 +1548,7 @@ func (x *DeleteConversationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteConversationResponse.ProtoReflect.Descriptor instead.
 func (*DeleteConversationResponse) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{16}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{22}
 }
 
 type SupportedModel struct {
@@ -1042,7 +1569,7 @@ type SupportedModel struct {
 
 func (x *SupportedModel) Reset() {
 	*x = SupportedModel{}
-	mi := &file_chat_v2_chat_proto_msgTypes[17]
+	mi := &file_chat_v2_chat_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1054,7 +1581,7 @@ func (x *SupportedModel) String() string {
 func (*SupportedModel) ProtoMessage() {}
 
 func (x *SupportedModel) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[17]
+	mi := &file_chat_v2_chat_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1067,7 +1594,7 @@ func (x *SupportedModel) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SupportedModel.ProtoReflect.Descriptor instead.
 func (*SupportedModel) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{17}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *SupportedModel) GetName() string {
@@ -1148,7 +1675,7 @@ type ListSupportedModelsRequest struct {
 
 func (x *ListSupportedModelsRequest) Reset() {
 	*x = ListSupportedModelsRequest{}
-	mi := &file_chat_v2_chat_proto_msgTypes[18]
+	mi := &file_chat_v2_chat_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1160,7 +1687,7 @@ func (x *ListSupportedModelsRequest) String() string {
 func (*ListSupportedModelsRequest) ProtoMessage() {}
 
 func (x *ListSupportedModelsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[18]
+	mi := &file_chat_v2_chat_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1173,7 +1700,7 @@ func (x *ListSupportedModelsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSupportedModelsRequest.ProtoReflect.Descriptor instead.
 func (*ListSupportedModelsRequest) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{18}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{24}
 }
 
 type ListSupportedModelsResponse struct {
@@ -1185,7 +1712,7 @@ type ListSupportedModelsResponse struct {
 
 func (x *ListSupportedModelsResponse) Reset() {
 	*x = ListSupportedModelsResponse{}
-	mi := &file_chat_v2_chat_proto_msgTypes[19]
+	mi := &file_chat_v2_chat_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1197,7 +1724,7 @@ func (x *ListSupportedModelsResponse) String() string {
 func (*ListSupportedModelsResponse) ProtoMessage() {}
 
 func (x *ListSupportedModelsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[19]
+	mi := &file_chat_v2_chat_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1210,7 +1737,7 @@ func (x *ListSupportedModelsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListSupportedModelsResponse.ProtoReflect.Descriptor instead.
 func (*ListSupportedModelsResponse) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{19}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *ListSupportedModelsResponse) GetModels() []*SupportedModel {
@@ -1231,7 +1758,7 @@ type StreamInitialization struct {
 
 func (x *StreamInitialization) Reset() {
 	*x = StreamInitialization{}
-	mi := &file_chat_v2_chat_proto_msgTypes[20]
+	mi := &file_chat_v2_chat_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1243,7 +1770,7 @@ func (x *StreamInitialization) String() string {
 func (*StreamInitialization) ProtoMessage() {}
 
 func (x *StreamInitialization) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[20]
+	mi := &file_chat_v2_chat_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1256,7 +1783,7 @@ func (x *StreamInitialization) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamInitialization.ProtoReflect.Descriptor instead.
 func (*StreamInitialization) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{20}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *StreamInitialization) GetConversationId() string {
@@ -1288,7 +1815,7 @@ type StreamPartBegin struct {
 
 func (x *StreamPartBegin) Reset() {
 	*x = StreamPartBegin{}
-	mi := &file_chat_v2_chat_proto_msgTypes[21]
+	mi := &file_chat_v2_chat_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1300,7 +1827,7 @@ func (x *StreamPartBegin) String() string {
 func (*StreamPartBegin) ProtoMessage() {}
 
 func (x *StreamPartBegin) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[21]
+	mi := &file_chat_v2_chat_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1313,7 +1840,7 @@ func (x *StreamPartBegin) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamPartBegin.ProtoReflect.Descriptor instead.
 func (*StreamPartBegin) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{21}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *StreamPartBegin) GetMessageId() string {
@@ -1343,7 +1870,7 @@ type MessageChunk struct {
 
 func (x *MessageChunk) Reset() {
 	*x = MessageChunk{}
-	mi := &file_chat_v2_chat_proto_msgTypes[22]
+	mi := &file_chat_v2_chat_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1355,7 +1882,7 @@ func (x *MessageChunk) String() string {
 func (*MessageChunk) ProtoMessage() {}
 
 func (x *MessageChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[22]
+	mi := &file_chat_v2_chat_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1368,7 +1895,7 @@ func (x *MessageChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageChunk.ProtoReflect.Descriptor instead.
 func (*MessageChunk) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{22}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *MessageChunk) GetMessageId() string {
@@ -1395,7 +1922,7 @@ type ReasoningChunk struct {
 
 func (x *ReasoningChunk) Reset() {
 	*x = ReasoningChunk{}
-	mi := &file_chat_v2_chat_proto_msgTypes[23]
+	mi := &file_chat_v2_chat_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1407,7 +1934,7 @@ func (x *ReasoningChunk) String() string {
 func (*ReasoningChunk) ProtoMessage() {}
 
 func (x *ReasoningChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[23]
+	mi := &file_chat_v2_chat_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1420,7 +1947,7 @@ func (x *ReasoningChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReasoningChunk.ProtoReflect.Descriptor instead.
 func (*ReasoningChunk) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{23}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ReasoningChunk) GetMessageId() string {
@@ -1447,7 +1974,7 @@ type IncompleteIndicator struct {
 
 func (x *IncompleteIndicator) Reset() {
 	*x = IncompleteIndicator{}
-	mi := &file_chat_v2_chat_proto_msgTypes[24]
+	mi := &file_chat_v2_chat_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1459,7 +1986,7 @@ func (x *IncompleteIndicator) String() string {
 func (*IncompleteIndicator) ProtoMessage() {}
 
 func (x *IncompleteIndicator) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[24]
+	mi := &file_chat_v2_chat_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1472,7 +1999,7 @@ func (x *IncompleteIndicator) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IncompleteIndicator.ProtoReflect.Descriptor instead.
 func (*IncompleteIndicator) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{24}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *IncompleteIndicator) GetReason() string {
@@ -1499,7 +2026,7 @@ type StreamPartEnd struct {
 
 func (x *StreamPartEnd) Reset() {
 	*x = StreamPartEnd{}
-	mi := &file_chat_v2_chat_proto_msgTypes[25]
+	mi := &file_chat_v2_chat_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1511,7 +2038,7 @@ func (x *StreamPartEnd) String() string {
 func (*StreamPartEnd) ProtoMessage() {}
 
 func (x *StreamPartEnd) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[25]
+	mi := &file_chat_v2_chat_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1524,7 +2051,7 @@ func (x *StreamPartEnd) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamPartEnd.ProtoReflect.Descriptor instead.
 func (*StreamPartEnd) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{25}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *StreamPartEnd) GetMessageId() string {
@@ -1551,7 +2078,7 @@ type StreamFinalization struct {
 
 func (x *StreamFinalization) Reset() {
 	*x = StreamFinalization{}
-	mi := &file_chat_v2_chat_proto_msgTypes[26]
+	mi := &file_chat_v2_chat_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1563,7 +2090,7 @@ func (x *StreamFinalization) String() string {
 func (*StreamFinalization) ProtoMessage() {}
 
 func (x *StreamFinalization) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[26]
+	mi := &file_chat_v2_chat_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1576,7 +2103,7 @@ func (x *StreamFinalization) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamFinalization.ProtoReflect.Descriptor instead.
 func (*StreamFinalization) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{26}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *StreamFinalization) GetConversationId() string {
@@ -1595,7 +2122,7 @@ type StreamError struct {
 
 func (x *StreamError) Reset() {
 	*x = StreamError{}
-	mi := &file_chat_v2_chat_proto_msgTypes[27]
+	mi := &file_chat_v2_chat_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1607,7 +2134,7 @@ func (x *StreamError) String() string {
 func (*StreamError) ProtoMessage() {}
 
 func (x *StreamError) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[27]
+	mi := &file_chat_v2_chat_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1620,7 +2147,7 @@ func (x *StreamError) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamError.ProtoReflect.Descriptor instead.
 func (*StreamError) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{27}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *StreamError) GetErrorMessage() string {
@@ -1650,7 +2177,7 @@ type CreateConversationMessageStreamRequest struct {
 
 func (x *CreateConversationMessageStreamRequest) Reset() {
 	*x = CreateConversationMessageStreamRequest{}
-	mi := &file_chat_v2_chat_proto_msgTypes[28]
+	mi := &file_chat_v2_chat_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1662,7 +2189,7 @@ func (x *CreateConversationMessageStreamRequest) String() string {
 func (*CreateConversationMessageStreamRequest) ProtoMessage() {}
 
 func (x *CreateConversationMessageStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[28]
+	mi := &file_chat_v2_chat_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1675,7 +2202,7 @@ func (x *CreateConversationMessageStreamRequest) ProtoReflect() protoreflect.Mes
 
 // Deprecated: Use CreateConversationMessageStreamRequest.ProtoReflect.Descriptor instead.
 func (*CreateConversationMessageStreamRequest) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{28}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *CreateConversationMessageStreamRequest) GetProjectId() string {
@@ -1734,27 +2261,199 @@ func (x *CreateConversationMessageStreamRequest) GetCustomModelId() string {
 	return ""
 }
 
-// Response for streaming a message within an existing conversation
-type CreateConversationMessageStreamResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to ResponsePayload:
-	//
-	//	*CreateConversationMessageStreamResponse_StreamInitialization
-	//	*CreateConversationMessageStreamResponse_StreamPartBegin
-	//	*CreateConversationMessageStreamResponse_MessageChunk
-	//	*CreateConversationMessageStreamResponse_IncompleteIndicator
-	//	*CreateConversationMessageStreamResponse_StreamPartEnd
-	//	*CreateConversationMessageStreamResponse_StreamFinalization
+type RegenerateConversationMessageStreamRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	MessageId      string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // Any message of the user turn to regenerate
+	ModelSlug      string                 `protobuf:"bytes,3,opt,name=model_slug,json=modelSlug,proto3" json:"model_slug,omitempty"`
+	CustomModelId  *string                `protobuf:"bytes,4,opt,name=custom_model_id,json=customModelId,proto3,oneof" json:"custom_model_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RegenerateConversationMessageStreamRequest) Reset() {
+	*x = RegenerateConversationMessageStreamRequest{}
+	mi := &file_chat_v2_chat_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegenerateConversationMessageStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegenerateConversationMessageStreamRequest) ProtoMessage() {}
+
+func (x *RegenerateConversationMessageStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegenerateConversationMessageStreamRequest.ProtoReflect.Descriptor instead.
+func (*RegenerateConversationMessageStreamRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RegenerateConversationMessageStreamRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *RegenerateConversationMessageStreamRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *RegenerateConversationMessageStreamRequest) GetModelSlug() string {
+	if x != nil {
+		return x.ModelSlug
+	}
+	return ""
+}
+
+func (x *RegenerateConversationMessageStreamRequest) GetCustomModelId() string {
+	if x != nil && x.CustomModelId != nil {
+		return *x.CustomModelId
+	}
+	return ""
+}
+
+// The branch shares the history before message_id, which must be a user message.
+type EditConversationMessageStreamRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId   string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	MessageId        string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ModelSlug        string                 `protobuf:"bytes,3,opt,name=model_slug,json=modelSlug,proto3" json:"model_slug,omitempty"`
+	UserMessage      string                 `protobuf:"bytes,4,opt,name=user_message,json=userMessage,proto3" json:"user_message,omitempty"`
+	UserSelectedText *string                `protobuf:"bytes,5,opt,name=user_selected_text,json=userSelectedText,proto3,oneof" json:"user_selected_text,omitempty"`
+	ConversationType *ConversationType      `protobuf:"varint,6,opt,name=conversation_type,json=conversationType,proto3,enum=chat.v2.ConversationType,oneof" json:"conversation_type,omitempty"`
+	Surrounding      *string                `protobuf:"bytes,7,opt,name=surrounding,proto3,oneof" json:"surrounding,omitempty"`
+	CustomModelId    *string                `protobuf:"bytes,8,opt,name=custom_model_id,json=customModelId,proto3,oneof" json:"custom_model_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *EditConversationMessageStreamRequest) Reset() {
+	*x = EditConversationMessageStreamRequest{}
+	mi := &file_chat_v2_chat_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditConversationMessageStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditConversationMessageStreamRequest) ProtoMessage() {}
+
+func (x *EditConversationMessageStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditConversationMessageStreamRequest.ProtoReflect.Descriptor instead.
+func (*EditConversationMessageStreamRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *EditConversationMessageStreamRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *EditConversationMessageStreamRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *EditConversationMessageStreamRequest) GetModelSlug() string {
+	if x != nil {
+		return x.ModelSlug
+	}
+	return ""
+}
+
+func (x *EditConversationMessageStreamRequest) GetUserMessage() string {
+	if x != nil {
+		return x.UserMessage
+	}
+	return ""
+}
+
+func (x *EditConversationMessageStreamRequest) GetUserSelectedText() string {
+	if x != nil && x.UserSelectedText != nil {
+		return *x.UserSelectedText
+	}
+	return ""
+}
+
+func (x *EditConversationMessageStreamRequest) GetConversationType() ConversationType {
+	if x != nil && x.ConversationType != nil {
+		return *x.ConversationType
+	}
+	return ConversationType_CONVERSATION_TYPE_UNSPECIFIED
+}
+
+func (x *EditConversationMessageStreamRequest) GetSurrounding() string {
+	if x != nil && x.Surrounding != nil {
+		return *x.Surrounding
+	}
+	return ""
+}
+
+func (x *EditConversationMessageStreamRequest) GetCustomModelId() string {
+	if x != nil && x.CustomModelId != nil {
+		return *x.CustomModelId
+	}
+	return ""
+}
+
+// Response for streaming a message within an existing conversation
+type CreateConversationMessageStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to ResponsePayload:
+	//
+	//	*CreateConversationMessageStreamResponse_StreamInitialization
+	//	*CreateConversationMessageStreamResponse_StreamPartBegin
+	//	*CreateConversationMessageStreamResponse_MessageChunk
+	//	*CreateConversationMessageStreamResponse_IncompleteIndicator
+	//	*CreateConversationMessageStreamResponse_StreamPartEnd
+	//	*CreateConversationMessageStreamResponse_StreamFinalization
 	//	*CreateConversationMessageStreamResponse_StreamError
 	//	*CreateConversationMessageStreamResponse_ReasoningChunk
 	ResponsePayload isCreateConversationMessageStreamResponse_ResponsePayload `protobuf_oneof:"response_payload"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Starts from 1 and increases by 1 for each event of a response,
+	// used to resume the stream with ResumeConversationStream.
+	Sequence      int64 `protobuf:"varint,9,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateConversationMessageStreamResponse) Reset() {
 	*x = CreateConversationMessageStreamResponse{}
-	mi := &file_chat_v2_chat_proto_msgTypes[29]
+	mi := &file_chat_v2_chat_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1766,7 +2465,7 @@ func (x *CreateConversationMessageStreamResponse) String() string {
 func (*CreateConversationMessageStreamResponse) ProtoMessage() {}
 
 func (x *CreateConversationMessageStreamResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[29]
+	mi := &file_chat_v2_chat_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1779,7 +2478,7 @@ func (x *CreateConversationMessageStreamResponse) ProtoReflect() protoreflect.Me
 
 // Deprecated: Use CreateConversationMessageStreamResponse.ProtoReflect.Descriptor instead.
 func (*CreateConversationMessageStreamResponse) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{29}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *CreateConversationMessageStreamResponse) GetResponsePayload() isCreateConversationMessageStreamResponse_ResponsePayload {
@@ -1861,6 +2560,13 @@ func (x *CreateConversationMessageStreamResponse) GetReasoningChunk() *Reasoning
 	return nil
 }
 
+func (x *CreateConversationMessageStreamResponse) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
 type isCreateConversationMessageStreamResponse_ResponsePayload interface {
 	isCreateConversationMessageStreamResponse_ResponsePayload()
 }
@@ -1921,6 +2627,146 @@ func (*CreateConversationMessageStreamResponse_StreamError) isCreateConversation
 func (*CreateConversationMessageStreamResponse_ReasoningChunk) isCreateConversationMessageStreamResponse_ResponsePayload() {
 }
 
+type CancelConversationMessageRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CancelConversationMessageRequest) Reset() {
+	*x = CancelConversationMessageRequest{}
+	mi := &file_chat_v2_chat_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelConversationMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelConversationMessageRequest) ProtoMessage() {}
+
+func (x *CancelConversationMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelConversationMessageRequest.ProtoReflect.Descriptor instead.
+func (*CancelConversationMessageRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *CancelConversationMessageRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+type CancelConversationMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conversation  *Conversation          `protobuf:"bytes,1,opt,name=conversation,proto3" json:"conversation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelConversationMessageResponse) Reset() {
+	*x = CancelConversationMessageResponse{}
+	mi := &file_chat_v2_chat_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelConversationMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelConversationMessageResponse) ProtoMessage() {}
+
+func (x *CancelConversationMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelConversationMessageResponse.ProtoReflect.Descriptor instead.
+func (*CancelConversationMessageResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *CancelConversationMessageResponse) GetConversation() *Conversation {
+	if x != nil {
+		return x.Conversation
+	}
+	return nil
+}
+
+type ResumeConversationStreamRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	FromSequence   int64                  `protobuf:"varint,2,opt,name=from_sequence,json=fromSequence,proto3" json:"from_sequence,omitempty"` // The first event to replay, 0 replays all events
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ResumeConversationStreamRequest) Reset() {
+	*x = ResumeConversationStreamRequest{}
+	mi := &file_chat_v2_chat_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeConversationStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeConversationStreamRequest) ProtoMessage() {}
+
+func (x *ResumeConversationStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeConversationStreamRequest.ProtoReflect.Descriptor instead.
+func (*ResumeConversationStreamRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ResumeConversationStreamRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ResumeConversationStreamRequest) GetFromSequence() int64 {
+	if x != nil {
+		return x.FromSequence
+	}
+	return 0
+}
+
 // Request to get citation keys suggestion based on project bibliography
 type GetCitationKeysRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1932,7 +2778,7 @@ type GetCitationKeysRequest struct {
 
 func (x *GetCitationKeysRequest) Reset() {
 	*x = GetCitationKeysRequest{}
-	mi := &file_chat_v2_chat_proto_msgTypes[30]
+	mi := &file_chat_v2_chat_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1944,7 +2790,7 @@ func (x *GetCitationKeysRequest) String() string {
 func (*GetCitationKeysRequest) ProtoMessage() {}
 
 func (x *GetCitationKeysRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[30]
+	mi := &file_chat_v2_chat_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1957,7 +2803,7 @@ func (x *GetCitationKeysRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCitationKeysRequest.ProtoReflect.Descriptor instead.
 func (*GetCitationKeysRequest) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{30}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *GetCitationKeysRequest) GetSentence() string {
@@ -1984,7 +2830,7 @@ type GetCitationKeysResponse struct {
 
 func (x *GetCitationKeysResponse) Reset() {
 	*x = GetCitationKeysResponse{}
-	mi := &file_chat_v2_chat_proto_msgTypes[31]
+	mi := &file_chat_v2_chat_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1996,7 +2842,7 @@ func (x *GetCitationKeysResponse) String() string {
 func (*GetCitationKeysResponse) ProtoMessage() {}
 
 func (x *GetCitationKeysResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v2_chat_proto_msgTypes[31]
+	mi := &file_chat_v2_chat_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2009,7 +2855,7 @@ func (x *GetCitationKeysResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCitationKeysResponse.ProtoReflect.Descriptor instead.
 func (*GetCitationKeysResponse) Descriptor() ([]byte, []int) {
-	return file_chat_v2_chat_proto_rawDescGZIP(), []int{31}
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *GetCitationKeysResponse) GetCitationKeys() []string {
@@ -2019,6 +2865,230 @@ func (x *GetCitationKeysResponse) GetCitationKeys() []string {
 	return nil
 }
 
+type AcceptProposedEditRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	EditId         string                 `protobuf:"bytes,2,opt,name=edit_id,json=editId,proto3" json:"edit_id,omitempty"`
+	ProjectId      string                 `protobuf:"bytes,3,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	MessageId      string                 `protobuf:"bytes,4,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // The message of the proposed edit
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AcceptProposedEditRequest) Reset() {
+	*x = AcceptProposedEditRequest{}
+	mi := &file_chat_v2_chat_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptProposedEditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptProposedEditRequest) ProtoMessage() {}
+
+func (x *AcceptProposedEditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptProposedEditRequest.ProtoReflect.Descriptor instead.
+func (*AcceptProposedEditRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *AcceptProposedEditRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *AcceptProposedEditRequest) GetEditId() string {
+	if x != nil {
+		return x.EditId
+	}
+	return ""
+}
+
+func (x *AcceptProposedEditRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *AcceptProposedEditRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type AcceptProposedEditResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	ProposedEdit  *MessageTypeProposedEdit `protobuf:"bytes,1,opt,name=proposed_edit,json=proposedEdit,proto3" json:"proposed_edit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptProposedEditResponse) Reset() {
+	*x = AcceptProposedEditResponse{}
+	mi := &file_chat_v2_chat_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptProposedEditResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptProposedEditResponse) ProtoMessage() {}
+
+func (x *AcceptProposedEditResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptProposedEditResponse.ProtoReflect.Descriptor instead.
+func (*AcceptProposedEditResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AcceptProposedEditResponse) GetProposedEdit() *MessageTypeProposedEdit {
+	if x != nil {
+		return x.ProposedEdit
+	}
+	return nil
+}
+
+type RejectProposedEditRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	EditId         string                 `protobuf:"bytes,2,opt,name=edit_id,json=editId,proto3" json:"edit_id,omitempty"`
+	ProjectId      string                 `protobuf:"bytes,3,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	MessageId      string                 `protobuf:"bytes,4,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // The message of the proposed edit
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RejectProposedEditRequest) Reset() {
+	*x = RejectProposedEditRequest{}
+	mi := &file_chat_v2_chat_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectProposedEditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectProposedEditRequest) ProtoMessage() {}
+
+func (x *RejectProposedEditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectProposedEditRequest.ProtoReflect.Descriptor instead.
+func (*RejectProposedEditRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RejectProposedEditRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *RejectProposedEditRequest) GetEditId() string {
+	if x != nil {
+		return x.EditId
+	}
+	return ""
+}
+
+func (x *RejectProposedEditRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *RejectProposedEditRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type RejectProposedEditResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	ProposedEdit  *MessageTypeProposedEdit `protobuf:"bytes,1,opt,name=proposed_edit,json=proposedEdit,proto3" json:"proposed_edit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectProposedEditResponse) Reset() {
+	*x = RejectProposedEditResponse{}
+	mi := &file_chat_v2_chat_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectProposedEditResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectProposedEditResponse) ProtoMessage() {}
+
+func (x *RejectProposedEditResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v2_chat_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectProposedEditResponse.ProtoReflect.Descriptor instead.
+func (*RejectProposedEditResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v2_chat_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RejectProposedEditResponse) GetProposedEdit() *MessageTypeProposedEdit {
+	if x != nil {
+		return x.ProposedEdit
+	}
+	return nil
+}
+
 var File_chat_v2_chat_proto protoreflect.FileDescriptor
 
 const file_chat_v2_chat_proto_rawDesc = "" +
@@ -2040,34 +3110,69 @@ const file_chat_v2_chat_proto_rawDesc = "" +
 	"model_slug\x18\x02 \x01(\tR\tmodelSlug\x12!\n" +
 	"\treasoning\x18\x03 \x01(\tH\x00R\treasoning\x88\x01\x01B\f\n" +
 	"\n" +
-	"_reasoning\"\x9e\x01\n" +
+	"_reasoning\"\xc7\x01\n" +
 	"\x0fMessageTypeUser\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x12(\n" +
 	"\rselected_text\x18\x02 \x01(\tH\x00R\fselectedText\x88\x01\x01\x12%\n" +
-	"\vsurrounding\x18\a \x01(\tH\x01R\vsurrounding\x88\x01\x01B\x10\n" +
+	"\vsurrounding\x18\a \x01(\tH\x01R\vsurrounding\x88\x01\x01\x12'\n" +
+	"\x0fproject_version\x18\b \x01(\tR\x0eprojectVersionB\x10\n" +
 	"\x0e_selected_textB\x0e\n" +
-	"\f_surrounding\"6\n" +
+	"\f_surrounding\"\xdc\x01\n" +
+	"\x15MessageTypeCompaction\x12\x18\n" +
+	"\asummary\x18\x01 \x01(\tR\asummary\x12/\n" +
+	"\x13summarized_messages\x18\x02 \x01(\x05R\x12summarizedMessages\x120\n" +
+	"\x14dropped_tool_outputs\x18\x03 \x01(\x05R\x12droppedToolOutputs\x12#\n" +
+	"\rtokens_before\x18\x04 \x01(\x03R\ftokensBefore\x12!\n" +
+	"\ftokens_after\x18\x05 \x01(\x03R\vtokensAfter\"q\n" +
+	"\x19MessageTypeProjectRefresh\x12!\n" +
+	"\ffrom_version\x18\x01 \x01(\tR\vfromVersion\x12\x1d\n" +
+	"\n" +
+	"to_version\x18\x02 \x01(\tR\ttoVersion\x12\x12\n" +
+	"\x04diff\x18\x03 \x01(\tR\x04diff\"\xa9\x02\n" +
+	"\x17MessageTypeProposedEdit\x12\x17\n" +
+	"\aedit_id\x18\x01 \x01(\tR\x06editId\x12\x15\n" +
+	"\x06doc_id\x18\x02 \x01(\tR\x05docId\x12\x1b\n" +
+	"\tfile_path\x18\x03 \x01(\tR\bfilePath\x12\x1f\n" +
+	"\vdoc_version\x18\x04 \x01(\x05R\n" +
+	"docVersion\x12\x1d\n" +
+	"\n" +
+	"start_line\x18\x05 \x01(\x05R\tstartLine\x12\x1b\n" +
+	"\told_lines\x18\x06 \x03(\tR\boldLines\x12\x1b\n" +
+	"\tnew_lines\x18\a \x03(\tR\bnewLines\x12\x12\n" +
+	"\x04diff\x18\b \x01(\tR\x04diff\x123\n" +
+	"\x06status\x18\t \x01(\x0e2\x1b.chat.v2.ProposedEditStatusR\x06status\"6\n" +
 	"\x12MessageTypeUnknown\x12 \n" +
-	"\vdescription\x18\x01 \x01(\tR\vdescription\"\xaa\x03\n" +
+	"\vdescription\x18\x01 \x01(\tR\vdescription\"\x84\x05\n" +
 	"\x0eMessagePayload\x124\n" +
 	"\x06system\x18\x01 \x01(\v2\x1a.chat.v2.MessageTypeSystemH\x00R\x06system\x12.\n" +
 	"\x04user\x18\x02 \x01(\v2\x18.chat.v2.MessageTypeUserH\x00R\x04user\x12=\n" +
 	"\tassistant\x18\x03 \x01(\v2\x1d.chat.v2.MessageTypeAssistantH\x00R\tassistant\x12m\n" +
 	"\x1btool_call_prepare_arguments\x18\x04 \x01(\v2,.chat.v2.MessageTypeToolCallPrepareArgumentsH\x00R\x18toolCallPrepareArguments\x12;\n" +
 	"\ttool_call\x18\x05 \x01(\v2\x1c.chat.v2.MessageTypeToolCallH\x00R\btoolCall\x127\n" +
-	"\aunknown\x18\x06 \x01(\v2\x1b.chat.v2.MessageTypeUnknownH\x00R\aunknownB\x0e\n" +
+	"\aunknown\x18\x06 \x01(\v2\x1b.chat.v2.MessageTypeUnknownH\x00R\aunknown\x12@\n" +
+	"\n" +
+	"compaction\x18\a \x01(\v2\x1e.chat.v2.MessageTypeCompactionH\x00R\n" +
+	"compaction\x12M\n" +
+	"\x0fproject_refresh\x18\b \x01(\v2\".chat.v2.MessageTypeProjectRefreshH\x00R\x0eprojectRefresh\x12G\n" +
+	"\rproposed_edit\x18\t \x01(\v2 .chat.v2.MessageTypeProposedEditH\x00R\fproposedEditB\x0e\n" +
 	"\fmessage_type\"y\n" +
 	"\aMessage\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x121\n" +
 	"\apayload\x18\x02 \x01(\v2\x17.chat.v2.MessagePayloadR\apayload\x12\x1c\n" +
-	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\"\x81\x01\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\"j\n" +
+	"\x13MessageAlternatives\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x124\n" +
+	"\falternatives\x18\x02 \x03(\v2\x10.chat.v2.MessageR\falternatives\"\xe0\x01\n" +
 	"\fConversation\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1d\n" +
 	"\n" +
 	"model_slug\x18\x03 \x01(\tR\tmodelSlug\x12,\n" +
-	"\bmessages\x18\x04 \x03(\v2\x10.chat.v2.MessageR\bmessages\"M\n" +
+	"\bmessages\x18\x04 \x03(\v2\x10.chat.v2.MessageR\bmessages\x12\x1b\n" +
+	"\tbranch_id\x18\x05 \x01(\tR\bbranchId\x12@\n" +
+	"\falternatives\x18\x06 \x03(\v2\x1c.chat.v2.MessageAlternativesR\falternatives\"M\n" +
 	"\x18ListConversationsRequest\x12\"\n" +
 	"\n" +
 	"project_id\x18\x01 \x01(\tH\x00R\tprojectId\x88\x01\x01B\r\n" +
@@ -2082,6 +3187,12 @@ const file_chat_v2_chat_proto_rawDesc = "" +
 	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x14\n" +
 	"\x05title\x18\x02 \x01(\tR\x05title\"W\n" +
 	"\x1aUpdateConversationResponse\x129\n" +
+	"\fconversation\x18\x01 \x01(\v2\x15.chat.v2.ConversationR\fconversation\"i\n" +
+	"\x1fSwitchConversationBranchRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"]\n" +
+	" SwitchConversationBranchResponse\x129\n" +
 	"\fconversation\x18\x01 \x01(\v2\x15.chat.v2.ConversationR\fconversation\"D\n" +
 	"\x19DeleteConversationRequest\x12'\n" +
 	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\"\x1c\n" +
@@ -2148,7 +3259,30 @@ const file_chat_v2_chat_proto_rawDesc = "" +
 	"\x13_user_selected_textB\x14\n" +
 	"\x12_conversation_typeB\x0e\n" +
 	"\f_surroundingB\x12\n" +
-	"\x10_custom_model_id\"\xfd\x04\n" +
+	"\x10_custom_model_id\"\xd4\x01\n" +
+	"*RegenerateConversationMessageStreamRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x1d\n" +
+	"\n" +
+	"model_slug\x18\x03 \x01(\tR\tmodelSlug\x12+\n" +
+	"\x0fcustom_model_id\x18\x04 \x01(\tH\x00R\rcustomModelId\x88\x01\x01B\x12\n" +
+	"\x10_custom_model_id\"\xd5\x03\n" +
+	"$EditConversationMessageStreamRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x1d\n" +
+	"\n" +
+	"model_slug\x18\x03 \x01(\tR\tmodelSlug\x12!\n" +
+	"\fuser_message\x18\x04 \x01(\tR\vuserMessage\x121\n" +
+	"\x12user_selected_text\x18\x05 \x01(\tH\x00R\x10userSelectedText\x88\x01\x01\x12K\n" +
+	"\x11conversation_type\x18\x06 \x01(\x0e2\x19.chat.v2.ConversationTypeH\x01R\x10conversationType\x88\x01\x01\x12%\n" +
+	"\vsurrounding\x18\a \x01(\tH\x02R\vsurrounding\x88\x01\x01\x12+\n" +
+	"\x0fcustom_model_id\x18\b \x01(\tH\x03R\rcustomModelId\x88\x01\x01B\x15\n" +
+	"\x13_user_selected_textB\x14\n" +
+	"\x12_conversation_typeB\x0e\n" +
+	"\f_surroundingB\x12\n" +
+	"\x10_custom_model_id\"\x99\x05\n" +
 	"'CreateConversationMessageStreamResponse\x12T\n" +
 	"\x15stream_initialization\x18\x01 \x01(\v2\x1d.chat.v2.StreamInitializationH\x00R\x14streamInitialization\x12F\n" +
 	"\x11stream_part_begin\x18\x02 \x01(\v2\x18.chat.v2.StreamPartBeginH\x00R\x0fstreamPartBegin\x12<\n" +
@@ -2157,25 +3291,63 @@ const file_chat_v2_chat_proto_rawDesc = "" +
 	"\x0fstream_part_end\x18\x05 \x01(\v2\x16.chat.v2.StreamPartEndH\x00R\rstreamPartEnd\x12N\n" +
 	"\x13stream_finalization\x18\x06 \x01(\v2\x1b.chat.v2.StreamFinalizationH\x00R\x12streamFinalization\x129\n" +
 	"\fstream_error\x18\a \x01(\v2\x14.chat.v2.StreamErrorH\x00R\vstreamError\x12B\n" +
-	"\x0freasoning_chunk\x18\b \x01(\v2\x17.chat.v2.ReasoningChunkH\x00R\x0ereasoningChunkB\x12\n" +
-	"\x10response_payload\"S\n" +
+	"\x0freasoning_chunk\x18\b \x01(\v2\x17.chat.v2.ReasoningChunkH\x00R\x0ereasoningChunk\x12\x1a\n" +
+	"\bsequence\x18\t \x01(\x03R\bsequenceB\x12\n" +
+	"\x10response_payload\"K\n" +
+	" CancelConversationMessageRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\"^\n" +
+	"!CancelConversationMessageResponse\x129\n" +
+	"\fconversation\x18\x01 \x01(\v2\x15.chat.v2.ConversationR\fconversation\"o\n" +
+	"\x1fResumeConversationStreamRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12#\n" +
+	"\rfrom_sequence\x18\x02 \x01(\x03R\ffromSequence\"S\n" +
 	"\x16GetCitationKeysRequest\x12\x1a\n" +
 	"\bsentence\x18\x01 \x01(\tR\bsentence\x12\x1d\n" +
 	"\n" +
 	"project_id\x18\x02 \x01(\tR\tprojectId\">\n" +
 	"\x17GetCitationKeysResponse\x12#\n" +
-	"\rcitation_keys\x18\x01 \x03(\tR\fcitationKeys*R\n" +
+	"\rcitation_keys\x18\x01 \x03(\tR\fcitationKeys\"\x9b\x01\n" +
+	"\x19AcceptProposedEditRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x17\n" +
+	"\aedit_id\x18\x02 \x01(\tR\x06editId\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x03 \x01(\tR\tprojectId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x04 \x01(\tR\tmessageId\"c\n" +
+	"\x1aAcceptProposedEditResponse\x12E\n" +
+	"\rproposed_edit\x18\x01 \x01(\v2 .chat.v2.MessageTypeProposedEditR\fproposedEdit\"\x9b\x01\n" +
+	"\x19RejectProposedEditRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x17\n" +
+	"\aedit_id\x18\x02 \x01(\tR\x06editId\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x03 \x01(\tR\tprojectId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x04 \x01(\tR\tmessageId\"c\n" +
+	"\x1aRejectProposedEditResponse\x12E\n" +
+	"\rproposed_edit\x18\x01 \x01(\v2 .chat.v2.MessageTypeProposedEditR\fproposedEdit*\xa2\x01\n" +
+	"\x12ProposedEditStatus\x12$\n" +
+	" PROPOSED_EDIT_STATUS_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cPROPOSED_EDIT_STATUS_PENDING\x10\x01\x12!\n" +
+	"\x1dPROPOSED_EDIT_STATUS_ACCEPTED\x10\x02\x12!\n" +
+	"\x1dPROPOSED_EDIT_STATUS_REJECTED\x10\x03*R\n" +
 	"\x10ConversationType\x12!\n" +
 	"\x1dCONVERSATION_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
-	"\x17CONVERSATION_TYPE_DEBUG\x10\x012\xa7\b\n" +
+	"\x17CONVERSATION_TYPE_DEBUG\x10\x012\xd2\x13\n" +
 	"\vChatService\x12\x83\x01\n" +
 	"\x11ListConversations\x12!.chat.v2.ListConversationsRequest\x1a\".chat.v2.ListConversationsResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/_pd/api/v2/chats/conversations\x12\x8f\x01\n" +
 	"\x0fGetConversation\x12\x1f.chat.v2.GetConversationRequest\x1a .chat.v2.GetConversationResponse\"9\x82\xd3\xe4\x93\x023\x121/_pd/api/v2/chats/conversations/{conversation_id}\x12\xc2\x01\n" +
-	"\x1fCreateConversationMessageStream\x12/.chat.v2.CreateConversationMessageStreamRequest\x1a0.chat.v2.CreateConversationMessageStreamResponse\":\x82\xd3\xe4\x93\x024:\x01*\"//_pd/api/v2/chats/conversations/messages/stream0\x01\x12\x9b\x01\n" +
+	"\x1fCreateConversationMessageStream\x12/.chat.v2.CreateConversationMessageStreamRequest\x1a0.chat.v2.CreateConversationMessageStreamResponse\":\x82\xd3\xe4\x93\x024:\x01*\"//_pd/api/v2/chats/conversations/messages/stream0\x01\x12\xf4\x01\n" +
+	"#RegenerateConversationMessageStream\x123.chat.v2.RegenerateConversationMessageStreamRequest\x1a0.chat.v2.CreateConversationMessageStreamResponse\"d\x82\xd3\xe4\x93\x02^:\x01*\"Y/_pd/api/v2/chats/conversations/{conversation_id}/messages/{message_id}/regenerate/stream0\x01\x12\xe2\x01\n" +
+	"\x1dEditConversationMessageStream\x12-.chat.v2.EditConversationMessageStreamRequest\x1a0.chat.v2.CreateConversationMessageStreamResponse\"^\x82\xd3\xe4\x93\x02X:\x01*\"S/_pd/api/v2/chats/conversations/{conversation_id}/messages/{message_id}/edit/stream0\x01\x12\xc0\x01\n" +
+	"\x19CancelConversationMessage\x12).chat.v2.CancelConversationMessageRequest\x1a*.chat.v2.CancelConversationMessageResponse\"L\x82\xd3\xe4\x93\x02F:\x01*\"A/_pd/api/v2/chats/conversations/{conversation_id}/messages/cancel\x12\xcd\x01\n" +
+	"\x18ResumeConversationStream\x12(.chat.v2.ResumeConversationStreamRequest\x1a0.chat.v2.CreateConversationMessageStreamResponse\"S\x82\xd3\xe4\x93\x02M:\x01*\"H/_pd/api/v2/chats/conversations/{conversation_id}/messages/resume/stream0\x01\x12\xbd\x01\n" +
+	"\x18SwitchConversationBranch\x12(.chat.v2.SwitchConversationBranchRequest\x1a).chat.v2.SwitchConversationBranchResponse\"L\x82\xd3\xe4\x93\x02F:\x01*\"A/_pd/api/v2/chats/conversations/{conversation_id}/branches/switch\x12\x9b\x01\n" +
 	"\x12UpdateConversation\x12\".chat.v2.UpdateConversationRequest\x1a#.chat.v2.UpdateConversationResponse\"<\x82\xd3\xe4\x93\x026:\x01*21/_pd/api/v2/chats/conversations/{conversation_id}\x12\x98\x01\n" +
 	"\x12DeleteConversation\x12\".chat.v2.DeleteConversationRequest\x1a#.chat.v2.DeleteConversationResponse\"9\x82\xd3\xe4\x93\x023*1/_pd/api/v2/chats/conversations/{conversation_id}\x12\x82\x01\n" +
 	"\x13ListSupportedModels\x12#.chat.v2.ListSupportedModelsRequest\x1a$.chat.v2.ListSupportedModelsResponse\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/_pd/api/v2/chats/models\x12}\n" +
-	"\x0fGetCitationKeys\x12\x1f.chat.v2.GetCitationKeysRequest\x1a .chat.v2.GetCitationKeysResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/_pd/api/v2/chats/citation-keysB\x7f\n" +
+	"\x0fGetCitationKeys\x12\x1f.chat.v2.GetCitationKeysRequest\x1a .chat.v2.GetCitationKeysResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/_pd/api/v2/chats/citation-keys\x12\xbb\x01\n" +
+	"\x12AcceptProposedEdit\x12\".chat.v2.AcceptProposedEditRequest\x1a#.chat.v2.AcceptProposedEditResponse\"\\\x82\xd3\xe4\x93\x02V:\x01*\"Q/_pd/api/v2/chats/conversations/{conversation_id}/proposed-edits/{edit_id}/accept\x12\xbb\x01\n" +
+	"\x12RejectProposedEdit\x12\".chat.v2.RejectProposedEditRequest\x1a#.chat.v2.RejectProposedEditResponse\"\\\x82\xd3\xe4\x93\x02V:\x01*\"Q/_pd/api/v2/chats/conversations/{conversation_id}/proposed-edits/{edit_id}/rejectB\x7f\n" +
 	"\vcom.chat.v2B\tChatProtoP\x01Z(paperdebugger/pkg/gen/api/chat/v2;chatv2\xa2\x02\x03CXX\xaa\x02\aChat.V2\xca\x02\aChat\\V2\xe2\x02\x13Chat\\V2\\GPBMetadata\xea\x02\bChat::V2b\x06proto3"
 
 var (
@@ -2190,86 +3362,127 @@ func file_chat_v2_chat_proto_rawDescGZIP() []byte {
 	return file_chat_v2_chat_proto_rawDescData
 }
 
-var file_chat_v2_chat_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_chat_v2_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_chat_v2_chat_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_chat_v2_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 47)
 var file_chat_v2_chat_proto_goTypes = []any{
-	(ConversationType)(0),                           // 0: chat.v2.ConversationType
-	(*MessageTypeToolCall)(nil),                     // 1: chat.v2.MessageTypeToolCall
-	(*MessageTypeToolCallPrepareArguments)(nil),     // 2: chat.v2.MessageTypeToolCallPrepareArguments
-	(*MessageTypeSystem)(nil),                       // 3: chat.v2.MessageTypeSystem
-	(*MessageTypeAssistant)(nil),                    // 4: chat.v2.MessageTypeAssistant
-	(*MessageTypeUser)(nil),                         // 5: chat.v2.MessageTypeUser
-	(*MessageTypeUnknown)(nil),                      // 6: chat.v2.MessageTypeUnknown
-	(*MessagePayload)(nil),                          // 7: chat.v2.MessagePayload
-	(*Message)(nil),                                 // 8: chat.v2.Message
-	(*Conversation)(nil),                            // 9: chat.v2.Conversation
-	(*ListConversationsRequest)(nil),                // 10: chat.v2.ListConversationsRequest
-	(*ListConversationsResponse)(nil),               // 11: chat.v2.ListConversationsResponse
-	(*GetConversationRequest)(nil),                  // 12: chat.v2.GetConversationRequest
-	(*GetConversationResponse)(nil),                 // 13: chat.v2.GetConversationResponse
-	(*UpdateConversationRequest)(nil),               // 14: chat.v2.UpdateConversationRequest
-	(*UpdateConversationResponse)(nil),              // 15: chat.v2.UpdateConversationResponse
-	(*DeleteConversationRequest)(nil),               // 16: chat.v2.DeleteConversationRequest
-	(*DeleteConversationResponse)(nil),              // 17: chat.v2.DeleteConversationResponse
-	(*SupportedModel)(nil),                          // 18: chat.v2.SupportedModel
-	(*ListSupportedModelsRequest)(nil),              // 19: chat.v2.ListSupportedModelsRequest
-	(*ListSupportedModelsResponse)(nil),             // 20: chat.v2.ListSupportedModelsResponse
-	(*StreamInitialization)(nil),                    // 21: chat.v2.StreamInitialization
-	(*StreamPartBegin)(nil),                         // 22: chat.v2.StreamPartBegin
-	(*MessageChunk)(nil),                            // 23: chat.v2.MessageChunk
-	(*ReasoningChunk)(nil),                          // 24: chat.v2.ReasoningChunk
-	(*IncompleteIndicator)(nil),                     // 25: chat.v2.IncompleteIndicator
-	(*StreamPartEnd)(nil),                           // 26: chat.v2.StreamPartEnd
-	(*StreamFinalization)(nil),                      // 27: chat.v2.StreamFinalization
-	(*StreamError)(nil),                             // 28: chat.v2.StreamError
-	(*CreateConversationMessageStreamRequest)(nil),  // 29: chat.v2.CreateConversationMessageStreamRequest
-	(*CreateConversationMessageStreamResponse)(nil), // 30: chat.v2.CreateConversationMessageStreamResponse
-	(*GetCitationKeysRequest)(nil),                  // 31: chat.v2.GetCitationKeysRequest
-	(*GetCitationKeysResponse)(nil),                 // 32: chat.v2.GetCitationKeysResponse
+	(ProposedEditStatus)(0),                            // 0: chat.v2.ProposedEditStatus
+	(ConversationType)(0),                              // 1: chat.v2.ConversationType
+	(*MessageTypeToolCall)(nil),                        // 2: chat.v2.MessageTypeToolCall
+	(*MessageTypeToolCallPrepareArguments)(nil),        // 3: chat.v2.MessageTypeToolCallPrepareArguments
+	(*MessageTypeSystem)(nil),                          // 4: chat.v2.MessageTypeSystem
+	(*MessageTypeAssistant)(nil),                       // 5: chat.v2.MessageTypeAssistant
+	(*MessageTypeUser)(nil),                            // 6: chat.v2.MessageTypeUser
+	(*MessageTypeCompaction)(nil),                      // 7: chat.v2.MessageTypeCompaction
+	(*MessageTypeProjectRefresh)(nil),                  // 8: chat.v2.MessageTypeProjectRefresh
+	(*MessageTypeProposedEdit)(nil),                    // 9: chat.v2.MessageTypeProposedEdit
+	(*MessageTypeUnknown)(nil),                         // 10: chat.v2.MessageTypeUnknown
+	(*MessagePayload)(nil),                             // 11: chat.v2.MessagePayload
+	(*Message)(nil),                                    // 12: chat.v2.Message
+	(*MessageAlternatives)(nil),                        // 13: chat.v2.MessageAlternatives
+	(*Conversation)(nil),                               // 14: chat.v2.Conversation
+	(*ListConversationsRequest)(nil),                   // 15: chat.v2.ListConversationsRequest
+	(*ListConversationsResponse)(nil),                  // 16: chat.v2.ListConversationsResponse
+	(*GetConversationRequest)(nil),                     // 17: chat.v2.GetConversationRequest
+	(*GetConversationResponse)(nil),                    // 18: chat.v2.GetConversationResponse
+	(*UpdateConversationRequest)(nil),                  // 19: chat.v2.UpdateConversationRequest
+	(*UpdateConversationResponse)(nil),                 // 20: chat.v2.UpdateConversationResponse
+	(*SwitchConversationBranchRequest)(nil),            // 21: chat.v2.SwitchConversationBranchRequest
+	(*SwitchConversationBranchResponse)(nil),           // 22: chat.v2.SwitchConversationBranchResponse
+	(*DeleteConversationRequest)(nil),                  // 23: chat.v2.DeleteConversationRequest
+	(*DeleteConversationResponse)(nil),                 // 24: chat.v2.DeleteConversationResponse
+	(*SupportedModel)(nil),                             // 25: chat.v2.SupportedModel
+	(*ListSupportedModelsRequest)(nil),                 // 26: chat.v2.ListSupportedModelsRequest
+	(*ListSupportedModelsResponse)(nil),                // 27: chat.v2.ListSupportedModelsResponse
+	(*StreamInitialization)(nil),                       // 28: chat.v2.StreamInitialization
+	(*StreamPartBegin)(nil),                            // 29: chat.v2.StreamPartBegin
+	(*MessageChunk)(nil),                               // 30: chat.v2.MessageChunk
+	(*ReasoningChunk)(nil),                             // 31: chat.v2.ReasoningChunk
+	(*IncompleteIndicator)(nil),                        // 32: chat.v2.IncompleteIndicator
+	(*StreamPartEnd)(nil),                              // 33: chat.v2.StreamPartEnd
+	(*StreamFinalization)(nil),                         // 34: chat.v2.StreamFinalization
+	(*StreamError)(nil),                                // 35: chat.v2.StreamError
+	(*CreateConversationMessageStreamRequest)(nil),     // 36: chat.v2.CreateConversationMessageStreamRequest
+	(*RegenerateConversationMessageStreamRequest)(nil), // 37: chat.v2.RegenerateConversationMessageStreamRequest
+	(*EditConversationMessageStreamRequest)(nil),       // 38: chat.v2.EditConversationMessageStreamRequest
+	(*CreateConversationMessageStreamResponse)(nil),    // 39: chat.v2.CreateConversationMessageStreamResponse
+	(*CancelConversationMessageRequest)(nil),           // 40: chat.v2.CancelConversationMessageRequest
+	(*CancelConversationMessageResponse)(nil),          // 41: chat.v2.CancelConversationMessageResponse
+	(*ResumeConversationStreamRequest)(nil),            // 42: chat.v2.ResumeConversationStreamRequest
+	(*GetCitationKeysRequest)(nil),                     // 43: chat.v2.GetCitationKeysRequest
+	(*GetCitationKeysResponse)(nil),                    // 44: chat.v2.GetCitationKeysResponse
+	(*AcceptProposedEditRequest)(nil),                  // 45: chat.v2.AcceptProposedEditRequest
+	(*AcceptProposedEditResponse)(nil),                 // 46: chat.v2.AcceptProposedEditResponse
+	(*RejectProposedEditRequest)(nil),                  // 47: chat.v2.RejectProposedEditRequest
+	(*RejectProposedEditResponse)(nil),                 // 48: chat.v2.RejectProposedEditResponse
 }
 var file_chat_v2_chat_proto_depIdxs = []int32{
-	3,  // 0: chat.v2.MessagePayload.system:type_name -> chat.v2.MessageTypeSystem
-	5,  // 1: chat.v2.MessagePayload.user:type_name -> chat.v2.MessageTypeUser
-	4,  // 2: chat.v2.MessagePayload.assistant:type_name -> chat.v2.MessageTypeAssistant
-	2,  // 3: chat.v2.MessagePayload.tool_call_prepare_arguments:type_name -> chat.v2.MessageTypeToolCallPrepareArguments
-	1,  // 4: chat.v2.MessagePayload.tool_call:type_name -> chat.v2.MessageTypeToolCall
-	6,  // 5: chat.v2.MessagePayload.unknown:type_name -> chat.v2.MessageTypeUnknown
-	7,  // 6: chat.v2.Message.payload:type_name -> chat.v2.MessagePayload
-	8,  // 7: chat.v2.Conversation.messages:type_name -> chat.v2.Message
-	9,  // 8: chat.v2.ListConversationsResponse.conversations:type_name -> chat.v2.Conversation
-	9,  // 9: chat.v2.GetConversationResponse.conversation:type_name -> chat.v2.Conversation
-	9,  // 10: chat.v2.UpdateConversationResponse.conversation:type_name -> chat.v2.Conversation
-	18, // 11: chat.v2.ListSupportedModelsResponse.models:type_name -> chat.v2.SupportedModel
-	7,  // 12: chat.v2.StreamPartBegin.payload:type_name -> chat.v2.MessagePayload
-	7,  // 13: chat.v2.StreamPartEnd.payload:type_name -> chat.v2.MessagePayload
-	0,  // 14: chat.v2.CreateConversationMessageStreamRequest.conversation_type:type_name -> chat.v2.ConversationType
-	21, // 15: chat.v2.CreateConversationMessageStreamResponse.stream_initialization:type_name -> chat.v2.StreamInitialization
-	22, // 16: chat.v2.CreateConversationMessageStreamResponse.stream_part_begin:type_name -> chat.v2.StreamPartBegin
-	23, // 17: chat.v2.CreateConversationMessageStreamResponse.message_chunk:type_name -> chat.v2.MessageChunk
-	25, // 18: chat.v2.CreateConversationMessageStreamResponse.incomplete_indicator:type_name -> chat.v2.IncompleteIndicator
-	26, // 19: chat.v2.CreateConversationMessageStreamResponse.stream_part_end:type_name -> chat.v2.StreamPartEnd
-	27, // 20: chat.v2.CreateConversationMessageStreamResponse.stream_finalization:type_name -> chat.v2.StreamFinalization
-	28, // 21: chat.v2.CreateConversationMessageStreamResponse.stream_error:type_name -> chat.v2.StreamError
-	24, // 22: chat.v2.CreateConversationMessageStreamResponse.reasoning_chunk:type_name -> chat.v2.ReasoningChunk
-	10, // 23: chat.v2.ChatService.ListConversations:input_type -> chat.v2.ListConversationsRequest
-	12, // 24: chat.v2.ChatService.GetConversation:input_type -> chat.v2.GetConversationRequest
-	29, // 25: chat.v2.ChatService.CreateConversationMessageStream:input_type -> chat.v2.CreateConversationMessageStreamRequest
-	14, // 26: chat.v2.ChatService.UpdateConversation:input_type -> chat.v2.UpdateConversationRequest
-	16, // 27: chat.v2.ChatService.DeleteConversation:input_type -> chat.v2.DeleteConversationRequest
-	19, // 28: chat.v2.ChatService.ListSupportedModels:input_type -> chat.v2.ListSupportedModelsRequest
-	31, // 29: chat.v2.ChatService.GetCitationKeys:input_type -> chat.v2.GetCitationKeysRequest
-	11, // 30: chat.v2.ChatService.ListConversations:output_type -> chat.v2.ListConversationsResponse
-	13, // 31: chat.v2.ChatService.GetConversation:output_type -> chat.v2.GetConversationResponse
-	30, // 32: chat.v2.ChatService.CreateConversationMessageStream:output_type -> chat.v2.CreateConversationMessageStreamResponse
-	15, // 33: chat.v2.ChatService.UpdateConversation:output_type -> chat.v2.UpdateConversationResponse
-	17, // 34: chat.v2.ChatService.DeleteConversation:output_type -> chat.v2.DeleteConversationResponse
-	20, // 35: chat.v2.ChatService.ListSupportedModels:output_type -> chat.v2.ListSupportedModelsResponse
-	32, // 36: chat.v2.ChatService.GetCitationKeys:output_type -> chat.v2.GetCitationKeysResponse
-	30, // [30:37] is the sub-list for method output_type
-	23, // [23:30] is the sub-list for method input_type
-	23, // [23:23] is the sub-list for extension type_name
-	23, // [23:23] is the sub-list for extension extendee
-	0,  // [0:23] is the sub-list for field type_name
+	0,  // 0: chat.v2.MessageTypeProposedEdit.status:type_name -> chat.v2.ProposedEditStatus
+	4,  // 1: chat.v2.MessagePayload.system:type_name -> chat.v2.MessageTypeSystem
+	6,  // 2: chat.v2.MessagePayload.user:type_name -> chat.v2.MessageTypeUser
+	5,  // 3: chat.v2.MessagePayload.assistant:type_name -> chat.v2.MessageTypeAssistant
+	3,  // 4: chat.v2.MessagePayload.tool_call_prepare_arguments:type_name -> chat.v2.MessageTypeToolCallPrepareArguments
+	2,  // 5: chat.v2.MessagePayload.tool_call:type_name -> chat.v2.MessageTypeToolCall
+	10, // 6: chat.v2.MessagePayload.unknown:type_name -> chat.v2.MessageTypeUnknown
+	7,  // 7: chat.v2.MessagePayload.compaction:type_name -> chat.v2.MessageTypeCompaction
+	8,  // 8: chat.v2.MessagePayload.project_refresh:type_name -> chat.v2.MessageTypeProjectRefresh
+	9,  // 9: chat.v2.MessagePayload.proposed_edit:type_name -> chat.v2.MessageTypeProposedEdit
+	11, // 10: chat.v2.Message.payload:type_name -> chat.v2.MessagePayload
+	12, // 11: chat.v2.MessageAlternatives.alternatives:type_name -> chat.v2.Message
+	12, // 12: chat.v2.Conversation.messages:type_name -> chat.v2.Message
+	13, // 13: chat.v2.Conversation.alternatives:type_name -> chat.v2.MessageAlternatives
+	14, // 14: chat.v2.ListConversationsResponse.conversations:type_name -> chat.v2.Conversation
+	14, // 15: chat.v2.GetConversationResponse.conversation:type_name -> chat.v2.Conversation
+	14, // 16: chat.v2.UpdateConversationResponse.conversation:type_name -> chat.v2.Conversation
+	14, // 17: chat.v2.SwitchConversationBranchResponse.conversation:type_name -> chat.v2.Conversation
+	25, // 18: chat.v2.ListSupportedModelsResponse.models:type_name -> chat.v2.SupportedModel
+	11, // 19: chat.v2.StreamPartBegin.payload:type_name -> chat.v2.MessagePayload
+	11, // 20: chat.v2.StreamPartEnd.payload:type_name -> chat.v2.MessagePayload
+	1,  // 21: chat.v2.CreateConversationMessageStreamRequest.conversation_type:type_name -> chat.v2.ConversationType
+	1,  // 22: chat.v2.EditConversationMessageStreamRequest.conversation_type:type_name -> chat.v2.ConversationType
+	28, // 23: chat.v2.CreateConversationMessageStreamResponse.stream_initialization:type_name -> chat.v2.StreamInitialization
+	29, // 24: chat.v2.CreateConversationMessageStreamResponse.stream_part_begin:type_name -> chat.v2.StreamPartBegin
+	30, // 25: chat.v2.CreateConversationMessageStreamResponse.message_chunk:type_name -> chat.v2.MessageChunk
+	32, // 26: chat.v2.CreateConversationMessageStreamResponse.incomplete_indicator:type_name -> chat.v2.IncompleteIndicator
+	33, // 27: chat.v2.CreateConversationMessageStreamResponse.stream_part_end:type_name -> chat.v2.StreamPartEnd
+	34, // 28: chat.v2.CreateConversationMessageStreamResponse.stream_finalization:type_name -> chat.v2.StreamFinalization
+	35, // 29: chat.v2.CreateConversationMessageStreamResponse.stream_error:type_name -> chat.v2.StreamError
+	31, // 30: chat.v2.CreateConversationMessageStreamResponse.reasoning_chunk:type_name -> chat.v2.ReasoningChunk
+	14, // 31: chat.v2.CancelConversationMessageResponse.conversation:type_name -> chat.v2.Conversation
+	9,  // 32: chat.v2.AcceptProposedEditResponse.proposed_edit:type_name -> chat.v2.MessageTypeProposedEdit
+	9,  // 33: chat.v2.RejectProposedEditResponse.proposed_edit:type_name -> chat.v2.MessageTypeProposedEdit
+	15, // 34: chat.v2.ChatService.ListConversations:input_type -> chat.v2.ListConversationsRequest
+	17, // 35: chat.v2.ChatService.GetConversation:input_type -> chat.v2.GetConversationRequest
+	36, // 36: chat.v2.ChatService.CreateConversationMessageStream:input_type -> chat.v2.CreateConversationMessageStreamRequest
+	37, // 37: chat.v2.ChatService.RegenerateConversationMessageStream:input_type -> chat.v2.RegenerateConversationMessageStreamRequest
+	38, // 38: chat.v2.ChatService.EditConversationMessageStream:input_type -> chat.v2.EditConversationMessageStreamRequest
+	40, // 39: chat.v2.ChatService.CancelConversationMessage:input_type -> chat.v2.CancelConversationMessageRequest
+	42, // 40: chat.v2.ChatService.ResumeConversationStream:input_type -> chat.v2.ResumeConversationStreamRequest
+	21, // 41: chat.v2.ChatService.SwitchConversationBranch:input_type -> chat.v2.SwitchConversationBranchRequest
+	19, // 42: chat.v2.ChatService.UpdateConversation:input_type -> chat.v2.UpdateConversationRequest
+	23, // 43: chat.v2.ChatService.DeleteConversation:input_type -> chat.v2.DeleteConversationRequest
+	26, // 44: chat.v2.ChatService.ListSupportedModels:input_type -> chat.v2.ListSupportedModelsRequest
+	43, // 45: chat.v2.ChatService.GetCitationKeys:input_type -> chat.v2.GetCitationKeysRequest
+	45, // 46: chat.v2.ChatService.AcceptProposedEdit:input_type -> chat.v2.AcceptProposedEditRequest
+	47, // 47: chat.v2.ChatService.RejectProposedEdit:input_type -> chat.v2.RejectProposedEditRequest
+	16, // 48: chat.v2.ChatService.ListConversations:output_type -> chat.v2.ListConversationsResponse
+	18, // 49: chat.v2.ChatService.GetConversation:output_type -> chat.v2.GetConversationResponse
+	39, // 50: chat.v2.ChatService.CreateConversationMessageStream:output_type -> chat.v2.CreateConversationMessageStreamResponse
+	39, // 51: chat.v2.ChatService.RegenerateConversationMessageStream:output_type -> chat.v2.CreateConversationMessageStreamResponse
+	39, // 52: chat.v2.ChatService.EditConversationMessageStream:output_type -> chat.v2.CreateConversationMessageStreamResponse
+	41, // 53: chat.v2.ChatService.CancelConversationMessage:output_type -> chat.v2.CancelConversationMessageResponse
+	39, // 54: chat.v2.ChatService.ResumeConversationStream:output_type -> chat.v2.CreateConversationMessageStreamResponse
+	22, // 55: chat.v2.ChatService.SwitchConversationBranch:output_type -> chat.v2.SwitchConversationBranchResponse
+	20, // 56: chat.v2.ChatService.UpdateConversation:output_type -> chat.v2.UpdateConversationResponse
+	24, // 57: chat.v2.ChatService.DeleteConversation:output_type -> chat.v2.DeleteConversationResponse
+	27, // 58: chat.v2.ChatService.ListSupportedModels:output_type -> chat.v2.ListSupportedModelsResponse
+	44, // 59: chat.v2.ChatService.GetCitationKeys:output_type -> chat.v2.GetCitationKeysResponse
+	46, // 60: chat.v2.ChatService.AcceptProposedEdit:output_type -> chat.v2.AcceptProposedEditResponse
+	48, // 61: chat.v2.ChatService.RejectProposedEdit:output_type -> chat.v2.RejectProposedEditResponse
+	48, // [48:62] is the sub-list for method output_type
+	34, // [34:48] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_chat_v2_chat_proto_init() }
@@ -2279,18 +3492,23 @@ func file_chat_v2_chat_proto_init() {
 	}
 	file_chat_v2_chat_proto_msgTypes[3].OneofWrappers = []any{}
 	file_chat_v2_chat_proto_msgTypes[4].OneofWrappers = []any{}
-	file_chat_v2_chat_proto_msgTypes[6].OneofWrappers = []any{
+	file_chat_v2_chat_proto_msgTypes[9].OneofWrappers = []any{
 		(*MessagePayload_System)(nil),
 		(*MessagePayload_User)(nil),
 		(*MessagePayload_Assistant)(nil),
 		(*MessagePayload_ToolCallPrepareArguments)(nil),
 		(*MessagePayload_ToolCall)(nil),
 		(*MessagePayload_Unknown)(nil),
-	}
-	file_chat_v2_chat_proto_msgTypes[9].OneofWrappers = []any{}
-	file_chat_v2_chat_proto_msgTypes[17].OneofWrappers = []any{}
-	file_chat_v2_chat_proto_msgTypes[28].OneofWrappers = []any{}
-	file_chat_v2_chat_proto_msgTypes[29].OneofWrappers = []any{
+		(*MessagePayload_Compaction)(nil),
+		(*MessagePayload_ProjectRefresh)(nil),
+		(*MessagePayload_ProposedEdit)(nil),
+	}
+	file_chat_v2_chat_proto_msgTypes[13].OneofWrappers = []any{}
+	file_chat_v2_chat_proto_msgTypes[23].OneofWrappers = []any{}
+	file_chat_v2_chat_proto_msgTypes[34].OneofWrappers = []any{}
+	file_chat_v2_chat_proto_msgTypes[35].OneofWrappers = []any{}
+	file_chat_v2_chat_proto_msgTypes[36].OneofWrappers = []any{}
+	file_chat_v2_chat_proto_msgTypes[37].OneofWrappers = []any{
 		(*CreateConversationMessageStreamResponse_StreamInitialization)(nil),
 		(*CreateConversationMessageStreamResponse_StreamPartBegin)(nil),
 		(*CreateConversationMessageStreamResponse_MessageChunk)(nil),
@@ -2305,8 +3523,8 @@ func file_chat_v2_chat_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chat_v2_chat_proto_rawDesc), len(file_chat_v2_chat_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   32,
+			NumEnums:      2,
+			NumMessages:   47,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
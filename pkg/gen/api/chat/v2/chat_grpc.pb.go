@@ -19,13 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ChatService_ListConversations_FullMethodName               = "/chat.v2.ChatService/ListConversations"
-	ChatService_GetConversation_FullMethodName                 = "/chat.v2.ChatService/GetConversation"
-	ChatService_CreateConversationMessageStream_FullMethodName = "/chat.v2.ChatService/CreateConversationMessageStream"
-	ChatService_UpdateConversation_FullMethodName              = "/chat.v2.ChatService/UpdateConversation"
-	ChatService_DeleteConversation_FullMethodName              = "/chat.v2.ChatService/DeleteConversation"
-	ChatService_ListSupportedModels_FullMethodName             = "/chat.v2.ChatService/ListSupportedModels"
-	ChatService_GetCitationKeys_FullMethodName                 = "/chat.v2.ChatService/GetCitationKeys"
+	ChatService_ListConversations_FullMethodName                   = "/chat.v2.ChatService/ListConversations"
+	ChatService_GetConversation_FullMethodName                     = "/chat.v2.ChatService/GetConversation"
+	ChatService_CreateConversationMessageStream_FullMethodName     = "/chat.v2.ChatService/CreateConversationMessageStream"
+	ChatService_RegenerateConversationMessageStream_FullMethodName = "/chat.v2.ChatService/RegenerateConversationMessageStream"
+	ChatService_EditConversationMessageStream_FullMethodName       = "/chat.v2.ChatService/EditConversationMessageStream"
+	ChatService_CancelConversationMessage_FullMethodName           = "/chat.v2.ChatService/CancelConversationMessage"
+	ChatService_ResumeConversationStream_FullMethodName            = "/chat.v2.ChatService/ResumeConversationStream"
+	ChatService_SwitchConversationBranch_FullMethodName            = "/chat.v2.ChatService/SwitchConversationBranch"
+	ChatService_UpdateConversation_FullMethodName                  = "/chat.v2.ChatService/UpdateConversation"
+	ChatService_DeleteConversation_FullMethodName                  = "/chat.v2.ChatService/DeleteConversation"
+	ChatService_ListSupportedModels_FullMethodName                 = "/chat.v2.ChatService/ListSupportedModels"
+	ChatService_GetCitationKeys_FullMethodName                     = "/chat.v2.ChatService/GetCitationKeys"
+	ChatService_AcceptProposedEdit_FullMethodName                  = "/chat.v2.ChatService/AcceptProposedEdit"
+	ChatService_RejectProposedEdit_FullMethodName                  = "/chat.v2.ChatService/RejectProposedEdit"
 )
 
 // ChatServiceClient is the client API for ChatService service.
@@ -35,10 +42,23 @@ type ChatServiceClient interface {
 	ListConversations(ctx context.Context, in *ListConversationsRequest, opts ...grpc.CallOption) (*ListConversationsResponse, error)
 	GetConversation(ctx context.Context, in *GetConversationRequest, opts ...grpc.CallOption) (*GetConversationResponse, error)
 	CreateConversationMessageStream(ctx context.Context, in *CreateConversationMessageStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateConversationMessageStreamResponse], error)
+	// Regenerates the response to the user turn containing message_id in a new branch.
+	RegenerateConversationMessageStream(ctx context.Context, in *RegenerateConversationMessageStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateConversationMessageStreamResponse], error)
+	// Replaces the user message message_id in a new branch and streams the response.
+	EditConversationMessageStream(ctx context.Context, in *EditConversationMessageStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateConversationMessageStreamResponse], error)
+	// Stops the response being generated, what was produced so far is kept in the conversation.
+	CancelConversationMessage(ctx context.Context, in *CancelConversationMessageRequest, opts ...grpc.CallOption) (*CancelConversationMessageResponse, error)
+	// Reconnects to the response being generated, replaying the events from from_sequence.
+	ResumeConversationStream(ctx context.Context, in *ResumeConversationStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateConversationMessageStreamResponse], error)
+	SwitchConversationBranch(ctx context.Context, in *SwitchConversationBranchRequest, opts ...grpc.CallOption) (*SwitchConversationBranchResponse, error)
 	UpdateConversation(ctx context.Context, in *UpdateConversationRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error)
 	DeleteConversation(ctx context.Context, in *DeleteConversationRequest, opts ...grpc.CallOption) (*DeleteConversationResponse, error)
 	ListSupportedModels(ctx context.Context, in *ListSupportedModelsRequest, opts ...grpc.CallOption) (*ListSupportedModelsResponse, error)
 	GetCitationKeys(ctx context.Context, in *GetCitationKeysRequest, opts ...grpc.CallOption) (*GetCitationKeysResponse, error)
+	// Records that the user applied an edit proposed by the assistant.
+	AcceptProposedEdit(ctx context.Context, in *AcceptProposedEditRequest, opts ...grpc.CallOption) (*AcceptProposedEditResponse, error)
+	// Records that the user discarded an edit proposed by the assistant.
+	RejectProposedEdit(ctx context.Context, in *RejectProposedEditRequest, opts ...grpc.CallOption) (*RejectProposedEditResponse, error)
 }
 
 type chatServiceClient struct {
@@ -88,6 +108,83 @@ func (c *chatServiceClient) CreateConversationMessageStream(ctx context.Context,
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ChatService_CreateConversationMessageStreamClient = grpc.ServerStreamingClient[CreateConversationMessageStreamResponse]
 
+func (c *chatServiceClient) RegenerateConversationMessageStream(ctx context.Context, in *RegenerateConversationMessageStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateConversationMessageStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChatService_ServiceDesc.Streams[1], ChatService_RegenerateConversationMessageStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RegenerateConversationMessageStreamRequest, CreateConversationMessageStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_RegenerateConversationMessageStreamClient = grpc.ServerStreamingClient[CreateConversationMessageStreamResponse]
+
+func (c *chatServiceClient) EditConversationMessageStream(ctx context.Context, in *EditConversationMessageStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateConversationMessageStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChatService_ServiceDesc.Streams[2], ChatService_EditConversationMessageStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[EditConversationMessageStreamRequest, CreateConversationMessageStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_EditConversationMessageStreamClient = grpc.ServerStreamingClient[CreateConversationMessageStreamResponse]
+
+func (c *chatServiceClient) CancelConversationMessage(ctx context.Context, in *CancelConversationMessageRequest, opts ...grpc.CallOption) (*CancelConversationMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelConversationMessageResponse)
+	err := c.cc.Invoke(ctx, ChatService_CancelConversationMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) ResumeConversationStream(ctx context.Context, in *ResumeConversationStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CreateConversationMessageStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChatService_ServiceDesc.Streams[3], ChatService_ResumeConversationStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ResumeConversationStreamRequest, CreateConversationMessageStreamResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_ResumeConversationStreamClient = grpc.ServerStreamingClient[CreateConversationMessageStreamResponse]
+
+func (c *chatServiceClient) SwitchConversationBranch(ctx context.Context, in *SwitchConversationBranchRequest, opts ...grpc.CallOption) (*SwitchConversationBranchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SwitchConversationBranchResponse)
+	err := c.cc.Invoke(ctx, ChatService_SwitchConversationBranch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatServiceClient) UpdateConversation(ctx context.Context, in *UpdateConversationRequest, opts ...grpc.CallOption) (*UpdateConversationResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateConversationResponse)
@@ -128,6 +225,26 @@ func (c *chatServiceClient) GetCitationKeys(ctx context.Context, in *GetCitation
 	return out, nil
 }
 
+func (c *chatServiceClient) AcceptProposedEdit(ctx context.Context, in *AcceptProposedEditRequest, opts ...grpc.CallOption) (*AcceptProposedEditResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptProposedEditResponse)
+	err := c.cc.Invoke(ctx, ChatService_AcceptProposedEdit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatServiceClient) RejectProposedEdit(ctx context.Context, in *RejectProposedEditRequest, opts ...grpc.CallOption) (*RejectProposedEditResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectProposedEditResponse)
+	err := c.cc.Invoke(ctx, ChatService_RejectProposedEdit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServiceServer is the server API for ChatService service.
 // All implementations must embed UnimplementedChatServiceServer
 // for forward compatibility.
@@ -135,10 +252,23 @@ type ChatServiceServer interface {
 	ListConversations(context.Context, *ListConversationsRequest) (*ListConversationsResponse, error)
 	GetConversation(context.Context, *GetConversationRequest) (*GetConversationResponse, error)
 	CreateConversationMessageStream(*CreateConversationMessageStreamRequest, grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]) error
+	// Regenerates the response to the user turn containing message_id in a new branch.
+	RegenerateConversationMessageStream(*RegenerateConversationMessageStreamRequest, grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]) error
+	// Replaces the user message message_id in a new branch and streams the response.
+	EditConversationMessageStream(*EditConversationMessageStreamRequest, grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]) error
+	// Stops the response being generated, what was produced so far is kept in the conversation.
+	CancelConversationMessage(context.Context, *CancelConversationMessageRequest) (*CancelConversationMessageResponse, error)
+	// Reconnects to the response being generated, replaying the events from from_sequence.
+	ResumeConversationStream(*ResumeConversationStreamRequest, grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]) error
+	SwitchConversationBranch(context.Context, *SwitchConversationBranchRequest) (*SwitchConversationBranchResponse, error)
 	UpdateConversation(context.Context, *UpdateConversationRequest) (*UpdateConversationResponse, error)
 	DeleteConversation(context.Context, *DeleteConversationRequest) (*DeleteConversationResponse, error)
 	ListSupportedModels(context.Context, *ListSupportedModelsRequest) (*ListSupportedModelsResponse, error)
 	GetCitationKeys(context.Context, *GetCitationKeysRequest) (*GetCitationKeysResponse, error)
+	// Records that the user applied an edit proposed by the assistant.
+	AcceptProposedEdit(context.Context, *AcceptProposedEditRequest) (*AcceptProposedEditResponse, error)
+	// Records that the user discarded an edit proposed by the assistant.
+	RejectProposedEdit(context.Context, *RejectProposedEditRequest) (*RejectProposedEditResponse, error)
 	mustEmbedUnimplementedChatServiceServer()
 }
 
@@ -158,6 +288,21 @@ func (UnimplementedChatServiceServer) GetConversation(context.Context, *GetConve
 func (UnimplementedChatServiceServer) CreateConversationMessageStream(*CreateConversationMessageStreamRequest, grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]) error {
 	return status.Error(codes.Unimplemented, "method CreateConversationMessageStream not implemented")
 }
+func (UnimplementedChatServiceServer) RegenerateConversationMessageStream(*RegenerateConversationMessageStreamRequest, grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]) error {
+	return status.Error(codes.Unimplemented, "method RegenerateConversationMessageStream not implemented")
+}
+func (UnimplementedChatServiceServer) EditConversationMessageStream(*EditConversationMessageStreamRequest, grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]) error {
+	return status.Error(codes.Unimplemented, "method EditConversationMessageStream not implemented")
+}
+func (UnimplementedChatServiceServer) CancelConversationMessage(context.Context, *CancelConversationMessageRequest) (*CancelConversationMessageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelConversationMessage not implemented")
+}
+func (UnimplementedChatServiceServer) ResumeConversationStream(*ResumeConversationStreamRequest, grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]) error {
+	return status.Error(codes.Unimplemented, "method ResumeConversationStream not implemented")
+}
+func (UnimplementedChatServiceServer) SwitchConversationBranch(context.Context, *SwitchConversationBranchRequest) (*SwitchConversationBranchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SwitchConversationBranch not implemented")
+}
 func (UnimplementedChatServiceServer) UpdateConversation(context.Context, *UpdateConversationRequest) (*UpdateConversationResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateConversation not implemented")
 }
@@ -170,6 +315,12 @@ func (UnimplementedChatServiceServer) ListSupportedModels(context.Context, *List
 func (UnimplementedChatServiceServer) GetCitationKeys(context.Context, *GetCitationKeysRequest) (*GetCitationKeysResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetCitationKeys not implemented")
 }
+func (UnimplementedChatServiceServer) AcceptProposedEdit(context.Context, *AcceptProposedEditRequest) (*AcceptProposedEditResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AcceptProposedEdit not implemented")
+}
+func (UnimplementedChatServiceServer) RejectProposedEdit(context.Context, *RejectProposedEditRequest) (*RejectProposedEditResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RejectProposedEdit not implemented")
+}
 func (UnimplementedChatServiceServer) mustEmbedUnimplementedChatServiceServer() {}
 func (UnimplementedChatServiceServer) testEmbeddedByValue()                     {}
 
@@ -238,6 +389,75 @@ func _ChatService_CreateConversationMessageStream_Handler(srv interface{}, strea
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type ChatService_CreateConversationMessageStreamServer = grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]
 
+func _ChatService_RegenerateConversationMessageStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RegenerateConversationMessageStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServiceServer).RegenerateConversationMessageStream(m, &grpc.GenericServerStream[RegenerateConversationMessageStreamRequest, CreateConversationMessageStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_RegenerateConversationMessageStreamServer = grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]
+
+func _ChatService_EditConversationMessageStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(EditConversationMessageStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServiceServer).EditConversationMessageStream(m, &grpc.GenericServerStream[EditConversationMessageStreamRequest, CreateConversationMessageStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_EditConversationMessageStreamServer = grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]
+
+func _ChatService_CancelConversationMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelConversationMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).CancelConversationMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_CancelConversationMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).CancelConversationMessage(ctx, req.(*CancelConversationMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_ResumeConversationStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ResumeConversationStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChatServiceServer).ResumeConversationStream(m, &grpc.GenericServerStream[ResumeConversationStreamRequest, CreateConversationMessageStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatService_ResumeConversationStreamServer = grpc.ServerStreamingServer[CreateConversationMessageStreamResponse]
+
+func _ChatService_SwitchConversationBranch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwitchConversationBranchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).SwitchConversationBranch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_SwitchConversationBranch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).SwitchConversationBranch(ctx, req.(*SwitchConversationBranchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ChatService_UpdateConversation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateConversationRequest)
 	if err := dec(in); err != nil {
@@ -310,6 +530,42 @@ func _ChatService_GetCitationKeys_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChatService_AcceptProposedEdit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptProposedEditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).AcceptProposedEdit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_AcceptProposedEdit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).AcceptProposedEdit(ctx, req.(*AcceptProposedEditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatService_RejectProposedEdit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectProposedEditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServiceServer).RejectProposedEdit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatService_RejectProposedEdit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServiceServer).RejectProposedEdit(ctx, req.(*RejectProposedEditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChatService_ServiceDesc is the grpc.ServiceDesc for ChatService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -325,6 +581,14 @@ var ChatService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetConversation",
 			Handler:    _ChatService_GetConversation_Handler,
 		},
+		{
+			MethodName: "CancelConversationMessage",
+			Handler:    _ChatService_CancelConversationMessage_Handler,
+		},
+		{
+			MethodName: "SwitchConversationBranch",
+			Handler:    _ChatService_SwitchConversationBranch_Handler,
+		},
 		{
 			MethodName: "UpdateConversation",
 			Handler:    _ChatService_UpdateConversation_Handler,
@@ -341,6 +605,14 @@ var ChatService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCitationKeys",
 			Handler:    _ChatService_GetCitationKeys_Handler,
 		},
+		{
+			MethodName: "AcceptProposedEdit",
+			Handler:    _ChatService_AcceptProposedEdit_Handler,
+		},
+		{
+			MethodName: "RejectProposedEdit",
+			Handler:    _ChatService_RejectProposedEdit_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -348,6 +620,21 @@ var ChatService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ChatService_CreateConversationMessageStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "RegenerateConversationMessageStream",
+			Handler:       _ChatService_RegenerateConversationMessageStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "EditConversationMessageStream",
+			Handler:       _ChatService_EditConversationMessageStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ResumeConversationStream",
+			Handler:       _ChatService_ResumeConversationStream_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "chat/v2/chat.proto",
 }
@@ -10,6 +10,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,6 +23,394 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CommentStatus int32
+
+const (
+	CommentStatus_COMMENT_STATUS_UNSPECIFIED CommentStatus = 0
+	CommentStatus_COMMENT_STATUS_OPEN        CommentStatus = 1 // No action was taken yet
+	CommentStatus_COMMENT_STATUS_ACCEPTED    CommentStatus = 2 // Added to Overleaf
+	CommentStatus_COMMENT_STATUS_REJECTED    CommentStatus = 3
+	CommentStatus_COMMENT_STATUS_RESOLVED    CommentStatus = 4 // Addressed in the paper
+)
+
+// Enum value maps for CommentStatus.
+var (
+	CommentStatus_name = map[int32]string{
+		0: "COMMENT_STATUS_UNSPECIFIED",
+		1: "COMMENT_STATUS_OPEN",
+		2: "COMMENT_STATUS_ACCEPTED",
+		3: "COMMENT_STATUS_REJECTED",
+		4: "COMMENT_STATUS_RESOLVED",
+	}
+	CommentStatus_value = map[string]int32{
+		"COMMENT_STATUS_UNSPECIFIED": 0,
+		"COMMENT_STATUS_OPEN":        1,
+		"COMMENT_STATUS_ACCEPTED":    2,
+		"COMMENT_STATUS_REJECTED":    3,
+		"COMMENT_STATUS_RESOLVED":    4,
+	}
+)
+
+func (x CommentStatus) Enum() *CommentStatus {
+	p := new(CommentStatus)
+	*p = x
+	return p
+}
+
+func (x CommentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CommentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_comment_v1_comment_proto_enumTypes[0].Descriptor()
+}
+
+func (CommentStatus) Type() protoreflect.EnumType {
+	return &file_comment_v1_comment_proto_enumTypes[0]
+}
+
+func (x CommentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CommentStatus.Descriptor instead.
+func (CommentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{0}
+}
+
+type CommentReplyRole int32
+
+const (
+	CommentReplyRole_COMMENT_REPLY_ROLE_UNSPECIFIED CommentReplyRole = 0
+	CommentReplyRole_COMMENT_REPLY_ROLE_USER        CommentReplyRole = 1
+	CommentReplyRole_COMMENT_REPLY_ROLE_ASSISTANT   CommentReplyRole = 2
+)
+
+// Enum value maps for CommentReplyRole.
+var (
+	CommentReplyRole_name = map[int32]string{
+		0: "COMMENT_REPLY_ROLE_UNSPECIFIED",
+		1: "COMMENT_REPLY_ROLE_USER",
+		2: "COMMENT_REPLY_ROLE_ASSISTANT",
+	}
+	CommentReplyRole_value = map[string]int32{
+		"COMMENT_REPLY_ROLE_UNSPECIFIED": 0,
+		"COMMENT_REPLY_ROLE_USER":        1,
+		"COMMENT_REPLY_ROLE_ASSISTANT":   2,
+	}
+)
+
+func (x CommentReplyRole) Enum() *CommentReplyRole {
+	p := new(CommentReplyRole)
+	*p = x
+	return p
+}
+
+func (x CommentReplyRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CommentReplyRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_comment_v1_comment_proto_enumTypes[1].Descriptor()
+}
+
+func (CommentReplyRole) Type() protoreflect.EnumType {
+	return &file_comment_v1_comment_proto_enumTypes[1]
+}
+
+func (x CommentReplyRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CommentReplyRole.Descriptor instead.
+func (CommentReplyRole) EnumDescriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{1}
+}
+
+type CommentExportFormat int32
+
+const (
+	CommentExportFormat_COMMENT_EXPORT_FORMAT_UNSPECIFIED CommentExportFormat = 0
+	CommentExportFormat_COMMENT_EXPORT_FORMAT_MARKDOWN    CommentExportFormat = 1 // A reviewer report, review.md
+	CommentExportFormat_COMMENT_EXPORT_FORMAT_JSON        CommentExportFormat = 2 // review.json
+	CommentExportFormat_COMMENT_EXPORT_FORMAT_LATEX       CommentExportFormat = 3 // The docs of the project with a \todo note of todonotes at each comment
+)
+
+// Enum value maps for CommentExportFormat.
+var (
+	CommentExportFormat_name = map[int32]string{
+		0: "COMMENT_EXPORT_FORMAT_UNSPECIFIED",
+		1: "COMMENT_EXPORT_FORMAT_MARKDOWN",
+		2: "COMMENT_EXPORT_FORMAT_JSON",
+		3: "COMMENT_EXPORT_FORMAT_LATEX",
+	}
+	CommentExportFormat_value = map[string]int32{
+		"COMMENT_EXPORT_FORMAT_UNSPECIFIED": 0,
+		"COMMENT_EXPORT_FORMAT_MARKDOWN":    1,
+		"COMMENT_EXPORT_FORMAT_JSON":        2,
+		"COMMENT_EXPORT_FORMAT_LATEX":       3,
+	}
+)
+
+func (x CommentExportFormat) Enum() *CommentExportFormat {
+	p := new(CommentExportFormat)
+	*p = x
+	return p
+}
+
+func (x CommentExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CommentExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_comment_v1_comment_proto_enumTypes[2].Descriptor()
+}
+
+func (CommentExportFormat) Type() protoreflect.EnumType {
+	return &file_comment_v1_comment_proto_enumTypes[2]
+}
+
+func (x CommentExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CommentExportFormat.Descriptor instead.
+func (CommentExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{2}
+}
+
+type Comment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProjectId     string                 `protobuf:"bytes,2,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	DocId         string                 `protobuf:"bytes,3,opt,name=doc_id,json=docId,proto3" json:"doc_id,omitempty"`
+	DocPath       string                 `protobuf:"bytes,4,opt,name=doc_path,json=docPath,proto3" json:"doc_path,omitempty"`
+	DocVersion    int32                  `protobuf:"varint,5,opt,name=doc_version,json=docVersion,proto3" json:"doc_version,omitempty"`
+	DocSha1       string                 `protobuf:"bytes,6,opt,name=doc_sha1,json=docSha1,proto3" json:"doc_sha1,omitempty"`
+	QuotePosition int32                  `protobuf:"varint,7,opt,name=quote_position,json=quotePosition,proto3" json:"quote_position,omitempty"` // In runes
+	QuoteText     string                 `protobuf:"bytes,8,opt,name=quote_text,json=quoteText,proto3" json:"quote_text,omitempty"`
+	Comment       string                 `protobuf:"bytes,9,opt,name=comment,proto3" json:"comment,omitempty"`
+	Importance    string                 `protobuf:"bytes,10,opt,name=importance,proto3" json:"importance,omitempty"`
+	Section       string                 `protobuf:"bytes,11,opt,name=section,proto3" json:"section,omitempty"`
+	Status        CommentStatus          `protobuf:"varint,12,opt,name=status,proto3,enum=comment.v1.CommentStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Orphaned      bool                   `protobuf:"varint,15,opt,name=orphaned,proto3" json:"orphaned,omitempty"` // The quote was not found after the doc changed
+	Replies       []*CommentReply        `protobuf:"bytes,16,rep,name=replies,proto3" json:"replies,omitempty"`    // Oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	mi := &file_comment_v1_comment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Comment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Comment) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *Comment) GetDocId() string {
+	if x != nil {
+		return x.DocId
+	}
+	return ""
+}
+
+func (x *Comment) GetDocPath() string {
+	if x != nil {
+		return x.DocPath
+	}
+	return ""
+}
+
+func (x *Comment) GetDocVersion() int32 {
+	if x != nil {
+		return x.DocVersion
+	}
+	return 0
+}
+
+func (x *Comment) GetDocSha1() string {
+	if x != nil {
+		return x.DocSha1
+	}
+	return ""
+}
+
+func (x *Comment) GetQuotePosition() int32 {
+	if x != nil {
+		return x.QuotePosition
+	}
+	return 0
+}
+
+func (x *Comment) GetQuoteText() string {
+	if x != nil {
+		return x.QuoteText
+	}
+	return ""
+}
+
+func (x *Comment) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Comment) GetImportance() string {
+	if x != nil {
+		return x.Importance
+	}
+	return ""
+}
+
+func (x *Comment) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *Comment) GetStatus() CommentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CommentStatus_COMMENT_STATUS_UNSPECIFIED
+}
+
+func (x *Comment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Comment) GetOrphaned() bool {
+	if x != nil {
+		return x.Orphaned
+	}
+	return false
+}
+
+func (x *Comment) GetReplies() []*CommentReply {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+type CommentReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Role          CommentReplyRole       `protobuf:"varint,2,opt,name=role,proto3,enum=comment.v1.CommentReplyRole" json:"role,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommentReply) Reset() {
+	*x = CommentReply{}
+	mi := &file_comment_v1_comment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentReply) ProtoMessage() {}
+
+func (x *CommentReply) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentReply.ProtoReflect.Descriptor instead.
+func (*CommentReply) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CommentReply) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CommentReply) GetRole() CommentReplyRole {
+	if x != nil {
+		return x.Role
+	}
+	return CommentReplyRole_COMMENT_REPLY_ROLE_UNSPECIFIED
+}
+
+func (x *CommentReply) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CommentReply) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 type CommentsAcceptedRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ProjectId      string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
@@ -34,7 +423,7 @@ type CommentsAcceptedRequest struct {
 
 func (x *CommentsAcceptedRequest) Reset() {
 	*x = CommentsAcceptedRequest{}
-	mi := &file_comment_v1_comment_proto_msgTypes[0]
+	mi := &file_comment_v1_comment_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -46,7 +435,7 @@ func (x *CommentsAcceptedRequest) String() string {
 func (*CommentsAcceptedRequest) ProtoMessage() {}
 
 func (x *CommentsAcceptedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_comment_v1_comment_proto_msgTypes[0]
+	mi := &file_comment_v1_comment_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -59,7 +448,7 @@ func (x *CommentsAcceptedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommentsAcceptedRequest.ProtoReflect.Descriptor instead.
 func (*CommentsAcceptedRequest) Descriptor() ([]byte, []int) {
-	return file_comment_v1_comment_proto_rawDescGZIP(), []int{0}
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CommentsAcceptedRequest) GetProjectId() string {
@@ -98,7 +487,7 @@ type CommentsAcceptedResponse struct {
 
 func (x *CommentsAcceptedResponse) Reset() {
 	*x = CommentsAcceptedResponse{}
-	mi := &file_comment_v1_comment_proto_msgTypes[1]
+	mi := &file_comment_v1_comment_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -110,7 +499,7 @@ func (x *CommentsAcceptedResponse) String() string {
 func (*CommentsAcceptedResponse) ProtoMessage() {}
 
 func (x *CommentsAcceptedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_comment_v1_comment_proto_msgTypes[1]
+	mi := &file_comment_v1_comment_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -123,26 +512,986 @@ func (x *CommentsAcceptedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommentsAcceptedResponse.ProtoReflect.Descriptor instead.
 func (*CommentsAcceptedResponse) Descriptor() ([]byte, []int) {
-	return file_comment_v1_comment_proto_rawDescGZIP(), []int{1}
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{3}
 }
 
-var File_comment_v1_comment_proto protoreflect.FileDescriptor
+type RejectCommentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	CommentIds    []string               `protobuf:"bytes,2,rep,name=comment_ids,json=commentIds,proto3" json:"comment_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_comment_v1_comment_proto_rawDesc = "" +
-	"\n" +
-	"\x18comment/v1/comment.proto\x12\n" +
-	"comment.v1\x1a\x1cgoogle/api/annotations.proto\"\xa1\x01\n" +
-	"\x17CommentsAcceptedRequest\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\x12'\n" +
-	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x1d\n" +
-	"\n" +
-	"message_id\x18\x03 \x01(\tR\tmessageId\x12\x1f\n" +
-	"\vcomment_ids\x18\x04 \x03(\tR\n" +
-	"commentIds\"\x1a\n" +
-	"\x18CommentsAcceptedResponse2\x9a\x01\n" +
-	"\x0eCommentService\x12\x87\x01\n" +
-	"\x10CommentsAccepted\x12#.comment.v1.CommentsAcceptedRequest\x1a$.comment.v1.CommentsAcceptedResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/_pd/api/v1/comments/acceptedB\x97\x01\n" +
+func (x *RejectCommentsRequest) Reset() {
+	*x = RejectCommentsRequest{}
+	mi := &file_comment_v1_comment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectCommentsRequest) ProtoMessage() {}
+
+func (x *RejectCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectCommentsRequest.ProtoReflect.Descriptor instead.
+func (*RejectCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RejectCommentsRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *RejectCommentsRequest) GetCommentIds() []string {
+	if x != nil {
+		return x.CommentIds
+	}
+	return nil
+}
+
+type RejectCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectCommentsResponse) Reset() {
+	*x = RejectCommentsResponse{}
+	mi := &file_comment_v1_comment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectCommentsResponse) ProtoMessage() {}
+
+func (x *RejectCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectCommentsResponse.ProtoReflect.Descriptor instead.
+func (*RejectCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RejectCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+type ResolveCommentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	CommentIds    []string               `protobuf:"bytes,2,rep,name=comment_ids,json=commentIds,proto3" json:"comment_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveCommentsRequest) Reset() {
+	*x = ResolveCommentsRequest{}
+	mi := &file_comment_v1_comment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveCommentsRequest) ProtoMessage() {}
+
+func (x *ResolveCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveCommentsRequest.ProtoReflect.Descriptor instead.
+func (*ResolveCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResolveCommentsRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *ResolveCommentsRequest) GetCommentIds() []string {
+	if x != nil {
+		return x.CommentIds
+	}
+	return nil
+}
+
+type ResolveCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveCommentsResponse) Reset() {
+	*x = ResolveCommentsResponse{}
+	mi := &file_comment_v1_comment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveCommentsResponse) ProtoMessage() {}
+
+func (x *ResolveCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveCommentsResponse.ProtoReflect.Descriptor instead.
+func (*ResolveCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResolveCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+type ListCommentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	DocId         string                 `protobuf:"bytes,2,opt,name=doc_id,json=docId,proto3" json:"doc_id,omitempty"` // Filters, ignored if empty
+	Section       string                 `protobuf:"bytes,3,opt,name=section,proto3" json:"section,omitempty"`
+	Importance    []string               `protobuf:"bytes,4,rep,name=importance,proto3" json:"importance,omitempty"`
+	Statuses      []CommentStatus        `protobuf:"varint,5,rep,packed,name=statuses,proto3,enum=comment.v1.CommentStatus" json:"statuses,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"` // Defaults to 50, at most 200
+	Offset        int32                  `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	Orphaned      *bool                  `protobuf:"varint,8,opt,name=orphaned,proto3,oneof" json:"orphaned,omitempty"` // Only the orphaned comments if true, only the anchored ones if false
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsRequest) Reset() {
+	*x = ListCommentsRequest{}
+	mi := &file_comment_v1_comment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsRequest) ProtoMessage() {}
+
+func (x *ListCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsRequest.ProtoReflect.Descriptor instead.
+func (*ListCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListCommentsRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *ListCommentsRequest) GetDocId() string {
+	if x != nil {
+		return x.DocId
+	}
+	return ""
+}
+
+func (x *ListCommentsRequest) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *ListCommentsRequest) GetImportance() []string {
+	if x != nil {
+		return x.Importance
+	}
+	return nil
+}
+
+func (x *ListCommentsRequest) GetStatuses() []CommentStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListCommentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCommentsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListCommentsRequest) GetOrphaned() bool {
+	if x != nil && x.Orphaned != nil {
+		return *x.Orphaned
+	}
+	return false
+}
+
+type ListCommentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comments      []*Comment             `protobuf:"bytes,1,rep,name=comments,proto3" json:"comments,omitempty"`                        // In order of doc path and quote position
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // The number of comments matching the filters
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCommentsResponse) Reset() {
+	*x = ListCommentsResponse{}
+	mi := &file_comment_v1_comment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCommentsResponse) ProtoMessage() {}
+
+func (x *ListCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCommentsResponse.ProtoReflect.Descriptor instead.
+func (*ListCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListCommentsResponse) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *ListCommentsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type GetCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	CommentId     string                 `protobuf:"bytes,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentRequest) Reset() {
+	*x = GetCommentRequest{}
+	mi := &file_comment_v1_comment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentRequest) ProtoMessage() {}
+
+func (x *GetCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentRequest.ProtoReflect.Descriptor instead.
+func (*GetCommentRequest) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCommentRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *GetCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+type GetCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCommentResponse) Reset() {
+	*x = GetCommentResponse{}
+	mi := &file_comment_v1_comment_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCommentResponse) ProtoMessage() {}
+
+func (x *GetCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCommentResponse.ProtoReflect.Descriptor instead.
+func (*GetCommentResponse) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetCommentResponse) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+type AddCommentReplyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	CommentId     string                 `protobuf:"bytes,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCommentReplyRequest) Reset() {
+	*x = AddCommentReplyRequest{}
+	mi := &file_comment_v1_comment_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCommentReplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCommentReplyRequest) ProtoMessage() {}
+
+func (x *AddCommentReplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCommentReplyRequest.ProtoReflect.Descriptor instead.
+func (*AddCommentReplyRequest) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AddCommentReplyRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *AddCommentReplyRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *AddCommentReplyRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type AddCommentReplyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddCommentReplyResponse) Reset() {
+	*x = AddCommentReplyResponse{}
+	mi := &file_comment_v1_comment_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddCommentReplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddCommentReplyResponse) ProtoMessage() {}
+
+func (x *AddCommentReplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddCommentReplyResponse.ProtoReflect.Descriptor instead.
+func (*AddCommentReplyResponse) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AddCommentReplyResponse) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+type AskAboutCommentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	CommentId     string                 `protobuf:"bytes,2,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
+	Question      string                 `protobuf:"bytes,3,opt,name=question,proto3" json:"question,omitempty"`                                        // Added to the discussion, followed by the answer
+	ModelSlug     string                 `protobuf:"bytes,4,opt,name=model_slug,json=modelSlug,proto3" json:"model_slug,omitempty"`                     // Optional, defaults to the model of the other assistant features
+	CustomModelId *string                `protobuf:"bytes,5,opt,name=custom_model_id,json=customModelId,proto3,oneof" json:"custom_model_id,omitempty"` // Selected custom model ID, the model_slug is then ignored
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AskAboutCommentRequest) Reset() {
+	*x = AskAboutCommentRequest{}
+	mi := &file_comment_v1_comment_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AskAboutCommentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AskAboutCommentRequest) ProtoMessage() {}
+
+func (x *AskAboutCommentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AskAboutCommentRequest.ProtoReflect.Descriptor instead.
+func (*AskAboutCommentRequest) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AskAboutCommentRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *AskAboutCommentRequest) GetCommentId() string {
+	if x != nil {
+		return x.CommentId
+	}
+	return ""
+}
+
+func (x *AskAboutCommentRequest) GetQuestion() string {
+	if x != nil {
+		return x.Question
+	}
+	return ""
+}
+
+func (x *AskAboutCommentRequest) GetModelSlug() string {
+	if x != nil {
+		return x.ModelSlug
+	}
+	return ""
+}
+
+func (x *AskAboutCommentRequest) GetCustomModelId() string {
+	if x != nil && x.CustomModelId != nil {
+		return *x.CustomModelId
+	}
+	return ""
+}
+
+type AskAboutCommentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	Answer        *CommentReply          `protobuf:"bytes,2,opt,name=answer,proto3" json:"answer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AskAboutCommentResponse) Reset() {
+	*x = AskAboutCommentResponse{}
+	mi := &file_comment_v1_comment_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AskAboutCommentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AskAboutCommentResponse) ProtoMessage() {}
+
+func (x *AskAboutCommentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AskAboutCommentResponse.ProtoReflect.Descriptor instead.
+func (*AskAboutCommentResponse) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AskAboutCommentResponse) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+func (x *AskAboutCommentResponse) GetAnswer() *CommentReply {
+	if x != nil {
+		return x.Answer
+	}
+	return nil
+}
+
+type ExportCommentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Format        CommentExportFormat    `protobuf:"varint,2,opt,name=format,proto3,enum=comment.v1.CommentExportFormat" json:"format,omitempty"`
+	Statuses      []CommentStatus        `protobuf:"varint,3,rep,packed,name=statuses,proto3,enum=comment.v1.CommentStatus" json:"statuses,omitempty"` // Defaults to the open and accepted comments
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportCommentsRequest) Reset() {
+	*x = ExportCommentsRequest{}
+	mi := &file_comment_v1_comment_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportCommentsRequest) ProtoMessage() {}
+
+func (x *ExportCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportCommentsRequest.ProtoReflect.Descriptor instead.
+func (*ExportCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExportCommentsRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *ExportCommentsRequest) GetFormat() CommentExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return CommentExportFormat_COMMENT_EXPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ExportCommentsRequest) GetStatuses() []CommentStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type ExportedFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportedFile) Reset() {
+	*x = ExportedFile{}
+	mi := &file_comment_v1_comment_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportedFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportedFile) ProtoMessage() {}
+
+func (x *ExportedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportedFile.ProtoReflect.Descriptor instead.
+func (*ExportedFile) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExportedFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ExportedFile) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+type ExportCommentsResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Files             []*ExportedFile        `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	SkippedCommentIds []string               `protobuf:"bytes,2,rep,name=skipped_comment_ids,json=skippedCommentIds,proto3" json:"skipped_comment_ids,omitempty"` // The comments not inserted in the LaTeX docs, as their quote is not found
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ExportCommentsResponse) Reset() {
+	*x = ExportCommentsResponse{}
+	mi := &file_comment_v1_comment_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportCommentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportCommentsResponse) ProtoMessage() {}
+
+func (x *ExportCommentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_comment_v1_comment_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportCommentsResponse.ProtoReflect.Descriptor instead.
+func (*ExportCommentsResponse) Descriptor() ([]byte, []int) {
+	return file_comment_v1_comment_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExportCommentsResponse) GetFiles() []*ExportedFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ExportCommentsResponse) GetSkippedCommentIds() []string {
+	if x != nil {
+		return x.SkippedCommentIds
+	}
+	return nil
+}
+
+var File_comment_v1_comment_proto protoreflect.FileDescriptor
+
+const file_comment_v1_comment_proto_rawDesc = "" +
+	"\n" +
+	"\x18comment/v1/comment.proto\x12\n" +
+	"comment.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb9\x04\n" +
+	"\aComment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x02 \x01(\tR\tprojectId\x12\x15\n" +
+	"\x06doc_id\x18\x03 \x01(\tR\x05docId\x12\x19\n" +
+	"\bdoc_path\x18\x04 \x01(\tR\adocPath\x12\x1f\n" +
+	"\vdoc_version\x18\x05 \x01(\x05R\n" +
+	"docVersion\x12\x19\n" +
+	"\bdoc_sha1\x18\x06 \x01(\tR\adocSha1\x12%\n" +
+	"\x0equote_position\x18\a \x01(\x05R\rquotePosition\x12\x1d\n" +
+	"\n" +
+	"quote_text\x18\b \x01(\tR\tquoteText\x12\x18\n" +
+	"\acomment\x18\t \x01(\tR\acomment\x12\x1e\n" +
+	"\n" +
+	"importance\x18\n" +
+	" \x01(\tR\n" +
+	"importance\x12\x18\n" +
+	"\asection\x18\v \x01(\tR\asection\x121\n" +
+	"\x06status\x18\f \x01(\x0e2\x19.comment.v1.CommentStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\borphaned\x18\x0f \x01(\bR\borphaned\x122\n" +
+	"\areplies\x18\x10 \x03(\v2\x18.comment.v1.CommentReplyR\areplies\"\xa5\x01\n" +
+	"\fCommentReply\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x120\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x1c.comment.v1.CommentReplyRoleR\x04role\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa1\x01\n" +
+	"\x17CommentsAcceptedRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\x12\x1f\n" +
+	"\vcomment_ids\x18\x04 \x03(\tR\n" +
+	"commentIds\"\x1a\n" +
+	"\x18CommentsAcceptedResponse\"W\n" +
+	"\x15RejectCommentsRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x1f\n" +
+	"\vcomment_ids\x18\x02 \x03(\tR\n" +
+	"commentIds\"I\n" +
+	"\x16RejectCommentsResponse\x12/\n" +
+	"\bcomments\x18\x01 \x03(\v2\x13.comment.v1.CommentR\bcomments\"X\n" +
+	"\x16ResolveCommentsRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x1f\n" +
+	"\vcomment_ids\x18\x02 \x03(\tR\n" +
+	"commentIds\"J\n" +
+	"\x17ResolveCommentsResponse\x12/\n" +
+	"\bcomments\x18\x01 \x03(\v2\x13.comment.v1.CommentR\bcomments\"\x98\x02\n" +
+	"\x13ListCommentsRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x15\n" +
+	"\x06doc_id\x18\x02 \x01(\tR\x05docId\x12\x18\n" +
+	"\asection\x18\x03 \x01(\tR\asection\x12\x1e\n" +
+	"\n" +
+	"importance\x18\x04 \x03(\tR\n" +
+	"importance\x125\n" +
+	"\bstatuses\x18\x05 \x03(\x0e2\x19.comment.v1.CommentStatusR\bstatuses\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\a \x01(\x05R\x06offset\x12\x1f\n" +
+	"\borphaned\x18\b \x01(\bH\x00R\borphaned\x88\x01\x01B\v\n" +
+	"\t_orphaned\"h\n" +
+	"\x14ListCommentsResponse\x12/\n" +
+	"\bcomments\x18\x01 \x03(\v2\x13.comment.v1.CommentR\bcomments\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\"Q\n" +
+	"\x11GetCommentRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\tR\tcommentId\"C\n" +
+	"\x12GetCommentResponse\x12-\n" +
+	"\acomment\x18\x01 \x01(\v2\x13.comment.v1.CommentR\acomment\"p\n" +
+	"\x16AddCommentReplyRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\tR\tcommentId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\"H\n" +
+	"\x17AddCommentReplyResponse\x12-\n" +
+	"\acomment\x18\x01 \x01(\v2\x13.comment.v1.CommentR\acomment\"\xd2\x01\n" +
+	"\x16AskAboutCommentRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x1d\n" +
+	"\n" +
+	"comment_id\x18\x02 \x01(\tR\tcommentId\x12\x1a\n" +
+	"\bquestion\x18\x03 \x01(\tR\bquestion\x12\x1d\n" +
+	"\n" +
+	"model_slug\x18\x04 \x01(\tR\tmodelSlug\x12+\n" +
+	"\x0fcustom_model_id\x18\x05 \x01(\tH\x00R\rcustomModelId\x88\x01\x01B\x12\n" +
+	"\x10_custom_model_id\"z\n" +
+	"\x17AskAboutCommentResponse\x12-\n" +
+	"\acomment\x18\x01 \x01(\v2\x13.comment.v1.CommentR\acomment\x120\n" +
+	"\x06answer\x18\x02 \x01(\v2\x18.comment.v1.CommentReplyR\x06answer\"\xa6\x01\n" +
+	"\x15ExportCommentsRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x127\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x1f.comment.v1.CommentExportFormatR\x06format\x125\n" +
+	"\bstatuses\x18\x03 \x03(\x0e2\x19.comment.v1.CommentStatusR\bstatuses\"<\n" +
+	"\fExportedFile\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\tR\acontent\"x\n" +
+	"\x16ExportCommentsResponse\x12.\n" +
+	"\x05files\x18\x01 \x03(\v2\x18.comment.v1.ExportedFileR\x05files\x12.\n" +
+	"\x13skipped_comment_ids\x18\x02 \x03(\tR\x11skippedCommentIds*\x9f\x01\n" +
+	"\rCommentStatus\x12\x1e\n" +
+	"\x1aCOMMENT_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13COMMENT_STATUS_OPEN\x10\x01\x12\x1b\n" +
+	"\x17COMMENT_STATUS_ACCEPTED\x10\x02\x12\x1b\n" +
+	"\x17COMMENT_STATUS_REJECTED\x10\x03\x12\x1b\n" +
+	"\x17COMMENT_STATUS_RESOLVED\x10\x04*u\n" +
+	"\x10CommentReplyRole\x12\"\n" +
+	"\x1eCOMMENT_REPLY_ROLE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17COMMENT_REPLY_ROLE_USER\x10\x01\x12 \n" +
+	"\x1cCOMMENT_REPLY_ROLE_ASSISTANT\x10\x02*\xa1\x01\n" +
+	"\x13CommentExportFormat\x12%\n" +
+	"!COMMENT_EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eCOMMENT_EXPORT_FORMAT_MARKDOWN\x10\x01\x12\x1e\n" +
+	"\x1aCOMMENT_EXPORT_FORMAT_JSON\x10\x02\x12\x1f\n" +
+	"\x1bCOMMENT_EXPORT_FORMAT_LATEX\x10\x032\xa2\t\n" +
+	"\x0eCommentService\x12\x87\x01\n" +
+	"\x10CommentsAccepted\x12#.comment.v1.CommentsAcceptedRequest\x1a$.comment.v1.CommentsAcceptedResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/_pd/api/v1/comments/accepted\x12\x81\x01\n" +
+	"\x0eRejectComments\x12!.comment.v1.RejectCommentsRequest\x1a\".comment.v1.RejectCommentsResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/_pd/api/v1/comments/rejected\x12\x84\x01\n" +
+	"\x0fResolveComments\x12\".comment.v1.ResolveCommentsRequest\x1a#.comment.v1.ResolveCommentsResponse\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/_pd/api/v1/comments/resolved\x12\x85\x01\n" +
+	"\fListComments\x12\x1f.comment.v1.ListCommentsRequest\x1a .comment.v1.ListCommentsResponse\"2\x82\xd3\xe4\x93\x02,\x12*/_pd/api/v1/projects/{project_id}/comments\x12\x8c\x01\n" +
+	"\n" +
+	"GetComment\x12\x1d.comment.v1.GetCommentRequest\x1a\x1e.comment.v1.GetCommentResponse\"?\x82\xd3\xe4\x93\x029\x127/_pd/api/v1/projects/{project_id}/comments/{comment_id}\x12\xa6\x01\n" +
+	"\x0fAddCommentReply\x12\".comment.v1.AddCommentReplyRequest\x1a#.comment.v1.AddCommentReplyResponse\"J\x82\xd3\xe4\x93\x02D:\x01*\"?/_pd/api/v1/projects/{project_id}/comments/{comment_id}/replies\x12\xa2\x01\n" +
+	"\x0fAskAboutComment\x12\".comment.v1.AskAboutCommentRequest\x1a#.comment.v1.AskAboutCommentResponse\"F\x82\xd3\xe4\x93\x02@:\x01*\";/_pd/api/v1/projects/{project_id}/comments/{comment_id}/ask\x12\x95\x01\n" +
+	"\x0eExportComments\x12!.comment.v1.ExportCommentsRequest\x1a\".comment.v1.ExportCommentsResponse\"<\x82\xd3\xe4\x93\x026:\x01*\"1/_pd/api/v1/projects/{project_id}/comments/exportB\x97\x01\n" +
 	"\x0ecom.comment.v1B\fCommentProtoP\x01Z.paperdebugger/pkg/gen/api/comment/v1;commentv1\xa2\x02\x03CXX\xaa\x02\n" +
 	"Comment.V1\xca\x02\n" +
 	"Comment\\V1\xe2\x02\x16Comment\\V1\\GPBMetadata\xea\x02\vComment::V1b\x06proto3"
@@ -159,19 +1508,72 @@ func file_comment_v1_comment_proto_rawDescGZIP() []byte {
 	return file_comment_v1_comment_proto_rawDescData
 }
 
-var file_comment_v1_comment_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_comment_v1_comment_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_comment_v1_comment_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_comment_v1_comment_proto_goTypes = []any{
-	(*CommentsAcceptedRequest)(nil),  // 0: comment.v1.CommentsAcceptedRequest
-	(*CommentsAcceptedResponse)(nil), // 1: comment.v1.CommentsAcceptedResponse
+	(CommentStatus)(0),               // 0: comment.v1.CommentStatus
+	(CommentReplyRole)(0),            // 1: comment.v1.CommentReplyRole
+	(CommentExportFormat)(0),         // 2: comment.v1.CommentExportFormat
+	(*Comment)(nil),                  // 3: comment.v1.Comment
+	(*CommentReply)(nil),             // 4: comment.v1.CommentReply
+	(*CommentsAcceptedRequest)(nil),  // 5: comment.v1.CommentsAcceptedRequest
+	(*CommentsAcceptedResponse)(nil), // 6: comment.v1.CommentsAcceptedResponse
+	(*RejectCommentsRequest)(nil),    // 7: comment.v1.RejectCommentsRequest
+	(*RejectCommentsResponse)(nil),   // 8: comment.v1.RejectCommentsResponse
+	(*ResolveCommentsRequest)(nil),   // 9: comment.v1.ResolveCommentsRequest
+	(*ResolveCommentsResponse)(nil),  // 10: comment.v1.ResolveCommentsResponse
+	(*ListCommentsRequest)(nil),      // 11: comment.v1.ListCommentsRequest
+	(*ListCommentsResponse)(nil),     // 12: comment.v1.ListCommentsResponse
+	(*GetCommentRequest)(nil),        // 13: comment.v1.GetCommentRequest
+	(*GetCommentResponse)(nil),       // 14: comment.v1.GetCommentResponse
+	(*AddCommentReplyRequest)(nil),   // 15: comment.v1.AddCommentReplyRequest
+	(*AddCommentReplyResponse)(nil),  // 16: comment.v1.AddCommentReplyResponse
+	(*AskAboutCommentRequest)(nil),   // 17: comment.v1.AskAboutCommentRequest
+	(*AskAboutCommentResponse)(nil),  // 18: comment.v1.AskAboutCommentResponse
+	(*ExportCommentsRequest)(nil),    // 19: comment.v1.ExportCommentsRequest
+	(*ExportedFile)(nil),             // 20: comment.v1.ExportedFile
+	(*ExportCommentsResponse)(nil),   // 21: comment.v1.ExportCommentsResponse
+	(*timestamppb.Timestamp)(nil),    // 22: google.protobuf.Timestamp
 }
 var file_comment_v1_comment_proto_depIdxs = []int32{
-	0, // 0: comment.v1.CommentService.CommentsAccepted:input_type -> comment.v1.CommentsAcceptedRequest
-	1, // 1: comment.v1.CommentService.CommentsAccepted:output_type -> comment.v1.CommentsAcceptedResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: comment.v1.Comment.status:type_name -> comment.v1.CommentStatus
+	22, // 1: comment.v1.Comment.created_at:type_name -> google.protobuf.Timestamp
+	22, // 2: comment.v1.Comment.updated_at:type_name -> google.protobuf.Timestamp
+	4,  // 3: comment.v1.Comment.replies:type_name -> comment.v1.CommentReply
+	1,  // 4: comment.v1.CommentReply.role:type_name -> comment.v1.CommentReplyRole
+	22, // 5: comment.v1.CommentReply.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 6: comment.v1.RejectCommentsResponse.comments:type_name -> comment.v1.Comment
+	3,  // 7: comment.v1.ResolveCommentsResponse.comments:type_name -> comment.v1.Comment
+	0,  // 8: comment.v1.ListCommentsRequest.statuses:type_name -> comment.v1.CommentStatus
+	3,  // 9: comment.v1.ListCommentsResponse.comments:type_name -> comment.v1.Comment
+	3,  // 10: comment.v1.GetCommentResponse.comment:type_name -> comment.v1.Comment
+	3,  // 11: comment.v1.AddCommentReplyResponse.comment:type_name -> comment.v1.Comment
+	3,  // 12: comment.v1.AskAboutCommentResponse.comment:type_name -> comment.v1.Comment
+	4,  // 13: comment.v1.AskAboutCommentResponse.answer:type_name -> comment.v1.CommentReply
+	2,  // 14: comment.v1.ExportCommentsRequest.format:type_name -> comment.v1.CommentExportFormat
+	0,  // 15: comment.v1.ExportCommentsRequest.statuses:type_name -> comment.v1.CommentStatus
+	20, // 16: comment.v1.ExportCommentsResponse.files:type_name -> comment.v1.ExportedFile
+	5,  // 17: comment.v1.CommentService.CommentsAccepted:input_type -> comment.v1.CommentsAcceptedRequest
+	7,  // 18: comment.v1.CommentService.RejectComments:input_type -> comment.v1.RejectCommentsRequest
+	9,  // 19: comment.v1.CommentService.ResolveComments:input_type -> comment.v1.ResolveCommentsRequest
+	11, // 20: comment.v1.CommentService.ListComments:input_type -> comment.v1.ListCommentsRequest
+	13, // 21: comment.v1.CommentService.GetComment:input_type -> comment.v1.GetCommentRequest
+	15, // 22: comment.v1.CommentService.AddCommentReply:input_type -> comment.v1.AddCommentReplyRequest
+	17, // 23: comment.v1.CommentService.AskAboutComment:input_type -> comment.v1.AskAboutCommentRequest
+	19, // 24: comment.v1.CommentService.ExportComments:input_type -> comment.v1.ExportCommentsRequest
+	6,  // 25: comment.v1.CommentService.CommentsAccepted:output_type -> comment.v1.CommentsAcceptedResponse
+	8,  // 26: comment.v1.CommentService.RejectComments:output_type -> comment.v1.RejectCommentsResponse
+	10, // 27: comment.v1.CommentService.ResolveComments:output_type -> comment.v1.ResolveCommentsResponse
+	12, // 28: comment.v1.CommentService.ListComments:output_type -> comment.v1.ListCommentsResponse
+	14, // 29: comment.v1.CommentService.GetComment:output_type -> comment.v1.GetCommentResponse
+	16, // 30: comment.v1.CommentService.AddCommentReply:output_type -> comment.v1.AddCommentReplyResponse
+	18, // 31: comment.v1.CommentService.AskAboutComment:output_type -> comment.v1.AskAboutCommentResponse
+	21, // 32: comment.v1.CommentService.ExportComments:output_type -> comment.v1.ExportCommentsResponse
+	25, // [25:33] is the sub-list for method output_type
+	17, // [17:25] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_comment_v1_comment_proto_init() }
@@ -179,18 +1581,21 @@ func file_comment_v1_comment_proto_init() {
 	if File_comment_v1_comment_proto != nil {
 		return
 	}
+	file_comment_v1_comment_proto_msgTypes[8].OneofWrappers = []any{}
+	file_comment_v1_comment_proto_msgTypes[14].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_comment_v1_comment_proto_rawDesc), len(file_comment_v1_comment_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      3,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_comment_v1_comment_proto_goTypes,
 		DependencyIndexes: file_comment_v1_comment_proto_depIdxs,
+		EnumInfos:         file_comment_v1_comment_proto_enumTypes,
 		MessageInfos:      file_comment_v1_comment_proto_msgTypes,
 	}.Build()
 	File_comment_v1_comment_proto = out.File
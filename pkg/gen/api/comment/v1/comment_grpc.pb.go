@@ -20,6 +20,13 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	CommentService_CommentsAccepted_FullMethodName = "/comment.v1.CommentService/CommentsAccepted"
+	CommentService_RejectComments_FullMethodName   = "/comment.v1.CommentService/RejectComments"
+	CommentService_ResolveComments_FullMethodName  = "/comment.v1.CommentService/ResolveComments"
+	CommentService_ListComments_FullMethodName     = "/comment.v1.CommentService/ListComments"
+	CommentService_GetComment_FullMethodName       = "/comment.v1.CommentService/GetComment"
+	CommentService_AddCommentReply_FullMethodName  = "/comment.v1.CommentService/AddCommentReply"
+	CommentService_AskAboutComment_FullMethodName  = "/comment.v1.CommentService/AskAboutComment"
+	CommentService_ExportComments_FullMethodName   = "/comment.v1.CommentService/ExportComments"
 )
 
 // CommentServiceClient is the client API for CommentService service.
@@ -27,6 +34,13 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CommentServiceClient interface {
 	CommentsAccepted(ctx context.Context, in *CommentsAcceptedRequest, opts ...grpc.CallOption) (*CommentsAcceptedResponse, error)
+	RejectComments(ctx context.Context, in *RejectCommentsRequest, opts ...grpc.CallOption) (*RejectCommentsResponse, error)
+	ResolveComments(ctx context.Context, in *ResolveCommentsRequest, opts ...grpc.CallOption) (*ResolveCommentsResponse, error)
+	ListComments(ctx context.Context, in *ListCommentsRequest, opts ...grpc.CallOption) (*ListCommentsResponse, error)
+	GetComment(ctx context.Context, in *GetCommentRequest, opts ...grpc.CallOption) (*GetCommentResponse, error)
+	AddCommentReply(ctx context.Context, in *AddCommentReplyRequest, opts ...grpc.CallOption) (*AddCommentReplyResponse, error)
+	AskAboutComment(ctx context.Context, in *AskAboutCommentRequest, opts ...grpc.CallOption) (*AskAboutCommentResponse, error)
+	ExportComments(ctx context.Context, in *ExportCommentsRequest, opts ...grpc.CallOption) (*ExportCommentsResponse, error)
 }
 
 type commentServiceClient struct {
@@ -47,11 +61,88 @@ func (c *commentServiceClient) CommentsAccepted(ctx context.Context, in *Comment
 	return out, nil
 }
 
+func (c *commentServiceClient) RejectComments(ctx context.Context, in *RejectCommentsRequest, opts ...grpc.CallOption) (*RejectCommentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectCommentsResponse)
+	err := c.cc.Invoke(ctx, CommentService_RejectComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ResolveComments(ctx context.Context, in *ResolveCommentsRequest, opts ...grpc.CallOption) (*ResolveCommentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveCommentsResponse)
+	err := c.cc.Invoke(ctx, CommentService_ResolveComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ListComments(ctx context.Context, in *ListCommentsRequest, opts ...grpc.CallOption) (*ListCommentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCommentsResponse)
+	err := c.cc.Invoke(ctx, CommentService_ListComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) GetComment(ctx context.Context, in *GetCommentRequest, opts ...grpc.CallOption) (*GetCommentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCommentResponse)
+	err := c.cc.Invoke(ctx, CommentService_GetComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) AddCommentReply(ctx context.Context, in *AddCommentReplyRequest, opts ...grpc.CallOption) (*AddCommentReplyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddCommentReplyResponse)
+	err := c.cc.Invoke(ctx, CommentService_AddCommentReply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) AskAboutComment(ctx context.Context, in *AskAboutCommentRequest, opts ...grpc.CallOption) (*AskAboutCommentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AskAboutCommentResponse)
+	err := c.cc.Invoke(ctx, CommentService_AskAboutComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commentServiceClient) ExportComments(ctx context.Context, in *ExportCommentsRequest, opts ...grpc.CallOption) (*ExportCommentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportCommentsResponse)
+	err := c.cc.Invoke(ctx, CommentService_ExportComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CommentServiceServer is the server API for CommentService service.
 // All implementations must embed UnimplementedCommentServiceServer
 // for forward compatibility.
 type CommentServiceServer interface {
 	CommentsAccepted(context.Context, *CommentsAcceptedRequest) (*CommentsAcceptedResponse, error)
+	RejectComments(context.Context, *RejectCommentsRequest) (*RejectCommentsResponse, error)
+	ResolveComments(context.Context, *ResolveCommentsRequest) (*ResolveCommentsResponse, error)
+	ListComments(context.Context, *ListCommentsRequest) (*ListCommentsResponse, error)
+	GetComment(context.Context, *GetCommentRequest) (*GetCommentResponse, error)
+	AddCommentReply(context.Context, *AddCommentReplyRequest) (*AddCommentReplyResponse, error)
+	AskAboutComment(context.Context, *AskAboutCommentRequest) (*AskAboutCommentResponse, error)
+	ExportComments(context.Context, *ExportCommentsRequest) (*ExportCommentsResponse, error)
 	mustEmbedUnimplementedCommentServiceServer()
 }
 
@@ -65,6 +156,27 @@ type UnimplementedCommentServiceServer struct{}
 func (UnimplementedCommentServiceServer) CommentsAccepted(context.Context, *CommentsAcceptedRequest) (*CommentsAcceptedResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CommentsAccepted not implemented")
 }
+func (UnimplementedCommentServiceServer) RejectComments(context.Context, *RejectCommentsRequest) (*RejectCommentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RejectComments not implemented")
+}
+func (UnimplementedCommentServiceServer) ResolveComments(context.Context, *ResolveCommentsRequest) (*ResolveCommentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveComments not implemented")
+}
+func (UnimplementedCommentServiceServer) ListComments(context.Context, *ListCommentsRequest) (*ListCommentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListComments not implemented")
+}
+func (UnimplementedCommentServiceServer) GetComment(context.Context, *GetCommentRequest) (*GetCommentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetComment not implemented")
+}
+func (UnimplementedCommentServiceServer) AddCommentReply(context.Context, *AddCommentReplyRequest) (*AddCommentReplyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AddCommentReply not implemented")
+}
+func (UnimplementedCommentServiceServer) AskAboutComment(context.Context, *AskAboutCommentRequest) (*AskAboutCommentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AskAboutComment not implemented")
+}
+func (UnimplementedCommentServiceServer) ExportComments(context.Context, *ExportCommentsRequest) (*ExportCommentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportComments not implemented")
+}
 func (UnimplementedCommentServiceServer) mustEmbedUnimplementedCommentServiceServer() {}
 func (UnimplementedCommentServiceServer) testEmbeddedByValue()                        {}
 
@@ -104,6 +216,132 @@ func _CommentService_CommentsAccepted_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CommentService_RejectComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).RejectComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_RejectComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).RejectComments(ctx, req.(*RejectCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ResolveComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ResolveComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_ResolveComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ResolveComments(ctx, req.(*ResolveCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ListComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ListComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_ListComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ListComments(ctx, req.(*ListCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_GetComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).GetComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_GetComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).GetComment(ctx, req.(*GetCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_AddCommentReply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddCommentReplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).AddCommentReply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_AddCommentReply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).AddCommentReply(ctx, req.(*AddCommentReplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_AskAboutComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AskAboutCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).AskAboutComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_AskAboutComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).AskAboutComment(ctx, req.(*AskAboutCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommentService_ExportComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommentServiceServer).ExportComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommentService_ExportComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommentServiceServer).ExportComments(ctx, req.(*ExportCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CommentService_ServiceDesc is the grpc.ServiceDesc for CommentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +353,34 @@ var CommentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CommentsAccepted",
 			Handler:    _CommentService_CommentsAccepted_Handler,
 		},
+		{
+			MethodName: "RejectComments",
+			Handler:    _CommentService_RejectComments_Handler,
+		},
+		{
+			MethodName: "ResolveComments",
+			Handler:    _CommentService_ResolveComments_Handler,
+		},
+		{
+			MethodName: "ListComments",
+			Handler:    _CommentService_ListComments_Handler,
+		},
+		{
+			MethodName: "GetComment",
+			Handler:    _CommentService_GetComment_Handler,
+		},
+		{
+			MethodName: "AddCommentReply",
+			Handler:    _CommentService_AddCommentReply_Handler,
+		},
+		{
+			MethodName: "AskAboutComment",
+			Handler:    _CommentService_AskAboutComment_Handler,
+		},
+		{
+			MethodName: "ExportComments",
+			Handler:    _CommentService_ExportComments_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "comment/v1/comment.proto",
@@ -23,6 +23,61 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type DocPatchType int32
+
+const (
+	DocPatchType_DOC_PATCH_TYPE_UNSPECIFIED DocPatchType = 0
+	DocPatchType_DOC_PATCH_TYPE_EDIT        DocPatchType = 1 // Apply line edits
+	DocPatchType_DOC_PATCH_TYPE_ADD         DocPatchType = 2 // Add a new doc with lines at filepath
+	DocPatchType_DOC_PATCH_TYPE_REMOVE      DocPatchType = 3
+	DocPatchType_DOC_PATCH_TYPE_RENAME      DocPatchType = 4 // Move the doc to filepath
+)
+
+// Enum value maps for DocPatchType.
+var (
+	DocPatchType_name = map[int32]string{
+		0: "DOC_PATCH_TYPE_UNSPECIFIED",
+		1: "DOC_PATCH_TYPE_EDIT",
+		2: "DOC_PATCH_TYPE_ADD",
+		3: "DOC_PATCH_TYPE_REMOVE",
+		4: "DOC_PATCH_TYPE_RENAME",
+	}
+	DocPatchType_value = map[string]int32{
+		"DOC_PATCH_TYPE_UNSPECIFIED": 0,
+		"DOC_PATCH_TYPE_EDIT":        1,
+		"DOC_PATCH_TYPE_ADD":         2,
+		"DOC_PATCH_TYPE_REMOVE":      3,
+		"DOC_PATCH_TYPE_RENAME":      4,
+	}
+)
+
+func (x DocPatchType) Enum() *DocPatchType {
+	p := new(DocPatchType)
+	*p = x
+	return p
+}
+
+func (x DocPatchType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DocPatchType) Descriptor() protoreflect.EnumDescriptor {
+	return file_project_v1_project_proto_enumTypes[0].Descriptor()
+}
+
+func (DocPatchType) Type() protoreflect.EnumType {
+	return &file_project_v1_project_proto_enumTypes[0]
+}
+
+func (x DocPatchType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DocPatchType.Descriptor instead.
+func (DocPatchType) EnumDescriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{0}
+}
+
 type Project struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -181,6 +236,7 @@ type UpsertProjectRequest struct {
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	RootDocId     string                 `protobuf:"bytes,3,opt,name=root_doc_id,json=rootDocId,proto3" json:"root_doc_id,omitempty"`
 	Docs          []*ProjectDoc          `protobuf:"bytes,4,rep,name=docs,proto3" json:"docs,omitempty"`
+	FilePaths     []string               `protobuf:"bytes,5,rep,name=file_paths,json=filePaths,proto3" json:"file_paths,omitempty"` // Paths of the files that are not docs, e.g. images, to check \includegraphics
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -243,6 +299,13 @@ func (x *UpsertProjectRequest) GetDocs() []*ProjectDoc {
 	return nil
 }
 
+func (x *UpsertProjectRequest) GetFilePaths() []string {
+	if x != nil {
+		return x.FilePaths
+	}
+	return nil
+}
+
 type UpsertProjectResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Project       *Project               `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
@@ -287,6 +350,264 @@ func (x *UpsertProjectResponse) GetProject() *Project {
 	return nil
 }
 
+// DocLineEdit replaces delete_count lines from start_line with lines. Lines are numbered from 0 in the base version,
+// the edits of a patch must not overlap.
+type DocLineEdit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartLine     int32                  `protobuf:"varint,1,opt,name=start_line,json=startLine,proto3" json:"start_line,omitempty"`
+	DeleteCount   int32                  `protobuf:"varint,2,opt,name=delete_count,json=deleteCount,proto3" json:"delete_count,omitempty"`
+	Lines         []string               `protobuf:"bytes,3,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocLineEdit) Reset() {
+	*x = DocLineEdit{}
+	mi := &file_project_v1_project_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocLineEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocLineEdit) ProtoMessage() {}
+
+func (x *DocLineEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocLineEdit.ProtoReflect.Descriptor instead.
+func (*DocLineEdit) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DocLineEdit) GetStartLine() int32 {
+	if x != nil {
+		return x.StartLine
+	}
+	return 0
+}
+
+func (x *DocLineEdit) GetDeleteCount() int32 {
+	if x != nil {
+		return x.DeleteCount
+	}
+	return 0
+}
+
+func (x *DocLineEdit) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type DocPatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          DocPatchType           `protobuf:"varint,1,opt,name=type,proto3,enum=project.v1.DocPatchType" json:"type,omitempty"`
+	DocId         string                 `protobuf:"bytes,2,opt,name=doc_id,json=docId,proto3" json:"doc_id,omitempty"`
+	BaseVersion   int32                  `protobuf:"varint,3,opt,name=base_version,json=baseVersion,proto3" json:"base_version,omitempty"` // Current version of the doc, except for ADD
+	Version       int32                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`                            // Version of the doc after the patch, not less than base_version
+	Filepath      string                 `protobuf:"bytes,5,opt,name=filepath,proto3" json:"filepath,omitempty"`                           // For ADD and RENAME
+	Lines         []string               `protobuf:"bytes,6,rep,name=lines,proto3" json:"lines,omitempty"`                                 // For ADD
+	Edits         []*DocLineEdit         `protobuf:"bytes,7,rep,name=edits,proto3" json:"edits,omitempty"`                                 // For EDIT
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocPatch) Reset() {
+	*x = DocPatch{}
+	mi := &file_project_v1_project_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocPatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocPatch) ProtoMessage() {}
+
+func (x *DocPatch) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocPatch.ProtoReflect.Descriptor instead.
+func (*DocPatch) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DocPatch) GetType() DocPatchType {
+	if x != nil {
+		return x.Type
+	}
+	return DocPatchType_DOC_PATCH_TYPE_UNSPECIFIED
+}
+
+func (x *DocPatch) GetDocId() string {
+	if x != nil {
+		return x.DocId
+	}
+	return ""
+}
+
+func (x *DocPatch) GetBaseVersion() int32 {
+	if x != nil {
+		return x.BaseVersion
+	}
+	return 0
+}
+
+func (x *DocPatch) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *DocPatch) GetFilepath() string {
+	if x != nil {
+		return x.Filepath
+	}
+	return ""
+}
+
+func (x *DocPatch) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *DocPatch) GetEdits() []*DocLineEdit {
+	if x != nil {
+		return x.Edits
+	}
+	return nil
+}
+
+type PatchProjectDocsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Patches       []*DocPatch            `protobuf:"bytes,2,rep,name=patches,proto3" json:"patches,omitempty"` // At most one patch per doc
+	RootDocId     *string                `protobuf:"bytes,3,opt,name=root_doc_id,json=rootDocId,proto3,oneof" json:"root_doc_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatchProjectDocsRequest) Reset() {
+	*x = PatchProjectDocsRequest{}
+	mi := &file_project_v1_project_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchProjectDocsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchProjectDocsRequest) ProtoMessage() {}
+
+func (x *PatchProjectDocsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchProjectDocsRequest.ProtoReflect.Descriptor instead.
+func (*PatchProjectDocsRequest) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PatchProjectDocsRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *PatchProjectDocsRequest) GetPatches() []*DocPatch {
+	if x != nil {
+		return x.Patches
+	}
+	return nil
+}
+
+func (x *PatchProjectDocsRequest) GetRootDocId() string {
+	if x != nil && x.RootDocId != nil {
+		return *x.RootDocId
+	}
+	return ""
+}
+
+type PatchProjectDocsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Project       *Project               `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatchProjectDocsResponse) Reset() {
+	*x = PatchProjectDocsResponse{}
+	mi := &file_project_v1_project_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatchProjectDocsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatchProjectDocsResponse) ProtoMessage() {}
+
+func (x *PatchProjectDocsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatchProjectDocsResponse.ProtoReflect.Descriptor instead.
+func (*PatchProjectDocsResponse) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PatchProjectDocsResponse) GetProject() *Project {
+	if x != nil {
+		return x.Project
+	}
+	return nil
+}
+
 type GetProjectRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
@@ -296,7 +617,7 @@ type GetProjectRequest struct {
 
 func (x *GetProjectRequest) Reset() {
 	*x = GetProjectRequest{}
-	mi := &file_project_v1_project_proto_msgTypes[4]
+	mi := &file_project_v1_project_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -308,7 +629,7 @@ func (x *GetProjectRequest) String() string {
 func (*GetProjectRequest) ProtoMessage() {}
 
 func (x *GetProjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[4]
+	mi := &file_project_v1_project_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -321,7 +642,7 @@ func (x *GetProjectRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProjectRequest.ProtoReflect.Descriptor instead.
 func (*GetProjectRequest) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{4}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetProjectRequest) GetProjectId() string {
@@ -340,7 +661,7 @@ type GetProjectResponse struct {
 
 func (x *GetProjectResponse) Reset() {
 	*x = GetProjectResponse{}
-	mi := &file_project_v1_project_proto_msgTypes[5]
+	mi := &file_project_v1_project_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -352,7 +673,7 @@ func (x *GetProjectResponse) String() string {
 func (*GetProjectResponse) ProtoMessage() {}
 
 func (x *GetProjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[5]
+	mi := &file_project_v1_project_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -365,7 +686,7 @@ func (x *GetProjectResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProjectResponse.ProtoReflect.Descriptor instead.
 func (*GetProjectResponse) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{5}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetProjectResponse) GetProject() *Project {
@@ -386,7 +707,7 @@ type RunProjectPaperScoreRequest struct {
 
 func (x *RunProjectPaperScoreRequest) Reset() {
 	*x = RunProjectPaperScoreRequest{}
-	mi := &file_project_v1_project_proto_msgTypes[6]
+	mi := &file_project_v1_project_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -398,7 +719,7 @@ func (x *RunProjectPaperScoreRequest) String() string {
 func (*RunProjectPaperScoreRequest) ProtoMessage() {}
 
 func (x *RunProjectPaperScoreRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[6]
+	mi := &file_project_v1_project_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -411,7 +732,7 @@ func (x *RunProjectPaperScoreRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunProjectPaperScoreRequest.ProtoReflect.Descriptor instead.
 func (*RunProjectPaperScoreRequest) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{6}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *RunProjectPaperScoreRequest) GetProjectId() string {
@@ -438,7 +759,7 @@ type RunProjectPaperScoreResponse struct {
 
 func (x *RunProjectPaperScoreResponse) Reset() {
 	*x = RunProjectPaperScoreResponse{}
-	mi := &file_project_v1_project_proto_msgTypes[7]
+	mi := &file_project_v1_project_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -450,7 +771,7 @@ func (x *RunProjectPaperScoreResponse) String() string {
 func (*RunProjectPaperScoreResponse) ProtoMessage() {}
 
 func (x *RunProjectPaperScoreResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[7]
+	mi := &file_project_v1_project_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -463,7 +784,7 @@ func (x *RunProjectPaperScoreResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunProjectPaperScoreResponse.ProtoReflect.Descriptor instead.
 func (*RunProjectPaperScoreResponse) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{7}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *RunProjectPaperScoreResponse) GetProjectId() string {
@@ -491,7 +812,7 @@ type RunProjectPaperScoreCommentRequest struct {
 
 func (x *RunProjectPaperScoreCommentRequest) Reset() {
 	*x = RunProjectPaperScoreCommentRequest{}
-	mi := &file_project_v1_project_proto_msgTypes[8]
+	mi := &file_project_v1_project_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -503,7 +824,7 @@ func (x *RunProjectPaperScoreCommentRequest) String() string {
 func (*RunProjectPaperScoreCommentRequest) ProtoMessage() {}
 
 func (x *RunProjectPaperScoreCommentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[8]
+	mi := &file_project_v1_project_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -516,7 +837,7 @@ func (x *RunProjectPaperScoreCommentRequest) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use RunProjectPaperScoreCommentRequest.ProtoReflect.Descriptor instead.
 func (*RunProjectPaperScoreCommentRequest) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{8}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *RunProjectPaperScoreCommentRequest) GetProjectId() string {
@@ -543,7 +864,7 @@ type RunProjectPaperScoreCommentResponse struct {
 
 func (x *RunProjectPaperScoreCommentResponse) Reset() {
 	*x = RunProjectPaperScoreCommentResponse{}
-	mi := &file_project_v1_project_proto_msgTypes[9]
+	mi := &file_project_v1_project_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -555,7 +876,7 @@ func (x *RunProjectPaperScoreCommentResponse) String() string {
 func (*RunProjectPaperScoreCommentResponse) ProtoMessage() {}
 
 func (x *RunProjectPaperScoreCommentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[9]
+	mi := &file_project_v1_project_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -568,7 +889,7 @@ func (x *RunProjectPaperScoreCommentResponse) ProtoReflect() protoreflect.Messag
 
 // Deprecated: Use RunProjectPaperScoreCommentResponse.ProtoReflect.Descriptor instead.
 func (*RunProjectPaperScoreCommentResponse) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{9}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *RunProjectPaperScoreCommentResponse) GetProjectId() string {
@@ -599,7 +920,7 @@ type RunProjectOverleafCommentRequest struct {
 
 func (x *RunProjectOverleafCommentRequest) Reset() {
 	*x = RunProjectOverleafCommentRequest{}
-	mi := &file_project_v1_project_proto_msgTypes[10]
+	mi := &file_project_v1_project_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -611,7 +932,7 @@ func (x *RunProjectOverleafCommentRequest) String() string {
 func (*RunProjectOverleafCommentRequest) ProtoMessage() {}
 
 func (x *RunProjectOverleafCommentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[10]
+	mi := &file_project_v1_project_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -624,7 +945,7 @@ func (x *RunProjectOverleafCommentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RunProjectOverleafCommentRequest.ProtoReflect.Descriptor instead.
 func (*RunProjectOverleafCommentRequest) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{10}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *RunProjectOverleafCommentRequest) GetProjectId() string {
@@ -672,7 +993,7 @@ type RunProjectOverleafCommentResponse struct {
 
 func (x *RunProjectOverleafCommentResponse) Reset() {
 	*x = RunProjectOverleafCommentResponse{}
-	mi := &file_project_v1_project_proto_msgTypes[11]
+	mi := &file_project_v1_project_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -684,7 +1005,7 @@ func (x *RunProjectOverleafCommentResponse) String() string {
 func (*RunProjectOverleafCommentResponse) ProtoMessage() {}
 
 func (x *RunProjectOverleafCommentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[11]
+	mi := &file_project_v1_project_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -697,7 +1018,7 @@ func (x *RunProjectOverleafCommentResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use RunProjectOverleafCommentResponse.ProtoReflect.Descriptor instead.
 func (*RunProjectOverleafCommentResponse) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{11}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *RunProjectOverleafCommentResponse) GetProjectId() string {
@@ -714,6 +1035,212 @@ func (x *RunProjectOverleafCommentResponse) GetComments() []*OverleafComment {
 	return nil
 }
 
+type LintProjectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	AddComments   bool                   `protobuf:"varint,2,opt,name=add_comments,json=addComments,proto3" json:"add_comments,omitempty"` // Also add the findings without an open comment as Overleaf comments
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintProjectRequest) Reset() {
+	*x = LintProjectRequest{}
+	mi := &file_project_v1_project_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintProjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintProjectRequest) ProtoMessage() {}
+
+func (x *LintProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintProjectRequest.ProtoReflect.Descriptor instead.
+func (*LintProjectRequest) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LintProjectRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *LintProjectRequest) GetAddComments() bool {
+	if x != nil {
+		return x.AddComments
+	}
+	return false
+}
+
+type LintFinding struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of undefined_reference, unused_label, duplicate_label, missing_citation, missing_graphics
+	// and unresolved_include
+	Rule          string `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	Key           string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`         // The label, citation key or file the finding is about
+	Command       string `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"` // The source of the command, e.g. \ref{fig:setup}
+	Message       string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Section       string `protobuf:"bytes,5,opt,name=section,proto3" json:"section,omitempty"`
+	FilePath      string `protobuf:"bytes,6,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Line          int32  `protobuf:"varint,7,opt,name=line,proto3" json:"line,omitempty"` // Starts at 1
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintFinding) Reset() {
+	*x = LintFinding{}
+	mi := &file_project_v1_project_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintFinding) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintFinding) ProtoMessage() {}
+
+func (x *LintFinding) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintFinding.ProtoReflect.Descriptor instead.
+func (*LintFinding) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LintFinding) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *LintFinding) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LintFinding) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *LintFinding) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LintFinding) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *LintFinding) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *LintFinding) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+type LintProjectResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Findings      []*LintFinding         `protobuf:"bytes,2,rep,name=findings,proto3" json:"findings,omitempty"`
+	Comments      []*OverleafComment     `protobuf:"bytes,3,rep,name=comments,proto3" json:"comments,omitempty"` // The comments added, only if add_comments is set
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LintProjectResponse) Reset() {
+	*x = LintProjectResponse{}
+	mi := &file_project_v1_project_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LintProjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LintProjectResponse) ProtoMessage() {}
+
+func (x *LintProjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LintProjectResponse.ProtoReflect.Descriptor instead.
+func (*LintProjectResponse) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LintProjectResponse) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *LintProjectResponse) GetFindings() []*LintFinding {
+	if x != nil {
+		return x.Findings
+	}
+	return nil
+}
+
+func (x *LintProjectResponse) GetComments() []*OverleafComment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
 type OverleafComment struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CommentId     string                 `protobuf:"bytes,1,opt,name=comment_id,json=commentId,proto3" json:"comment_id,omitempty"`
@@ -734,7 +1261,7 @@ type OverleafComment struct {
 
 func (x *OverleafComment) Reset() {
 	*x = OverleafComment{}
-	mi := &file_project_v1_project_proto_msgTypes[12]
+	mi := &file_project_v1_project_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -746,7 +1273,7 @@ func (x *OverleafComment) String() string {
 func (*OverleafComment) ProtoMessage() {}
 
 func (x *OverleafComment) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[12]
+	mi := &file_project_v1_project_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -759,7 +1286,7 @@ func (x *OverleafComment) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OverleafComment.ProtoReflect.Descriptor instead.
 func (*OverleafComment) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{12}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *OverleafComment) GetCommentId() string {
@@ -848,7 +1375,7 @@ type PaperScoreCommentResult struct {
 
 func (x *PaperScoreCommentResult) Reset() {
 	*x = PaperScoreCommentResult{}
-	mi := &file_project_v1_project_proto_msgTypes[13]
+	mi := &file_project_v1_project_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -860,7 +1387,7 @@ func (x *PaperScoreCommentResult) String() string {
 func (*PaperScoreCommentResult) ProtoMessage() {}
 
 func (x *PaperScoreCommentResult) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[13]
+	mi := &file_project_v1_project_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -873,7 +1400,7 @@ func (x *PaperScoreCommentResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PaperScoreCommentResult.ProtoReflect.Descriptor instead.
 func (*PaperScoreCommentResult) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{13}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *PaperScoreCommentResult) GetResults() []*PaperScoreCommentEntry {
@@ -896,7 +1423,7 @@ type PaperScoreCommentEntry struct {
 
 func (x *PaperScoreCommentEntry) Reset() {
 	*x = PaperScoreCommentEntry{}
-	mi := &file_project_v1_project_proto_msgTypes[14]
+	mi := &file_project_v1_project_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -908,7 +1435,7 @@ func (x *PaperScoreCommentEntry) String() string {
 func (*PaperScoreCommentEntry) ProtoMessage() {}
 
 func (x *PaperScoreCommentEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[14]
+	mi := &file_project_v1_project_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -921,7 +1448,7 @@ func (x *PaperScoreCommentEntry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PaperScoreCommentEntry.ProtoReflect.Descriptor instead.
 func (*PaperScoreCommentEntry) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{14}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *PaperScoreCommentEntry) GetSection() string {
@@ -964,7 +1491,7 @@ type PaperScoreResult struct {
 
 func (x *PaperScoreResult) Reset() {
 	*x = PaperScoreResult{}
-	mi := &file_project_v1_project_proto_msgTypes[15]
+	mi := &file_project_v1_project_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -976,7 +1503,7 @@ func (x *PaperScoreResult) String() string {
 func (*PaperScoreResult) ProtoMessage() {}
 
 func (x *PaperScoreResult) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[15]
+	mi := &file_project_v1_project_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -989,7 +1516,7 @@ func (x *PaperScoreResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PaperScoreResult.ProtoReflect.Descriptor instead.
 func (*PaperScoreResult) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{15}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *PaperScoreResult) GetScore() float32 {
@@ -1029,7 +1556,7 @@ type SuggestionList struct {
 
 func (x *SuggestionList) Reset() {
 	*x = SuggestionList{}
-	mi := &file_project_v1_project_proto_msgTypes[16]
+	mi := &file_project_v1_project_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1041,7 +1568,7 @@ func (x *SuggestionList) String() string {
 func (*SuggestionList) ProtoMessage() {}
 
 func (x *SuggestionList) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[16]
+	mi := &file_project_v1_project_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1054,7 +1581,7 @@ func (x *SuggestionList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SuggestionList.ProtoReflect.Descriptor instead.
 func (*SuggestionList) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{16}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *SuggestionList) GetSuggestions() []string {
@@ -1074,7 +1601,7 @@ type GetProjectInstructionsRequest struct {
 
 func (x *GetProjectInstructionsRequest) Reset() {
 	*x = GetProjectInstructionsRequest{}
-	mi := &file_project_v1_project_proto_msgTypes[17]
+	mi := &file_project_v1_project_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1086,7 +1613,7 @@ func (x *GetProjectInstructionsRequest) String() string {
 func (*GetProjectInstructionsRequest) ProtoMessage() {}
 
 func (x *GetProjectInstructionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[17]
+	mi := &file_project_v1_project_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1099,7 +1626,7 @@ func (x *GetProjectInstructionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProjectInstructionsRequest.ProtoReflect.Descriptor instead.
 func (*GetProjectInstructionsRequest) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{17}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *GetProjectInstructionsRequest) GetProjectId() string {
@@ -1119,7 +1646,7 @@ type GetProjectInstructionsResponse struct {
 
 func (x *GetProjectInstructionsResponse) Reset() {
 	*x = GetProjectInstructionsResponse{}
-	mi := &file_project_v1_project_proto_msgTypes[18]
+	mi := &file_project_v1_project_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1131,7 +1658,7 @@ func (x *GetProjectInstructionsResponse) String() string {
 func (*GetProjectInstructionsResponse) ProtoMessage() {}
 
 func (x *GetProjectInstructionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[18]
+	mi := &file_project_v1_project_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1144,7 +1671,7 @@ func (x *GetProjectInstructionsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProjectInstructionsResponse.ProtoReflect.Descriptor instead.
 func (*GetProjectInstructionsResponse) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{18}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *GetProjectInstructionsResponse) GetProjectId() string {
@@ -1171,7 +1698,7 @@ type UpsertProjectInstructionsRequest struct {
 
 func (x *UpsertProjectInstructionsRequest) Reset() {
 	*x = UpsertProjectInstructionsRequest{}
-	mi := &file_project_v1_project_proto_msgTypes[19]
+	mi := &file_project_v1_project_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1183,7 +1710,7 @@ func (x *UpsertProjectInstructionsRequest) String() string {
 func (*UpsertProjectInstructionsRequest) ProtoMessage() {}
 
 func (x *UpsertProjectInstructionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[19]
+	mi := &file_project_v1_project_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1196,7 +1723,7 @@ func (x *UpsertProjectInstructionsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpsertProjectInstructionsRequest.ProtoReflect.Descriptor instead.
 func (*UpsertProjectInstructionsRequest) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{19}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *UpsertProjectInstructionsRequest) GetProjectId() string {
@@ -1223,7 +1750,7 @@ type UpsertProjectInstructionsResponse struct {
 
 func (x *UpsertProjectInstructionsResponse) Reset() {
 	*x = UpsertProjectInstructionsResponse{}
-	mi := &file_project_v1_project_proto_msgTypes[20]
+	mi := &file_project_v1_project_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1235,7 +1762,7 @@ func (x *UpsertProjectInstructionsResponse) String() string {
 func (*UpsertProjectInstructionsResponse) ProtoMessage() {}
 
 func (x *UpsertProjectInstructionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_project_v1_project_proto_msgTypes[20]
+	mi := &file_project_v1_project_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1248,7 +1775,7 @@ func (x *UpsertProjectInstructionsResponse) ProtoReflect() protoreflect.Message
 
 // Deprecated: Use UpsertProjectInstructionsResponse.ProtoReflect.Descriptor instead.
 func (*UpsertProjectInstructionsResponse) Descriptor() ([]byte, []int) {
-	return file_project_v1_project_proto_rawDescGZIP(), []int{20}
+	return file_project_v1_project_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *UpsertProjectInstructionsResponse) GetProjectId() string {
@@ -1265,71 +1792,576 @@ func (x *UpsertProjectInstructionsResponse) GetInstructions() string {
 	return ""
 }
 
-var File_project_v1_project_proto protoreflect.FileDescriptor
+// Revisions
+type ProjectRevisionDoc struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Filepath      string                 `protobuf:"bytes,3,opt,name=filepath,proto3" json:"filepath,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_project_v1_project_proto_rawDesc = "" +
-	"\n" +
-	"\x18project/v1/project.proto\x12\n" +
-	"project.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xef\x01\n" +
-	"\aProject\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
-	"\n" +
-	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x12\n" +
-	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1e\n" +
-	"\vroot_doc_id\x18\x05 \x01(\tR\trootDocId\x12*\n" +
-	"\x04docs\x18\x06 \x03(\v2\x16.project.v1.ProjectDocR\x04docs\"h\n" +
-	"\n" +
-	"ProjectDoc\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
-	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x1a\n" +
-	"\bfilepath\x18\x03 \x01(\tR\bfilepath\x12\x14\n" +
-	"\x05lines\x18\x04 \x03(\tR\x05lines\"\x95\x01\n" +
-	"\x14UpsertProjectRequest\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1e\n" +
-	"\vroot_doc_id\x18\x03 \x01(\tR\trootDocId\x12*\n" +
-	"\x04docs\x18\x04 \x03(\v2\x16.project.v1.ProjectDocR\x04docs\"F\n" +
-	"\x15UpsertProjectResponse\x12-\n" +
-	"\aproject\x18\x01 \x01(\v2\x13.project.v1.ProjectR\aproject\"2\n" +
-	"\x11GetProjectRequest\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\"C\n" +
-	"\x12GetProjectResponse\x12-\n" +
-	"\aproject\x18\x01 \x01(\v2\x13.project.v1.ProjectR\aproject\"e\n" +
-	"\x1bRunProjectPaperScoreRequest\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\x12'\n" +
-	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\"|\n" +
-	"\x1cRunProjectPaperScoreResponse\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\x12=\n" +
-	"\vpaper_score\x18\x02 \x01(\v2\x1c.project.v1.PaperScoreResultR\n" +
-	"paperScore\"l\n" +
-	"\"RunProjectPaperScoreCommentRequest\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\x12'\n" +
-	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\"\x85\x01\n" +
-	"#RunProjectPaperScoreCommentResponse\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\x12?\n" +
-	"\bcomments\x18\x02 \x03(\v2#.project.v1.PaperScoreCommentResultR\bcomments\"\xb6\x01\n" +
-	" RunProjectOverleafCommentRequest\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x18\n" +
-	"\asection\x18\x02 \x01(\tR\asection\x12\x1f\n" +
-	"\vanchor_text\x18\x03 \x01(\tR\n" +
-	"anchorText\x12\x18\n" +
-	"\acomment\x18\x04 \x01(\tR\acomment\x12\x1e\n" +
-	"\n" +
-	"importance\x18\x05 \x01(\tR\n" +
-	"importance\"{\n" +
-	"!RunProjectOverleafCommentResponse\x12\x1d\n" +
-	"\n" +
-	"project_id\x18\x01 \x01(\tR\tprojectId\x127\n" +
-	"\bcomments\x18\x02 \x03(\v2\x1b.project.v1.OverleafCommentR\bcomments\"\xd7\x02\n" +
+func (x *ProjectRevisionDoc) Reset() {
+	*x = ProjectRevisionDoc{}
+	mi := &file_project_v1_project_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectRevisionDoc) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectRevisionDoc) ProtoMessage() {}
+
+func (x *ProjectRevisionDoc) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectRevisionDoc.ProtoReflect.Descriptor instead.
+func (*ProjectRevisionDoc) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ProjectRevisionDoc) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ProjectRevisionDoc) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ProjectRevisionDoc) GetFilepath() string {
+	if x != nil {
+		return x.Filepath
+	}
+	return ""
+}
+
+type ProjectRevision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      int32                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	RootDocId     string                 `protobuf:"bytes,3,opt,name=root_doc_id,json=rootDocId,proto3" json:"root_doc_id,omitempty"`
+	Docs          []*ProjectRevisionDoc  `protobuf:"bytes,4,rep,name=docs,proto3" json:"docs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProjectRevision) Reset() {
+	*x = ProjectRevision{}
+	mi := &file_project_v1_project_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectRevision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectRevision) ProtoMessage() {}
+
+func (x *ProjectRevision) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectRevision.ProtoReflect.Descriptor instead.
+func (*ProjectRevision) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ProjectRevision) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *ProjectRevision) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ProjectRevision) GetRootDocId() string {
+	if x != nil {
+		return x.RootDocId
+	}
+	return ""
+}
+
+func (x *ProjectRevision) GetDocs() []*ProjectRevisionDoc {
+	if x != nil {
+		return x.Docs
+	}
+	return nil
+}
+
+type ListProjectRevisionsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId      string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Limit          int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                                         // Defaults to 20, at most 100
+	BeforeRevision int32                  `protobuf:"varint,3,opt,name=before_revision,json=beforeRevision,proto3" json:"before_revision,omitempty"` // Only the revisions older than this one, 0 for the latest revisions
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListProjectRevisionsRequest) Reset() {
+	*x = ListProjectRevisionsRequest{}
+	mi := &file_project_v1_project_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProjectRevisionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProjectRevisionsRequest) ProtoMessage() {}
+
+func (x *ListProjectRevisionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProjectRevisionsRequest.ProtoReflect.Descriptor instead.
+func (*ListProjectRevisionsRequest) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListProjectRevisionsRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *ListProjectRevisionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListProjectRevisionsRequest) GetBeforeRevision() int32 {
+	if x != nil {
+		return x.BeforeRevision
+	}
+	return 0
+}
+
+type ListProjectRevisionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revisions     []*ProjectRevision     `protobuf:"bytes,1,rep,name=revisions,proto3" json:"revisions,omitempty"` // Newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProjectRevisionsResponse) Reset() {
+	*x = ListProjectRevisionsResponse{}
+	mi := &file_project_v1_project_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProjectRevisionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProjectRevisionsResponse) ProtoMessage() {}
+
+func (x *ListProjectRevisionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProjectRevisionsResponse.ProtoReflect.Descriptor instead.
+func (*ListProjectRevisionsResponse) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListProjectRevisionsResponse) GetRevisions() []*ProjectRevision {
+	if x != nil {
+		return x.Revisions
+	}
+	return nil
+}
+
+type GetProjectRevisionDocRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Revision      int32                  `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // 0 for the latest revision
+	DocId         string                 `protobuf:"bytes,3,opt,name=doc_id,json=docId,proto3" json:"doc_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProjectRevisionDocRequest) Reset() {
+	*x = GetProjectRevisionDocRequest{}
+	mi := &file_project_v1_project_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProjectRevisionDocRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProjectRevisionDocRequest) ProtoMessage() {}
+
+func (x *GetProjectRevisionDocRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProjectRevisionDocRequest.ProtoReflect.Descriptor instead.
+func (*GetProjectRevisionDocRequest) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetProjectRevisionDocRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *GetProjectRevisionDocRequest) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *GetProjectRevisionDocRequest) GetDocId() string {
+	if x != nil {
+		return x.DocId
+	}
+	return ""
+}
+
+type GetProjectRevisionDocResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revision      int32                  `protobuf:"varint,1,opt,name=revision,proto3" json:"revision,omitempty"`
+	Doc           *ProjectDoc            `protobuf:"bytes,2,opt,name=doc,proto3" json:"doc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProjectRevisionDocResponse) Reset() {
+	*x = GetProjectRevisionDocResponse{}
+	mi := &file_project_v1_project_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProjectRevisionDocResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProjectRevisionDocResponse) ProtoMessage() {}
+
+func (x *GetProjectRevisionDocResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProjectRevisionDocResponse.ProtoReflect.Descriptor instead.
+func (*GetProjectRevisionDocResponse) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetProjectRevisionDocResponse) GetRevision() int32 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *GetProjectRevisionDocResponse) GetDoc() *ProjectDoc {
+	if x != nil {
+		return x.Doc
+	}
+	return nil
+}
+
+type GetProjectRevisionDiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	FromRevision  int32                  `protobuf:"varint,2,opt,name=from_revision,json=fromRevision,proto3" json:"from_revision,omitempty"`
+	ToRevision    int32                  `protobuf:"varint,3,opt,name=to_revision,json=toRevision,proto3" json:"to_revision,omitempty"` // 0 for the latest revision
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProjectRevisionDiffRequest) Reset() {
+	*x = GetProjectRevisionDiffRequest{}
+	mi := &file_project_v1_project_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProjectRevisionDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProjectRevisionDiffRequest) ProtoMessage() {}
+
+func (x *GetProjectRevisionDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProjectRevisionDiffRequest.ProtoReflect.Descriptor instead.
+func (*GetProjectRevisionDiffRequest) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetProjectRevisionDiffRequest) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *GetProjectRevisionDiffRequest) GetFromRevision() int32 {
+	if x != nil {
+		return x.FromRevision
+	}
+	return 0
+}
+
+func (x *GetProjectRevisionDiffRequest) GetToRevision() int32 {
+	if x != nil {
+		return x.ToRevision
+	}
+	return 0
+}
+
+type GetProjectRevisionDiffResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromRevision  int32                  `protobuf:"varint,1,opt,name=from_revision,json=fromRevision,proto3" json:"from_revision,omitempty"`
+	ToRevision    int32                  `protobuf:"varint,2,opt,name=to_revision,json=toRevision,proto3" json:"to_revision,omitempty"`
+	Diff          string                 `protobuf:"bytes,3,opt,name=diff,proto3" json:"diff,omitempty"` // Unified diff, docs are diffed by ID and renamed docs keep their previous path
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProjectRevisionDiffResponse) Reset() {
+	*x = GetProjectRevisionDiffResponse{}
+	mi := &file_project_v1_project_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProjectRevisionDiffResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProjectRevisionDiffResponse) ProtoMessage() {}
+
+func (x *GetProjectRevisionDiffResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_project_v1_project_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProjectRevisionDiffResponse.ProtoReflect.Descriptor instead.
+func (*GetProjectRevisionDiffResponse) Descriptor() ([]byte, []int) {
+	return file_project_v1_project_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetProjectRevisionDiffResponse) GetFromRevision() int32 {
+	if x != nil {
+		return x.FromRevision
+	}
+	return 0
+}
+
+func (x *GetProjectRevisionDiffResponse) GetToRevision() int32 {
+	if x != nil {
+		return x.ToRevision
+	}
+	return 0
+}
+
+func (x *GetProjectRevisionDiffResponse) GetDiff() string {
+	if x != nil {
+		return x.Diff
+	}
+	return ""
+}
+
+var File_project_v1_project_proto protoreflect.FileDescriptor
+
+const file_project_v1_project_proto_rawDesc = "" +
+	"\n" +
+	"\x18project/v1/project.proto\x12\n" +
+	"project.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xef\x01\n" +
+	"\aProject\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1e\n" +
+	"\vroot_doc_id\x18\x05 \x01(\tR\trootDocId\x12*\n" +
+	"\x04docs\x18\x06 \x03(\v2\x16.project.v1.ProjectDocR\x04docs\"h\n" +
+	"\n" +
+	"ProjectDoc\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x1a\n" +
+	"\bfilepath\x18\x03 \x01(\tR\bfilepath\x12\x14\n" +
+	"\x05lines\x18\x04 \x03(\tR\x05lines\"\xb4\x01\n" +
+	"\x14UpsertProjectRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1e\n" +
+	"\vroot_doc_id\x18\x03 \x01(\tR\trootDocId\x12*\n" +
+	"\x04docs\x18\x04 \x03(\v2\x16.project.v1.ProjectDocR\x04docs\x12\x1d\n" +
+	"\n" +
+	"file_paths\x18\x05 \x03(\tR\tfilePaths\"F\n" +
+	"\x15UpsertProjectResponse\x12-\n" +
+	"\aproject\x18\x01 \x01(\v2\x13.project.v1.ProjectR\aproject\"e\n" +
+	"\vDocLineEdit\x12\x1d\n" +
+	"\n" +
+	"start_line\x18\x01 \x01(\x05R\tstartLine\x12!\n" +
+	"\fdelete_count\x18\x02 \x01(\x05R\vdeleteCount\x12\x14\n" +
+	"\x05lines\x18\x03 \x03(\tR\x05lines\"\xed\x01\n" +
+	"\bDocPatch\x12,\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x18.project.v1.DocPatchTypeR\x04type\x12\x15\n" +
+	"\x06doc_id\x18\x02 \x01(\tR\x05docId\x12!\n" +
+	"\fbase_version\x18\x03 \x01(\x05R\vbaseVersion\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x05R\aversion\x12\x1a\n" +
+	"\bfilepath\x18\x05 \x01(\tR\bfilepath\x12\x14\n" +
+	"\x05lines\x18\x06 \x03(\tR\x05lines\x12-\n" +
+	"\x05edits\x18\a \x03(\v2\x17.project.v1.DocLineEditR\x05edits\"\x9d\x01\n" +
+	"\x17PatchProjectDocsRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12.\n" +
+	"\apatches\x18\x02 \x03(\v2\x14.project.v1.DocPatchR\apatches\x12#\n" +
+	"\vroot_doc_id\x18\x03 \x01(\tH\x00R\trootDocId\x88\x01\x01B\x0e\n" +
+	"\f_root_doc_id\"I\n" +
+	"\x18PatchProjectDocsResponse\x12-\n" +
+	"\aproject\x18\x01 \x01(\v2\x13.project.v1.ProjectR\aproject\"2\n" +
+	"\x11GetProjectRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\"C\n" +
+	"\x12GetProjectResponse\x12-\n" +
+	"\aproject\x18\x01 \x01(\v2\x13.project.v1.ProjectR\aproject\"e\n" +
+	"\x1bRunProjectPaperScoreRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\"|\n" +
+	"\x1cRunProjectPaperScoreResponse\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12=\n" +
+	"\vpaper_score\x18\x02 \x01(\v2\x1c.project.v1.PaperScoreResultR\n" +
+	"paperScore\"l\n" +
+	"\"RunProjectPaperScoreCommentRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\"\x85\x01\n" +
+	"#RunProjectPaperScoreCommentResponse\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12?\n" +
+	"\bcomments\x18\x02 \x03(\v2#.project.v1.PaperScoreCommentResultR\bcomments\"\xb6\x01\n" +
+	" RunProjectOverleafCommentRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x18\n" +
+	"\asection\x18\x02 \x01(\tR\asection\x12\x1f\n" +
+	"\vanchor_text\x18\x03 \x01(\tR\n" +
+	"anchorText\x12\x18\n" +
+	"\acomment\x18\x04 \x01(\tR\acomment\x12\x1e\n" +
+	"\n" +
+	"importance\x18\x05 \x01(\tR\n" +
+	"importance\"{\n" +
+	"!RunProjectOverleafCommentResponse\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x127\n" +
+	"\bcomments\x18\x02 \x03(\v2\x1b.project.v1.OverleafCommentR\bcomments\"V\n" +
+	"\x12LintProjectRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12!\n" +
+	"\fadd_comments\x18\x02 \x01(\bR\vaddComments\"\xb2\x01\n" +
+	"\vLintFinding\x12\x12\n" +
+	"\x04rule\x18\x01 \x01(\tR\x04rule\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x18\n" +
+	"\asection\x18\x05 \x01(\tR\asection\x12\x1b\n" +
+	"\tfile_path\x18\x06 \x01(\tR\bfilePath\x12\x12\n" +
+	"\x04line\x18\a \x01(\x05R\x04line\"\xa2\x01\n" +
+	"\x13LintProjectResponse\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x123\n" +
+	"\bfindings\x18\x02 \x03(\v2\x17.project.v1.LintFindingR\bfindings\x127\n" +
+	"\bcomments\x18\x03 \x03(\v2\x1b.project.v1.OverleafCommentR\bcomments\"\xd7\x02\n" +
 	"\x0fOverleafComment\x12\x1d\n" +
 	"\n" +
 	"comment_id\x18\x01 \x01(\tR\tcommentId\x12\x1d\n" +
@@ -1389,16 +2421,63 @@ const file_project_v1_project_proto_rawDesc = "" +
 	"!UpsertProjectInstructionsResponse\x12\x1d\n" +
 	"\n" +
 	"project_id\x18\x01 \x01(\tR\tprojectId\x12\"\n" +
-	"\finstructions\x18\x02 \x01(\tR\finstructions2\x90\t\n" +
+	"\finstructions\x18\x02 \x01(\tR\finstructions\"Z\n" +
+	"\x12ProjectRevisionDoc\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12\x1a\n" +
+	"\bfilepath\x18\x03 \x01(\tR\bfilepath\"\xbc\x01\n" +
+	"\x0fProjectRevision\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x05R\brevision\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1e\n" +
+	"\vroot_doc_id\x18\x03 \x01(\tR\trootDocId\x122\n" +
+	"\x04docs\x18\x04 \x03(\v2\x1e.project.v1.ProjectRevisionDocR\x04docs\"{\n" +
+	"\x1bListProjectRevisionsRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12'\n" +
+	"\x0fbefore_revision\x18\x03 \x01(\x05R\x0ebeforeRevision\"Y\n" +
+	"\x1cListProjectRevisionsResponse\x129\n" +
+	"\trevisions\x18\x01 \x03(\v2\x1b.project.v1.ProjectRevisionR\trevisions\"p\n" +
+	"\x1cGetProjectRevisionDocRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x05R\brevision\x12\x15\n" +
+	"\x06doc_id\x18\x03 \x01(\tR\x05docId\"e\n" +
+	"\x1dGetProjectRevisionDocResponse\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x05R\brevision\x12(\n" +
+	"\x03doc\x18\x02 \x01(\v2\x16.project.v1.ProjectDocR\x03doc\"\x84\x01\n" +
+	"\x1dGetProjectRevisionDiffRequest\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12#\n" +
+	"\rfrom_revision\x18\x02 \x01(\x05R\ffromRevision\x12\x1f\n" +
+	"\vto_revision\x18\x03 \x01(\x05R\n" +
+	"toRevision\"z\n" +
+	"\x1eGetProjectRevisionDiffResponse\x12#\n" +
+	"\rfrom_revision\x18\x01 \x01(\x05R\ffromRevision\x12\x1f\n" +
+	"\vto_revision\x18\x02 \x01(\x05R\n" +
+	"toRevision\x12\x12\n" +
+	"\x04diff\x18\x03 \x01(\tR\x04diff*\x95\x01\n" +
+	"\fDocPatchType\x12\x1e\n" +
+	"\x1aDOC_PATCH_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13DOC_PATCH_TYPE_EDIT\x10\x01\x12\x16\n" +
+	"\x12DOC_PATCH_TYPE_ADD\x10\x02\x12\x19\n" +
+	"\x15DOC_PATCH_TYPE_REMOVE\x10\x03\x12\x19\n" +
+	"\x15DOC_PATCH_TYPE_RENAME\x10\x042\xc1\x0f\n" +
 	"\x0eProjectService\x12\x82\x01\n" +
-	"\rUpsertProject\x12 .project.v1.UpsertProjectRequest\x1a!.project.v1.UpsertProjectResponse\",\x82\xd3\xe4\x93\x02&:\x01*\x1a!/_pd/api/v1/projects/{project_id}\x12v\n" +
+	"\rUpsertProject\x12 .project.v1.UpsertProjectRequest\x1a!.project.v1.UpsertProjectResponse\",\x82\xd3\xe4\x93\x02&:\x01*\x1a!/_pd/api/v1/projects/{project_id}\x12\x90\x01\n" +
+	"\x10PatchProjectDocs\x12#.project.v1.PatchProjectDocsRequest\x1a$.project.v1.PatchProjectDocsResponse\"1\x82\xd3\xe4\x93\x02+:\x01*2&/_pd/api/v1/projects/{project_id}/docs\x12v\n" +
 	"\n" +
 	"GetProject\x12\x1d.project.v1.GetProjectRequest\x1a\x1e.project.v1.GetProjectResponse\")\x82\xd3\xe4\x93\x02#\x12!/_pd/api/v1/projects/{project_id}\x12\xa3\x01\n" +
 	"\x14RunProjectPaperScore\x12'.project.v1.RunProjectPaperScoreRequest\x1a(.project.v1.RunProjectPaperScoreResponse\"8\x82\xd3\xe4\x93\x022:\x01*\"-/_pd/api/v1/projects/{project_id}/paper-score\x12\xc0\x01\n" +
 	"\x1bRunProjectPaperScoreComment\x12..project.v1.RunProjectPaperScoreCommentRequest\x1a/.project.v1.RunProjectPaperScoreCommentResponse\"@\x82\xd3\xe4\x93\x02::\x01*\"5/_pd/api/v1/projects/{project_id}/paper-score-comment\x12\xb7\x01\n" +
-	"\x19RunProjectOverleafComment\x12,.project.v1.RunProjectOverleafCommentRequest\x1a-.project.v1.RunProjectOverleafCommentResponse\"=\x82\xd3\xe4\x93\x027:\x01*\"2/_pd/api/v1/projects/{project_id}/overleaf-comment\x12\xa7\x01\n" +
+	"\x19RunProjectOverleafComment\x12,.project.v1.RunProjectOverleafCommentRequest\x1a-.project.v1.RunProjectOverleafCommentResponse\"=\x82\xd3\xe4\x93\x027:\x01*\"2/_pd/api/v1/projects/{project_id}/overleaf-comment\x12\x81\x01\n" +
+	"\vLintProject\x12\x1e.project.v1.LintProjectRequest\x1a\x1f.project.v1.LintProjectResponse\"1\x82\xd3\xe4\x93\x02+:\x01*\"&/_pd/api/v1/projects/{project_id}/lint\x12\xa7\x01\n" +
 	"\x16GetProjectInstructions\x12).project.v1.GetProjectInstructionsRequest\x1a*.project.v1.GetProjectInstructionsResponse\"6\x82\xd3\xe4\x93\x020\x12./_pd/api/v1/projects/{project_id}/instructions\x12\xb3\x01\n" +
-	"\x19UpsertProjectInstructions\x12,.project.v1.UpsertProjectInstructionsRequest\x1a-.project.v1.UpsertProjectInstructionsResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./_pd/api/v1/projects/{project_id}/instructionsB\x97\x01\n" +
+	"\x19UpsertProjectInstructions\x12,.project.v1.UpsertProjectInstructionsRequest\x1a-.project.v1.UpsertProjectInstructionsResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./_pd/api/v1/projects/{project_id}/instructions\x12\x9e\x01\n" +
+	"\x14ListProjectRevisions\x12'.project.v1.ListProjectRevisionsRequest\x1a(.project.v1.ListProjectRevisionsResponse\"3\x82\xd3\xe4\x93\x02-\x12+/_pd/api/v1/projects/{project_id}/revisions\x12\xba\x01\n" +
+	"\x15GetProjectRevisionDoc\x12(.project.v1.GetProjectRevisionDocRequest\x1a).project.v1.GetProjectRevisionDocResponse\"L\x82\xd3\xe4\x93\x02F\x12D/_pd/api/v1/projects/{project_id}/revisions/{revision}/docs/{doc_id}\x12\xb9\x01\n" +
+	"\x16GetProjectRevisionDiff\x12).project.v1.GetProjectRevisionDiffRequest\x1a*.project.v1.GetProjectRevisionDiffResponse\"H\x82\xd3\xe4\x93\x02B\x12@/_pd/api/v1/projects/{project_id}/revisions/{from_revision}/diffB\x97\x01\n" +
 	"\x0ecom.project.v1B\fProjectProtoP\x01Z.paperdebugger/pkg/gen/api/project/v1;projectv1\xa2\x02\x03PXX\xaa\x02\n" +
 	"Project.V1\xca\x02\n" +
 	"Project\\V1\xe2\x02\x16Project\\V1\\GPBMetadata\xea\x02\vProject::V1b\x06proto3"
@@ -1415,66 +2494,103 @@ func file_project_v1_project_proto_rawDescGZIP() []byte {
 	return file_project_v1_project_proto_rawDescData
 }
 
-var file_project_v1_project_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_project_v1_project_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_project_v1_project_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_project_v1_project_proto_goTypes = []any{
-	(*Project)(nil),                             // 0: project.v1.Project
-	(*ProjectDoc)(nil),                          // 1: project.v1.ProjectDoc
-	(*UpsertProjectRequest)(nil),                // 2: project.v1.UpsertProjectRequest
-	(*UpsertProjectResponse)(nil),               // 3: project.v1.UpsertProjectResponse
-	(*GetProjectRequest)(nil),                   // 4: project.v1.GetProjectRequest
-	(*GetProjectResponse)(nil),                  // 5: project.v1.GetProjectResponse
-	(*RunProjectPaperScoreRequest)(nil),         // 6: project.v1.RunProjectPaperScoreRequest
-	(*RunProjectPaperScoreResponse)(nil),        // 7: project.v1.RunProjectPaperScoreResponse
-	(*RunProjectPaperScoreCommentRequest)(nil),  // 8: project.v1.RunProjectPaperScoreCommentRequest
-	(*RunProjectPaperScoreCommentResponse)(nil), // 9: project.v1.RunProjectPaperScoreCommentResponse
-	(*RunProjectOverleafCommentRequest)(nil),    // 10: project.v1.RunProjectOverleafCommentRequest
-	(*RunProjectOverleafCommentResponse)(nil),   // 11: project.v1.RunProjectOverleafCommentResponse
-	(*OverleafComment)(nil),                     // 12: project.v1.OverleafComment
-	(*PaperScoreCommentResult)(nil),             // 13: project.v1.PaperScoreCommentResult
-	(*PaperScoreCommentEntry)(nil),              // 14: project.v1.PaperScoreCommentEntry
-	(*PaperScoreResult)(nil),                    // 15: project.v1.PaperScoreResult
-	(*SuggestionList)(nil),                      // 16: project.v1.SuggestionList
-	(*GetProjectInstructionsRequest)(nil),       // 17: project.v1.GetProjectInstructionsRequest
-	(*GetProjectInstructionsResponse)(nil),      // 18: project.v1.GetProjectInstructionsResponse
-	(*UpsertProjectInstructionsRequest)(nil),    // 19: project.v1.UpsertProjectInstructionsRequest
-	(*UpsertProjectInstructionsResponse)(nil),   // 20: project.v1.UpsertProjectInstructionsResponse
-	nil,                           // 21: project.v1.PaperScoreResult.DetailsEntry
-	nil,                           // 22: project.v1.PaperScoreResult.SuggestionsEntry
-	(*timestamppb.Timestamp)(nil), // 23: google.protobuf.Timestamp
+	(DocPatchType)(0),                           // 0: project.v1.DocPatchType
+	(*Project)(nil),                             // 1: project.v1.Project
+	(*ProjectDoc)(nil),                          // 2: project.v1.ProjectDoc
+	(*UpsertProjectRequest)(nil),                // 3: project.v1.UpsertProjectRequest
+	(*UpsertProjectResponse)(nil),               // 4: project.v1.UpsertProjectResponse
+	(*DocLineEdit)(nil),                         // 5: project.v1.DocLineEdit
+	(*DocPatch)(nil),                            // 6: project.v1.DocPatch
+	(*PatchProjectDocsRequest)(nil),             // 7: project.v1.PatchProjectDocsRequest
+	(*PatchProjectDocsResponse)(nil),            // 8: project.v1.PatchProjectDocsResponse
+	(*GetProjectRequest)(nil),                   // 9: project.v1.GetProjectRequest
+	(*GetProjectResponse)(nil),                  // 10: project.v1.GetProjectResponse
+	(*RunProjectPaperScoreRequest)(nil),         // 11: project.v1.RunProjectPaperScoreRequest
+	(*RunProjectPaperScoreResponse)(nil),        // 12: project.v1.RunProjectPaperScoreResponse
+	(*RunProjectPaperScoreCommentRequest)(nil),  // 13: project.v1.RunProjectPaperScoreCommentRequest
+	(*RunProjectPaperScoreCommentResponse)(nil), // 14: project.v1.RunProjectPaperScoreCommentResponse
+	(*RunProjectOverleafCommentRequest)(nil),    // 15: project.v1.RunProjectOverleafCommentRequest
+	(*RunProjectOverleafCommentResponse)(nil),   // 16: project.v1.RunProjectOverleafCommentResponse
+	(*LintProjectRequest)(nil),                  // 17: project.v1.LintProjectRequest
+	(*LintFinding)(nil),                         // 18: project.v1.LintFinding
+	(*LintProjectResponse)(nil),                 // 19: project.v1.LintProjectResponse
+	(*OverleafComment)(nil),                     // 20: project.v1.OverleafComment
+	(*PaperScoreCommentResult)(nil),             // 21: project.v1.PaperScoreCommentResult
+	(*PaperScoreCommentEntry)(nil),              // 22: project.v1.PaperScoreCommentEntry
+	(*PaperScoreResult)(nil),                    // 23: project.v1.PaperScoreResult
+	(*SuggestionList)(nil),                      // 24: project.v1.SuggestionList
+	(*GetProjectInstructionsRequest)(nil),       // 25: project.v1.GetProjectInstructionsRequest
+	(*GetProjectInstructionsResponse)(nil),      // 26: project.v1.GetProjectInstructionsResponse
+	(*UpsertProjectInstructionsRequest)(nil),    // 27: project.v1.UpsertProjectInstructionsRequest
+	(*UpsertProjectInstructionsResponse)(nil),   // 28: project.v1.UpsertProjectInstructionsResponse
+	(*ProjectRevisionDoc)(nil),                  // 29: project.v1.ProjectRevisionDoc
+	(*ProjectRevision)(nil),                     // 30: project.v1.ProjectRevision
+	(*ListProjectRevisionsRequest)(nil),         // 31: project.v1.ListProjectRevisionsRequest
+	(*ListProjectRevisionsResponse)(nil),        // 32: project.v1.ListProjectRevisionsResponse
+	(*GetProjectRevisionDocRequest)(nil),        // 33: project.v1.GetProjectRevisionDocRequest
+	(*GetProjectRevisionDocResponse)(nil),       // 34: project.v1.GetProjectRevisionDocResponse
+	(*GetProjectRevisionDiffRequest)(nil),       // 35: project.v1.GetProjectRevisionDiffRequest
+	(*GetProjectRevisionDiffResponse)(nil),      // 36: project.v1.GetProjectRevisionDiffResponse
+	nil,                                         // 37: project.v1.PaperScoreResult.DetailsEntry
+	nil,                                         // 38: project.v1.PaperScoreResult.SuggestionsEntry
+	(*timestamppb.Timestamp)(nil),               // 39: google.protobuf.Timestamp
 }
 var file_project_v1_project_proto_depIdxs = []int32{
-	23, // 0: project.v1.Project.created_at:type_name -> google.protobuf.Timestamp
-	23, // 1: project.v1.Project.updated_at:type_name -> google.protobuf.Timestamp
-	1,  // 2: project.v1.Project.docs:type_name -> project.v1.ProjectDoc
-	1,  // 3: project.v1.UpsertProjectRequest.docs:type_name -> project.v1.ProjectDoc
-	0,  // 4: project.v1.UpsertProjectResponse.project:type_name -> project.v1.Project
-	0,  // 5: project.v1.GetProjectResponse.project:type_name -> project.v1.Project
-	15, // 6: project.v1.RunProjectPaperScoreResponse.paper_score:type_name -> project.v1.PaperScoreResult
-	13, // 7: project.v1.RunProjectPaperScoreCommentResponse.comments:type_name -> project.v1.PaperScoreCommentResult
-	12, // 8: project.v1.RunProjectOverleafCommentResponse.comments:type_name -> project.v1.OverleafComment
-	14, // 9: project.v1.PaperScoreCommentResult.results:type_name -> project.v1.PaperScoreCommentEntry
-	21, // 10: project.v1.PaperScoreResult.details:type_name -> project.v1.PaperScoreResult.DetailsEntry
-	22, // 11: project.v1.PaperScoreResult.suggestions:type_name -> project.v1.PaperScoreResult.SuggestionsEntry
-	16, // 12: project.v1.PaperScoreResult.SuggestionsEntry.value:type_name -> project.v1.SuggestionList
-	2,  // 13: project.v1.ProjectService.UpsertProject:input_type -> project.v1.UpsertProjectRequest
-	4,  // 14: project.v1.ProjectService.GetProject:input_type -> project.v1.GetProjectRequest
-	6,  // 15: project.v1.ProjectService.RunProjectPaperScore:input_type -> project.v1.RunProjectPaperScoreRequest
-	8,  // 16: project.v1.ProjectService.RunProjectPaperScoreComment:input_type -> project.v1.RunProjectPaperScoreCommentRequest
-	10, // 17: project.v1.ProjectService.RunProjectOverleafComment:input_type -> project.v1.RunProjectOverleafCommentRequest
-	17, // 18: project.v1.ProjectService.GetProjectInstructions:input_type -> project.v1.GetProjectInstructionsRequest
-	19, // 19: project.v1.ProjectService.UpsertProjectInstructions:input_type -> project.v1.UpsertProjectInstructionsRequest
-	3,  // 20: project.v1.ProjectService.UpsertProject:output_type -> project.v1.UpsertProjectResponse
-	5,  // 21: project.v1.ProjectService.GetProject:output_type -> project.v1.GetProjectResponse
-	7,  // 22: project.v1.ProjectService.RunProjectPaperScore:output_type -> project.v1.RunProjectPaperScoreResponse
-	9,  // 23: project.v1.ProjectService.RunProjectPaperScoreComment:output_type -> project.v1.RunProjectPaperScoreCommentResponse
-	11, // 24: project.v1.ProjectService.RunProjectOverleafComment:output_type -> project.v1.RunProjectOverleafCommentResponse
-	18, // 25: project.v1.ProjectService.GetProjectInstructions:output_type -> project.v1.GetProjectInstructionsResponse
-	20, // 26: project.v1.ProjectService.UpsertProjectInstructions:output_type -> project.v1.UpsertProjectInstructionsResponse
-	20, // [20:27] is the sub-list for method output_type
-	13, // [13:20] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	39, // 0: project.v1.Project.created_at:type_name -> google.protobuf.Timestamp
+	39, // 1: project.v1.Project.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 2: project.v1.Project.docs:type_name -> project.v1.ProjectDoc
+	2,  // 3: project.v1.UpsertProjectRequest.docs:type_name -> project.v1.ProjectDoc
+	1,  // 4: project.v1.UpsertProjectResponse.project:type_name -> project.v1.Project
+	0,  // 5: project.v1.DocPatch.type:type_name -> project.v1.DocPatchType
+	5,  // 6: project.v1.DocPatch.edits:type_name -> project.v1.DocLineEdit
+	6,  // 7: project.v1.PatchProjectDocsRequest.patches:type_name -> project.v1.DocPatch
+	1,  // 8: project.v1.PatchProjectDocsResponse.project:type_name -> project.v1.Project
+	1,  // 9: project.v1.GetProjectResponse.project:type_name -> project.v1.Project
+	23, // 10: project.v1.RunProjectPaperScoreResponse.paper_score:type_name -> project.v1.PaperScoreResult
+	21, // 11: project.v1.RunProjectPaperScoreCommentResponse.comments:type_name -> project.v1.PaperScoreCommentResult
+	20, // 12: project.v1.RunProjectOverleafCommentResponse.comments:type_name -> project.v1.OverleafComment
+	18, // 13: project.v1.LintProjectResponse.findings:type_name -> project.v1.LintFinding
+	20, // 14: project.v1.LintProjectResponse.comments:type_name -> project.v1.OverleafComment
+	22, // 15: project.v1.PaperScoreCommentResult.results:type_name -> project.v1.PaperScoreCommentEntry
+	37, // 16: project.v1.PaperScoreResult.details:type_name -> project.v1.PaperScoreResult.DetailsEntry
+	38, // 17: project.v1.PaperScoreResult.suggestions:type_name -> project.v1.PaperScoreResult.SuggestionsEntry
+	39, // 18: project.v1.ProjectRevision.created_at:type_name -> google.protobuf.Timestamp
+	29, // 19: project.v1.ProjectRevision.docs:type_name -> project.v1.ProjectRevisionDoc
+	30, // 20: project.v1.ListProjectRevisionsResponse.revisions:type_name -> project.v1.ProjectRevision
+	2,  // 21: project.v1.GetProjectRevisionDocResponse.doc:type_name -> project.v1.ProjectDoc
+	24, // 22: project.v1.PaperScoreResult.SuggestionsEntry.value:type_name -> project.v1.SuggestionList
+	3,  // 23: project.v1.ProjectService.UpsertProject:input_type -> project.v1.UpsertProjectRequest
+	7,  // 24: project.v1.ProjectService.PatchProjectDocs:input_type -> project.v1.PatchProjectDocsRequest
+	9,  // 25: project.v1.ProjectService.GetProject:input_type -> project.v1.GetProjectRequest
+	11, // 26: project.v1.ProjectService.RunProjectPaperScore:input_type -> project.v1.RunProjectPaperScoreRequest
+	13, // 27: project.v1.ProjectService.RunProjectPaperScoreComment:input_type -> project.v1.RunProjectPaperScoreCommentRequest
+	15, // 28: project.v1.ProjectService.RunProjectOverleafComment:input_type -> project.v1.RunProjectOverleafCommentRequest
+	17, // 29: project.v1.ProjectService.LintProject:input_type -> project.v1.LintProjectRequest
+	25, // 30: project.v1.ProjectService.GetProjectInstructions:input_type -> project.v1.GetProjectInstructionsRequest
+	27, // 31: project.v1.ProjectService.UpsertProjectInstructions:input_type -> project.v1.UpsertProjectInstructionsRequest
+	31, // 32: project.v1.ProjectService.ListProjectRevisions:input_type -> project.v1.ListProjectRevisionsRequest
+	33, // 33: project.v1.ProjectService.GetProjectRevisionDoc:input_type -> project.v1.GetProjectRevisionDocRequest
+	35, // 34: project.v1.ProjectService.GetProjectRevisionDiff:input_type -> project.v1.GetProjectRevisionDiffRequest
+	4,  // 35: project.v1.ProjectService.UpsertProject:output_type -> project.v1.UpsertProjectResponse
+	8,  // 36: project.v1.ProjectService.PatchProjectDocs:output_type -> project.v1.PatchProjectDocsResponse
+	10, // 37: project.v1.ProjectService.GetProject:output_type -> project.v1.GetProjectResponse
+	12, // 38: project.v1.ProjectService.RunProjectPaperScore:output_type -> project.v1.RunProjectPaperScoreResponse
+	14, // 39: project.v1.ProjectService.RunProjectPaperScoreComment:output_type -> project.v1.RunProjectPaperScoreCommentResponse
+	16, // 40: project.v1.ProjectService.RunProjectOverleafComment:output_type -> project.v1.RunProjectOverleafCommentResponse
+	19, // 41: project.v1.ProjectService.LintProject:output_type -> project.v1.LintProjectResponse
+	26, // 42: project.v1.ProjectService.GetProjectInstructions:output_type -> project.v1.GetProjectInstructionsResponse
+	28, // 43: project.v1.ProjectService.UpsertProjectInstructions:output_type -> project.v1.UpsertProjectInstructionsResponse
+	32, // 44: project.v1.ProjectService.ListProjectRevisions:output_type -> project.v1.ListProjectRevisionsResponse
+	34, // 45: project.v1.ProjectService.GetProjectRevisionDoc:output_type -> project.v1.GetProjectRevisionDocResponse
+	36, // 46: project.v1.ProjectService.GetProjectRevisionDiff:output_type -> project.v1.GetProjectRevisionDiffResponse
+	35, // [35:47] is the sub-list for method output_type
+	23, // [23:35] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_project_v1_project_proto_init() }
@@ -1482,18 +2598,20 @@ func file_project_v1_project_proto_init() {
 	if File_project_v1_project_proto != nil {
 		return
 	}
+	file_project_v1_project_proto_msgTypes[6].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_project_v1_project_proto_rawDesc), len(file_project_v1_project_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   23,
+			NumEnums:      1,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_project_v1_project_proto_goTypes,
 		DependencyIndexes: file_project_v1_project_proto_depIdxs,
+		EnumInfos:         file_project_v1_project_proto_enumTypes,
 		MessageInfos:      file_project_v1_project_proto_msgTypes,
 	}.Build()
 	File_project_v1_project_proto = out.File
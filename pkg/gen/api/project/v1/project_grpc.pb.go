@@ -20,12 +20,17 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	ProjectService_UpsertProject_FullMethodName               = "/project.v1.ProjectService/UpsertProject"
+	ProjectService_PatchProjectDocs_FullMethodName            = "/project.v1.ProjectService/PatchProjectDocs"
 	ProjectService_GetProject_FullMethodName                  = "/project.v1.ProjectService/GetProject"
 	ProjectService_RunProjectPaperScore_FullMethodName        = "/project.v1.ProjectService/RunProjectPaperScore"
 	ProjectService_RunProjectPaperScoreComment_FullMethodName = "/project.v1.ProjectService/RunProjectPaperScoreComment"
 	ProjectService_RunProjectOverleafComment_FullMethodName   = "/project.v1.ProjectService/RunProjectOverleafComment"
+	ProjectService_LintProject_FullMethodName                 = "/project.v1.ProjectService/LintProject"
 	ProjectService_GetProjectInstructions_FullMethodName      = "/project.v1.ProjectService/GetProjectInstructions"
 	ProjectService_UpsertProjectInstructions_FullMethodName   = "/project.v1.ProjectService/UpsertProjectInstructions"
+	ProjectService_ListProjectRevisions_FullMethodName        = "/project.v1.ProjectService/ListProjectRevisions"
+	ProjectService_GetProjectRevisionDoc_FullMethodName       = "/project.v1.ProjectService/GetProjectRevisionDoc"
+	ProjectService_GetProjectRevisionDiff_FullMethodName      = "/project.v1.ProjectService/GetProjectRevisionDiff"
 )
 
 // ProjectServiceClient is the client API for ProjectService service.
@@ -33,12 +38,23 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ProjectServiceClient interface {
 	UpsertProject(ctx context.Context, in *UpsertProjectRequest, opts ...grpc.CallOption) (*UpsertProjectResponse, error)
+	// PatchProjectDocs applies changes to the docs of a project synced with UpsertProject, without re-uploading them.
+	// Patches are applied to their base version, a patch whose base version is not the current version of the doc is
+	// rejected with ERROR_CODE_DOC_VERSION_CONFLICT: the client should sync the project again and retry.
+	PatchProjectDocs(ctx context.Context, in *PatchProjectDocsRequest, opts ...grpc.CallOption) (*PatchProjectDocsResponse, error)
 	GetProject(ctx context.Context, in *GetProjectRequest, opts ...grpc.CallOption) (*GetProjectResponse, error)
 	RunProjectPaperScore(ctx context.Context, in *RunProjectPaperScoreRequest, opts ...grpc.CallOption) (*RunProjectPaperScoreResponse, error)
 	RunProjectPaperScoreComment(ctx context.Context, in *RunProjectPaperScoreCommentRequest, opts ...grpc.CallOption) (*RunProjectPaperScoreCommentResponse, error)
 	RunProjectOverleafComment(ctx context.Context, in *RunProjectOverleafCommentRequest, opts ...grpc.CallOption) (*RunProjectOverleafCommentResponse, error)
+	// LintProject runs static checks of the LaTeX source of the project. The findings can also be added as Overleaf
+	// comments, as a pre-submission checklist.
+	LintProject(ctx context.Context, in *LintProjectRequest, opts ...grpc.CallOption) (*LintProjectResponse, error)
 	GetProjectInstructions(ctx context.Context, in *GetProjectInstructionsRequest, opts ...grpc.CallOption) (*GetProjectInstructionsResponse, error)
 	UpsertProjectInstructions(ctx context.Context, in *UpsertProjectInstructionsRequest, opts ...grpc.CallOption) (*UpsertProjectInstructionsResponse, error)
+	// A revision is recorded each time UpsertProject changes the content of the project.
+	ListProjectRevisions(ctx context.Context, in *ListProjectRevisionsRequest, opts ...grpc.CallOption) (*ListProjectRevisionsResponse, error)
+	GetProjectRevisionDoc(ctx context.Context, in *GetProjectRevisionDocRequest, opts ...grpc.CallOption) (*GetProjectRevisionDocResponse, error)
+	GetProjectRevisionDiff(ctx context.Context, in *GetProjectRevisionDiffRequest, opts ...grpc.CallOption) (*GetProjectRevisionDiffResponse, error)
 }
 
 type projectServiceClient struct {
@@ -59,6 +75,16 @@ func (c *projectServiceClient) UpsertProject(ctx context.Context, in *UpsertProj
 	return out, nil
 }
 
+func (c *projectServiceClient) PatchProjectDocs(ctx context.Context, in *PatchProjectDocsRequest, opts ...grpc.CallOption) (*PatchProjectDocsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PatchProjectDocsResponse)
+	err := c.cc.Invoke(ctx, ProjectService_PatchProjectDocs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *projectServiceClient) GetProject(ctx context.Context, in *GetProjectRequest, opts ...grpc.CallOption) (*GetProjectResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetProjectResponse)
@@ -99,6 +125,16 @@ func (c *projectServiceClient) RunProjectOverleafComment(ctx context.Context, in
 	return out, nil
 }
 
+func (c *projectServiceClient) LintProject(ctx context.Context, in *LintProjectRequest, opts ...grpc.CallOption) (*LintProjectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LintProjectResponse)
+	err := c.cc.Invoke(ctx, ProjectService_LintProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *projectServiceClient) GetProjectInstructions(ctx context.Context, in *GetProjectInstructionsRequest, opts ...grpc.CallOption) (*GetProjectInstructionsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetProjectInstructionsResponse)
@@ -119,17 +155,58 @@ func (c *projectServiceClient) UpsertProjectInstructions(ctx context.Context, in
 	return out, nil
 }
 
+func (c *projectServiceClient) ListProjectRevisions(ctx context.Context, in *ListProjectRevisionsRequest, opts ...grpc.CallOption) (*ListProjectRevisionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListProjectRevisionsResponse)
+	err := c.cc.Invoke(ctx, ProjectService_ListProjectRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectServiceClient) GetProjectRevisionDoc(ctx context.Context, in *GetProjectRevisionDocRequest, opts ...grpc.CallOption) (*GetProjectRevisionDocResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProjectRevisionDocResponse)
+	err := c.cc.Invoke(ctx, ProjectService_GetProjectRevisionDoc_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *projectServiceClient) GetProjectRevisionDiff(ctx context.Context, in *GetProjectRevisionDiffRequest, opts ...grpc.CallOption) (*GetProjectRevisionDiffResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProjectRevisionDiffResponse)
+	err := c.cc.Invoke(ctx, ProjectService_GetProjectRevisionDiff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProjectServiceServer is the server API for ProjectService service.
 // All implementations must embed UnimplementedProjectServiceServer
 // for forward compatibility.
 type ProjectServiceServer interface {
 	UpsertProject(context.Context, *UpsertProjectRequest) (*UpsertProjectResponse, error)
+	// PatchProjectDocs applies changes to the docs of a project synced with UpsertProject, without re-uploading them.
+	// Patches are applied to their base version, a patch whose base version is not the current version of the doc is
+	// rejected with ERROR_CODE_DOC_VERSION_CONFLICT: the client should sync the project again and retry.
+	PatchProjectDocs(context.Context, *PatchProjectDocsRequest) (*PatchProjectDocsResponse, error)
 	GetProject(context.Context, *GetProjectRequest) (*GetProjectResponse, error)
 	RunProjectPaperScore(context.Context, *RunProjectPaperScoreRequest) (*RunProjectPaperScoreResponse, error)
 	RunProjectPaperScoreComment(context.Context, *RunProjectPaperScoreCommentRequest) (*RunProjectPaperScoreCommentResponse, error)
 	RunProjectOverleafComment(context.Context, *RunProjectOverleafCommentRequest) (*RunProjectOverleafCommentResponse, error)
+	// LintProject runs static checks of the LaTeX source of the project. The findings can also be added as Overleaf
+	// comments, as a pre-submission checklist.
+	LintProject(context.Context, *LintProjectRequest) (*LintProjectResponse, error)
 	GetProjectInstructions(context.Context, *GetProjectInstructionsRequest) (*GetProjectInstructionsResponse, error)
 	UpsertProjectInstructions(context.Context, *UpsertProjectInstructionsRequest) (*UpsertProjectInstructionsResponse, error)
+	// A revision is recorded each time UpsertProject changes the content of the project.
+	ListProjectRevisions(context.Context, *ListProjectRevisionsRequest) (*ListProjectRevisionsResponse, error)
+	GetProjectRevisionDoc(context.Context, *GetProjectRevisionDocRequest) (*GetProjectRevisionDocResponse, error)
+	GetProjectRevisionDiff(context.Context, *GetProjectRevisionDiffRequest) (*GetProjectRevisionDiffResponse, error)
 	mustEmbedUnimplementedProjectServiceServer()
 }
 
@@ -143,6 +220,9 @@ type UnimplementedProjectServiceServer struct{}
 func (UnimplementedProjectServiceServer) UpsertProject(context.Context, *UpsertProjectRequest) (*UpsertProjectResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpsertProject not implemented")
 }
+func (UnimplementedProjectServiceServer) PatchProjectDocs(context.Context, *PatchProjectDocsRequest) (*PatchProjectDocsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PatchProjectDocs not implemented")
+}
 func (UnimplementedProjectServiceServer) GetProject(context.Context, *GetProjectRequest) (*GetProjectResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetProject not implemented")
 }
@@ -155,12 +235,24 @@ func (UnimplementedProjectServiceServer) RunProjectPaperScoreComment(context.Con
 func (UnimplementedProjectServiceServer) RunProjectOverleafComment(context.Context, *RunProjectOverleafCommentRequest) (*RunProjectOverleafCommentResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RunProjectOverleafComment not implemented")
 }
+func (UnimplementedProjectServiceServer) LintProject(context.Context, *LintProjectRequest) (*LintProjectResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LintProject not implemented")
+}
 func (UnimplementedProjectServiceServer) GetProjectInstructions(context.Context, *GetProjectInstructionsRequest) (*GetProjectInstructionsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetProjectInstructions not implemented")
 }
 func (UnimplementedProjectServiceServer) UpsertProjectInstructions(context.Context, *UpsertProjectInstructionsRequest) (*UpsertProjectInstructionsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpsertProjectInstructions not implemented")
 }
+func (UnimplementedProjectServiceServer) ListProjectRevisions(context.Context, *ListProjectRevisionsRequest) (*ListProjectRevisionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListProjectRevisions not implemented")
+}
+func (UnimplementedProjectServiceServer) GetProjectRevisionDoc(context.Context, *GetProjectRevisionDocRequest) (*GetProjectRevisionDocResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProjectRevisionDoc not implemented")
+}
+func (UnimplementedProjectServiceServer) GetProjectRevisionDiff(context.Context, *GetProjectRevisionDiffRequest) (*GetProjectRevisionDiffResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProjectRevisionDiff not implemented")
+}
 func (UnimplementedProjectServiceServer) mustEmbedUnimplementedProjectServiceServer() {}
 func (UnimplementedProjectServiceServer) testEmbeddedByValue()                        {}
 
@@ -200,6 +292,24 @@ func _ProjectService_UpsertProject_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProjectService_PatchProjectDocs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchProjectDocsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).PatchProjectDocs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_PatchProjectDocs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).PatchProjectDocs(ctx, req.(*PatchProjectDocsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProjectService_GetProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetProjectRequest)
 	if err := dec(in); err != nil {
@@ -272,6 +382,24 @@ func _ProjectService_RunProjectOverleafComment_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProjectService_LintProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LintProjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).LintProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_LintProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).LintProject(ctx, req.(*LintProjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ProjectService_GetProjectInstructions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetProjectInstructionsRequest)
 	if err := dec(in); err != nil {
@@ -308,6 +436,60 @@ func _ProjectService_UpsertProjectInstructions_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ProjectService_ListProjectRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).ListProjectRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_ListProjectRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).ListProjectRevisions(ctx, req.(*ListProjectRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectService_GetProjectRevisionDoc_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectRevisionDocRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).GetProjectRevisionDoc(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_GetProjectRevisionDoc_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).GetProjectRevisionDoc(ctx, req.(*GetProjectRevisionDocRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProjectService_GetProjectRevisionDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectRevisionDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProjectServiceServer).GetProjectRevisionDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProjectService_GetProjectRevisionDiff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProjectServiceServer).GetProjectRevisionDiff(ctx, req.(*GetProjectRevisionDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ProjectService_ServiceDesc is the grpc.ServiceDesc for ProjectService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -319,6 +501,10 @@ var ProjectService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpsertProject",
 			Handler:    _ProjectService_UpsertProject_Handler,
 		},
+		{
+			MethodName: "PatchProjectDocs",
+			Handler:    _ProjectService_PatchProjectDocs_Handler,
+		},
 		{
 			MethodName: "GetProject",
 			Handler:    _ProjectService_GetProject_Handler,
@@ -335,6 +521,10 @@ var ProjectService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RunProjectOverleafComment",
 			Handler:    _ProjectService_RunProjectOverleafComment_Handler,
 		},
+		{
+			MethodName: "LintProject",
+			Handler:    _ProjectService_LintProject_Handler,
+		},
 		{
 			MethodName: "GetProjectInstructions",
 			Handler:    _ProjectService_GetProjectInstructions_Handler,
@@ -343,6 +533,18 @@ var ProjectService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpsertProjectInstructions",
 			Handler:    _ProjectService_UpsertProjectInstructions_Handler,
 		},
+		{
+			MethodName: "ListProjectRevisions",
+			Handler:    _ProjectService_ListProjectRevisions_Handler,
+		},
+		{
+			MethodName: "GetProjectRevisionDoc",
+			Handler:    _ProjectService_GetProjectRevisionDoc_Handler,
+		},
+		{
+			MethodName: "GetProjectRevisionDiff",
+			Handler:    _ProjectService_GetProjectRevisionDiff_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "project/v1/project.proto",
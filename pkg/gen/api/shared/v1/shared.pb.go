@@ -37,6 +37,8 @@ const (
 	ErrorCode_ERROR_CODE_PERMISSION_DENIED    ErrorCode = 1008
 	ErrorCode_ERROR_CODE_INVALID_USER         ErrorCode = 1009
 	ErrorCode_ERROR_CODE_PROJECT_OUT_OF_DATE  ErrorCode = 1010
+	ErrorCode_ERROR_CODE_QUOTA_EXCEEDED       ErrorCode = 1011
+	ErrorCode_ERROR_CODE_DOC_VERSION_CONFLICT ErrorCode = 1012 // Retriable after syncing the project
 )
 
 // Enum value maps for ErrorCode.
@@ -54,6 +56,8 @@ var (
 		1008: "ERROR_CODE_PERMISSION_DENIED",
 		1009: "ERROR_CODE_INVALID_USER",
 		1010: "ERROR_CODE_PROJECT_OUT_OF_DATE",
+		1011: "ERROR_CODE_QUOTA_EXCEEDED",
+		1012: "ERROR_CODE_DOC_VERSION_CONFLICT",
 	}
 	ErrorCode_value = map[string]int32{
 		"ERROR_CODE_UNSPECIFIED":          0,
@@ -68,6 +72,8 @@ var (
 		"ERROR_CODE_PERMISSION_DENIED":    1008,
 		"ERROR_CODE_INVALID_USER":         1009,
 		"ERROR_CODE_PROJECT_OUT_OF_DATE":  1010,
+		"ERROR_CODE_QUOTA_EXCEEDED":       1011,
+		"ERROR_CODE_DOC_VERSION_CONFLICT": 1012,
 	}
 )
 
@@ -157,7 +163,7 @@ const file_shared_v1_shared_proto_rawDesc = "" +
 	"\x16shared/v1/shared.proto\x12\tshared.v1\"K\n" +
 	"\x05Error\x12(\n" +
 	"\x04code\x18\x02 \x01(\x0e2\x14.shared.v1.ErrorCodeR\x04code\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage*\x87\x03\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage*\xcd\x03\n" +
 	"\tErrorCode\x12\x1a\n" +
 	"\x16ERROR_CODE_UNSPECIFIED\x10\x00\x12\x17\n" +
 	"\x12ERROR_CODE_UNKNOWN\x10\xe8\a\x12\x18\n" +
@@ -170,7 +176,9 @@ const file_shared_v1_shared_proto_rawDesc = "" +
 	"\x18ERROR_CODE_INVALID_ACTOR\x10\xef\a\x12!\n" +
 	"\x1cERROR_CODE_PERMISSION_DENIED\x10\xf0\a\x12\x1c\n" +
 	"\x17ERROR_CODE_INVALID_USER\x10\xf1\a\x12#\n" +
-	"\x1eERROR_CODE_PROJECT_OUT_OF_DATE\x10\xf2\aB\x8f\x01\n" +
+	"\x1eERROR_CODE_PROJECT_OUT_OF_DATE\x10\xf2\a\x12\x1e\n" +
+	"\x19ERROR_CODE_QUOTA_EXCEEDED\x10\xf3\a\x12$\n" +
+	"\x1fERROR_CODE_DOC_VERSION_CONFLICT\x10\xf4\aB\x8f\x01\n" +
 	"\rcom.shared.v1B\vSharedProtoP\x01Z,paperdebugger/pkg/gen/api/shared/v1;sharedv1\xa2\x02\x03SXX\xaa\x02\tShared.V1\xca\x02\tShared\\V1\xe2\x02\x15Shared\\V1\\GPBMetadata\xea\x02\n" +
 	"Shared::V1b\x06proto3"
 
@@ -0,0 +1,841 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: usage/v1/usage.proto
+
+package usagev1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UsageLeaderboardType int32
+
+const (
+	UsageLeaderboardType_USAGE_LEADERBOARD_TYPE_UNSPECIFIED UsageLeaderboardType = 0 // Same as USER
+	UsageLeaderboardType_USAGE_LEADERBOARD_TYPE_USER        UsageLeaderboardType = 1
+	UsageLeaderboardType_USAGE_LEADERBOARD_TYPE_PROJECT     UsageLeaderboardType = 2
+)
+
+// Enum value maps for UsageLeaderboardType.
+var (
+	UsageLeaderboardType_name = map[int32]string{
+		0: "USAGE_LEADERBOARD_TYPE_UNSPECIFIED",
+		1: "USAGE_LEADERBOARD_TYPE_USER",
+		2: "USAGE_LEADERBOARD_TYPE_PROJECT",
+	}
+	UsageLeaderboardType_value = map[string]int32{
+		"USAGE_LEADERBOARD_TYPE_UNSPECIFIED": 0,
+		"USAGE_LEADERBOARD_TYPE_USER":        1,
+		"USAGE_LEADERBOARD_TYPE_PROJECT":     2,
+	}
+)
+
+func (x UsageLeaderboardType) Enum() *UsageLeaderboardType {
+	p := new(UsageLeaderboardType)
+	*p = x
+	return p
+}
+
+func (x UsageLeaderboardType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UsageLeaderboardType) Descriptor() protoreflect.EnumDescriptor {
+	return file_usage_v1_usage_proto_enumTypes[0].Descriptor()
+}
+
+func (UsageLeaderboardType) Type() protoreflect.EnumType {
+	return &file_usage_v1_usage_proto_enumTypes[0]
+}
+
+func (x UsageLeaderboardType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UsageLeaderboardType.Descriptor instead.
+func (UsageLeaderboardType) EnumDescriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{0}
+}
+
+type Usage struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SuccessCost      float64                `protobuf:"fixed64,1,opt,name=success_cost,json=successCost,proto3" json:"success_cost,omitempty"` // in USD
+	FailedCost       float64                `protobuf:"fixed64,2,opt,name=failed_cost,json=failedCost,proto3" json:"failed_cost,omitempty"`    // in USD
+	PromptTokens     int64                  `protobuf:"varint,3,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	CompletionTokens int64                  `protobuf:"varint,4,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Usage) Reset() {
+	*x = Usage{}
+	mi := &file_usage_v1_usage_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Usage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Usage) ProtoMessage() {}
+
+func (x *Usage) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Usage.ProtoReflect.Descriptor instead.
+func (*Usage) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Usage) GetSuccessCost() float64 {
+	if x != nil {
+		return x.SuccessCost
+	}
+	return 0
+}
+
+func (x *Usage) GetFailedCost() float64 {
+	if x != nil {
+		return x.FailedCost
+	}
+	return 0
+}
+
+func (x *Usage) GetPromptTokens() int64 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *Usage) GetCompletionTokens() int64 {
+	if x != nil {
+		return x.CompletionTokens
+	}
+	return 0
+}
+
+type ProjectUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     string                 `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	Usage         *Usage                 `protobuf:"bytes,2,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProjectUsage) Reset() {
+	*x = ProjectUsage{}
+	mi := &file_usage_v1_usage_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectUsage) ProtoMessage() {}
+
+func (x *ProjectUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectUsage.ProtoReflect.Descriptor instead.
+func (*ProjectUsage) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProjectUsage) GetProjectId() string {
+	if x != nil {
+		return x.ProjectId
+	}
+	return ""
+}
+
+func (x *ProjectUsage) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type GetUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectId     *string                `protobuf:"bytes,1,opt,name=project_id,json=projectId,proto3,oneof" json:"project_id,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_usage_v1_usage_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetUsageRequest) GetProjectId() string {
+	if x != nil && x.ProjectId != nil {
+		return *x.ProjectId
+	}
+	return ""
+}
+
+func (x *GetUsageRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetUsageRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+type GetUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         *Usage                 `protobuf:"bytes,1,opt,name=total,proto3" json:"total,omitempty"`
+	Projects      []*ProjectUsage        `protobuf:"bytes,2,rep,name=projects,proto3" json:"projects,omitempty"` // Most expensive first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageResponse) Reset() {
+	*x = GetUsageResponse{}
+	mi := &file_usage_v1_usage_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageResponse) ProtoMessage() {}
+
+func (x *GetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetUsageResponse) GetTotal() *Usage {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetProjects() []*ProjectUsage {
+	if x != nil {
+		return x.Projects
+	}
+	return nil
+}
+
+type UsageLeaderboardEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`       // User ID or project ID
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"` // Only for users
+	Usage         *Usage                 `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageLeaderboardEntry) Reset() {
+	*x = UsageLeaderboardEntry{}
+	mi := &file_usage_v1_usage_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageLeaderboardEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageLeaderboardEntry) ProtoMessage() {}
+
+func (x *UsageLeaderboardEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageLeaderboardEntry.ProtoReflect.Descriptor instead.
+func (*UsageLeaderboardEntry) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UsageLeaderboardEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UsageLeaderboardEntry) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UsageLeaderboardEntry) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type GetUsageLeaderboardRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          UsageLeaderboardType   `protobuf:"varint,1,opt,name=type,proto3,enum=usage.v1.UsageLeaderboardType" json:"type,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"` // Defaults to 20, at most 100
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageLeaderboardRequest) Reset() {
+	*x = GetUsageLeaderboardRequest{}
+	mi := &file_usage_v1_usage_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageLeaderboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageLeaderboardRequest) ProtoMessage() {}
+
+func (x *GetUsageLeaderboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageLeaderboardRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetUsageLeaderboardRequest) GetType() UsageLeaderboardType {
+	if x != nil {
+		return x.Type
+	}
+	return UsageLeaderboardType_USAGE_LEADERBOARD_TYPE_UNSPECIFIED
+}
+
+func (x *GetUsageLeaderboardRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetUsageLeaderboardRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *GetUsageLeaderboardRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetUsageLeaderboardResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Entries       []*UsageLeaderboardEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"` // Most expensive first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageLeaderboardResponse) Reset() {
+	*x = GetUsageLeaderboardResponse{}
+	mi := &file_usage_v1_usage_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageLeaderboardResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageLeaderboardResponse) ProtoMessage() {}
+
+func (x *GetUsageLeaderboardResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageLeaderboardResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageLeaderboardResponse) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetUsageLeaderboardResponse) GetEntries() []*UsageLeaderboardEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ModelUsage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelSlug     string                 `protobuf:"bytes,1,opt,name=model_slug,json=modelSlug,proto3" json:"model_slug,omitempty"` // Empty for usage tracked before models were recorded
+	Usage         *Usage                 `protobuf:"bytes,2,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelUsage) Reset() {
+	*x = ModelUsage{}
+	mi := &file_usage_v1_usage_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelUsage) ProtoMessage() {}
+
+func (x *ModelUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelUsage.ProtoReflect.Descriptor instead.
+func (*ModelUsage) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ModelUsage) GetModelSlug() string {
+	if x != nil {
+		return x.ModelSlug
+	}
+	return ""
+}
+
+func (x *ModelUsage) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+type GetUsageByModelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageByModelRequest) Reset() {
+	*x = GetUsageByModelRequest{}
+	mi := &file_usage_v1_usage_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageByModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageByModelRequest) ProtoMessage() {}
+
+func (x *GetUsageByModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageByModelRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageByModelRequest) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetUsageByModelRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *GetUsageByModelRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+type GetUsageByModelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Total         *Usage                 `protobuf:"bytes,1,opt,name=total,proto3" json:"total,omitempty"`
+	Models        []*ModelUsage          `protobuf:"bytes,2,rep,name=models,proto3" json:"models,omitempty"` // Most expensive first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageByModelResponse) Reset() {
+	*x = GetUsageByModelResponse{}
+	mi := &file_usage_v1_usage_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageByModelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageByModelResponse) ProtoMessage() {}
+
+func (x *GetUsageByModelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageByModelResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageByModelResponse) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetUsageByModelResponse) GetTotal() *Usage {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetUsageByModelResponse) GetModels() []*ModelUsage {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+// ExportUsage returns the hourly usages as a CSV file
+type ExportUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportUsageRequest) Reset() {
+	*x = ExportUsageRequest{}
+	mi := &file_usage_v1_usage_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportUsageRequest) ProtoMessage() {}
+
+func (x *ExportUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_v1_usage_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportUsageRequest.ProtoReflect.Descriptor instead.
+func (*ExportUsageRequest) Descriptor() ([]byte, []int) {
+	return file_usage_v1_usage_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExportUsageRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *ExportUsageRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+var File_usage_v1_usage_proto protoreflect.FileDescriptor
+
+const file_usage_v1_usage_proto_rawDesc = "" +
+	"\n" +
+	"\x14usage/v1/usage.proto\x12\busage.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x19google/api/httpbody.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9d\x01\n" +
+	"\x05Usage\x12!\n" +
+	"\fsuccess_cost\x18\x01 \x01(\x01R\vsuccessCost\x12\x1f\n" +
+	"\vfailed_cost\x18\x02 \x01(\x01R\n" +
+	"failedCost\x12#\n" +
+	"\rprompt_tokens\x18\x03 \x01(\x03R\fpromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\x04 \x01(\x03R\x10completionTokens\"T\n" +
+	"\fProjectUsage\x12\x1d\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tR\tprojectId\x12%\n" +
+	"\x05usage\x18\x02 \x01(\v2\x0f.usage.v1.UsageR\x05usage\"\xb6\x01\n" +
+	"\x0fGetUsageRequest\x12\"\n" +
+	"\n" +
+	"project_id\x18\x01 \x01(\tH\x00R\tprojectId\x88\x01\x01\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTimeB\r\n" +
+	"\v_project_id\"m\n" +
+	"\x10GetUsageResponse\x12%\n" +
+	"\x05total\x18\x01 \x01(\v2\x0f.usage.v1.UsageR\x05total\x122\n" +
+	"\bprojects\x18\x02 \x03(\v2\x16.usage.v1.ProjectUsageR\bprojects\"d\n" +
+	"\x15UsageLeaderboardEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12%\n" +
+	"\x05usage\x18\x03 \x01(\v2\x0f.usage.v1.UsageR\x05usage\"\xd8\x01\n" +
+	"\x1aGetUsageLeaderboardRequest\x122\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1e.usage.v1.UsageLeaderboardTypeR\x04type\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"X\n" +
+	"\x1bGetUsageLeaderboardResponse\x129\n" +
+	"\aentries\x18\x01 \x03(\v2\x1f.usage.v1.UsageLeaderboardEntryR\aentries\"R\n" +
+	"\n" +
+	"ModelUsage\x12\x1d\n" +
+	"\n" +
+	"model_slug\x18\x01 \x01(\tR\tmodelSlug\x12%\n" +
+	"\x05usage\x18\x02 \x01(\v2\x0f.usage.v1.UsageR\x05usage\"\x8a\x01\n" +
+	"\x16GetUsageByModelRequest\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\"n\n" +
+	"\x17GetUsageByModelResponse\x12%\n" +
+	"\x05total\x18\x01 \x01(\v2\x0f.usage.v1.UsageR\x05total\x12,\n" +
+	"\x06models\x18\x02 \x03(\v2\x14.usage.v1.ModelUsageR\x06models\"\x86\x01\n" +
+	"\x12ExportUsageRequest\x129\n" +
+	"\n" +
+	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime*\x83\x01\n" +
+	"\x14UsageLeaderboardType\x12&\n" +
+	"\"USAGE_LEADERBOARD_TYPE_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bUSAGE_LEADERBOARD_TYPE_USER\x10\x01\x12\"\n" +
+	"\x1eUSAGE_LEADERBOARD_TYPE_PROJECT\x10\x022\xe3\x03\n" +
+	"\fUsageService\x12h\n" +
+	"\bGetUsage\x12\x19.usage.v1.GetUsageRequest\x1a\x1a.usage.v1.GetUsageResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/_pd/api/v1/users/@self/usage\x12\x89\x01\n" +
+	"\x13GetUsageLeaderboard\x12$.usage.v1.GetUsageLeaderboardRequest\x1a%.usage.v1.GetUsageLeaderboardResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/_pd/api/v1/usage/leaderboard\x12x\n" +
+	"\x0fGetUsageByModel\x12 .usage.v1.GetUsageByModelRequest\x1a!.usage.v1.GetUsageByModelResponse\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/_pd/api/v1/usage/models\x12c\n" +
+	"\vExportUsage\x12\x1c.usage.v1.ExportUsageRequest\x1a\x14.google.api.HttpBody\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/_pd/api/v1/usage/exportB\x87\x01\n" +
+	"\fcom.usage.v1B\n" +
+	"UsageProtoP\x01Z*paperdebugger/pkg/gen/api/usage/v1;usagev1\xa2\x02\x03UXX\xaa\x02\bUsage.V1\xca\x02\bUsage\\V1\xe2\x02\x14Usage\\V1\\GPBMetadata\xea\x02\tUsage::V1b\x06proto3"
+
+var (
+	file_usage_v1_usage_proto_rawDescOnce sync.Once
+	file_usage_v1_usage_proto_rawDescData []byte
+)
+
+func file_usage_v1_usage_proto_rawDescGZIP() []byte {
+	file_usage_v1_usage_proto_rawDescOnce.Do(func() {
+		file_usage_v1_usage_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_usage_v1_usage_proto_rawDesc), len(file_usage_v1_usage_proto_rawDesc)))
+	})
+	return file_usage_v1_usage_proto_rawDescData
+}
+
+var file_usage_v1_usage_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_usage_v1_usage_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_usage_v1_usage_proto_goTypes = []any{
+	(UsageLeaderboardType)(0),           // 0: usage.v1.UsageLeaderboardType
+	(*Usage)(nil),                       // 1: usage.v1.Usage
+	(*ProjectUsage)(nil),                // 2: usage.v1.ProjectUsage
+	(*GetUsageRequest)(nil),             // 3: usage.v1.GetUsageRequest
+	(*GetUsageResponse)(nil),            // 4: usage.v1.GetUsageResponse
+	(*UsageLeaderboardEntry)(nil),       // 5: usage.v1.UsageLeaderboardEntry
+	(*GetUsageLeaderboardRequest)(nil),  // 6: usage.v1.GetUsageLeaderboardRequest
+	(*GetUsageLeaderboardResponse)(nil), // 7: usage.v1.GetUsageLeaderboardResponse
+	(*ModelUsage)(nil),                  // 8: usage.v1.ModelUsage
+	(*GetUsageByModelRequest)(nil),      // 9: usage.v1.GetUsageByModelRequest
+	(*GetUsageByModelResponse)(nil),     // 10: usage.v1.GetUsageByModelResponse
+	(*ExportUsageRequest)(nil),          // 11: usage.v1.ExportUsageRequest
+	(*timestamppb.Timestamp)(nil),       // 12: google.protobuf.Timestamp
+	(*httpbody.HttpBody)(nil),           // 13: google.api.HttpBody
+}
+var file_usage_v1_usage_proto_depIdxs = []int32{
+	1,  // 0: usage.v1.ProjectUsage.usage:type_name -> usage.v1.Usage
+	12, // 1: usage.v1.GetUsageRequest.start_time:type_name -> google.protobuf.Timestamp
+	12, // 2: usage.v1.GetUsageRequest.end_time:type_name -> google.protobuf.Timestamp
+	1,  // 3: usage.v1.GetUsageResponse.total:type_name -> usage.v1.Usage
+	2,  // 4: usage.v1.GetUsageResponse.projects:type_name -> usage.v1.ProjectUsage
+	1,  // 5: usage.v1.UsageLeaderboardEntry.usage:type_name -> usage.v1.Usage
+	0,  // 6: usage.v1.GetUsageLeaderboardRequest.type:type_name -> usage.v1.UsageLeaderboardType
+	12, // 7: usage.v1.GetUsageLeaderboardRequest.start_time:type_name -> google.protobuf.Timestamp
+	12, // 8: usage.v1.GetUsageLeaderboardRequest.end_time:type_name -> google.protobuf.Timestamp
+	5,  // 9: usage.v1.GetUsageLeaderboardResponse.entries:type_name -> usage.v1.UsageLeaderboardEntry
+	1,  // 10: usage.v1.ModelUsage.usage:type_name -> usage.v1.Usage
+	12, // 11: usage.v1.GetUsageByModelRequest.start_time:type_name -> google.protobuf.Timestamp
+	12, // 12: usage.v1.GetUsageByModelRequest.end_time:type_name -> google.protobuf.Timestamp
+	1,  // 13: usage.v1.GetUsageByModelResponse.total:type_name -> usage.v1.Usage
+	8,  // 14: usage.v1.GetUsageByModelResponse.models:type_name -> usage.v1.ModelUsage
+	12, // 15: usage.v1.ExportUsageRequest.start_time:type_name -> google.protobuf.Timestamp
+	12, // 16: usage.v1.ExportUsageRequest.end_time:type_name -> google.protobuf.Timestamp
+	3,  // 17: usage.v1.UsageService.GetUsage:input_type -> usage.v1.GetUsageRequest
+	6,  // 18: usage.v1.UsageService.GetUsageLeaderboard:input_type -> usage.v1.GetUsageLeaderboardRequest
+	9,  // 19: usage.v1.UsageService.GetUsageByModel:input_type -> usage.v1.GetUsageByModelRequest
+	11, // 20: usage.v1.UsageService.ExportUsage:input_type -> usage.v1.ExportUsageRequest
+	4,  // 21: usage.v1.UsageService.GetUsage:output_type -> usage.v1.GetUsageResponse
+	7,  // 22: usage.v1.UsageService.GetUsageLeaderboard:output_type -> usage.v1.GetUsageLeaderboardResponse
+	10, // 23: usage.v1.UsageService.GetUsageByModel:output_type -> usage.v1.GetUsageByModelResponse
+	13, // 24: usage.v1.UsageService.ExportUsage:output_type -> google.api.HttpBody
+	21, // [21:25] is the sub-list for method output_type
+	17, // [17:21] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_usage_v1_usage_proto_init() }
+func file_usage_v1_usage_proto_init() {
+	if File_usage_v1_usage_proto != nil {
+		return
+	}
+	file_usage_v1_usage_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_usage_v1_usage_proto_rawDesc), len(file_usage_v1_usage_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_usage_v1_usage_proto_goTypes,
+		DependencyIndexes: file_usage_v1_usage_proto_depIdxs,
+		EnumInfos:         file_usage_v1_usage_proto_enumTypes,
+		MessageInfos:      file_usage_v1_usage_proto_msgTypes,
+	}.Build()
+	File_usage_v1_usage_proto = out.File
+	file_usage_v1_usage_proto_goTypes = nil
+	file_usage_v1_usage_proto_depIdxs = nil
+}
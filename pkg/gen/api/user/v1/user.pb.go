@@ -23,6 +23,56 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// How the user macros of the paper are given to the paper scorer, see Settings.chat_macro_glossary for chat
+type MacroExpansion int32
+
+const (
+	MacroExpansion_MACRO_EXPANSION_UNSPECIFIED MacroExpansion = 0 // The macros are left as they are
+	MacroExpansion_MACRO_EXPANSION_EXPAND      MacroExpansion = 1 // The uses of the macros are replaced by their bodies
+	MacroExpansion_MACRO_EXPANSION_GLOSSARY    MacroExpansion = 2 // The macros are listed next to the paper
+)
+
+// Enum value maps for MacroExpansion.
+var (
+	MacroExpansion_name = map[int32]string{
+		0: "MACRO_EXPANSION_UNSPECIFIED",
+		1: "MACRO_EXPANSION_EXPAND",
+		2: "MACRO_EXPANSION_GLOSSARY",
+	}
+	MacroExpansion_value = map[string]int32{
+		"MACRO_EXPANSION_UNSPECIFIED": 0,
+		"MACRO_EXPANSION_EXPAND":      1,
+		"MACRO_EXPANSION_GLOSSARY":    2,
+	}
+)
+
+func (x MacroExpansion) Enum() *MacroExpansion {
+	p := new(MacroExpansion)
+	*p = x
+	return p
+}
+
+func (x MacroExpansion) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MacroExpansion) Descriptor() protoreflect.EnumDescriptor {
+	return file_user_v1_user_proto_enumTypes[0].Descriptor()
+}
+
+func (MacroExpansion) Type() protoreflect.EnumType {
+	return &file_user_v1_user_proto_enumTypes[0]
+}
+
+func (x MacroExpansion) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MacroExpansion.Descriptor instead.
+func (MacroExpansion) EnumDescriptor() ([]byte, []int) {
+	return file_user_v1_user_proto_rawDescGZIP(), []int{0}
+}
+
 type User struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -756,6 +806,8 @@ type Settings struct {
 	ShowedOnboarding             bool                   `protobuf:"varint,5,opt,name=showed_onboarding,json=showedOnboarding,proto3" json:"showed_onboarding,omitempty"`
 	OpenaiApiKey                 string                 `protobuf:"bytes,6,opt,name=openai_api_key,json=openaiApiKey,proto3" json:"openai_api_key,omitempty"`
 	CustomModels                 []*CustomModel         `protobuf:"bytes,7,rep,name=custom_models,json=customModels,proto3" json:"custom_models,omitempty"`
+	MacroExpansion               MacroExpansion         `protobuf:"varint,8,opt,name=macro_expansion,json=macroExpansion,proto3,enum=user.v1.MacroExpansion" json:"macro_expansion,omitempty"`
+	ChatMacroGlossary            bool                   `protobuf:"varint,9,opt,name=chat_macro_glossary,json=chatMacroGlossary,proto3" json:"chat_macro_glossary,omitempty"` // Lists the user macros in the system prompt of chat, which never expands them
 	unknownFields                protoimpl.UnknownFields
 	sizeCache                    protoimpl.SizeCache
 }
@@ -839,6 +891,20 @@ func (x *Settings) GetCustomModels() []*CustomModel {
 	return nil
 }
 
+func (x *Settings) GetMacroExpansion() MacroExpansion {
+	if x != nil {
+		return x.MacroExpansion
+	}
+	return MacroExpansion_MACRO_EXPANSION_UNSPECIFIED
+}
+
+func (x *Settings) GetChatMacroGlossary() bool {
+	if x != nil {
+		return x.ChatMacroGlossary
+	}
+	return false
+}
+
 type GetSettingsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1309,7 +1375,7 @@ const file_user_v1_user_proto_rawDesc = "" +
 	"\vtemperature\x18\n" +
 	" \x01(\x02R\vtemperature\x12.\n" +
 	"\x13parallel_tool_calls\x18\v \x01(\bR\x11parallelToolCalls\x12\x14\n" +
-	"\x05store\x18\f \x01(\bR\x05store\"\x8f\x03\n" +
+	"\x05store\x18\f \x01(\bR\x05store\"\x81\x04\n" +
 	"\bSettings\x12C\n" +
 	"\x1eshow_shortcuts_after_selection\x18\x01 \x01(\bR\x1bshowShortcutsAfterSelection\x12F\n" +
 	" full_width_paper_debugger_button\x18\x02 \x01(\bR\x1cfullWidthPaperDebuggerButton\x12<\n" +
@@ -1317,7 +1383,9 @@ const file_user_v1_user_proto_rawDesc = "" +
 	"\x11full_document_rag\x18\x04 \x01(\bR\x0ffullDocumentRag\x12+\n" +
 	"\x11showed_onboarding\x18\x05 \x01(\bR\x10showedOnboarding\x12$\n" +
 	"\x0eopenai_api_key\x18\x06 \x01(\tR\fopenaiApiKey\x129\n" +
-	"\rcustom_models\x18\a \x03(\v2\x14.user.v1.CustomModelR\fcustomModels\"\x14\n" +
+	"\rcustom_models\x18\a \x03(\v2\x14.user.v1.CustomModelR\fcustomModels\x12@\n" +
+	"\x0fmacro_expansion\x18\b \x01(\x0e2\x17.user.v1.MacroExpansionR\x0emacroExpansion\x12.\n" +
+	"\x13chat_macro_glossary\x18\t \x01(\bR\x11chatMacroGlossary\"\x14\n" +
 	"\x12GetSettingsRequest\"D\n" +
 	"\x13GetSettingsResponse\x12-\n" +
 	"\bsettings\x18\x01 \x01(\v2\x11.user.v1.SettingsR\bsettings\"F\n" +
@@ -1334,7 +1402,11 @@ const file_user_v1_user_proto_rawDesc = "" +
 	"\x1dUpsertUserInstructionsRequest\x12\"\n" +
 	"\finstructions\x18\x01 \x01(\tR\finstructions\"D\n" +
 	"\x1eUpsertUserInstructionsResponse\x12\"\n" +
-	"\finstructions\x18\x01 \x01(\tR\finstructions2\x83\n" +
+	"\finstructions\x18\x01 \x01(\tR\finstructions*k\n" +
+	"\x0eMacroExpansion\x12\x1f\n" +
+	"\x1bMACRO_EXPANSION_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16MACRO_EXPANSION_EXPAND\x10\x01\x12\x1c\n" +
+	"\x18MACRO_EXPANSION_GLOSSARY\x10\x022\x83\n" +
 	"\n" +
 	"\vUserService\x12]\n" +
 	"\aGetUser\x12\x17.user.v1.GetUserRequest\x1a\x18.user.v1.GetUserResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/_pd/api/v1/users/@self\x12q\n" +
@@ -1361,71 +1433,74 @@ func file_user_v1_user_proto_rawDescGZIP() []byte {
 	return file_user_v1_user_proto_rawDescData
 }
 
+var file_user_v1_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
 var file_user_v1_user_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_user_v1_user_proto_goTypes = []any{
-	(*User)(nil),                           // 0: user.v1.User
-	(*GetUserRequest)(nil),                 // 1: user.v1.GetUserRequest
-	(*GetUserResponse)(nil),                // 2: user.v1.GetUserResponse
-	(*Prompt)(nil),                         // 3: user.v1.Prompt
-	(*ListPromptsRequest)(nil),             // 4: user.v1.ListPromptsRequest
-	(*ListPromptsResponse)(nil),            // 5: user.v1.ListPromptsResponse
-	(*CreatePromptRequest)(nil),            // 6: user.v1.CreatePromptRequest
-	(*CreatePromptResponse)(nil),           // 7: user.v1.CreatePromptResponse
-	(*UpdatePromptRequest)(nil),            // 8: user.v1.UpdatePromptRequest
-	(*UpdatePromptResponse)(nil),           // 9: user.v1.UpdatePromptResponse
-	(*DeletePromptRequest)(nil),            // 10: user.v1.DeletePromptRequest
-	(*DeletePromptResponse)(nil),           // 11: user.v1.DeletePromptResponse
-	(*CustomModel)(nil),                    // 12: user.v1.CustomModel
-	(*Settings)(nil),                       // 13: user.v1.Settings
-	(*GetSettingsRequest)(nil),             // 14: user.v1.GetSettingsRequest
-	(*GetSettingsResponse)(nil),            // 15: user.v1.GetSettingsResponse
-	(*UpdateSettingsRequest)(nil),          // 16: user.v1.UpdateSettingsRequest
-	(*UpdateSettingsResponse)(nil),         // 17: user.v1.UpdateSettingsResponse
-	(*ResetSettingsRequest)(nil),           // 18: user.v1.ResetSettingsRequest
-	(*ResetSettingsResponse)(nil),          // 19: user.v1.ResetSettingsResponse
-	(*GetUserInstructionsRequest)(nil),     // 20: user.v1.GetUserInstructionsRequest
-	(*GetUserInstructionsResponse)(nil),    // 21: user.v1.GetUserInstructionsResponse
-	(*UpsertUserInstructionsRequest)(nil),  // 22: user.v1.UpsertUserInstructionsRequest
-	(*UpsertUserInstructionsResponse)(nil), // 23: user.v1.UpsertUserInstructionsResponse
-	(*timestamppb.Timestamp)(nil),          // 24: google.protobuf.Timestamp
+	(MacroExpansion)(0),                    // 0: user.v1.MacroExpansion
+	(*User)(nil),                           // 1: user.v1.User
+	(*GetUserRequest)(nil),                 // 2: user.v1.GetUserRequest
+	(*GetUserResponse)(nil),                // 3: user.v1.GetUserResponse
+	(*Prompt)(nil),                         // 4: user.v1.Prompt
+	(*ListPromptsRequest)(nil),             // 5: user.v1.ListPromptsRequest
+	(*ListPromptsResponse)(nil),            // 6: user.v1.ListPromptsResponse
+	(*CreatePromptRequest)(nil),            // 7: user.v1.CreatePromptRequest
+	(*CreatePromptResponse)(nil),           // 8: user.v1.CreatePromptResponse
+	(*UpdatePromptRequest)(nil),            // 9: user.v1.UpdatePromptRequest
+	(*UpdatePromptResponse)(nil),           // 10: user.v1.UpdatePromptResponse
+	(*DeletePromptRequest)(nil),            // 11: user.v1.DeletePromptRequest
+	(*DeletePromptResponse)(nil),           // 12: user.v1.DeletePromptResponse
+	(*CustomModel)(nil),                    // 13: user.v1.CustomModel
+	(*Settings)(nil),                       // 14: user.v1.Settings
+	(*GetSettingsRequest)(nil),             // 15: user.v1.GetSettingsRequest
+	(*GetSettingsResponse)(nil),            // 16: user.v1.GetSettingsResponse
+	(*UpdateSettingsRequest)(nil),          // 17: user.v1.UpdateSettingsRequest
+	(*UpdateSettingsResponse)(nil),         // 18: user.v1.UpdateSettingsResponse
+	(*ResetSettingsRequest)(nil),           // 19: user.v1.ResetSettingsRequest
+	(*ResetSettingsResponse)(nil),          // 20: user.v1.ResetSettingsResponse
+	(*GetUserInstructionsRequest)(nil),     // 21: user.v1.GetUserInstructionsRequest
+	(*GetUserInstructionsResponse)(nil),    // 22: user.v1.GetUserInstructionsResponse
+	(*UpsertUserInstructionsRequest)(nil),  // 23: user.v1.UpsertUserInstructionsRequest
+	(*UpsertUserInstructionsResponse)(nil), // 24: user.v1.UpsertUserInstructionsResponse
+	(*timestamppb.Timestamp)(nil),          // 25: google.protobuf.Timestamp
 }
 var file_user_v1_user_proto_depIdxs = []int32{
-	0,  // 0: user.v1.GetUserResponse.user:type_name -> user.v1.User
-	24, // 1: user.v1.Prompt.created_at:type_name -> google.protobuf.Timestamp
-	24, // 2: user.v1.Prompt.updated_at:type_name -> google.protobuf.Timestamp
-	3,  // 3: user.v1.ListPromptsResponse.prompts:type_name -> user.v1.Prompt
-	3,  // 4: user.v1.CreatePromptResponse.prompt:type_name -> user.v1.Prompt
-	3,  // 5: user.v1.UpdatePromptResponse.prompt:type_name -> user.v1.Prompt
-	12, // 6: user.v1.Settings.custom_models:type_name -> user.v1.CustomModel
-	13, // 7: user.v1.GetSettingsResponse.settings:type_name -> user.v1.Settings
-	13, // 8: user.v1.UpdateSettingsRequest.settings:type_name -> user.v1.Settings
-	13, // 9: user.v1.UpdateSettingsResponse.settings:type_name -> user.v1.Settings
-	13, // 10: user.v1.ResetSettingsResponse.settings:type_name -> user.v1.Settings
-	1,  // 11: user.v1.UserService.GetUser:input_type -> user.v1.GetUserRequest
-	4,  // 12: user.v1.UserService.ListPrompts:input_type -> user.v1.ListPromptsRequest
-	6,  // 13: user.v1.UserService.CreatePrompt:input_type -> user.v1.CreatePromptRequest
-	8,  // 14: user.v1.UserService.UpdatePrompt:input_type -> user.v1.UpdatePromptRequest
-	20, // 15: user.v1.UserService.GetUserInstructions:input_type -> user.v1.GetUserInstructionsRequest
-	22, // 16: user.v1.UserService.UpsertUserInstructions:input_type -> user.v1.UpsertUserInstructionsRequest
-	10, // 17: user.v1.UserService.DeletePrompt:input_type -> user.v1.DeletePromptRequest
-	14, // 18: user.v1.UserService.GetSettings:input_type -> user.v1.GetSettingsRequest
-	16, // 19: user.v1.UserService.UpdateSettings:input_type -> user.v1.UpdateSettingsRequest
-	18, // 20: user.v1.UserService.ResetSettings:input_type -> user.v1.ResetSettingsRequest
-	2,  // 21: user.v1.UserService.GetUser:output_type -> user.v1.GetUserResponse
-	5,  // 22: user.v1.UserService.ListPrompts:output_type -> user.v1.ListPromptsResponse
-	7,  // 23: user.v1.UserService.CreatePrompt:output_type -> user.v1.CreatePromptResponse
-	9,  // 24: user.v1.UserService.UpdatePrompt:output_type -> user.v1.UpdatePromptResponse
-	21, // 25: user.v1.UserService.GetUserInstructions:output_type -> user.v1.GetUserInstructionsResponse
-	23, // 26: user.v1.UserService.UpsertUserInstructions:output_type -> user.v1.UpsertUserInstructionsResponse
-	11, // 27: user.v1.UserService.DeletePrompt:output_type -> user.v1.DeletePromptResponse
-	15, // 28: user.v1.UserService.GetSettings:output_type -> user.v1.GetSettingsResponse
-	17, // 29: user.v1.UserService.UpdateSettings:output_type -> user.v1.UpdateSettingsResponse
-	19, // 30: user.v1.UserService.ResetSettings:output_type -> user.v1.ResetSettingsResponse
-	21, // [21:31] is the sub-list for method output_type
-	11, // [11:21] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	1,  // 0: user.v1.GetUserResponse.user:type_name -> user.v1.User
+	25, // 1: user.v1.Prompt.created_at:type_name -> google.protobuf.Timestamp
+	25, // 2: user.v1.Prompt.updated_at:type_name -> google.protobuf.Timestamp
+	4,  // 3: user.v1.ListPromptsResponse.prompts:type_name -> user.v1.Prompt
+	4,  // 4: user.v1.CreatePromptResponse.prompt:type_name -> user.v1.Prompt
+	4,  // 5: user.v1.UpdatePromptResponse.prompt:type_name -> user.v1.Prompt
+	13, // 6: user.v1.Settings.custom_models:type_name -> user.v1.CustomModel
+	0,  // 7: user.v1.Settings.macro_expansion:type_name -> user.v1.MacroExpansion
+	14, // 8: user.v1.GetSettingsResponse.settings:type_name -> user.v1.Settings
+	14, // 9: user.v1.UpdateSettingsRequest.settings:type_name -> user.v1.Settings
+	14, // 10: user.v1.UpdateSettingsResponse.settings:type_name -> user.v1.Settings
+	14, // 11: user.v1.ResetSettingsResponse.settings:type_name -> user.v1.Settings
+	2,  // 12: user.v1.UserService.GetUser:input_type -> user.v1.GetUserRequest
+	5,  // 13: user.v1.UserService.ListPrompts:input_type -> user.v1.ListPromptsRequest
+	7,  // 14: user.v1.UserService.CreatePrompt:input_type -> user.v1.CreatePromptRequest
+	9,  // 15: user.v1.UserService.UpdatePrompt:input_type -> user.v1.UpdatePromptRequest
+	21, // 16: user.v1.UserService.GetUserInstructions:input_type -> user.v1.GetUserInstructionsRequest
+	23, // 17: user.v1.UserService.UpsertUserInstructions:input_type -> user.v1.UpsertUserInstructionsRequest
+	11, // 18: user.v1.UserService.DeletePrompt:input_type -> user.v1.DeletePromptRequest
+	15, // 19: user.v1.UserService.GetSettings:input_type -> user.v1.GetSettingsRequest
+	17, // 20: user.v1.UserService.UpdateSettings:input_type -> user.v1.UpdateSettingsRequest
+	19, // 21: user.v1.UserService.ResetSettings:input_type -> user.v1.ResetSettingsRequest
+	3,  // 22: user.v1.UserService.GetUser:output_type -> user.v1.GetUserResponse
+	6,  // 23: user.v1.UserService.ListPrompts:output_type -> user.v1.ListPromptsResponse
+	8,  // 24: user.v1.UserService.CreatePrompt:output_type -> user.v1.CreatePromptResponse
+	10, // 25: user.v1.UserService.UpdatePrompt:output_type -> user.v1.UpdatePromptResponse
+	22, // 26: user.v1.UserService.GetUserInstructions:output_type -> user.v1.GetUserInstructionsResponse
+	24, // 27: user.v1.UserService.UpsertUserInstructions:output_type -> user.v1.UpsertUserInstructionsResponse
+	12, // 28: user.v1.UserService.DeletePrompt:output_type -> user.v1.DeletePromptResponse
+	16, // 29: user.v1.UserService.GetSettings:output_type -> user.v1.GetSettingsResponse
+	18, // 30: user.v1.UserService.UpdateSettings:output_type -> user.v1.UpdateSettingsResponse
+	20, // 31: user.v1.UserService.ResetSettings:output_type -> user.v1.ResetSettingsResponse
+	22, // [22:32] is the sub-list for method output_type
+	12, // [12:22] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_user_v1_user_proto_init() }
@@ -1438,13 +1513,14 @@ func file_user_v1_user_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_user_v1_user_proto_rawDesc), len(file_user_v1_user_proto_rawDesc)),
-			NumEnums:      0,
+			NumEnums:      1,
 			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_user_v1_user_proto_goTypes,
 		DependencyIndexes: file_user_v1_user_proto_depIdxs,
+		EnumInfos:         file_user_v1_user_proto_enumTypes,
 		MessageInfos:      file_user_v1_user_proto_msgTypes,
 	}.Build()
 	File_user_v1_user_proto = out.File